@@ -0,0 +1,11 @@
+package interfaces
+
+import "rol/domain"
+
+//IBootEventBus bus for the events observed from the booting devices by the network services
+type IBootEventBus interface {
+	//Publish event to the subscribers, it doesn't block the caller
+	Publish(event domain.BootEvent)
+	//Subscribe handler to all published events
+	Subscribe(handler func(event domain.BootEvent))
+}
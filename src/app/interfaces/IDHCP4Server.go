@@ -1,6 +1,9 @@
 package interfaces
 
-import "rol/domain"
+import (
+	"github.com/google/uuid"
+	"rol/domain"
+)
 
 //IDHCP4Server interface for DHCP v4 server implementations
 type IDHCP4Server interface {
@@ -12,4 +15,9 @@ type IDHCP4Server interface {
 	Stop()
 	//GetState of DHCP v4 server
 	GetState() domain.DHCPServerState
+	//ReleaseLease return leased ip address to the pool of free addresses
+	ReleaseLease(ip string) error
+	//ReleaseExpiredLease remove lease if it's still expired and return its ip address to the pool of free addresses,
+	//returns false if lease was renewed or removed meanwhile
+	ReleaseExpiredLease(leaseID uuid.UUID) (bool, error)
 }
@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"rol/domain"
+)
+
+//IDHCP6Server interface for DHCP v6 server implementations
+type IDHCP6Server interface {
+	//ReloadConfiguration DHCP v6 server from config
+	ReloadConfiguration(dhcp6config domain.DHCP6Config) error
+	//Start DHCP v6 server
+	Start() error
+	//Stop DHCP v6 server
+	Stop()
+	//GetState of DHCP v6 server
+	GetState() domain.DHCPServerState
+	//ReleaseLease return leased ip address to the pool of free addresses
+	ReleaseLease(ip string) error
+	//ReleaseExpiredLease remove lease if it's still expired and return its ip address to the pool of free addresses,
+	//returns false if lease was renewed or removed meanwhile
+	ReleaseExpiredLease(leaseID uuid.UUID) (bool, error)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import "rol/domain"
+
+//IDHCP6ServerFactory interface for DHCP v6 server fabric implementations
+type IDHCP6ServerFactory interface {
+	//Create DHCP v6 server with config
+	//
+	//Params:
+	//	config - dhcp v6 config
+	//Return:
+	//  IDHCP6Server - dhcp v6 server
+	//	error - if an error occurred, otherwise nil
+	Create(config domain.DHCP6Config) (IDHCP6Server, error)
+}
@@ -0,0 +1,46 @@
+package interfaces
+
+import (
+	"github.com/google/uuid"
+	"rol/domain"
+)
+
+//IEthernetSwitchDriverRegistry is the interface of the registry of ethernet switch drivers
+type IEthernetSwitchDriverRegistry interface {
+	//GetModels gets all switch models of the registered drivers
+	//
+	//Return:
+	//	[]domain.EthernetSwitchModel - switch models in registration order
+	GetModels() []domain.EthernetSwitchModel
+	//GetModel gets switch model by code
+	//
+	//Params:
+	//	code - switch model code
+	//Return:
+	//	domain.EthernetSwitchModel - switch model
+	//	error - NotFound error if model is not registered
+	GetModel(code string) (domain.EthernetSwitchModel, error)
+	//RequiresSNMP checks that the driver of the switch model needs SNMP credentials
+	//
+	//Params:
+	//	code - switch model code
+	//Return:
+	//	bool - true if SNMP credentials are required
+	//	error - NotFound error if model is not registered
+	RequiresSNMP(code string) (bool, error)
+	//NewManager creates switch manager by the driver of the switch model
+	//
+	//Params:
+	//	ethernetSwitch - switch entity
+	//Return:
+	//	IEthernetSwitchManager - switch manager, nil if the driver stores switch configuration in database only
+	//	error - NotFound error if switch model is not registered
+	NewManager(ethernetSwitch domain.EthernetSwitch) (IEthernetSwitchManager, error)
+	//ReleaseSwitch releases resources that the drivers hold for the switch
+	//
+	//Params:
+	//	switchID - ethernet switch ID
+	//Return:
+	//	error - if an error occurs, otherwise nil
+	ReleaseSwitch(switchID uuid.UUID) error
+}
@@ -1,5 +1,7 @@
 package interfaces
 
+import "rol/domain"
+
 //IEthernetSwitchManager is the interface is needed to manage ethernet switch
 type IEthernetSwitchManager interface {
 	//GetVLANs gets all VLANs on switch
@@ -17,6 +19,14 @@ type IEthernetSwitchManager interface {
 	//	[]int - slice of tagged VLANs IDs
 	//	error - if an error occurs, otherwise nil
 	GetVLANsOnPort(portName string) (int, []int, error)
+	//GetPortPVID gets port PVID
+	//
+	//Params:
+	//	portName - port name
+	//Return:
+	//	int - PVID
+	//	error - if an error occurs, otherwise nil
+	GetPortPVID(portName string) (int, error)
 	//AddTaggedVLANOnPort add tagged VLAN on given port
 	//
 	//Params:
@@ -63,6 +73,42 @@ type IEthernetSwitchManager interface {
 	//Return:
 	//	error - if an error occurs, otherwise nil
 	CreateVLAN(vlanID int) error
+	//GetCapabilities gets features of the switch: ports, VLANs and PoE support
+	//
+	//Return:
+	//	domain.EthernetSwitchCapabilities - switch capabilities
+	//	error - if an error occurs, otherwise nil
+	GetCapabilities() (domain.EthernetSwitchCapabilities, error)
+	//ApplyOperations applies operations in the given order, the switch managed over CLI
+	//applies all of them within one session. Applying stops on the first failed operation
+	//
+	//Params:
+	//	operations - switch configuration changes
+	//Return:
+	//	error - if an error occurs, otherwise nil
+	ApplyOperations(operations []domain.EthernetSwitchOperation) error
+	//GetMACAddressTable gets MAC addresses learned on the switch ports
+	//
+	//Return:
+	//	[]domain.EthernetSwitchMACAddress - MAC address forwarding table entries
+	//	error - if an error occurs, otherwise nil
+	GetMACAddressTable() ([]domain.EthernetSwitchMACAddress, error)
+	//GetLLDPNeighbors gets LLDP neighbors of the switch ports
+	//
+	//Return:
+	//	[]domain.EthernetSwitchLLDPNeighbor - neighbors of all ports
+	//	error - if an error occurs, otherwise nil
+	GetLLDPNeighbors() ([]domain.EthernetSwitchLLDPNeighbor, error)
+	//SaveConfig save current settings on switch
+	//
+	//Return:
+	//	error - if an error occurs, otherwise nil
+	SaveConfig() error
+}
+
+//IEthernetSwitchPOEManager is the interface is needed to manage PoE on the ethernet switch ports,
+//it is implemented only by the managers of the switches with PoE support
+type IEthernetSwitchPOEManager interface {
 	//GetPOEPortStatus gets poe status on given port
 	//
 	//Params:
@@ -86,9 +132,23 @@ type IEthernetSwitchManager interface {
 	//Return:
 	//	error - if an error occurs, otherwise nil
 	DisablePOEPort(portName string) error
-	//SaveConfig save current settings on switch
+}
+
+//IEthernetSwitchConfigManager is the interface is needed to backup and restore the ethernet switch configuration,
+//it is implemented only by the managers of the switches with text running configuration
+type IEthernetSwitchConfigManager interface {
+	//GetRunningConfig gets full running configuration of the switch
 	//
 	//Return:
+	//	string - running configuration text
 	//	error - if an error occurs, otherwise nil
-	SaveConfig() error
+	GetRunningConfig() (string, error)
+	//RestoreConfig applies VLANs, ports VLANs membership, PVID and PoE state from the running configuration
+	//that was received by GetRunningConfig
+	//
+	//Params:
+	//	config - running configuration text
+	//Return:
+	//	error - if an error occurs, otherwise nil
+	RestoreConfig(config string) error
 }
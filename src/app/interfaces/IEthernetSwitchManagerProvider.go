@@ -9,4 +9,6 @@ import (
 type IEthernetSwitchManagerProvider interface {
 	//Get ethernet switch manager
 	Get(ctx context.Context, switchID uuid.UUID) (IEthernetSwitchManager, error)
+	//Release ethernet switch manager and resources that the switch driver holds for the deleted switch
+	Release(switchID uuid.UUID) error
 }
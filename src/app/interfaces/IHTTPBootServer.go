@@ -0,0 +1,19 @@
+package interfaces
+
+import "rol/domain"
+
+//IHTTPBootServer define interface for HTTP boot file server implementation
+type IHTTPBootServer interface {
+	//ReloadConfig for HTTP boot server, applied on the next start
+	ReloadConfig(config domain.HTTPBootConfig) error
+	//ReloadPaths for HTTP boot server on fly (without stop/start)
+	ReloadPaths(paths []domain.HTTPBootPathRatio) error
+	//Start HTTP boot server
+	Start() error
+	//Stop HTTP boot server
+	Stop()
+	//GetState of HTTP boot server
+	GetState() domain.HTTPBootServerState
+	//GetFileStats get download statistics of the served files since the server creation
+	GetFileStats() []domain.HTTPBootFileStats
+}
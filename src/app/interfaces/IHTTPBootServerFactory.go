@@ -0,0 +1,15 @@
+package interfaces
+
+import "rol/domain"
+
+//IHTTPBootServerFactory interface for HTTP boot server fabric implementations
+type IHTTPBootServerFactory interface {
+	//Create HTTP boot server
+	//
+	//Params:
+	//	config - http boot server config
+	//Return:
+	//  IHTTPBootServer - http boot server
+	//	error - if an error occurred, otherwise nil
+	Create(config domain.HTTPBootConfig) (IHTTPBootServer, error)
+}
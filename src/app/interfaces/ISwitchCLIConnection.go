@@ -0,0 +1,32 @@
+package interfaces
+
+//ISwitchCLIConnection is the interface of the transport to the switch command line interface
+type ISwitchCLIConnection interface {
+	//Connect makes a connection with the switch CLI server
+	//
+	//Params:
+	//	address - server address with port
+	//Return:
+	//	error - if an error occurs, otherwise nil
+	Connect(address string) error
+	//Read reads all output lines before expect word
+	//
+	//Params:
+	//	expect - the word to which you want to read lines
+	//Return:
+	//	string - CLI output
+	//	error - if an error occurs, otherwise nil
+	Read(expect string) (string, error)
+	//Send sends command to the switch CLI
+	//
+	//Params:
+	//	command - command to send
+	//Return:
+	//	error - if an error occurs, otherwise nil
+	Send(command string) error
+	//Close closes the connection if it is opened
+	//
+	//Return:
+	//	error - if an error occurs, otherwise nil
+	Close() error
+}
@@ -14,4 +14,10 @@ type ITFTPServer interface {
 	Stop()
 	//GetState of TFTP server
 	GetState() domain.TFTPServerState
+	//GetLastError get message of the last start or serve error, empty if server is started successfully
+	GetLastError() string
+	//GetTransfers get active and recent finished transfers, the newest first
+	GetTransfers() []domain.TFTPTransfer
+	//GetPathStats get transfer counters for each requested file
+	GetPathStats() []domain.TFTPPathStats
 }
@@ -0,0 +1,88 @@
+package mappers
+
+import (
+	"rol/domain"
+	"rol/dtos"
+)
+
+//MapDHCP6ServerToDto writes dhcp v6 config fields to dto
+//
+//Params:
+//	entity - DHCP v6 config entity
+//	*dto - DHCP v6 server dto
+func MapDHCP6ServerToDto(entity domain.DHCP6Config, dto *dtos.DHCP6ServerDto) {
+	dto.ID = entity.ID
+	dto.CreatedAt = entity.CreatedAt
+	dto.UpdatedAt = entity.UpdatedAt
+	dto.Interface = entity.Interface
+	dto.Range = entity.Range
+	dto.DNS = entity.DNS
+	dto.Port = entity.Port
+	dto.Enabled = entity.Enabled
+	dto.LeaseTime = entity.LeaseTime
+}
+
+//MapDHCP6ServerCreateDtoToEntity writes dhcp v6 create dto fields to entity
+//
+//Params:
+// 	dto - DHCP v6 server create dto
+//	entity - DHCP v6 config entity
+func MapDHCP6ServerCreateDtoToEntity(dto dtos.DHCP6ServerCreateDto, entity *domain.DHCP6Config) {
+	entity.Interface = dto.Interface
+	entity.Range = dto.Range
+	entity.DNS = dto.DNS
+	entity.Port = dto.Port
+	entity.Enabled = dto.Enabled
+	entity.LeaseTime = dto.LeaseTime
+}
+
+//MapDHCP6ServerUpdateDtoToEntity writes dhcp v6 update dto fields to entity
+//
+//Params:
+// 	dto - DHCP v6 server update dto
+//	entity - DHCP v6 config entity
+func MapDHCP6ServerUpdateDtoToEntity(dto dtos.DHCP6ServerUpdateDto, entity *domain.DHCP6Config) {
+	entity.DNS = dto.DNS
+	entity.Port = dto.Port
+	entity.Enabled = dto.Enabled
+	entity.LeaseTime = dto.LeaseTime
+}
+
+//MapDHCP6LeaseToDto writes dhcp v6 lease fields to dto
+//
+//Params:
+//	entity - DHCP v6 lease entity
+//	*dto - DHCP v6 lease dto
+func MapDHCP6LeaseToDto(entity domain.DHCP6Lease, dto *dtos.DHCP6LeaseDto) {
+	dto.IP = entity.IP
+	dto.DUID = entity.DUID
+	dto.MAC = entity.MAC
+	dto.Expires = entity.Expires
+	dto.UpdatedAt = entity.UpdatedAt
+	dto.CreatedAt = entity.CreatedAt
+	dto.ID = entity.ID
+}
+
+//MapDHCP6LeaseCreateDtoToEntity writes dhcp v6 lease create dto fields to lease entity
+//
+//Params:
+// 	dto - DHCP v6 server lease create dto
+//	entity - DHCP v6 lease entity
+func MapDHCP6LeaseCreateDtoToEntity(dto dtos.DHCP6LeaseCreateDto, entity *domain.DHCP6Lease) {
+	entity.IP = dto.IP
+	entity.DUID = dto.DUID
+	entity.MAC = dto.MAC
+	entity.Expires = dto.Expires
+}
+
+//MapDHCP6LeaseUpdateDtoToEntity writes dhcp v6 lease update dto fields to lease entity
+//
+//Params:
+// 	dto - DHCP v6 server lease update dto
+//	entity - DHCP v6 lease entity
+func MapDHCP6LeaseUpdateDtoToEntity(dto dtos.DHCP6LeaseUpdateDto, entity *domain.DHCP6Lease) {
+	entity.IP = dto.IP
+	entity.DUID = dto.DUID
+	entity.MAC = dto.MAC
+	entity.Expires = dto.Expires
+}
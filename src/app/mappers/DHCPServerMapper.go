@@ -17,6 +17,8 @@ func MapDHCP4ServerToDto(entity domain.DHCP4Config, dto *dtos.DHCP4ServerDto) {
 	dto.Interface = entity.Interface
 	dto.DNS = entity.DNS
 	dto.NTP = entity.NTP
+	dto.NextServer = entity.NextServer
+	dto.BootFileName = entity.BootFileName
 	dto.Range = entity.Range
 	dto.Mask = entity.Mask
 	dto.ServerID = entity.ServerID
@@ -35,6 +37,8 @@ func MapDHCP4ServerCreateDtoToEntity(dto dtos.DHCP4ServerCreateDto, entity *doma
 	entity.DNS = dto.DNS
 	entity.Interface = dto.Interface
 	entity.NTP = dto.NTP
+	entity.NextServer = dto.NextServer
+	entity.BootFileName = dto.BootFileName
 	entity.Range = dto.Range
 	entity.Mask = dto.Mask
 	entity.ServerID = dto.ServerID
@@ -52,6 +56,8 @@ func MapDHCP4ServerCreateDtoToEntity(dto dtos.DHCP4ServerCreateDto, entity *doma
 func MapDHCP4ServerUpdateDtoToEntity(dto dtos.DHCP4ServerUpdateDto, entity *domain.DHCP4Config) {
 	entity.DNS = dto.DNS
 	entity.NTP = dto.NTP
+	entity.NextServer = dto.NextServer
+	entity.BootFileName = dto.BootFileName
 	entity.Port = dto.Port
 	entity.Enabled = dto.Enabled
 	entity.LeaseTime = dto.LeaseTime
@@ -92,3 +98,87 @@ func MapDHCP4LeaseUpdateDtoToEntity(dto dtos.DHCP4LeaseUpdateDto, entity *domain
 	entity.MAC = dto.MAC
 	entity.Expires = dto.Expires
 }
+
+//MapDHCP4ReservationToDto writes dhcp v4 reservation fields to dto
+//
+//Params:
+//	entity - DHCP v4 reservation entity
+//	*dto - DHCP v4 reservation dto
+func MapDHCP4ReservationToDto(entity domain.DHCP4Reservation, dto *dtos.DHCP4ReservationDto) {
+	dto.ID = entity.ID
+	dto.CreatedAt = entity.CreatedAt
+	dto.UpdatedAt = entity.UpdatedAt
+	dto.IP = entity.IP
+	dto.MAC = entity.MAC
+	dto.Hostname = entity.Hostname
+	dto.BootFileName = entity.BootFileName
+}
+
+//MapDHCP4ReservationCreateDtoToEntity writes dhcp v4 reservation create dto fields to reservation entity
+//
+//Params:
+// 	dto - DHCP v4 server reservation create dto
+//	entity - DHCP v4 reservation entity
+func MapDHCP4ReservationCreateDtoToEntity(dto dtos.DHCP4ReservationCreateDto, entity *domain.DHCP4Reservation) {
+	entity.IP = dto.IP
+	entity.MAC = dto.MAC
+	entity.Hostname = dto.Hostname
+	entity.BootFileName = dto.BootFileName
+}
+
+//MapDHCP4ReservationUpdateDtoToEntity writes dhcp v4 reservation update dto fields to reservation entity
+//
+//Params:
+// 	dto - DHCP v4 server reservation update dto
+//	entity - DHCP v4 reservation entity
+func MapDHCP4ReservationUpdateDtoToEntity(dto dtos.DHCP4ReservationUpdateDto, entity *domain.DHCP4Reservation) {
+	entity.IP = dto.IP
+	entity.MAC = dto.MAC
+	entity.Hostname = dto.Hostname
+	entity.BootFileName = dto.BootFileName
+}
+
+//MapDHCP4BootRuleToDto writes dhcp v4 boot rule fields to dto
+//
+//Params:
+//	entity - DHCP v4 boot rule entity
+//	*dto - DHCP v4 boot rule dto
+func MapDHCP4BootRuleToDto(entity domain.DHCP4BootRule, dto *dtos.DHCP4BootRuleDto) {
+	dto.ID = entity.ID
+	dto.CreatedAt = entity.CreatedAt
+	dto.UpdatedAt = entity.UpdatedAt
+	dto.Priority = entity.Priority
+	dto.Architecture = entity.Architecture
+	dto.VendorClass = entity.VendorClass
+	dto.UserClass = entity.UserClass
+	dto.NextServer = entity.NextServer
+	dto.BootFileName = entity.BootFileName
+}
+
+//MapDHCP4BootRuleCreateDtoToEntity writes dhcp v4 boot rule create dto fields to boot rule entity
+//
+//Params:
+// 	dto - DHCP v4 server boot rule create dto
+//	entity - DHCP v4 boot rule entity
+func MapDHCP4BootRuleCreateDtoToEntity(dto dtos.DHCP4BootRuleCreateDto, entity *domain.DHCP4BootRule) {
+	entity.Priority = dto.Priority
+	entity.Architecture = dto.Architecture
+	entity.VendorClass = dto.VendorClass
+	entity.UserClass = dto.UserClass
+	entity.NextServer = dto.NextServer
+	entity.BootFileName = dto.BootFileName
+}
+
+//MapDHCP4BootRuleUpdateDtoToEntity writes dhcp v4 boot rule update dto fields to boot rule entity
+//
+//Params:
+// 	dto - DHCP v4 server boot rule update dto
+//	entity - DHCP v4 boot rule entity
+func MapDHCP4BootRuleUpdateDtoToEntity(dto dtos.DHCP4BootRuleUpdateDto, entity *domain.DHCP4BootRule) {
+	entity.Priority = dto.Priority
+	entity.Architecture = dto.Architecture
+	entity.VendorClass = dto.VendorClass
+	entity.UserClass = dto.UserClass
+	entity.NextServer = dto.NextServer
+	entity.BootFileName = dto.BootFileName
+}
@@ -0,0 +1,45 @@
+package mappers
+
+import (
+	"rol/domain"
+	"rol/dtos"
+)
+
+//MapDeviceBootToDto writes device boot entity to dto
+//Params
+//	entity - device boot entity
+//	dto - dest device boot dto
+func MapDeviceBootToDto(entity domain.DeviceBoot, dto *dtos.DeviceBootDto) {
+	dto.ID = entity.ID
+	dto.CreatedAt = entity.CreatedAt
+	dto.UpdatedAt = entity.UpdatedAt
+	dto.DeviceID = entity.DeviceID
+	dto.BootType = entity.BootType
+	dto.TFTPServerID = entity.TFTPConfigID
+	dto.StageTimeout = entity.StageTimeout
+	dto.StageIndex = entity.StageIndex
+	dto.StageName = entity.StageName
+	dto.State = entity.State.String()
+	dto.FinishedAt = entity.FinishedAt
+	dto.Error = entity.Error
+	if dto.Stages == nil {
+		dto.Stages = []dtos.DeviceBootStageDto{}
+	}
+}
+
+//MapDeviceBootStageToDto writes device boot stage entity to dto
+//Params
+//	entity - device boot stage entity
+//	dto - dest device boot stage dto
+func MapDeviceBootStageToDto(entity domain.DeviceBootStage, dto *dtos.DeviceBootStageDto) {
+	dto.ID = entity.ID
+	dto.CreatedAt = entity.CreatedAt
+	dto.UpdatedAt = entity.UpdatedAt
+	dto.Index = entity.Index
+	dto.Name = entity.Name
+	dto.Action = entity.Action
+	dto.State = entity.State.String()
+	dto.StartedAt = entity.StartedAt
+	dto.FinishedAt = entity.FinishedAt
+	dto.Message = entity.Message
+}
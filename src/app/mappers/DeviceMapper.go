@@ -0,0 +1,77 @@
+package mappers
+
+import (
+	"rol/domain"
+	"rol/dtos"
+)
+
+//MapDeviceCreateDtoToEntity writes device create dto fields to entity
+//Params
+//	dto - device create dto
+//	entity - dest device entity
+func MapDeviceCreateDtoToEntity(dto dtos.DeviceCreateDto, entity *domain.Device) {
+	entity.Name = dto.Name
+	entity.Serial = dto.Serial
+	entity.DeviceTemplate = dto.DeviceTemplate
+}
+
+//MapDeviceUpdateDtoToEntity writes device update dto fields to entity
+//Params
+//	dto - device update dto
+//	entity - dest device entity
+func MapDeviceUpdateDtoToEntity(dto dtos.DeviceUpdateDto, entity *domain.Device) {
+	entity.Name = dto.Name
+	entity.Serial = dto.Serial
+}
+
+//MapDeviceToDto writes device entity to dto
+//Params
+//	entity - device entity
+//	dto - dest device dto
+func MapDeviceToDto(entity domain.Device, dto *dtos.DeviceDto) {
+	dto.ID = entity.ID
+	dto.Name = entity.Name
+	dto.Serial = entity.Serial
+	dto.DeviceTemplate = entity.DeviceTemplate
+	dto.PowerState = entity.PowerState.String()
+	dto.CreatedAt = entity.CreatedAt
+	dto.UpdatedAt = entity.UpdatedAt
+	if dto.NetworkInterfaces == nil {
+		dto.NetworkInterfaces = []dtos.DeviceNetworkInterfaceDto{}
+	}
+}
+
+//MapDeviceNetworkInterfaceCreateDtoToEntity writes device network interface create dto fields to entity
+//Params
+//	dto - device network interface create dto
+//	entity - dest device network interface entity
+func MapDeviceNetworkInterfaceCreateDtoToEntity(dto dtos.DeviceNetworkInterfaceCreateDto, entity *domain.DeviceNetworkInterface) {
+	entity.Name = dto.Name
+	entity.MAC = dto.MAC
+	entity.EthernetSwitchID = dto.EthernetSwitchID
+	entity.EthernetSwitchPortID = dto.EthernetSwitchPortID
+}
+
+//MapDeviceNetworkInterfaceUpdateDtoToEntity writes device network interface update dto fields to entity
+//Params
+//	dto - device network interface update dto
+//	entity - dest device network interface entity
+func MapDeviceNetworkInterfaceUpdateDtoToEntity(dto dtos.DeviceNetworkInterfaceUpdateDto, entity *domain.DeviceNetworkInterface) {
+	entity.MAC = dto.MAC
+	entity.EthernetSwitchID = dto.EthernetSwitchID
+	entity.EthernetSwitchPortID = dto.EthernetSwitchPortID
+}
+
+//MapDeviceNetworkInterfaceToDto writes device network interface entity to dto
+//Params
+//	entity - device network interface entity
+//	dto - dest device network interface dto
+func MapDeviceNetworkInterfaceToDto(entity domain.DeviceNetworkInterface, dto *dtos.DeviceNetworkInterfaceDto) {
+	dto.ID = entity.ID
+	dto.Name = entity.Name
+	dto.MAC = entity.MAC
+	dto.EthernetSwitchID = entity.EthernetSwitchID
+	dto.EthernetSwitchPortID = entity.EthernetSwitchPortID
+	dto.CreatedAt = entity.CreatedAt
+	dto.UpdatedAt = entity.UpdatedAt
+}
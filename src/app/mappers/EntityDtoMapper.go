@@ -31,6 +31,16 @@ func MapDtoToEntity(dto interface{}, entity interface{}) error {
 		MapTFTPPathCreateDtoToEntity(dto.(dtos.TFTPPathCreateDto), entity.(*domain.TFTPPathRatio))
 	case dtos.TFTPPathUpdateDto:
 		MapTFTPPathUpdateDtoToEntity(dto.(dtos.TFTPPathUpdateDto), entity.(*domain.TFTPPathRatio))
+	//HTTPBootConfig
+	case dtos.HTTPBootServerCreateDto:
+		MapHTTPBootServerCreateDtoToEntity(dto.(dtos.HTTPBootServerCreateDto), entity.(*domain.HTTPBootConfig))
+	case dtos.HTTPBootServerUpdateDto:
+		MapHTTPBootServerUpdateDtoToEntity(dto.(dtos.HTTPBootServerUpdateDto), entity.(*domain.HTTPBootConfig))
+	//HTTPBootPathRatio
+	case dtos.HTTPBootPathCreateDto:
+		MapHTTPBootPathCreateDtoToEntity(dto.(dtos.HTTPBootPathCreateDto), entity.(*domain.HTTPBootPathRatio))
+	case dtos.HTTPBootPathUpdateDto:
+		MapHTTPBootPathUpdateDtoToEntity(dto.(dtos.HTTPBootPathUpdateDto), entity.(*domain.HTTPBootPathRatio))
 	// EthernetSwitch
 	case dtos.EthernetSwitchCreateDto:
 		MapEthernetSwitchCreateDto(dto.(dtos.EthernetSwitchCreateDto), entity.(*domain.EthernetSwitch))
@@ -65,6 +75,32 @@ func MapDtoToEntity(dto interface{}, entity interface{}) error {
 		MapDHCP4LeaseCreateDtoToEntity(dto.(dtos.DHCP4LeaseCreateDto), entity.(*domain.DHCP4Lease))
 	case dtos.DHCP4LeaseUpdateDto:
 		MapDHCP4LeaseUpdateDtoToEntity(dto.(dtos.DHCP4LeaseUpdateDto), entity.(*domain.DHCP4Lease))
+	case dtos.DHCP4ReservationCreateDto:
+		MapDHCP4ReservationCreateDtoToEntity(dto.(dtos.DHCP4ReservationCreateDto), entity.(*domain.DHCP4Reservation))
+	case dtos.DHCP4ReservationUpdateDto:
+		MapDHCP4ReservationUpdateDtoToEntity(dto.(dtos.DHCP4ReservationUpdateDto), entity.(*domain.DHCP4Reservation))
+	case dtos.DHCP4BootRuleCreateDto:
+		MapDHCP4BootRuleCreateDtoToEntity(dto.(dtos.DHCP4BootRuleCreateDto), entity.(*domain.DHCP4BootRule))
+	case dtos.DHCP4BootRuleUpdateDto:
+		MapDHCP4BootRuleUpdateDtoToEntity(dto.(dtos.DHCP4BootRuleUpdateDto), entity.(*domain.DHCP4BootRule))
+	case dtos.DHCP6ServerCreateDto:
+		MapDHCP6ServerCreateDtoToEntity(dto.(dtos.DHCP6ServerCreateDto), entity.(*domain.DHCP6Config))
+	case dtos.DHCP6ServerUpdateDto:
+		MapDHCP6ServerUpdateDtoToEntity(dto.(dtos.DHCP6ServerUpdateDto), entity.(*domain.DHCP6Config))
+	case dtos.DHCP6LeaseCreateDto:
+		MapDHCP6LeaseCreateDtoToEntity(dto.(dtos.DHCP6LeaseCreateDto), entity.(*domain.DHCP6Lease))
+	case dtos.DHCP6LeaseUpdateDto:
+		MapDHCP6LeaseUpdateDtoToEntity(dto.(dtos.DHCP6LeaseUpdateDto), entity.(*domain.DHCP6Lease))
+	//Device
+	case dtos.DeviceCreateDto:
+		MapDeviceCreateDtoToEntity(dto.(dtos.DeviceCreateDto), entity.(*domain.Device))
+	case dtos.DeviceUpdateDto:
+		MapDeviceUpdateDtoToEntity(dto.(dtos.DeviceUpdateDto), entity.(*domain.Device))
+	//DeviceNetworkInterface
+	case dtos.DeviceNetworkInterfaceCreateDto:
+		MapDeviceNetworkInterfaceCreateDtoToEntity(dto.(dtos.DeviceNetworkInterfaceCreateDto), entity.(*domain.DeviceNetworkInterface))
+	case dtos.DeviceNetworkInterfaceUpdateDto:
+		MapDeviceNetworkInterfaceUpdateDtoToEntity(dto.(dtos.DeviceNetworkInterfaceUpdateDto), entity.(*domain.DeviceNetworkInterface))
 	default:
 		return errors.Internal.Newf("can't find route for map dto %+v to entity %+v", dto, entity)
 	}
@@ -85,6 +121,12 @@ func MapEntityToDto(entity interface{}, dto interface{}) error {
 	//TFTPPathRatio
 	case domain.TFTPPathRatio:
 		MapTFTPPathRatioToDto(entity.(domain.TFTPPathRatio), dto.(*dtos.TFTPPathDto))
+	//HTTPBootConfig
+	case domain.HTTPBootConfig:
+		MapHTTPBootConfigToDto(entity.(domain.HTTPBootConfig), dto.(*dtos.HTTPBootServerDto))
+	//HTTPBootPathRatio
+	case domain.HTTPBootPathRatio:
+		MapHTTPBootPathRatioToDto(entity.(domain.HTTPBootPathRatio), dto.(*dtos.HTTPBootPathDto))
 	// EthernetSwitch
 	case domain.EthernetSwitch:
 		MapEthernetSwitchToDto(entity.(domain.EthernetSwitch), dto.(*dtos.EthernetSwitchDto))
@@ -109,12 +151,39 @@ func MapEntityToDto(entity interface{}, dto interface{}) error {
 	//EthernetSwitchVLAN
 	case domain.EthernetSwitchVLAN:
 		MapEthernetSwitchVLANToDto(entity.(domain.EthernetSwitchVLAN), dto.(*dtos.EthernetSwitchVLANDto))
+	//EthernetSwitchConfigBackup
+	case domain.EthernetSwitchConfigBackup:
+		MapEthernetSwitchConfigBackupToDto(entity.(domain.EthernetSwitchConfigBackup), dto.(*dtos.EthernetSwitchConfigBackupDto))
 	//DHCP4Server
 	case domain.DHCP4Config:
 		MapDHCP4ServerToDto(entity.(domain.DHCP4Config), dto.(*dtos.DHCP4ServerDto))
 	//DHCP4Lease
 	case domain.DHCP4Lease:
 		MapDHCP4LeaseToDto(entity.(domain.DHCP4Lease), dto.(*dtos.DHCP4LeaseDto))
+	//DHCP4Reservation
+	case domain.DHCP4Reservation:
+		MapDHCP4ReservationToDto(entity.(domain.DHCP4Reservation), dto.(*dtos.DHCP4ReservationDto))
+	//DHCP4BootRule
+	case domain.DHCP4BootRule:
+		MapDHCP4BootRuleToDto(entity.(domain.DHCP4BootRule), dto.(*dtos.DHCP4BootRuleDto))
+	//DHCP6Server
+	case domain.DHCP6Config:
+		MapDHCP6ServerToDto(entity.(domain.DHCP6Config), dto.(*dtos.DHCP6ServerDto))
+	//DHCP6Lease
+	case domain.DHCP6Lease:
+		MapDHCP6LeaseToDto(entity.(domain.DHCP6Lease), dto.(*dtos.DHCP6LeaseDto))
+	//Device
+	case domain.Device:
+		MapDeviceToDto(entity.(domain.Device), dto.(*dtos.DeviceDto))
+	//DeviceNetworkInterface
+	case domain.DeviceNetworkInterface:
+		MapDeviceNetworkInterfaceToDto(entity.(domain.DeviceNetworkInterface), dto.(*dtos.DeviceNetworkInterfaceDto))
+	//DeviceBoot
+	case domain.DeviceBoot:
+		MapDeviceBootToDto(entity.(domain.DeviceBoot), dto.(*dtos.DeviceBootDto))
+	//DeviceBootStage
+	case domain.DeviceBootStage:
+		MapDeviceBootStageToDto(entity.(domain.DeviceBootStage), dto.(*dtos.DeviceBootStageDto))
 
 	default:
 		return errors.Internal.Newf("can't find route for map entity %+v to dto %+v", dto, entity)
@@ -0,0 +1,20 @@
+package mappers
+
+import (
+	"rol/domain"
+	"rol/dtos"
+)
+
+//MapEthernetSwitchConfigBackupToDto writes ethernet switch config backup entity to dto
+//Params
+//	entity - ethernet switch config backup entity
+//	dto - dest ethernet switch config backup dto
+func MapEthernetSwitchConfigBackupToDto(entity domain.EthernetSwitchConfigBackup, dto *dtos.EthernetSwitchConfigBackupDto) {
+	dto.ID = entity.ID
+	dto.CreatedAt = entity.CreatedAt
+	dto.UpdatedAt = entity.UpdatedAt
+	dto.EthernetSwitchID = entity.EthernetSwitchID
+	dto.Version = entity.Version
+	dto.Reason = entity.Reason
+	dto.Config = entity.Config
+}
@@ -5,6 +5,14 @@ import (
 	"rol/dtos"
 )
 
+//ethernetSwitchTransportOrDefault returns telnet transport if transport is not set
+func ethernetSwitchTransportOrDefault(transport string) string {
+	if transport == "" {
+		return domain.EthernetSwitchTransportTelnet
+	}
+	return transport
+}
+
 //MapEthernetSwitchUpdateDto writes ethernet switch update dto fields to entity
 //Params
 //	dto - ethernet switch update dto
@@ -17,6 +25,18 @@ func MapEthernetSwitchUpdateDto(dto dtos.EthernetSwitchUpdateDto, entity *domain
 	entity.Password = dto.Password
 	entity.Username = dto.Username
 	entity.Serial = dto.Serial
+	entity.Transport = ethernetSwitchTransportOrDefault(dto.Transport)
+	entity.SSHHostKeyFingerprint = dto.SSHHostKeyFingerprint
+	entity.SNMPVersion = dto.SNMPVersion
+	entity.SNMPPort = dto.SNMPPort
+	entity.SNMPCommunity = dto.SNMPCommunity
+	entity.SNMPUsername = dto.SNMPUsername
+	entity.SNMPAuthProtocol = dto.SNMPAuthProtocol
+	//  pragma: allowlist nextline secret
+	entity.SNMPAuthPassword = dto.SNMPAuthPassword
+	entity.SNMPPrivProtocol = dto.SNMPPrivProtocol
+	//  pragma: allowlist nextline secret
+	entity.SNMPPrivPassword = dto.SNMPPrivPassword
 }
 
 //MapEthernetSwitchCreateDto writes ethernet switch create dto fields to entity
@@ -31,6 +51,18 @@ func MapEthernetSwitchCreateDto(dto dtos.EthernetSwitchCreateDto, entity *domain
 	entity.Password = dto.Password
 	entity.Username = dto.Username
 	entity.Serial = dto.Serial
+	entity.Transport = ethernetSwitchTransportOrDefault(dto.Transport)
+	entity.SSHHostKeyFingerprint = dto.SSHHostKeyFingerprint
+	entity.SNMPVersion = dto.SNMPVersion
+	entity.SNMPPort = dto.SNMPPort
+	entity.SNMPCommunity = dto.SNMPCommunity
+	entity.SNMPUsername = dto.SNMPUsername
+	entity.SNMPAuthProtocol = dto.SNMPAuthProtocol
+	//  pragma: allowlist nextline secret
+	entity.SNMPAuthPassword = dto.SNMPAuthPassword
+	entity.SNMPPrivProtocol = dto.SNMPPrivProtocol
+	//  pragma: allowlist nextline secret
+	entity.SNMPPrivPassword = dto.SNMPPrivPassword
 }
 
 //MapEthernetSwitchToDto writes ethernet switch entity fields to dto
@@ -44,6 +76,13 @@ func MapEthernetSwitchToDto(entity domain.EthernetSwitch, dto *dtos.EthernetSwit
 	dto.Username = entity.Username
 	dto.SwitchModel = entity.SwitchModel
 	dto.Serial = entity.Serial
+	dto.Transport = ethernetSwitchTransportOrDefault(entity.Transport)
+	dto.SSHHostKeyFingerprint = entity.SSHHostKeyFingerprint
+	dto.SNMPVersion = entity.SNMPVersion
+	dto.SNMPPort = entity.SNMPPort
+	dto.SNMPUsername = entity.SNMPUsername
+	dto.SNMPAuthProtocol = entity.SNMPAuthProtocol
+	dto.SNMPPrivProtocol = entity.SNMPPrivProtocol
 	dto.CreatedAt = entity.CreatedAt
 	dto.UpdatedAt = entity.UpdatedAt
 }
@@ -56,4 +95,51 @@ func MapEthernetSwitchModelToDto(entity domain.EthernetSwitchModel, dto *dtos.Et
 	dto.Code = entity.Code
 	dto.Manufacturer = entity.Manufacturer
 	dto.Model = entity.Model
+	dto.Capabilities = append([]string{}, entity.Capabilities...)
+}
+
+//MapEthernetSwitchCapabilitiesToDto writes ethernet switch capabilities fields to dto
+//Params
+//	entity - ethernet switch capabilities
+//	dto - dest ethernet switch capabilities dto
+func MapEthernetSwitchCapabilitiesToDto(entity domain.EthernetSwitchCapabilities, dto *dtos.EthernetSwitchCapabilitiesDto) {
+	dto.Ports = append([]string{}, entity.Ports...)
+	dto.MaxVLANs = entity.MaxVLANs
+	dto.POEPorts = append([]string{}, entity.POEPorts...)
+	dto.POETypes = append([]string{}, entity.POETypes...)
+	dto.POEBudget = entity.POEBudget
+}
+
+//MapEthernetSwitchDriftToDto writes ethernet switch drift fields to dto
+//Params
+//	entity - ethernet switch drift
+//	dto - dest ethernet switch drift dto
+func MapEthernetSwitchDriftToDto(entity domain.EthernetSwitchDrift, dto *dtos.EthernetSwitchDriftDto) {
+	dto.SwitchID = entity.SwitchID
+	dto.CheckedAt = entity.CheckedAt
+	dto.InSync = entity.InSync()
+	dto.Error = entity.Error
+	dto.VLANs = []dtos.EthernetSwitchVLANDriftDto{}
+	for _, vlan := range entity.VLANs {
+		dto.VLANs = append(dto.VLANs, dtos.EthernetSwitchVLANDriftDto{
+			VlanID:                vlan.VlanID,
+			State:                 vlan.State,
+			DatabaseTaggedPorts:   append([]string{}, vlan.DatabaseTaggedPorts...),
+			DatabaseUntaggedPorts: append([]string{}, vlan.DatabaseUntaggedPorts...),
+			SwitchTaggedPorts:     append([]string{}, vlan.SwitchTaggedPorts...),
+			SwitchUntaggedPorts:   append([]string{}, vlan.SwitchUntaggedPorts...),
+		})
+	}
+	dto.Ports = []dtos.EthernetSwitchPortDriftDto{}
+	for _, port := range entity.Ports {
+		dto.Ports = append(dto.Ports, dtos.EthernetSwitchPortDriftDto{
+			PortID:             port.PortID,
+			PortName:           port.PortName,
+			State:              port.State,
+			DatabasePOEEnabled: port.DatabasePOEEnabled,
+			SwitchPOEEnabled:   port.SwitchPOEEnabled,
+			DatabasePVID:       port.DatabasePVID,
+			SwitchPVID:         port.SwitchPVID,
+		})
+	}
 }
@@ -40,3 +40,24 @@ func MapEthernetSwitchPortToDto(entity domain.EthernetSwitchPort, dto *dtos.Ethe
 	dto.CreatedAt = entity.CreatedAt
 	dto.UpdatedAt = entity.UpdatedAt
 }
+
+//MapEthernetSwitchMACAddressToDto writes ethernet switch MAC address table entry fields to dto
+//Params
+//	entity - MAC address table entry
+//	dto - dest ethernet switch MAC address dto
+func MapEthernetSwitchMACAddressToDto(entity domain.EthernetSwitchMACAddress, dto *dtos.EthernetSwitchMACAddressDto) {
+	dto.MAC = entity.MAC
+	dto.VLANID = entity.VLANID
+}
+
+//MapEthernetSwitchLLDPNeighborToDto writes ethernet switch LLDP neighbor fields to dto
+//Params
+//	entity - LLDP neighbor
+//	dto - dest ethernet switch LLDP neighbor dto
+func MapEthernetSwitchLLDPNeighborToDto(entity domain.EthernetSwitchLLDPNeighbor, dto *dtos.EthernetSwitchLLDPNeighborDto) {
+	dto.ChassisID = entity.ChassisID
+	dto.PortID = entity.PortID
+	dto.PortDescription = entity.PortDescription
+	dto.SystemName = entity.SystemName
+	dto.ManagementAddress = entity.ManagementAddress
+}
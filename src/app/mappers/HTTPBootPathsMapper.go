@@ -0,0 +1,39 @@
+// Package mappers uses for entity <--> dto conversions
+package mappers
+
+import (
+	"github.com/google/uuid"
+	"rol/domain"
+	"rol/dtos"
+)
+
+//MapHTTPBootPathRatioToDto writes HTTP boot path ratio entity to dto
+//Params
+//	entity - HTTP boot path entity
+//	dto - dest HTTP boot path dto
+func MapHTTPBootPathRatioToDto(entity domain.HTTPBootPathRatio, dto *dtos.HTTPBootPathDto) {
+	mapEntityToBaseDto[uuid.UUID](entity, &dto.BaseDto)
+	dto.ActualPath = entity.ActualPath
+	dto.VirtualPath = entity.VirtualPath
+	dto.IsDirectory = entity.IsDirectory
+}
+
+//MapHTTPBootPathCreateDtoToEntity writes HTTP boot path create dto fields to entity
+//Params
+//	dto - HTTP boot path create dto
+//	entity - dest HTTP boot path entity
+func MapHTTPBootPathCreateDtoToEntity(dto dtos.HTTPBootPathCreateDto, entity *domain.HTTPBootPathRatio) {
+	entity.ActualPath = dto.ActualPath
+	entity.VirtualPath = dto.VirtualPath
+	entity.IsDirectory = dto.IsDirectory
+}
+
+//MapHTTPBootPathUpdateDtoToEntity writes HTTP boot path update dto fields to entity
+//Params
+//	dto - HTTP boot path update dto
+//	entity - dest HTTP boot path entity
+func MapHTTPBootPathUpdateDtoToEntity(dto dtos.HTTPBootPathUpdateDto, entity *domain.HTTPBootPathRatio) {
+	entity.ActualPath = dto.ActualPath
+	entity.VirtualPath = dto.VirtualPath
+	entity.IsDirectory = dto.IsDirectory
+}
@@ -0,0 +1,55 @@
+// Package mappers uses for entity <--> dto conversions
+package mappers
+
+import (
+	"github.com/google/uuid"
+	"rol/domain"
+	"rol/dtos"
+)
+
+//MapHTTPBootConfigToDto writes HTTP boot config entity to dto
+//
+//Params
+//	entity - HTTP boot config entity
+//	dto - dest HTTP boot server dto
+func MapHTTPBootConfigToDto(entity domain.HTTPBootConfig, dto *dtos.HTTPBootServerDto) {
+	mapEntityToBaseDto[uuid.UUID](entity, &dto.BaseDto)
+	dto.Address = entity.Address
+	dto.Port = entity.Port
+	dto.Enabled = entity.Enabled
+}
+
+//MapHTTPBootServerCreateDtoToEntity writes HTTP boot server create dto fields to entity
+//
+//Params
+//	dto - HTTP boot server create dto
+//	entity - dest HTTP boot config entity
+func MapHTTPBootServerCreateDtoToEntity(dto dtos.HTTPBootServerCreateDto, entity *domain.HTTPBootConfig) {
+	entity.Address = dto.Address
+	entity.Port = dto.Port
+	entity.Enabled = dto.Enabled
+}
+
+//MapHTTPBootServerUpdateDtoToEntity writes HTTP boot server update dto fields to entity
+//
+//Params
+//	dto - HTTP boot server update dto
+//	entity - dest HTTP boot config entity
+func MapHTTPBootServerUpdateDtoToEntity(dto dtos.HTTPBootServerUpdateDto, entity *domain.HTTPBootConfig) {
+	entity.Address = dto.Address
+	entity.Port = dto.Port
+	entity.Enabled = dto.Enabled
+}
+
+//MapHTTPBootFileStatsToDto writes HTTP boot file stats to dto
+//
+//Params
+//	stats - HTTP boot file stats
+//	dto - dest HTTP boot file stats dto
+func MapHTTPBootFileStatsToDto(stats domain.HTTPBootFileStats, dto *dtos.HTTPBootFileStatsDto) {
+	dto.VirtualPath = stats.VirtualPath
+	dto.Requests = stats.Requests
+	dto.Downloads = stats.Downloads
+	dto.BytesSent = stats.BytesSent
+	dto.LastRequestAt = stats.LastRequestAt
+}
@@ -15,6 +15,10 @@ func MapTFTPPathRatioToDto(entity domain.TFTPPathRatio, dto *dtos.TFTPPathDto) {
 	mapEntityToBaseDto[uuid.UUID](entity, &dto.BaseDto)
 	dto.ActualPath = entity.ActualPath
 	dto.VirtualPath = entity.VirtualPath
+	dto.IsDirectory = entity.IsDirectory
+	dto.ClientIP = entity.ClientIP
+	dto.ClientMAC = entity.ClientMAC
+	dto.IsTemplate = entity.IsTemplate
 }
 
 //MapTFTPPathCreateDtoToEntity writes TFTP path create dto fields to entity
@@ -24,6 +28,10 @@ func MapTFTPPathRatioToDto(entity domain.TFTPPathRatio, dto *dtos.TFTPPathDto) {
 func MapTFTPPathCreateDtoToEntity(dto dtos.TFTPPathCreateDto, entity *domain.TFTPPathRatio) {
 	entity.ActualPath = dto.ActualPath
 	entity.VirtualPath = dto.VirtualPath
+	entity.IsDirectory = dto.IsDirectory
+	entity.ClientIP = dto.ClientIP
+	entity.ClientMAC = dto.ClientMAC
+	entity.IsTemplate = dto.IsTemplate
 }
 
 //MapTFTPPathUpdateDtoToEntity writes TFTP path update dto fields to entity
@@ -33,4 +41,8 @@ func MapTFTPPathCreateDtoToEntity(dto dtos.TFTPPathCreateDto, entity *domain.TFT
 func MapTFTPPathUpdateDtoToEntity(dto dtos.TFTPPathUpdateDto, entity *domain.TFTPPathRatio) {
 	entity.ActualPath = dto.ActualPath
 	entity.VirtualPath = dto.VirtualPath
+	entity.IsDirectory = dto.IsDirectory
+	entity.ClientIP = dto.ClientIP
+	entity.ClientMAC = dto.ClientMAC
+	entity.IsTemplate = dto.IsTemplate
 }
@@ -18,6 +18,9 @@ func MapTFTPConfigToDto(entity domain.TFTPConfig, dto *dtos.TFTPServerDto) {
 	dto.UpdatedAt = entity.UpdatedAt
 	dto.CreatedAt = entity.CreatedAt
 	dto.Enabled = entity.Enabled
+	dto.WriteEnabled = entity.WriteEnabled
+	dto.UploadDir = entity.UploadDir
+	dto.MaxUploadSize = entity.MaxUploadSize
 }
 
 //MapTFTPServerCreateDtoToEntity writes TFTP config create dto fields to entity
@@ -29,6 +32,9 @@ func MapTFTPServerCreateDtoToEntity(dto dtos.TFTPServerCreateDto, entity *domain
 	entity.Port = dto.Port
 	entity.Address = dto.Address
 	entity.Enabled = dto.Enabled
+	entity.WriteEnabled = dto.WriteEnabled
+	entity.UploadDir = dto.UploadDir
+	entity.MaxUploadSize = dto.MaxUploadSize
 }
 
 //MapTFTPServerUpdateDtoToEntity writes TFTP config update dto fields to entity
@@ -40,4 +46,37 @@ func MapTFTPServerUpdateDtoToEntity(dto dtos.TFTPServerUpdateDto, entity *domain
 	entity.Port = dto.Port
 	entity.Address = dto.Address
 	entity.Enabled = dto.Enabled
+	entity.WriteEnabled = dto.WriteEnabled
+	entity.UploadDir = dto.UploadDir
+	entity.MaxUploadSize = dto.MaxUploadSize
+}
+
+//MapTFTPTransferToDto writes TFTP transfer to dto
+//
+//Params
+//	transfer - TFTP transfer
+//	dto - dest TFTP transfer dto
+func MapTFTPTransferToDto(transfer domain.TFTPTransfer, dto *dtos.TFTPTransferDto) {
+	dto.ClientAddress = transfer.ClientAddress
+	dto.IsUpload = transfer.IsUpload
+	dto.VirtualPath = transfer.VirtualPath
+	dto.ActualPath = transfer.ActualPath
+	dto.Bytes = transfer.Bytes
+	dto.StartedAt = transfer.StartedAt
+	dto.DurationMs = transfer.Duration.Milliseconds()
+	dto.State = transfer.State.String()
+	dto.Error = transfer.Error
+}
+
+//MapTFTPPathStatsToDto writes TFTP path stats to dto
+//
+//Params
+//	stats - TFTP path stats
+//	dto - dest TFTP path stats dto
+func MapTFTPPathStatsToDto(stats domain.TFTPPathStats, dto *dtos.TFTPPathStatsDto) {
+	dto.VirtualPath = stats.VirtualPath
+	dto.Succeeded = stats.Succeeded
+	dto.Failed = stats.Failed
+	dto.Bytes = stats.Bytes
+	dto.LastTransferAt = stats.LastTransferAt
 }
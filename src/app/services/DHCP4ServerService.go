@@ -3,19 +3,33 @@ package services
 import (
 	"context"
 	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"reflect"
 	"rol/app/errors"
 	"rol/app/interfaces"
 	"rol/app/validators"
 	"rol/domain"
 	"rol/dtos"
+	"sync"
+	"time"
 )
 
+//dhcp4LeasesReaperInterval interval between expired leases cleanups
+const dhcp4LeasesReaperInterval = time.Minute
+
 //DHCP4ServerService service structure for managing DHCP servers
 type DHCP4ServerService struct {
 	configsRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Config]
-	leasesRepo  interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease]
-	factory     interfaces.IDHCP4ServerFactory
-	servers     map[uuid.UUID]interfaces.IDHCP4Server
+	leasesRepo       interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease]
+	reservationsRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Reservation]
+	bootRulesRepo    interfaces.IGenericRepository[uuid.UUID, domain.DHCP4BootRule]
+	factory          interfaces.IDHCP4ServerFactory
+	servers          map[uuid.UUID]interfaces.IDHCP4Server
+	//serversMutex guards servers map, that is shared with the leases reaper
+	serversMutex sync.RWMutex
+	logger       *logrus.Logger
+	//logSourceName - logger recording source
+	logSourceName string
 }
 
 //NewDHCP4ServerService constructor for DHCPServerService service
@@ -23,23 +37,77 @@ type DHCP4ServerService struct {
 //Params:
 //	servers - repository with domain.DHCPServer entity
 //	leases - repository with domain.DHCPLease entity
+//	reservations - repository with domain.DHCP4Reservation entity
+//	bootRules - repository with domain.DHCP4BootRule entity
+//	dhcp4factory - dhcp v4 servers factory
+//	logger - logrus logger
 //Return:
 //	*DHCPServerService - New DHCP servers service
 func NewDHCP4ServerService(
 	configs interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Config],
 	leases interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease],
+	reservations interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Reservation],
+	bootRules interfaces.IGenericRepository[uuid.UUID, domain.DHCP4BootRule],
 	dhcp4factory interfaces.IDHCP4ServerFactory,
+	logger *logrus.Logger,
 ) *DHCP4ServerService {
 	return &DHCP4ServerService{
-		configsRepo: configs,
-		leasesRepo:  leases,
-		servers:     map[uuid.UUID]interfaces.IDHCP4Server{},
-		factory:     dhcp4factory,
+		configsRepo:      configs,
+		leasesRepo:       leases,
+		reservationsRepo: reservations,
+		bootRulesRepo:    bootRules,
+		servers:          map[uuid.UUID]interfaces.IDHCP4Server{},
+		factory:          dhcp4factory,
+		logger:           logger,
+		logSourceName:    reflect.TypeOf(DHCP4ServerService{}).Name(),
+	}
+}
+
+func (s *DHCP4ServerService) log(ctx context.Context, level, message string) {
+	if ctx != nil {
+		actionID := uuid.UUID{}
+		if ctx.Value("requestID") != nil {
+			actionID = ctx.Value("requestID").(uuid.UUID)
+		}
+
+		entry := s.logger.WithFields(logrus.Fields{
+			"actionID": actionID,
+			"source":   s.logSourceName,
+		})
+		switch level {
+		case "err", "error":
+			entry.Error(message)
+		case "info":
+			entry.Info(message)
+		case "warn", "warning":
+			entry.Warn(message)
+		case "debug":
+			entry.Debug(message)
+		}
 	}
 }
 
+func (s *DHCP4ServerService) getServer(id uuid.UUID) (interfaces.IDHCP4Server, bool) {
+	s.serversMutex.RLock()
+	defer s.serversMutex.RUnlock()
+	server, ok := s.servers[id]
+	return server, ok
+}
+
+func (s *DHCP4ServerService) setServer(id uuid.UUID, server interfaces.IDHCP4Server) {
+	s.serversMutex.Lock()
+	defer s.serversMutex.Unlock()
+	s.servers[id] = server
+}
+
+func (s *DHCP4ServerService) removeServer(id uuid.UUID) {
+	s.serversMutex.Lock()
+	defer s.serversMutex.Unlock()
+	delete(s.servers, id)
+}
+
 func (s *DHCP4ServerService) getServerState(configID uuid.UUID, enabled bool) domain.DHCPServerState {
-	if server, ok := s.servers[configID]; ok {
+	if server, ok := s.getServer(configID); ok {
 		return server.GetState()
 	} else if enabled {
 		return domain.DHCPStateError
@@ -111,12 +179,13 @@ func DHCP4ServerServiceInit(s *DHCP4ServerService) error {
 		if err != nil {
 			return errors.Internal.Wrapf(err, "failed to create dhcp v4 server with id: %s", config.ID.String())
 		}
-		s.servers[config.ID] = server
+		s.setServer(config.ID, server)
 		err = server.Start()
 		if err != nil {
 			return errors.Internal.Wrapf(err, "failed to start dhcp v4 server with id: %s", config.ID.String())
 		}
 	}
+	go s.leasesReaper()
 	return nil
 }
 
@@ -166,7 +235,7 @@ func (s *DHCP4ServerService) CreateServer(ctx context.Context, createDto dtos.DH
 	if err != nil {
 		return dto, errors.Wrap(err, "failed to create dhcp v4 server")
 	}
-	s.servers[config.ID] = server
+	s.setServer(config.ID, server)
 
 	// Start runtime server and set state to dto
 	dto.State = domain.DHCPStateStopped.String()
@@ -208,14 +277,14 @@ func (s *DHCP4ServerService) UpdateServer(ctx context.Context, id uuid.UUID, upd
 	}
 
 	// Reload configuration in runtime server
-	var server interfaces.IDHCP4Server
-	if server, ok := s.servers[config.ID]; !ok {
+	server, ok := s.getServer(config.ID)
+	if !ok {
 		//create new runtime server
 		server, err = s.factory.Create(config)
 		if err != nil {
 			return dto, errors.Wrap(err, "failed to create dhcp v4 server")
 		}
-		s.servers[config.ID] = server
+		s.setServer(config.ID, server)
 	} else {
 		//Update runtime configuration on existed DHCP v4 server
 		server.Stop()
@@ -246,9 +315,9 @@ func (s *DHCP4ServerService) UpdateServer(ctx context.Context, id uuid.UUID, upd
 //	error - if an error occurs, otherwise nil
 func (s *DHCP4ServerService) DeleteServer(ctx context.Context, id uuid.UUID) error {
 	// Stop and delete runtime server
-	if server, ok := s.servers[id]; ok {
+	if server, ok := s.getServer(id); ok {
 		server.Stop()
-		delete(s.servers, id)
+		s.removeServer(id)
 	}
 
 	//Delete all leases
@@ -269,6 +338,18 @@ func (s *DHCP4ServerService) DeleteServer(ctx context.Context, id uuid.UUID) err
 		}
 	}
 
+	//Delete all reservations
+	err = s.reservationsRepo.DeleteAll(ctx, s.reservationsByServerQuery(ctx, id))
+	if err != nil {
+		return errors.Wrap(err, "failed to remove reservations")
+	}
+
+	//Delete all boot rules
+	err = s.bootRulesRepo.DeleteAll(ctx, s.bootRulesByServerQuery(ctx, id))
+	if err != nil {
+		return errors.Wrap(err, "failed to remove boot rules")
+	}
+
 	// Delete config
 	err = s.configsRepo.Delete(ctx, id)
 	if err != nil {
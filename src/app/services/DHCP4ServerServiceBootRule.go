@@ -0,0 +1,158 @@
+package services
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/mappers"
+	"rol/app/validators"
+	"rol/domain"
+	"rol/dtos"
+)
+
+func (s *DHCP4ServerService) bootRulesByServerQuery(ctx context.Context, serverID uuid.UUID) interfaces.IQueryBuilder {
+	queryBuilder := s.bootRulesRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DHCP4ConfigID", "==", serverID)
+	return queryBuilder
+}
+
+//GetBootRuleList Get list of DHCP v4 server boot rules with search and pagination
+//
+//Params:
+//	ctx - context is used only for logging
+//	serverID - DHCP v4 server ID
+//	search - string for search in entity string fields
+//	orderBy - order by entity field name
+//	orderDirection - ascending or descending order
+//	page - page number
+//	pageSize - page size
+//Return
+//	dtos.PaginatedItemsDto[dtos.DHCP4BootRuleDto] - paginated list of DHCP v4 boot rules
+//	error - if an error occurs, otherwise nil
+func (s *DHCP4ServerService) GetBootRuleList(ctx context.Context, serverID uuid.UUID, search, orderBy, orderDirection string, page, pageSize int) (
+	dtos.PaginatedItemsDto[dtos.DHCP4BootRuleDto],
+	error,
+) {
+	paginatedItemsDto := dtos.NewEmptyPaginatedItemsDto[dtos.DHCP4BootRuleDto]()
+	err := s.serverExistenceCheck(ctx, serverID)
+	if err != nil {
+		return paginatedItemsDto, err
+	}
+	queryBuilder := s.bootRulesByServerQuery(ctx, serverID)
+	if len(search) > 3 {
+		AddSearchInAllFields(search, s.bootRulesRepo, queryBuilder)
+	}
+	return GetListExtended[dtos.DHCP4BootRuleDto](ctx, s.bootRulesRepo, queryBuilder, orderBy, orderDirection, page, pageSize)
+}
+
+//GetBootRuleByID Get DHCP v4 server boot rule by ID
+//Params
+//	ctx - context is used only for logging
+//	serverID - DHCP v4 server ID
+//	ruleID - DHCP v4 boot rule ID
+//Return
+//	dtos.DHCP4BootRuleDto - DHCP v4 boot rule dto
+//	error - if an error occurs, otherwise nil
+func (s *DHCP4ServerService) GetBootRuleByID(ctx context.Context, serverID, ruleID uuid.UUID) (
+	dtos.DHCP4BootRuleDto,
+	error,
+) {
+	dto := dtos.DHCP4BootRuleDto{}
+	err := s.serverExistenceCheck(ctx, serverID)
+	if err != nil {
+		return dto, err
+	}
+	return GetByID[dtos.DHCP4BootRuleDto](ctx, s.bootRulesRepo, ruleID, s.bootRulesByServerQuery(ctx, serverID))
+}
+
+//CreateBootRule create DHCP v4 server boot rule
+//Params
+//	ctx - context is used only for logging
+//	serverID - DHCP v4 server ID
+//	createDto - dto for creating DHCP v4 boot rule
+//Return
+//	dtos.DHCP4BootRuleDto - DHCP v4 boot rule dto
+//	error - if an error occurs, otherwise nil
+func (s *DHCP4ServerService) CreateBootRule(ctx context.Context, serverID uuid.UUID, createDto dtos.DHCP4BootRuleCreateDto) (
+	dtos.DHCP4BootRuleDto,
+	error,
+) {
+	outDto := dtos.DHCP4BootRuleDto{}
+	err := validators.ValidateDHCP4BootRuleCreateDto(createDto)
+	if err != nil {
+		return outDto, err
+	}
+	err = s.serverExistenceCheck(ctx, serverID)
+	if err != nil {
+		return outDto, err
+	}
+
+	entity := new(domain.DHCP4BootRule)
+	err = mappers.MapDtoToEntity(createDto, entity)
+	if err != nil {
+		return outDto, errors.Internal.Wrap(err, "error map entity to dto")
+	}
+	entity.DHCP4ConfigID = serverID
+	newEntity, err := s.bootRulesRepo.Insert(ctx, *entity)
+	if err != nil {
+		return outDto, errors.Internal.Wrap(err, "create entity error")
+	}
+
+	err = mappers.MapEntityToDto(newEntity, &outDto)
+	if err != nil {
+		return outDto, errors.Internal.Wrap(err, "error map dto to entity")
+	}
+	return outDto, nil
+}
+
+//UpdateBootRule update DHCP v4 server boot rule
+//Params
+//	ctx - context is used only for logging
+//	serverID - DHCP v4 server ID
+//	ruleID - DHCP v4 boot rule ID
+//	updateDto - dto for updating DHCP v4 boot rule
+//Return
+//	dtos.DHCP4BootRuleDto - DHCP v4 boot rule dto
+//	error - if an error occurs, otherwise nil
+func (s *DHCP4ServerService) UpdateBootRule(ctx context.Context, serverID, ruleID uuid.UUID, updateDto dtos.DHCP4BootRuleUpdateDto) (
+	dtos.DHCP4BootRuleDto,
+	error,
+) {
+	dto := dtos.DHCP4BootRuleDto{}
+	err := validators.ValidateDHCP4BootRuleUpdateDto(updateDto)
+	if err != nil {
+		return dto, err
+	}
+	err = s.serverExistenceCheck(ctx, serverID)
+	if err != nil {
+		return dto, err
+	}
+	return Update[dtos.DHCP4BootRuleDto](ctx, s.bootRulesRepo, updateDto, ruleID, s.bootRulesByServerQuery(ctx, serverID))
+}
+
+//DeleteBootRule delete DHCP v4 server boot rule
+//Params
+//	ctx - context is used only for logging
+//	serverID - ID for DHCP v4 server
+//	ruleID - ID for DHCP v4 boot rule
+//Return
+//	error - if an error occurs, otherwise nil
+func (s *DHCP4ServerService) DeleteBootRule(ctx context.Context, serverID, ruleID uuid.UUID) error {
+	err := s.serverExistenceCheck(ctx, serverID)
+	if err != nil {
+		return err
+	}
+	exist, err := s.bootRulesRepo.IsExist(ctx, ruleID, s.bootRulesByServerQuery(ctx, serverID))
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to check existence of the boot rule")
+	}
+	if !exist {
+		return errors.NotFound.New("boot rule with this ID is not found")
+	}
+	err = s.bootRulesRepo.Delete(ctx, ruleID)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to remove boot rule by id")
+	}
+	return nil
+}
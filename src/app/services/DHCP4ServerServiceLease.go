@@ -2,12 +2,14 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"github.com/google/uuid"
 	"rol/app/errors"
 	"rol/app/mappers"
 	"rol/app/validators"
 	"rol/domain"
 	"rol/dtos"
+	"time"
 )
 
 //GetLeaseList Get list of DHCP server leases with search and pagination
@@ -146,9 +148,85 @@ func (s *DHCP4ServerService) DeleteLease(ctx context.Context, serverID, leaseID
 	if err != nil {
 		return errors.Wrap(err, "can't found lease")
 	}
-	err = s.leasesRepo.Delete(ctx, lease.ID)
+	return s.removeLease(ctx, lease)
+}
+
+//removeLease delete lease from repository and return its ip address to the runtime server pool
+func (s *DHCP4ServerService) removeLease(ctx context.Context, lease domain.DHCP4Lease) error {
+	err := s.leasesRepo.Delete(ctx, lease.ID)
 	if err != nil {
 		return errors.Internal.Wrap(err, "failed to remove lease by id")
 	}
+	if server, ok := s.getServer(lease.DHCP4ConfigID); ok {
+		err = server.ReleaseLease(lease.IP)
+		if err != nil {
+			return errors.Internal.Wrap(err, "failed to release lease ip address on dhcp v4 server")
+		}
+	}
+	return nil
+}
+
+//RemoveExpiredLeases delete expired leases of all DHCP v4 servers
+//and return their ip addresses to the runtime servers pools
+//Params
+//	ctx - context is used only for logging
+//Return
+//	error - if an error occurs, otherwise nil
+func (s *DHCP4ServerService) RemoveExpiredLeases(ctx context.Context) error {
+	queryBuilder := s.leasesRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("Expires", "<", time.Now())
+	expiredCount, err := s.leasesRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to count expired leases")
+	}
+	if expiredCount == 0 {
+		return nil
+	}
+	leases, err := s.leasesRepo.GetList(ctx, "", "", 1, expiredCount, queryBuilder)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to get expired leases")
+	}
+	removedCount := 0
+	for _, lease := range leases {
+		removed, err := s.removeExpiredLease(ctx, lease)
+		if err != nil {
+			return err
+		}
+		if removed {
+			removedCount++
+		}
+	}
+	s.log(ctx, "debug", fmt.Sprintf("removed %d expired dhcp v4 leases", removedCount))
 	return nil
 }
+
+//removeExpiredLease delete lease if it's still expired, lease can be renewed by the runtime server
+//after it was found as expired, so the runtime server re-checks it under its lock before releasing the ip address
+func (s *DHCP4ServerService) removeExpiredLease(ctx context.Context, lease domain.DHCP4Lease) (bool, error) {
+	if server, ok := s.getServer(lease.DHCP4ConfigID); ok {
+		removed, err := server.ReleaseExpiredLease(lease.ID)
+		if err != nil {
+			return false, errors.Internal.Wrap(err, "failed to release expired lease on dhcp v4 server")
+		}
+		return removed, nil
+	}
+	queryBuilder := s.leasesRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("ID", "==", lease.ID).Where("Expires", "<", time.Now())
+	err := s.leasesRepo.DeleteAll(ctx, queryBuilder)
+	if err != nil {
+		return false, errors.Internal.Wrap(err, "failed to remove expired lease")
+	}
+	return true, nil
+}
+
+func (s *DHCP4ServerService) leasesReaper() {
+	ticker := time.NewTicker(dhcp4LeasesReaperInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx := context.Background()
+		err := s.RemoveExpiredLeases(ctx)
+		if err != nil {
+			s.log(ctx, "error", fmt.Sprintf("failed to remove expired dhcp v4 leases: %s", err.Error()))
+		}
+	}
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"context"
+	"encoding/binary"
+	"github.com/google/uuid"
+	"net"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/mappers"
+	"rol/app/validators"
+	"rol/domain"
+	"rol/dtos"
+	"strings"
+)
+
+func (s *DHCP4ServerService) reservationsByServerQuery(ctx context.Context, serverID uuid.UUID) interfaces.IQueryBuilder {
+	queryBuilder := s.reservationsRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DHCP4ConfigID", "==", serverID)
+	return queryBuilder
+}
+
+func ipv4ToUint32(ip net.IP) uint32 {
+	return binary.BigEndian.Uint32(ip.To4())
+}
+
+//reservationAddressValidation checks that reserved ip address belongs to the server subnet,
+//is outside the dynamic range and does not match server, gateway, network or broadcast address
+func reservationAddressValidation(config domain.DHCP4Config, reservationIP string) error {
+	ip := net.ParseIP(reservationIP).To4()
+	serverIP := net.ParseIP(config.ServerID).To4()
+	mask := net.ParseIP(config.Mask).To4()
+	if ip == nil || serverIP == nil || mask == nil {
+		return errors.Internal.Newf("failed to parse ip addresses for dhcp v4 server %s", config.ID.String())
+	}
+	subnet := net.IPNet{IP: serverIP.Mask(net.IPMask(mask)), Mask: net.IPMask(mask)}
+	err := errors.Validation.New(errors.ValidationErrorMessage)
+	if !subnet.Contains(ip) {
+		return errors.AddErrorContext(err, "IP", "ip address is not in the dhcp v4 server subnet")
+	}
+	broadcast := ipv4ToUint32(subnet.IP) | ^ipv4ToUint32(net.IP(subnet.Mask))
+	switch ipv4ToUint32(ip) {
+	case ipv4ToUint32(subnet.IP), broadcast:
+		return errors.AddErrorContext(err, "IP", "ip address cannot be a network or broadcast address")
+	case ipv4ToUint32(serverIP):
+		return errors.AddErrorContext(err, "IP", "ip address cannot be the dhcp v4 server address")
+	}
+	if gateway := net.ParseIP(config.Gateway).To4(); gateway != nil && gateway.Equal(ip) {
+		return errors.AddErrorContext(err, "IP", "ip address cannot be the gateway address")
+	}
+	startEndIPs := strings.Split(config.Range, "-")
+	if len(startEndIPs) == 2 {
+		rangeStart := net.ParseIP(startEndIPs[0]).To4()
+		rangeEnd := net.ParseIP(startEndIPs[1]).To4()
+		if rangeStart != nil && rangeEnd != nil &&
+			ipv4ToUint32(ip) >= ipv4ToUint32(rangeStart) && ipv4ToUint32(ip) <= ipv4ToUint32(rangeEnd) {
+			return errors.AddErrorContext(err, "IP", "ip address cannot be inside the dynamic range of the dhcp v4 server")
+		}
+	}
+	return nil
+}
+
+func (s *DHCP4ServerService) reservationFieldIsUnique(ctx context.Context, serverID, reservationID uuid.UUID, fieldName, value string) (bool, error) {
+	queryBuilder := s.reservationsByServerQuery(ctx, serverID)
+	queryBuilder.Where(fieldName, "==", value)
+	if reservationID != [16]byte{} {
+		queryBuilder.Where("ID", "!=", reservationID)
+	}
+	count, err := s.reservationsRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return false, errors.Internal.Wrap(err, "failed to count dhcp v4 reservations")
+	}
+	return count == 0, nil
+}
+
+func (s *DHCP4ServerService) reservationValidation(ctx context.Context, serverID, reservationID uuid.UUID, baseDto dtos.DHCP4ReservationBaseDto) error {
+	config, err := s.configsRepo.GetByID(ctx, serverID)
+	if err != nil {
+		if errors.As(err, errors.NotFound) {
+			return errors.NotFound.New("server with this ID is not found")
+		}
+		return errors.Internal.Wrap(err, "failed to get dhcp v4 server config")
+	}
+	err = reservationAddressValidation(config, baseDto.IP)
+	if err != nil {
+		return err
+	}
+	macIsUnique, err := s.reservationFieldIsUnique(ctx, serverID, reservationID, "MAC", baseDto.MAC)
+	if err != nil {
+		return err
+	}
+	ipIsUnique, err := s.reservationFieldIsUnique(ctx, serverID, reservationID, "IP", baseDto.IP)
+	if err != nil {
+		return err
+	}
+	if !macIsUnique || !ipIsUnique {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		if !macIsUnique {
+			err = errors.AddErrorContext(err, "MAC", "reservation with this mac address already exist")
+		}
+		if !ipIsUnique {
+			err = errors.AddErrorContext(err, "IP", "reservation with this ip address already exist")
+		}
+		return err
+	}
+	return nil
+}
+
+//GetReservationList Get list of DHCP v4 server reservations with search and pagination
+//
+//Params:
+//	ctx - context is used only for logging
+//	serverID - DHCP v4 server ID
+//	search - string for search in entity string fields
+//	orderBy - order by entity field name
+//	orderDirection - ascending or descending order
+//	page - page number
+//	pageSize - page size
+//Return
+//	dtos.PaginatedItemsDto[dtos.DHCP4ReservationDto] - paginated list of DHCP v4 reservations
+//	error - if an error occurs, otherwise nil
+func (s *DHCP4ServerService) GetReservationList(ctx context.Context, serverID uuid.UUID, search, orderBy, orderDirection string, page, pageSize int) (
+	dtos.PaginatedItemsDto[dtos.DHCP4ReservationDto],
+	error,
+) {
+	paginatedItemsDto := dtos.NewEmptyPaginatedItemsDto[dtos.DHCP4ReservationDto]()
+	err := s.serverExistenceCheck(ctx, serverID)
+	if err != nil {
+		return paginatedItemsDto, err
+	}
+	queryBuilder := s.reservationsByServerQuery(ctx, serverID)
+	if len(search) > 3 {
+		AddSearchInAllFields(search, s.reservationsRepo, queryBuilder)
+	}
+	return GetListExtended[dtos.DHCP4ReservationDto](ctx, s.reservationsRepo, queryBuilder, orderBy, orderDirection, page, pageSize)
+}
+
+//GetReservationByID Get DHCP v4 server reservation by ID
+//Params
+//	ctx - context is used only for logging
+//	serverID - DHCP v4 server ID
+//	reservationID - DHCP v4 reservation ID
+//Return
+//	dtos.DHCP4ReservationDto - DHCP v4 reservation dto
+//	error - if an error occurs, otherwise nil
+func (s *DHCP4ServerService) GetReservationByID(ctx context.Context, serverID, reservationID uuid.UUID) (
+	dtos.DHCP4ReservationDto,
+	error,
+) {
+	dto := dtos.DHCP4ReservationDto{}
+	err := s.serverExistenceCheck(ctx, serverID)
+	if err != nil {
+		return dto, err
+	}
+	return GetByID[dtos.DHCP4ReservationDto](ctx, s.reservationsRepo, reservationID, s.reservationsByServerQuery(ctx, serverID))
+}
+
+//CreateReservation create DHCP v4 server reservation
+//Params
+//	ctx - context is used only for logging
+//	serverID - DHCP v4 server ID
+//	createDto - dto for creating DHCP v4 reservation
+//Return
+//	dtos.DHCP4ReservationDto - DHCP v4 reservation dto
+//	error - if an error occurs, otherwise nil
+func (s *DHCP4ServerService) CreateReservation(ctx context.Context, serverID uuid.UUID, createDto dtos.DHCP4ReservationCreateDto) (
+	dtos.DHCP4ReservationDto,
+	error,
+) {
+	outDto := dtos.DHCP4ReservationDto{}
+	err := validators.ValidateDHCP4ReservationCreateDto(createDto)
+	if err != nil {
+		return outDto, err
+	}
+	//the plugin looks reservations up by the mac address in lower case
+	createDto.MAC = strings.ToLower(createDto.MAC)
+	err = s.reservationValidation(ctx, serverID, [16]byte{}, createDto.DHCP4ReservationBaseDto)
+	if err != nil {
+		return outDto, err
+	}
+
+	entity := new(domain.DHCP4Reservation)
+	err = mappers.MapDtoToEntity(createDto, entity)
+	if err != nil {
+		return outDto, errors.Internal.Wrap(err, "error map entity to dto")
+	}
+	entity.DHCP4ConfigID = serverID
+	newEntity, err := s.reservationsRepo.Insert(ctx, *entity)
+	if err != nil {
+		return outDto, errors.Internal.Wrap(err, "create entity error")
+	}
+
+	err = mappers.MapEntityToDto(newEntity, &outDto)
+	if err != nil {
+		return outDto, errors.Internal.Wrap(err, "error map dto to entity")
+	}
+	return outDto, nil
+}
+
+//UpdateReservation update DHCP v4 server reservation
+//Params
+//	ctx - context is used only for logging
+//	serverID - DHCP v4 server ID
+//	reservationID - DHCP v4 reservation ID
+//	updateDto - dto for updating DHCP v4 reservation
+//Return
+//	dtos.DHCP4ReservationDto - DHCP v4 reservation dto
+//	error - if an error occurs, otherwise nil
+func (s *DHCP4ServerService) UpdateReservation(ctx context.Context, serverID, reservationID uuid.UUID, updateDto dtos.DHCP4ReservationUpdateDto) (
+	dtos.DHCP4ReservationDto,
+	error,
+) {
+	dto := dtos.DHCP4ReservationDto{}
+	err := validators.ValidateDHCP4ReservationUpdateDto(updateDto)
+	if err != nil {
+		return dto, err
+	}
+	updateDto.MAC = strings.ToLower(updateDto.MAC)
+	err = s.reservationValidation(ctx, serverID, reservationID, updateDto.DHCP4ReservationBaseDto)
+	if err != nil {
+		return dto, err
+	}
+	return Update[dtos.DHCP4ReservationDto](ctx, s.reservationsRepo, updateDto, reservationID, s.reservationsByServerQuery(ctx, serverID))
+}
+
+//DeleteReservation delete DHCP v4 server reservation
+//Params
+//	ctx - context is used only for logging
+//	serverID - ID for DHCP v4 server
+//	reservationID - ID for DHCP v4 reservation
+//Return
+//	error - if an error occurs, otherwise nil
+func (s *DHCP4ServerService) DeleteReservation(ctx context.Context, serverID, reservationID uuid.UUID) error {
+	err := s.serverExistenceCheck(ctx, serverID)
+	if err != nil {
+		return err
+	}
+	exist, err := s.reservationsRepo.IsExist(ctx, reservationID, s.reservationsByServerQuery(ctx, serverID))
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to check existence of the reservation")
+	}
+	if !exist {
+		return errors.NotFound.New("reservation with this ID is not found")
+	}
+	err = s.reservationsRepo.Delete(ctx, reservationID)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to remove reservation by id")
+	}
+	return nil
+}
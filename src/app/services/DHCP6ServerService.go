@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"reflect"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/validators"
+	"rol/domain"
+	"rol/dtos"
+	"sync"
+	"time"
+)
+
+//dhcp6LeasesReaperInterval interval between expired leases cleanups
+const dhcp6LeasesReaperInterval = time.Minute
+
+//DHCP6ServerService service structure for managing DHCP v6 servers
+type DHCP6ServerService struct {
+	configsRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP6Config]
+	leasesRepo  interfaces.IGenericRepository[uuid.UUID, domain.DHCP6Lease]
+	factory     interfaces.IDHCP6ServerFactory
+	servers     map[uuid.UUID]interfaces.IDHCP6Server
+	//serversMutex guards servers map, that is shared with the leases reaper
+	serversMutex sync.RWMutex
+	logger       *logrus.Logger
+	//logSourceName - logger recording source
+	logSourceName string
+}
+
+//NewDHCP6ServerService constructor for DHCP6ServerService service
+//
+//Params:
+//	configs - repository with domain.DHCP6Config entity
+//	leases - repository with domain.DHCP6Lease entity
+//	dhcp6factory - dhcp v6 servers factory
+//	logger - logrus logger
+//Return:
+//	*DHCP6ServerService - New DHCP v6 servers service
+func NewDHCP6ServerService(
+	configs interfaces.IGenericRepository[uuid.UUID, domain.DHCP6Config],
+	leases interfaces.IGenericRepository[uuid.UUID, domain.DHCP6Lease],
+	dhcp6factory interfaces.IDHCP6ServerFactory,
+	logger *logrus.Logger,
+) *DHCP6ServerService {
+	return &DHCP6ServerService{
+		configsRepo:   configs,
+		leasesRepo:    leases,
+		servers:       map[uuid.UUID]interfaces.IDHCP6Server{},
+		factory:       dhcp6factory,
+		logger:        logger,
+		logSourceName: reflect.TypeOf(DHCP6ServerService{}).Name(),
+	}
+}
+
+func (s *DHCP6ServerService) log(ctx context.Context, level, message string) {
+	if ctx != nil {
+		actionID := uuid.UUID{}
+		if ctx.Value("requestID") != nil {
+			actionID = ctx.Value("requestID").(uuid.UUID)
+		}
+
+		entry := s.logger.WithFields(logrus.Fields{
+			"actionID": actionID,
+			"source":   s.logSourceName,
+		})
+		switch level {
+		case "err", "error":
+			entry.Error(message)
+		case "info":
+			entry.Info(message)
+		case "warn", "warning":
+			entry.Warn(message)
+		case "debug":
+			entry.Debug(message)
+		}
+	}
+}
+
+func (s *DHCP6ServerService) getServer(id uuid.UUID) (interfaces.IDHCP6Server, bool) {
+	s.serversMutex.RLock()
+	defer s.serversMutex.RUnlock()
+	server, ok := s.servers[id]
+	return server, ok
+}
+
+func (s *DHCP6ServerService) setServer(id uuid.UUID, server interfaces.IDHCP6Server) {
+	s.serversMutex.Lock()
+	defer s.serversMutex.Unlock()
+	s.servers[id] = server
+}
+
+func (s *DHCP6ServerService) removeServer(id uuid.UUID) {
+	s.serversMutex.Lock()
+	defer s.serversMutex.Unlock()
+	delete(s.servers, id)
+}
+
+func (s *DHCP6ServerService) getServerState(configID uuid.UUID, enabled bool) domain.DHCPServerState {
+	if server, ok := s.getServer(configID); ok {
+		return server.GetState()
+	} else if enabled {
+		return domain.DHCPStateError
+	} else {
+		return domain.DHCPStateStopped
+	}
+}
+
+func (s *DHCP6ServerService) serverExistenceCheck(ctx context.Context, serverID uuid.UUID) error {
+	serverExist, err := s.configsRepo.IsExist(ctx, serverID, nil)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to check existence of the server")
+	}
+	if !serverExist {
+		return errors.NotFound.New("server with this ID is not found")
+	}
+	return nil
+}
+
+//startServer starts runtime server and returns its state, start error is logged
+func (s *DHCP6ServerService) startServer(ctx context.Context, server interfaces.IDHCP6Server) domain.DHCPServerState {
+	err := server.Start()
+	if err != nil {
+		s.log(ctx, "error", "failed to start dhcp v6 server: "+err.Error())
+		return domain.DHCPStateError
+	}
+	return server.GetState()
+}
+
+//DHCP6ServerServiceInit starts all enabled DHCP v6 servers
+func DHCP6ServerServiceInit(s *DHCP6ServerService) error {
+	ctx := context.Background()
+	queryBuilder := s.configsRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("Enabled", "==", true)
+	enabledServersCount, err := s.configsRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return errors.Internal.Wrap(err, "Failed to count enabled dhcp v6 servers")
+	}
+	serversConfigs, err := s.configsRepo.GetList(ctx, "", "", 1, enabledServersCount, queryBuilder)
+	if err != nil {
+		return errors.Internal.Wrap(err, "Failed to get configs for enabled dhcp v6 servers")
+	}
+	for _, config := range serversConfigs {
+		server, err := s.factory.Create(config)
+		if err != nil {
+			return errors.Internal.Wrapf(err, "failed to create dhcp v6 server with id: %s", config.ID.String())
+		}
+		s.setServer(config.ID, server)
+		//interface of the server can be absent, so error state is kept and other servers are started
+		s.startServer(ctx, server)
+	}
+	go s.leasesReaper()
+	return nil
+}
+
+//GetServerList Get list of DHCP v6 servers with search and pagination
+//
+//Params:
+//	ctx - context is used only for logging
+//	search - string for search in entity string fields
+//	orderBy - order by entity field name
+//	orderDirection - ascending or descending order
+//	page - page number
+//	pageSize - page size
+//Return
+//	dtos.PaginatedItemsDto[dtos.DHCP6ServerDto] - paginated list of DHCP v6 servers
+//	error - if an error occurs, otherwise nil
+func (s *DHCP6ServerService) GetServerList(ctx context.Context, search, orderBy, orderDirection string, page, pageSize int) (dtos.PaginatedItemsDto[dtos.DHCP6ServerDto], error) {
+	paginatedItems, err := GetList[dtos.DHCP6ServerDto](ctx, s.configsRepo, search, orderBy, orderDirection, page, pageSize)
+	if err != nil {
+		return paginatedItems, err
+	}
+	for i, dtoItem := range paginatedItems.Items {
+		(&paginatedItems.Items[i]).State = s.getServerState(dtoItem.ID, dtoItem.Enabled).String()
+	}
+	return paginatedItems, nil
+}
+
+//GetServerByID Get DHCP v6 server by ID
+//Params
+//	ctx - context is used only for logging
+//	id - DHCP v6 server ID
+//Return
+//	dtos.DHCP6ServerDto - DHCP v6 server dto
+//	error - if an error occurs, otherwise nil
+func (s *DHCP6ServerService) GetServerByID(ctx context.Context, id uuid.UUID) (dtos.DHCP6ServerDto, error) {
+	dto, err := GetByID[dtos.DHCP6ServerDto](ctx, s.configsRepo, id, nil)
+	if err != nil {
+		return dto, err
+	}
+	dto.State = s.getServerState(dto.ID, dto.Enabled).String()
+	return dto, nil
+}
+
+//CreateServer create DHCP v6 server
+//Params
+//	ctx - context is used only for logging
+//	createDto - dto for creating DHCP v6 server
+//Return
+//	dtos.DHCP6ServerDto - DHCP v6 server dto
+//	error - if an error occurs, otherwise nil
+func (s *DHCP6ServerService) CreateServer(ctx context.Context, createDto dtos.DHCP6ServerCreateDto) (dtos.DHCP6ServerDto, error) {
+	dto := dtos.DHCP6ServerDto{}
+	err := validators.ValidateDHCP6ServerCreateDto(createDto)
+	if err != nil {
+		return dto, err
+	}
+
+	dto, err = Create[dtos.DHCP6ServerDto](ctx, s.configsRepo, createDto)
+	if err != nil {
+		return dto, err
+	}
+	config, err := s.configsRepo.GetByID(ctx, dto.ID)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to get config for dhcp v6 server")
+	}
+
+	server, err := s.factory.Create(config)
+	if err != nil {
+		return dto, errors.Wrap(err, "failed to create dhcp v6 server")
+	}
+	s.setServer(config.ID, server)
+
+	dto.State = domain.DHCPStateStopped.String()
+	if createDto.Enabled {
+		dto.State = s.startServer(ctx, server).String()
+	}
+	return dto, nil
+}
+
+//UpdateServer update DHCP v6 server
+//Params
+//	ctx - context is used only for logging
+//	id - DHCP v6 server ID
+//	updateDto - dto for updating DHCP v6 server
+//Return
+//	dtos.DHCP6ServerDto - DHCP v6 server dto
+//	error - if an error occurs, otherwise nil
+func (s *DHCP6ServerService) UpdateServer(ctx context.Context, id uuid.UUID, updateDto dtos.DHCP6ServerUpdateDto) (dtos.DHCP6ServerDto, error) {
+	dto := dtos.DHCP6ServerDto{}
+	err := validators.ValidateDHCP6ServerUpdateDto(updateDto)
+	if err != nil {
+		return dto, err
+	}
+
+	dto, err = Update[dtos.DHCP6ServerDto](ctx, s.configsRepo, updateDto, id, nil)
+	if err != nil {
+		return dto, err
+	}
+	config, err := s.configsRepo.GetByID(ctx, dto.ID)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to get config for dhcp v6 server")
+	}
+
+	server, ok := s.getServer(config.ID)
+	if !ok {
+		server, err = s.factory.Create(config)
+		if err != nil {
+			return dto, errors.Wrap(err, "failed to create dhcp v6 server")
+		}
+		s.setServer(config.ID, server)
+	} else {
+		server.Stop()
+		err = server.ReloadConfiguration(config)
+		if err != nil {
+			return dto, errors.Wrap(err, "failed to reload DHCP v6 server configuration")
+		}
+	}
+	dto.State = domain.DHCPStateStopped.String()
+	if config.Enabled {
+		dto.State = s.startServer(ctx, server).String()
+	}
+	return dto, nil
+}
+
+//DeleteServer delete DHCP v6 server from server pool
+//Params
+//	ctx - context is used only for logging
+//	id - ID for DHCP v6 server
+//Return
+//	error - if an error occurs, otherwise nil
+func (s *DHCP6ServerService) DeleteServer(ctx context.Context, id uuid.UUID) error {
+	err := s.serverExistenceCheck(ctx, id)
+	if err != nil {
+		return err
+	}
+	if server, ok := s.getServer(id); ok {
+		server.Stop()
+		s.removeServer(id)
+	}
+	err = s.leasesRepo.DeleteAll(ctx, s.leasesByServerQuery(ctx, id))
+	if err != nil {
+		return errors.Wrap(err, "failed to remove leases")
+	}
+	err = s.configsRepo.Delete(ctx, id)
+	if err != nil {
+		return errors.Wrap(err, "failed to remove dhcp v6 server configuration")
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/mappers"
+	"rol/app/validators"
+	"rol/domain"
+	"rol/dtos"
+	"time"
+)
+
+func (s *DHCP6ServerService) leasesByServerQuery(ctx context.Context, serverID uuid.UUID) interfaces.IQueryBuilder {
+	queryBuilder := s.leasesRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DHCP6ConfigID", "==", serverID)
+	return queryBuilder
+}
+
+//GetLeaseList Get list of DHCP v6 server leases with search and pagination
+//
+//Params:
+//	ctx - context is used only for logging
+//	serverID - DHCP v6 server ID
+//	search - string for search in entity string fields
+//	orderBy - order by entity field name
+//	orderDirection - ascending or descending order
+//	page - page number
+//	pageSize - page size
+//Return
+//	dtos.PaginatedItemsDto[dtos.DHCP6LeaseDto] - paginated list of DHCP v6 leases
+//	error - if an error occurs, otherwise nil
+func (s *DHCP6ServerService) GetLeaseList(ctx context.Context, serverID uuid.UUID, search, orderBy, orderDirection string, page, pageSize int) (
+	dtos.PaginatedItemsDto[dtos.DHCP6LeaseDto],
+	error,
+) {
+	paginatedItemsDto := dtos.NewEmptyPaginatedItemsDto[dtos.DHCP6LeaseDto]()
+	err := s.serverExistenceCheck(ctx, serverID)
+	if err != nil {
+		return paginatedItemsDto, err
+	}
+	queryBuilder := s.leasesByServerQuery(ctx, serverID)
+	if len(search) > 3 {
+		AddSearchInAllFields(search, s.leasesRepo, queryBuilder)
+	}
+	return GetListExtended[dtos.DHCP6LeaseDto](ctx, s.leasesRepo, queryBuilder, orderBy, orderDirection, page, pageSize)
+}
+
+//GetLeaseByID Get DHCP v6 server lease by ID
+//Params
+//	ctx - context is used only for logging
+//	serverID - DHCP v6 server ID
+//	leaseID - DHCP v6 lease ID
+//Return
+//	dtos.DHCP6LeaseDto - DHCP v6 lease dto
+//	error - if an error occurs, otherwise nil
+func (s *DHCP6ServerService) GetLeaseByID(ctx context.Context, serverID, leaseID uuid.UUID) (dtos.DHCP6LeaseDto, error) {
+	dto := dtos.DHCP6LeaseDto{}
+	err := s.serverExistenceCheck(ctx, serverID)
+	if err != nil {
+		return dto, err
+	}
+	return GetByID[dtos.DHCP6LeaseDto](ctx, s.leasesRepo, leaseID, s.leasesByServerQuery(ctx, serverID))
+}
+
+//CreateLease create DHCP v6 server lease
+//Params
+//	ctx - context is used only for logging
+//	serverID - DHCP v6 server ID
+//	createDto - dto for creating DHCP v6 lease
+//Return
+//	dtos.DHCP6LeaseDto - DHCP v6 lease dto
+//	error - if an error occurs, otherwise nil
+func (s *DHCP6ServerService) CreateLease(ctx context.Context, serverID uuid.UUID, createDto dtos.DHCP6LeaseCreateDto) (dtos.DHCP6LeaseDto, error) {
+	outDto := dtos.DHCP6LeaseDto{}
+	err := validators.ValidateDHCP6LeaseCreateDto(createDto)
+	if err != nil {
+		return outDto, err
+	}
+	err = s.serverExistenceCheck(ctx, serverID)
+	if err != nil {
+		return outDto, err
+	}
+
+	entity := new(domain.DHCP6Lease)
+	err = mappers.MapDtoToEntity(createDto, entity)
+	if err != nil {
+		return outDto, errors.Internal.Wrap(err, "error map entity to dto")
+	}
+	entity.DHCP6ConfigID = serverID
+	newEntity, err := s.leasesRepo.Insert(ctx, *entity)
+	if err != nil {
+		return outDto, errors.Internal.Wrap(err, "create entity error")
+	}
+
+	err = mappers.MapEntityToDto(newEntity, &outDto)
+	if err != nil {
+		return outDto, errors.Internal.Wrap(err, "error map dto to entity")
+	}
+	return outDto, nil
+}
+
+//UpdateLease update DHCP v6 server lease
+//Params
+//	ctx - context is used only for logging
+//	serverID - DHCP v6 server ID
+//	leaseID - DHCP v6 lease ID
+//	updateDto - dto for updating DHCP v6 lease
+//Return
+//	dtos.DHCP6LeaseDto - DHCP v6 lease dto
+//	error - if an error occurs, otherwise nil
+func (s *DHCP6ServerService) UpdateLease(ctx context.Context, serverID, leaseID uuid.UUID, updateDto dtos.DHCP6LeaseUpdateDto) (dtos.DHCP6LeaseDto, error) {
+	dto := dtos.DHCP6LeaseDto{}
+	err := validators.ValidateDHCP6LeaseUpdateDto(updateDto)
+	if err != nil {
+		return dto, err
+	}
+	err = s.serverExistenceCheck(ctx, serverID)
+	if err != nil {
+		return dto, err
+	}
+	return Update[dtos.DHCP6LeaseDto](ctx, s.leasesRepo, updateDto, leaseID, s.leasesByServerQuery(ctx, serverID))
+}
+
+//DeleteLease delete DHCP v6 server lease
+//Params
+//	ctx - context is used only for logging
+//	serverID - ID for DHCP v6 server
+//	leaseID - ID for DHCP v6 lease
+//Return
+//	error - if an error occurs, otherwise nil
+func (s *DHCP6ServerService) DeleteLease(ctx context.Context, serverID, leaseID uuid.UUID) error {
+	err := s.serverExistenceCheck(ctx, serverID)
+	if err != nil {
+		return err
+	}
+	lease, err := s.leasesRepo.GetByIDExtended(ctx, leaseID, s.leasesByServerQuery(ctx, serverID))
+	if err != nil {
+		return errors.Wrap(err, "can't found lease")
+	}
+	return s.removeLease(ctx, lease)
+}
+
+//removeLease delete lease from repository and return its ip address to the runtime server pool
+func (s *DHCP6ServerService) removeLease(ctx context.Context, lease domain.DHCP6Lease) error {
+	err := s.leasesRepo.Delete(ctx, lease.ID)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to remove lease by id")
+	}
+	if server, ok := s.getServer(lease.DHCP6ConfigID); ok {
+		err = server.ReleaseLease(lease.IP)
+		if err != nil {
+			return errors.Internal.Wrap(err, "failed to release lease ip address on dhcp v6 server")
+		}
+	}
+	return nil
+}
+
+//RemoveExpiredLeases delete expired leases of all DHCP v6 servers
+//and return their ip addresses to the runtime servers pools
+//Params
+//	ctx - context is used only for logging
+//Return
+//	error - if an error occurs, otherwise nil
+func (s *DHCP6ServerService) RemoveExpiredLeases(ctx context.Context) error {
+	queryBuilder := s.leasesRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("Expires", "<", time.Now())
+	expiredCount, err := s.leasesRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to count expired leases")
+	}
+	if expiredCount == 0 {
+		return nil
+	}
+	leases, err := s.leasesRepo.GetList(ctx, "", "", 1, expiredCount, queryBuilder)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to get expired leases")
+	}
+	removedCount := 0
+	for _, lease := range leases {
+		removed, err := s.removeExpiredLease(ctx, lease)
+		if err != nil {
+			return err
+		}
+		if removed {
+			removedCount++
+		}
+	}
+	s.log(ctx, "debug", fmt.Sprintf("removed %d expired dhcp v6 leases", removedCount))
+	return nil
+}
+
+//removeExpiredLease delete lease if it's still expired, lease can be renewed by the runtime server
+//after it was found as expired, so the runtime server re-checks it under its lock before releasing the ip address
+func (s *DHCP6ServerService) removeExpiredLease(ctx context.Context, lease domain.DHCP6Lease) (bool, error) {
+	if server, ok := s.getServer(lease.DHCP6ConfigID); ok {
+		removed, err := server.ReleaseExpiredLease(lease.ID)
+		if err != nil {
+			return false, errors.Internal.Wrap(err, "failed to release expired lease on dhcp v6 server")
+		}
+		return removed, nil
+	}
+	queryBuilder := s.leasesRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("ID", "==", lease.ID).Where("Expires", "<", time.Now())
+	err := s.leasesRepo.DeleteAll(ctx, queryBuilder)
+	if err != nil {
+		return false, errors.Internal.Wrap(err, "failed to remove expired lease")
+	}
+	return true, nil
+}
+
+func (s *DHCP6ServerService) leasesReaper() {
+	ticker := time.NewTicker(dhcp6LeasesReaperInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		ctx := context.Background()
+		err := s.RemoveExpiredLeases(ctx)
+		if err != nil {
+			s.log(ctx, "error", fmt.Sprintf("failed to remove expired dhcp v6 leases: %s", err.Error()))
+		}
+	}
+}
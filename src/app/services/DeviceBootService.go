@@ -0,0 +1,429 @@
+package services
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"reflect"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/mappers"
+	"rol/app/validators"
+	"rol/domain"
+	"rol/dtos"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//defaultDeviceBootStageTimeout stage timeout in seconds, used if timeout is not set in start dto
+	defaultDeviceBootStageTimeout = 300
+	//deviceBootEventsQueueSize max count of the events queued for the single boot
+	deviceBootEventsQueueSize = 64
+)
+
+//deviceBootRun runtime of the running device boot
+type deviceBootRun struct {
+	boot domain.DeviceBoot
+	//stages boot stages of the device template
+	stages []domain.BootStageTemplate
+	//macs all device mac addresses in lower case, events are matched with the boot by them
+	macs map[string]bool
+	//netBootMAC mac address of the device net boot interface, stages files are installed for it
+	netBootMAC string
+	//managementMAC mac address of the device management interface
+	managementMAC string
+	events        chan domain.BootEvent
+	cancel        chan struct{}
+	done          chan struct{}
+}
+
+//DeviceBootService service that boots devices by the boot stages of their templates
+type DeviceBootService struct {
+	bootsRepo       interfaces.IGenericRepository[uuid.UUID, domain.DeviceBoot]
+	stagesRepo      interfaces.IGenericRepository[uuid.UUID, domain.DeviceBootStage]
+	deviceRepo      interfaces.IGenericRepository[uuid.UUID, domain.Device]
+	interfaceRepo   interfaces.IGenericRepository[uuid.UUID, domain.DeviceNetworkInterface]
+	templateStorage interfaces.IGenericTemplateStorage[domain.DeviceTemplate]
+	deviceService   *DeviceService
+	tftpService     *TFTPServerService
+	events          interfaces.IBootEventBus
+	//runs running boots, key is a device id
+	runs map[uuid.UUID]*deviceBootRun
+	//runsMutex guards runs map, that is shared with the events handler
+	runsMutex sync.Mutex
+	logger    *logrus.Logger
+	//logSourceName - logger recording source
+	logSourceName string
+}
+
+//NewDeviceBootService constructor for device boot service
+//
+//Params
+//	bootsRepo - device boots repository
+//	stagesRepo - device boot stages repository
+//	deviceRepo - device repository
+//	interfaceRepo - device network interface repository
+//	templateStorage - device template storage
+//	deviceService - device service, used for the device power control
+//	tftpService - TFTP server service, used for the stages files installation
+//	events - bus of the events observed from the booting devices
+//	logger - logrus logger
+//Return
+//	*DeviceBootService - new device boot service
+func NewDeviceBootService(bootsRepo interfaces.IGenericRepository[uuid.UUID, domain.DeviceBoot],
+	stagesRepo interfaces.IGenericRepository[uuid.UUID, domain.DeviceBootStage],
+	deviceRepo interfaces.IGenericRepository[uuid.UUID, domain.Device],
+	interfaceRepo interfaces.IGenericRepository[uuid.UUID, domain.DeviceNetworkInterface],
+	templateStorage interfaces.IGenericTemplateStorage[domain.DeviceTemplate],
+	deviceService *DeviceService, tftpService *TFTPServerService, events interfaces.IBootEventBus,
+	logger *logrus.Logger) *DeviceBootService {
+	return &DeviceBootService{
+		bootsRepo:       bootsRepo,
+		stagesRepo:      stagesRepo,
+		deviceRepo:      deviceRepo,
+		interfaceRepo:   interfaceRepo,
+		templateStorage: templateStorage,
+		deviceService:   deviceService,
+		tftpService:     tftpService,
+		events:          events,
+		runs:            map[uuid.UUID]*deviceBootRun{},
+		logger:          logger,
+		logSourceName:   reflect.TypeOf(DeviceBootService{}).Name(),
+	}
+}
+
+func (s *DeviceBootService) log(ctx context.Context, level, message string) {
+	if ctx != nil {
+		actionID := uuid.UUID{}
+		if ctx.Value("requestID") != nil {
+			actionID = ctx.Value("requestID").(uuid.UUID)
+		}
+
+		entry := s.logger.WithFields(logrus.Fields{
+			"actionID": actionID,
+			"source":   s.logSourceName,
+		})
+		switch level {
+		case "err", "error":
+			entry.Error(message)
+		case "info":
+			entry.Info(message)
+		case "warn", "warning":
+			entry.Warn(message)
+		case "debug":
+			entry.Debug(message)
+		}
+	}
+}
+
+//DeviceBootServiceInit subscribes device boot service to the boot events
+//and cancels boots that were interrupted by the app restart
+func DeviceBootServiceInit(s *DeviceBootService) error {
+	ctx := context.Background()
+	queryBuilder := s.bootsRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("State", "==", domain.DeviceBootStateRunning)
+	runningCount, err := s.bootsRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to count running device boots")
+	}
+	if runningCount > 0 {
+		boots, err := s.bootsRepo.GetList(ctx, "", "", 1, runningCount, queryBuilder)
+		if err != nil {
+			return errors.Internal.Wrap(err, "failed to get running device boots")
+		}
+		for _, boot := range boots {
+			boot.State = domain.DeviceBootStateCancelled
+			boot.FinishedAt = time.Now()
+			boot.Error = "boot is interrupted by the app restart"
+			if _, err = s.bootsRepo.Update(ctx, boot); err != nil {
+				return errors.Internal.Wrap(err, "failed to cancel interrupted device boot")
+			}
+		}
+	}
+	s.events.Subscribe(s.handleBootEvent)
+	return nil
+}
+
+//handleBootEvent passes the event to the boot of the device with the event mac address
+func (s *DeviceBootService) handleBootEvent(event domain.BootEvent) {
+	if event.ClientMAC == "" {
+		return
+	}
+	s.runsMutex.Lock()
+	defer s.runsMutex.Unlock()
+	for _, run := range s.runs {
+		if !run.macs[event.ClientMAC] {
+			continue
+		}
+		select {
+		case run.events <- event:
+		default:
+		}
+	}
+}
+
+func (s *DeviceBootService) getDeviceBootStages(template domain.DeviceTemplate, bootType string) []domain.BootStageTemplate {
+	switch bootType {
+	case "net":
+		return template.NetBootStages
+	case "disc":
+		return template.DiscBootStages
+	case "usb":
+		return template.USBBootStages
+	}
+	return nil
+}
+
+func newDeviceBootValidationError(key, value string) error {
+	err := errors.Validation.New(errors.ValidationErrorMessage)
+	return errors.AddErrorContext(err, key, value)
+}
+
+//newDeviceBootRun collects device mac addresses and checks that the device interfaces are suitable for the stages
+func (s *DeviceBootService) newDeviceBootRun(ctx context.Context, device domain.Device, template domain.DeviceTemplate,
+	stages []domain.BootStageTemplate) (*deviceBootRun, error) {
+	run := &deviceBootRun{
+		stages: stages,
+		macs:   map[string]bool{},
+		events: make(chan domain.BootEvent, deviceBootEventsQueueSize),
+		cancel: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	queryBuilder := s.interfaceRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DeviceID", "==", device.ID)
+	interfacesCount, err := s.interfaceRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to count device network interfaces")
+	}
+	networkInterfaces, err := s.interfaceRepo.GetList(ctx, "", "", 1, interfacesCount, queryBuilder)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to get device network interfaces")
+	}
+	for _, networkInterface := range networkInterfaces {
+		mac := networkInterface.MAC
+		if mac == "" {
+			continue
+		}
+		run.macs[mac] = true
+		for _, templateInterface := range template.NetworkInterfaces {
+			if templateInterface.Name != networkInterface.Name {
+				continue
+			}
+			if templateInterface.NetBoot && run.netBootMAC == "" {
+				run.netBootMAC = mac
+			}
+			if templateInterface.Management && run.managementMAC == "" {
+				run.managementMAC = mac
+			}
+		}
+	}
+	for _, stage := range stages {
+		action := strings.ToLower(stage.Action)
+		if action == bootStageActionFile && run.netBootMAC == "" {
+			return nil, newDeviceBootValidationError("NetworkInterfaces", "device has no net boot network interface with mac address")
+		}
+		if action == bootStageActionCheckManagement && run.managementMAC == "" {
+			return nil, newDeviceBootValidationError("NetworkInterfaces", "device has no management network interface with mac address")
+		}
+	}
+	return run, nil
+}
+
+//StartBoot starts the device boot, boot stages are executed in background
+//
+//Params
+//	ctx - context
+//	deviceID - device id
+//	startDto - device boot start dto
+//Return
+//	dtos.DeviceBootDto - started device boot
+//	error - if an error occurs, otherwise nil
+func (s *DeviceBootService) StartBoot(ctx context.Context, deviceID uuid.UUID, startDto dtos.DeviceBootStartDto) (dtos.DeviceBootDto, error) {
+	dto := dtos.DeviceBootDto{}
+	err := validators.ValidateDeviceBootStartDto(startDto)
+	if err != nil {
+		return dto, err
+	}
+	device, err := s.deviceRepo.GetByID(ctx, deviceID)
+	if err != nil {
+		return dto, err
+	}
+	template, err := s.templateStorage.GetByName(ctx, device.DeviceTemplate)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to get device template from storage")
+	}
+	stages := s.getDeviceBootStages(template, startDto.BootType)
+	if len(stages) == 0 {
+		return dto, newDeviceBootValidationError("BootType", "device template has no stages for this boot type")
+	}
+	_, err = s.tftpService.GetServerByID(ctx, startDto.TFTPServerID)
+	if err != nil {
+		if errors.As(err, errors.NotFound) {
+			return dto, newDeviceBootValidationError("TFTPServerID", "tftp server with this id is not found")
+		}
+		return dto, errors.Internal.Wrap(err, "failed to get tftp server")
+	}
+	run, err := s.newDeviceBootRun(ctx, device, template, stages)
+	if err != nil {
+		return dto, err
+	}
+	stageTimeout := startDto.StageTimeout
+	if stageTimeout == 0 {
+		stageTimeout = defaultDeviceBootStageTimeout
+	}
+	s.runsMutex.Lock()
+	defer s.runsMutex.Unlock()
+	if _, ok := s.runs[deviceID]; ok {
+		return dto, newDeviceBootValidationError("DeviceID", "device boot is already running")
+	}
+	run.boot, err = s.bootsRepo.Insert(ctx, domain.DeviceBoot{
+		DeviceID:     deviceID,
+		BootType:     startDto.BootType,
+		TFTPConfigID: startDto.TFTPServerID,
+		StageTimeout: stageTimeout,
+		StageName:    stages[0].Name,
+		State:        domain.DeviceBootStateRunning,
+	})
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to create device boot in repository")
+	}
+	err = mappers.MapEntityToDto(run.boot, &dto)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "error map entity to dto")
+	}
+	s.runs[deviceID] = run
+	go s.runBoot(run)
+	return dto, nil
+}
+
+func (s *DeviceBootService) getBootStages(ctx context.Context, bootID uuid.UUID) ([]dtos.DeviceBootStageDto, error) {
+	stagesDtos := []dtos.DeviceBootStageDto{}
+	queryBuilder := s.stagesRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DeviceBootID", "==", bootID)
+	stagesCount, err := s.stagesRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return stagesDtos, errors.Internal.Wrap(err, "failed to count device boot stages")
+	}
+	if stagesCount == 0 {
+		return stagesDtos, nil
+	}
+	stages, err := s.stagesRepo.GetList(ctx, "Index", "asc", 1, stagesCount, queryBuilder)
+	if err != nil {
+		return stagesDtos, errors.Internal.Wrap(err, "failed to get device boot stages")
+	}
+	for _, stage := range stages {
+		stageDto := dtos.DeviceBootStageDto{}
+		err = mappers.MapEntityToDto(stage, &stageDto)
+		if err != nil {
+			return stagesDtos, errors.Internal.Wrap(err, "error map entity to dto")
+		}
+		stagesDtos = append(stagesDtos, stageDto)
+	}
+	return stagesDtos, nil
+}
+
+func (s *DeviceBootService) getBootsQueryBuilder(ctx context.Context, deviceID uuid.UUID) interfaces.IQueryBuilder {
+	queryBuilder := s.bootsRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DeviceID", "==", deviceID)
+	return queryBuilder
+}
+
+//GetCurrentBoot get the last boot of the device with the history of its stages
+//
+//Params
+//	ctx - context
+//	deviceID - device id
+//Return
+//	dtos.DeviceBootDto - the last device boot
+//	error - if an error occurs, otherwise nil
+func (s *DeviceBootService) GetCurrentBoot(ctx context.Context, deviceID uuid.UUID) (dtos.DeviceBootDto, error) {
+	dto := dtos.DeviceBootDto{}
+	boots, err := s.bootsRepo.GetList(ctx, "CreatedAt", "desc", 1, 1, s.getBootsQueryBuilder(ctx, deviceID))
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to get device boots")
+	}
+	if len(boots) == 0 {
+		return dto, errors.NotFound.New("device was never booted")
+	}
+	err = mappers.MapEntityToDto(boots[0], &dto)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "error map entity to dto")
+	}
+	dto.Stages, err = s.getBootStages(ctx, boots[0].ID)
+	return dto, err
+}
+
+//GetBootList get list of the device boots with pagination
+//
+//Params
+//	ctx - context
+//	deviceID - device id
+//	orderBy - order by entity field name
+//	orderDirection - ascending or descending order
+//	page - page number
+//	pageSize - page size
+//Return
+//	dtos.PaginatedItemsDto[dtos.DeviceBootDto] - paginated list of the device boots without stages
+//	error - if an error occurs, otherwise nil
+func (s *DeviceBootService) GetBootList(ctx context.Context, deviceID uuid.UUID, orderBy, orderDirection string,
+	page, pageSize int) (dtos.PaginatedItemsDto[dtos.DeviceBootDto], error) {
+	exist, err := s.deviceRepo.IsExist(ctx, deviceID, nil)
+	if err != nil {
+		return dtos.PaginatedItemsDto[dtos.DeviceBootDto]{}, errors.Internal.Wrap(err, "failed to check existence of the device")
+	}
+	if !exist {
+		return dtos.PaginatedItemsDto[dtos.DeviceBootDto]{}, errors.NotFound.New("device with this id is not found")
+	}
+	return GetListExtended[dtos.DeviceBootDto](ctx, s.bootsRepo, s.getBootsQueryBuilder(ctx, deviceID), orderBy, orderDirection, page, pageSize)
+}
+
+//WaitBoot waits until the running boot of the device is finished
+//
+//Params
+//	ctx - context, waiting is interrupted when it is done
+//	deviceID - device id
+//Return
+//	dtos.DeviceBootDto - current device boot
+//	error - if an error occurs, otherwise nil
+func (s *DeviceBootService) WaitBoot(ctx context.Context, deviceID uuid.UUID) (dtos.DeviceBootDto, error) {
+	s.runsMutex.Lock()
+	run, ok := s.runs[deviceID]
+	s.runsMutex.Unlock()
+	if ok {
+		select {
+		case <-run.done:
+		case <-ctx.Done():
+			return dtos.DeviceBootDto{}, errors.Internal.Wrap(ctx.Err(), "device boot waiting is interrupted")
+		}
+	}
+	return s.GetCurrentBoot(ctx, deviceID)
+}
+
+//CancelBoot cancels the running boot of the device and waits until the current stage is stopped
+//
+//Params
+//	ctx - context
+//	deviceID - device id
+//Return
+//	dtos.DeviceBootDto - cancelled device boot
+//	error - if an error occurs, otherwise nil
+func (s *DeviceBootService) CancelBoot(ctx context.Context, deviceID uuid.UUID) (dtos.DeviceBootDto, error) {
+	s.runsMutex.Lock()
+	run, ok := s.runs[deviceID]
+	if ok {
+		//run is removed from the map by the runner, so cancel channel is closed only once
+		select {
+		case <-run.cancel:
+		default:
+			close(run.cancel)
+		}
+	}
+	s.runsMutex.Unlock()
+	if !ok {
+		return dtos.DeviceBootDto{}, errors.NotFound.New("device has no running boot")
+	}
+	<-run.done
+	return s.GetCurrentBoot(ctx, deviceID)
+}
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"os"
+	"path"
+	"path/filepath"
+	"rol/domain"
+	"rol/dtos"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	bootStageActionFile              = "file"
+	bootStageActionCheckPowerSwitch  = "checkpowerswitch"
+	bootStageActionPowerOff          = "poweroff"
+	bootStageActionEmergencyPowerOff = "emergencypoweroff"
+	bootStageActionPowerOn           = "poweron"
+	bootStageActionEmergencyPowerOn  = "emergencypoweron"
+	bootStageActionCheckManagement   = "checkmanagement"
+)
+
+//deviceBootStageResult result of the executed boot stage
+type deviceBootStageResult struct {
+	state   domain.DeviceBootState
+	message string
+}
+
+func newDeviceBootStageResult(state domain.DeviceBootState, format string, args ...interface{}) deviceBootStageResult {
+	return deviceBootStageResult{state: state, message: fmt.Sprintf(format, args...)}
+}
+
+func (r *deviceBootRun) isCancelled() bool {
+	select {
+	case <-r.cancel:
+		return true
+	default:
+		return false
+	}
+}
+
+//drainEvents drops events that were received before the stage is started
+func (r *deviceBootRun) drainEvents() {
+	for {
+		select {
+		case <-r.events:
+		default:
+			return
+		}
+	}
+}
+
+func (r *deviceBootRun) getStageTimeout() time.Duration {
+	return time.Duration(r.boot.StageTimeout) * time.Second
+}
+
+//normalizeBootStageVirtualPath converts the template virtual file name to the form used by the TFTP server
+func normalizeBootStageVirtualPath(virtualPath string) string {
+	cleaned := path.Clean("/" + strings.ReplaceAll(virtualPath, "\\", "/"))
+	return strings.TrimPrefix(cleaned, "/")
+}
+
+//getBootStageActualPath converts the template existing file name, that is relative from app directory, to the full path
+func getBootStageActualPath(existingFileName string) string {
+	if filepath.IsAbs(existingFileName) {
+		return existingFileName
+	}
+	executablePath, _ := os.Executable()
+	return filepath.Join(filepath.Dir(executablePath), existingFileName)
+}
+
+//getPendingBootStageFiles sorted virtual paths of the files that are not fetched yet
+func getPendingBootStageFiles(pending map[string]bool) []string {
+	files := []string{}
+	for file := range pending {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+//runBoot executes boot stages one by one until one of them is not succeeded
+func (s *DeviceBootService) runBoot(run *deviceBootRun) {
+	ctx := context.Background()
+	defer func() {
+		s.runsMutex.Lock()
+		delete(s.runs, run.boot.DeviceID)
+		s.runsMutex.Unlock()
+		close(run.done)
+	}()
+	result := newDeviceBootStageResult(domain.DeviceBootStateSucceeded, "")
+	powerCycled := false
+	for index, stage := range run.stages {
+		if run.isCancelled() {
+			result = newDeviceBootStageResult(domain.DeviceBootStateCancelled, "boot is cancelled")
+			break
+		}
+		run.boot.StageIndex = index
+		run.boot.StageName = stage.Name
+		s.updateBoot(ctx, run)
+		stageEntity, err := s.stagesRepo.Insert(ctx, domain.DeviceBootStage{
+			DeviceBootID: run.boot.ID,
+			Index:        index,
+			Name:         stage.Name,
+			Action:       stage.Action,
+			State:        domain.DeviceBootStateRunning,
+			StartedAt:    time.Now(),
+		})
+		if err != nil {
+			s.log(ctx, "error", fmt.Sprintf("failed to create device boot stage: %s", err.Error()))
+		}
+		s.log(ctx, "info", fmt.Sprintf("device %s boot stage %d %q is started", run.boot.DeviceID.String(), index, stage.Name))
+		result = s.runStage(ctx, run, stage, &powerCycled)
+		stageEntity.State = result.state
+		stageEntity.Message = result.message
+		stageEntity.FinishedAt = time.Now()
+		if stageEntity.ID != [16]byte{} {
+			if _, err = s.stagesRepo.Update(ctx, stageEntity); err != nil {
+				s.log(ctx, "error", fmt.Sprintf("failed to update device boot stage: %s", err.Error()))
+			}
+		}
+		s.log(ctx, "info", fmt.Sprintf("device %s boot stage %d %q is %s: %s", run.boot.DeviceID.String(), index,
+			stage.Name, result.state.String(), result.message))
+		if result.state != domain.DeviceBootStateSucceeded {
+			break
+		}
+	}
+	run.boot.State = result.state
+	run.boot.FinishedAt = time.Now()
+	if result.state != domain.DeviceBootStateSucceeded {
+		run.boot.Error = result.message
+	}
+	s.updateBoot(ctx, run)
+}
+
+func (s *DeviceBootService) updateBoot(ctx context.Context, run *deviceBootRun) {
+	updated, err := s.bootsRepo.Update(ctx, run.boot)
+	if err != nil {
+		s.log(ctx, "error", fmt.Sprintf("failed to update device boot: %s", err.Error()))
+		return
+	}
+	run.boot = updated
+}
+
+//runStage executes the stage action
+func (s *DeviceBootService) runStage(ctx context.Context, run *deviceBootRun, stage domain.BootStageTemplate, powerCycled *bool) deviceBootStageResult {
+	deviceID := run.boot.DeviceID
+	var err error
+	switch strings.ToLower(stage.Action) {
+	case bootStageActionFile:
+		return s.runFileStage(ctx, run, stage, powerCycled)
+	case bootStageActionCheckManagement:
+		return s.runCheckManagementStage(run)
+	case bootStageActionCheckPowerSwitch:
+		err = s.deviceService.CheckPowerControl(ctx, deviceID)
+	case bootStageActionPowerOff, bootStageActionEmergencyPowerOff:
+		_, err = s.deviceService.PowerOff(ctx, deviceID)
+	case bootStageActionPowerOn, bootStageActionEmergencyPowerOn:
+		_, err = s.deviceService.PowerOn(ctx, deviceID)
+	default:
+		return newDeviceBootStageResult(domain.DeviceBootStateFailed, "unknown stage action %q", stage.Action)
+	}
+	if err != nil {
+		return newDeviceBootStageResult(domain.DeviceBootStateFailed, "%s", err.Error())
+	}
+	return newDeviceBootStageResult(domain.DeviceBootStateSucceeded, "done")
+}
+
+//installStageFiles creates TFTP paths of the stage files for the device net boot mac address
+func (s *DeviceBootService) installStageFiles(ctx context.Context, run *deviceBootRun, stage domain.BootStageTemplate) ([]uuid.UUID, error) {
+	pathsIDs := []uuid.UUID{}
+	for _, file := range stage.Files {
+		pathDto, err := s.tftpService.CreatePath(ctx, run.boot.TFTPConfigID, dtos.TFTPPathCreateDto{
+			TFTPPathBaseDto: dtos.TFTPPathBaseDto{
+				ActualPath:  getBootStageActualPath(file.ExistingFileName),
+				VirtualPath: normalizeBootStageVirtualPath(file.VirtualFileName),
+				ClientMAC:   run.netBootMAC,
+			},
+		})
+		if err != nil {
+			s.removeStageFiles(ctx, run, pathsIDs)
+			return nil, err
+		}
+		pathsIDs = append(pathsIDs, pathDto.ID)
+	}
+	return pathsIDs, nil
+}
+
+func (s *DeviceBootService) removeStageFiles(ctx context.Context, run *deviceBootRun, pathsIDs []uuid.UUID) {
+	for _, id := range pathsIDs {
+		if err := s.tftpService.DeletePath(ctx, run.boot.TFTPConfigID, id); err != nil {
+			s.log(ctx, "error", fmt.Sprintf("failed to remove tftp path of the device boot stage: %s", err.Error()))
+		}
+	}
+}
+
+//runFileStage installs stage files, power cycles the device on the first file stage
+//and waits until all files are fetched by the device
+func (s *DeviceBootService) runFileStage(ctx context.Context, run *deviceBootRun, stage domain.BootStageTemplate, powerCycled *bool) deviceBootStageResult {
+	run.drainEvents()
+	pending := map[string]bool{}
+	for _, file := range stage.Files {
+		pending[normalizeBootStageVirtualPath(file.VirtualFileName)] = true
+	}
+	pathsIDs, err := s.installStageFiles(ctx, run, stage)
+	if err != nil {
+		return newDeviceBootStageResult(domain.DeviceBootStateFailed, "failed to install stage files: %s", err.Error())
+	}
+	defer s.removeStageFiles(ctx, run, pathsIDs)
+	if !*powerCycled {
+		if _, err = s.deviceService.powerCycle(ctx, run.boot.DeviceID); err != nil {
+			return newDeviceBootStageResult(domain.DeviceBootStateFailed, "failed to power cycle device: %s", err.Error())
+		}
+		*powerCycled = true
+	}
+	timer := time.NewTimer(run.getStageTimeout())
+	defer timer.Stop()
+	for len(pending) > 0 {
+		select {
+		case event := <-run.events:
+			if event.Type == domain.BootEventTFTPFetch {
+				delete(pending, event.VirtualPath)
+			}
+		case <-timer.C:
+			return newDeviceBootStageResult(domain.DeviceBootStateFailed, "timeout waiting for files fetch: %s",
+				strings.Join(getPendingBootStageFiles(pending), ", "))
+		case <-run.cancel:
+			return newDeviceBootStageResult(domain.DeviceBootStateCancelled, "boot is cancelled")
+		}
+	}
+	return newDeviceBootStageResult(domain.DeviceBootStateSucceeded, "%d files fetched", len(stage.Files))
+}
+
+//runCheckManagementStage waits for the DHCP request from the device management interface
+func (s *DeviceBootService) runCheckManagementStage(run *deviceBootRun) deviceBootStageResult {
+	timer := time.NewTimer(run.getStageTimeout())
+	defer timer.Stop()
+	for {
+		select {
+		case event := <-run.events:
+			if event.Type == domain.BootEventDHCPRequest && event.ClientMAC == run.managementMAC {
+				return newDeviceBootStageResult(domain.DeviceBootStateSucceeded, "dhcp request from %s", event.ClientMAC)
+			}
+		case <-timer.C:
+			return newDeviceBootStageResult(domain.DeviceBootStateFailed, "timeout waiting for dhcp request from %s", run.managementMAC)
+		case <-run.cancel:
+			return newDeviceBootStageResult(domain.DeviceBootStateCancelled, "boot is cancelled")
+		}
+	}
+}
@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"reflect"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/mappers"
+	"rol/app/validators"
+	"rol/domain"
+	"rol/dtos"
+	"sync"
+)
+
+//DeviceService service structure for Device entity
+type DeviceService struct {
+	deviceRepo      interfaces.IGenericRepository[uuid.UUID, domain.Device]
+	interfaceRepo   interfaces.IGenericRepository[uuid.UUID, domain.DeviceNetworkInterface]
+	switchRepo      interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitch]
+	portRepo        interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitchPort]
+	templateStorage interfaces.IGenericTemplateStorage[domain.DeviceTemplate]
+	managers        interfaces.IEthernetSwitchManagerProvider
+	//powerCycles devices with running power cycle
+	powerCycles map[uuid.UUID]bool
+	//powerCyclesMutex guards power cycles
+	powerCyclesMutex sync.Mutex
+	logger           *logrus.Logger
+	//logSourceName - logger recording source
+	logSourceName string
+}
+
+//NewDeviceService constructor for domain.Device service
+//Params
+//	deviceRepo - device repository
+//	interfaceRepo - device network interface repository
+//	switchRepo - ethernet switch repository
+//	portRepo - ethernet switch port repository
+//	templateStorage - device template storage
+//	managersProvider - ethernet switch managers provider
+//	logger - logrus logger
+//Return
+//	*DeviceService - new device service
+//	error - if an error occurs, otherwise nil
+func NewDeviceService(deviceRepo interfaces.IGenericRepository[uuid.UUID, domain.Device],
+	interfaceRepo interfaces.IGenericRepository[uuid.UUID, domain.DeviceNetworkInterface],
+	switchRepo interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitch],
+	portRepo interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitchPort],
+	templateStorage interfaces.IGenericTemplateStorage[domain.DeviceTemplate],
+	managersProvider interfaces.IEthernetSwitchManagerProvider,
+	logger *logrus.Logger) (*DeviceService, error) {
+	return &DeviceService{
+		deviceRepo:      deviceRepo,
+		interfaceRepo:   interfaceRepo,
+		switchRepo:      switchRepo,
+		portRepo:        portRepo,
+		templateStorage: templateStorage,
+		managers:        managersProvider,
+		powerCycles:     map[uuid.UUID]bool{},
+		logger:          logger,
+		logSourceName:   reflect.TypeOf(DeviceService{}).Name(),
+	}, nil
+}
+
+func (d *DeviceService) log(ctx context.Context, level, message string) {
+	if ctx != nil {
+		actionID := uuid.UUID{}
+		if ctx.Value("requestID") != nil {
+			actionID = ctx.Value("requestID").(uuid.UUID)
+		}
+
+		entry := d.logger.WithFields(logrus.Fields{
+			"actionID": actionID,
+			"source":   d.logSourceName,
+		})
+		switch level {
+		case "err", "error":
+			entry.Error(message)
+		case "info":
+			entry.Info(message)
+		case "warn", "warning":
+			entry.Warn(message)
+		case "debug":
+			entry.Debug(message)
+		}
+	}
+}
+
+func (d *DeviceService) serialIsUnique(ctx context.Context, serial string, id uuid.UUID) (bool, error) {
+	queryBuilder := d.deviceRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("Serial", "==", serial)
+	if [16]byte{} != id {
+		queryBuilder.Where("ID", "!=", id)
+	}
+	count, err := d.deviceRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return false, errors.Internal.Wrap(err, "failed to count devices in repository")
+	}
+	return count == 0, nil
+}
+
+func (d *DeviceService) serialUniquenessCheck(ctx context.Context, serial string, id uuid.UUID) error {
+	uniqSerial, err := d.serialIsUnique(ctx, serial, id)
+	if err != nil {
+		return errors.Internal.Wrap(err, "error occurred while checking uniqueness of the device serial")
+	}
+	if !uniqSerial {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return errors.AddErrorContext(err, "Serial", "device with this serial number already exist")
+	}
+	return nil
+}
+
+func (d *DeviceService) getTemplate(ctx context.Context, templateName string) (domain.DeviceTemplate, error) {
+	template, err := d.templateStorage.GetByName(ctx, templateName)
+	if err != nil {
+		if errors.As(err, errors.NotFound) {
+			err = errors.Validation.New(errors.ValidationErrorMessage)
+			return template, errors.AddErrorContext(err, "DeviceTemplate", "device template not found")
+		}
+		return template, errors.Internal.Wrap(err, "failed to get device template from storage")
+	}
+	return template, nil
+}
+
+func (d *DeviceService) createDtoInterfacesValidation(ctx context.Context, template domain.DeviceTemplate,
+	createDto dtos.DeviceCreateDto) error {
+	var validationErr error
+	addValidationErr := func(key, value string) {
+		if validationErr == nil {
+			validationErr = errors.Validation.New(errors.ValidationErrorMessage)
+		}
+		validationErr = errors.AddErrorContext(validationErr, key, value)
+	}
+	names := map[string]bool{}
+	macs := map[string]bool{}
+	ports := map[uuid.UUID]bool{}
+	for i, networkInterface := range createDto.NetworkInterfaces {
+		keyPrefix := fmt.Sprintf("NetworkInterfaces[%d].", i)
+		if !templateHasNetworkInterface(template, networkInterface.Name) {
+			addValidationErr(keyPrefix+"Name", "device template has no network interface with this name")
+		}
+		if names[networkInterface.Name] {
+			addValidationErr(keyPrefix+"Name", "network interface name must be unique within device")
+		}
+		names[networkInterface.Name] = true
+		if networkInterface.MAC != "" {
+			if macs[networkInterface.MAC] {
+				addValidationErr(keyPrefix+"MAC", "mac address must be unique within device")
+			}
+			macs[networkInterface.MAC] = true
+		}
+		if networkInterface.EthernetSwitchPortID != [16]byte{} {
+			if ports[networkInterface.EthernetSwitchPortID] {
+				addValidationErr(keyPrefix+"EthernetSwitchPortID", "ethernet switch port must be unique within device")
+			}
+			ports[networkInterface.EthernetSwitchPortID] = true
+		}
+		err := d.networkInterfaceDataValidation(ctx, networkInterface.DeviceNetworkInterfaceBaseDto, [16]byte{})
+		if err != nil {
+			if !errors.As(err, errors.Validation) {
+				return err
+			}
+			for key, value := range errors.GetErrorContext(err) {
+				addValidationErr(keyPrefix+key, value)
+			}
+		}
+	}
+	return validationErr
+}
+
+func templateHasNetworkInterface(template domain.DeviceTemplate, name string) bool {
+	for _, templateInterface := range template.NetworkInterfaces {
+		if templateInterface.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func (d *DeviceService) fillDeviceDtoNetworkInterfaces(ctx context.Context, dto *dtos.DeviceDto) error {
+	networkInterfaces, err := d.getAllDeviceNetworkInterfaces(ctx, dto.ID)
+	if err != nil {
+		return err
+	}
+	dto.NetworkInterfaces = make([]dtos.DeviceNetworkInterfaceDto, len(networkInterfaces))
+	for i, networkInterface := range networkInterfaces {
+		mappers.MapDeviceNetworkInterfaceToDto(networkInterface, &dto.NetworkInterfaces[i])
+	}
+	return nil
+}
+
+//GetList Get list of devices with filtering and pagination
+//Params
+//	ctx - context is used only for logging
+//	search - string for search in entity string fields
+//	orderBy - order by entity field name
+//	orderDirection - ascending or descending order
+//	page - page number
+//	pageSize - page size
+//Return
+//	dtos.PaginatedItemsDto[dtos.DeviceDto] - paginated list of devices
+//	error - if an error occurs, otherwise nil
+func (d *DeviceService) GetList(ctx context.Context, search, orderBy, orderDirection string, page, pageSize int) (dtos.PaginatedItemsDto[dtos.DeviceDto], error) {
+	paginatedDtos, err := GetList[dtos.DeviceDto](ctx, d.deviceRepo, search, orderBy, orderDirection, page, pageSize)
+	if err != nil {
+		return paginatedDtos, err
+	}
+	for i := range paginatedDtos.Items {
+		err = d.fillDeviceDtoNetworkInterfaces(ctx, &paginatedDtos.Items[i])
+		if err != nil {
+			return paginatedDtos, errors.Internal.Wrap(err, "failed to get device network interfaces")
+		}
+	}
+	return paginatedDtos, nil
+}
+
+//GetByID Get device by ID
+//Params
+//	ctx - context is used only for logging
+//	id - device id
+//Return
+//	dtos.DeviceDto - device dto
+//	error - if an error occurs, otherwise nil
+func (d *DeviceService) GetByID(ctx context.Context, id uuid.UUID) (dtos.DeviceDto, error) {
+	dto, err := GetByID[dtos.DeviceDto](ctx, d.deviceRepo, id, nil)
+	if err != nil {
+		return dto, err
+	}
+	err = d.fillDeviceDtoNetworkInterfaces(ctx, &dto)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to get device network interfaces")
+	}
+	return dto, nil
+}
+
+//Create instantiate new device from the device template
+//Params
+//	ctx - context
+//	createDto - device create dto
+//Return
+//	dtos.DeviceDto - created device
+//	error - if an error occurs, otherwise nil
+func (d *DeviceService) Create(ctx context.Context, createDto dtos.DeviceCreateDto) (dtos.DeviceDto, error) {
+	dto := dtos.DeviceDto{}
+	err := validators.ValidateDeviceCreateDto(createDto)
+	if err != nil {
+		return dto, err
+	}
+	createDto.NetworkInterfaces = append([]dtos.DeviceNetworkInterfaceCreateDto{}, createDto.NetworkInterfaces...)
+	for i := range createDto.NetworkInterfaces {
+		createDto.NetworkInterfaces[i].MAC = normalizeMAC(createDto.NetworkInterfaces[i].MAC)
+	}
+	template, err := d.getTemplate(ctx, createDto.DeviceTemplate)
+	if err != nil {
+		return dto, err
+	}
+	err = d.serialUniquenessCheck(ctx, createDto.Serial, [16]byte{})
+	if err != nil {
+		return dto, err
+	}
+	err = d.createDtoInterfacesValidation(ctx, template, createDto)
+	if err != nil {
+		return dto, err
+	}
+	entity := domain.Device{}
+	err = mappers.MapDtoToEntity(createDto, &entity)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "error map dto to entity")
+	}
+	device, err := d.deviceRepo.Insert(ctx, entity)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to insert device to repository")
+	}
+	err = d.createDeviceNetworkInterfaces(ctx, device.ID, template, createDto.NetworkInterfaces)
+	if err != nil {
+		//remove partially created device, otherwise it blocks creation retry with the same serial
+		if deleteErr := d.Delete(ctx, device.ID); deleteErr != nil {
+			return dto, errors.Internal.Wrapf(err, "failed to remove partially created device: %s", deleteErr.Error())
+		}
+		return dto, err
+	}
+	return d.GetByID(ctx, device.ID)
+}
+
+//Update save the changes to the existing device
+//Params
+//	ctx - context is used only for logging
+//	updateDto - device update dto
+//	id - device id
+//Return
+//	dtos.DeviceDto - updated device
+//	error - if an error occurs, otherwise nil
+func (d *DeviceService) Update(ctx context.Context, updateDto dtos.DeviceUpdateDto, id uuid.UUID) (dtos.DeviceDto, error) {
+	dto := dtos.DeviceDto{}
+	err := validators.ValidateDeviceUpdateDto(updateDto)
+	if err != nil {
+		return dto, err
+	}
+	err = d.serialUniquenessCheck(ctx, updateDto.Serial, id)
+	if err != nil {
+		return dto, err
+	}
+	dto, err = Update[dtos.DeviceDto](ctx, d.deviceRepo, updateDto, id, nil)
+	if err != nil {
+		return dto, err
+	}
+	err = d.fillDeviceDtoNetworkInterfaces(ctx, &dto)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to get device network interfaces")
+	}
+	return dto, nil
+}
+
+//Delete mark device and its network interfaces as deleted
+//Params
+//	ctx - context is used only for logging
+//	id - device id
+//Return
+//	error - if an error occurs, otherwise nil
+func (d *DeviceService) Delete(ctx context.Context, id uuid.UUID) error {
+	_, err := d.deviceRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = d.deleteAllNetworkInterfacesByDeviceID(ctx, id)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to remove device network interfaces")
+	}
+	err = d.deviceRepo.Delete(ctx, id)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to delete entity from repository")
+	}
+	return nil
+}
@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"net"
+	"rol/app/errors"
+	"rol/app/mappers"
+	"rol/app/validators"
+	"rol/domain"
+	"rol/dtos"
+)
+
+func (d *DeviceService) getAllDeviceNetworkInterfaces(ctx context.Context, deviceID uuid.UUID) ([]domain.DeviceNetworkInterface, error) {
+	queryBuilder := d.interfaceRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DeviceID", "==", deviceID)
+	count, err := d.interfaceRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to count device network interfaces")
+	}
+	networkInterfaces, err := d.interfaceRepo.GetList(ctx, "Name", "asc", 1, count, queryBuilder)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to get device network interfaces list")
+	}
+	return networkInterfaces, nil
+}
+
+//createDeviceNetworkInterfaces creates device network interfaces for all template interfaces,
+//interfaces that are not described in create dto are created only with the name
+func (d *DeviceService) createDeviceNetworkInterfaces(ctx context.Context, deviceID uuid.UUID, template domain.DeviceTemplate, createDtos []dtos.DeviceNetworkInterfaceCreateDto) error {
+	for _, templateInterface := range template.NetworkInterfaces {
+		interfaceCreateDto := dtos.DeviceNetworkInterfaceCreateDto{Name: templateInterface.Name}
+		for _, networkInterface := range createDtos {
+			if networkInterface.Name == templateInterface.Name {
+				interfaceCreateDto = networkInterface
+			}
+		}
+		interfaceEntity := domain.DeviceNetworkInterface{}
+		err := mappers.MapDtoToEntity(interfaceCreateDto, &interfaceEntity)
+		if err != nil {
+			return errors.Internal.Wrap(err, "error map dto to entity")
+		}
+		interfaceEntity.DeviceID = deviceID
+		_, err = d.interfaceRepo.Insert(ctx, interfaceEntity)
+		if err != nil {
+			return errors.Internal.Wrap(err, "failed to insert device network interface to repository")
+		}
+	}
+	return nil
+}
+
+func (d *DeviceService) deleteAllNetworkInterfacesByDeviceID(ctx context.Context, deviceID uuid.UUID) error {
+	queryBuilder := d.interfaceRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DeviceID", "==", deviceID)
+	err := d.interfaceRepo.DeleteAll(ctx, queryBuilder)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to delete device network interfaces")
+	}
+	return nil
+}
+
+func (d *DeviceService) deviceExistenceCheck(ctx context.Context, deviceID uuid.UUID) error {
+	exist, err := d.deviceRepo.IsExist(ctx, deviceID, nil)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to check device existence")
+	}
+	if !exist {
+		return errors.NotFound.New("device not found")
+	}
+	return nil
+}
+
+//normalizeMAC converts mac address to the lower case form that is stored in the repository,
+//empty or unparsable value is returned as is
+func normalizeMAC(mac string) string {
+	hwAddr, err := net.ParseMAC(mac)
+	if err != nil {
+		return mac
+	}
+	return hwAddr.String()
+}
+
+func (d *DeviceService) macIsUnique(ctx context.Context, mac string, interfaceID uuid.UUID) (bool, error) {
+	queryBuilder := d.interfaceRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("MAC", "==", normalizeMAC(mac))
+	if [16]byte{} != interfaceID {
+		queryBuilder.Where("ID", "!=", interfaceID)
+	}
+	count, err := d.interfaceRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return false, errors.Internal.Wrap(err, "failed to count device network interfaces")
+	}
+	return count == 0, nil
+}
+
+func (d *DeviceService) portIsFree(ctx context.Context, portID, interfaceID uuid.UUID) (bool, error) {
+	queryBuilder := d.interfaceRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("EthernetSwitchPortID", "==", portID)
+	if [16]byte{} != interfaceID {
+		queryBuilder.Where("ID", "!=", interfaceID)
+	}
+	count, err := d.interfaceRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return false, errors.Internal.Wrap(err, "failed to count device network interfaces")
+	}
+	return count == 0, nil
+}
+
+func (d *DeviceService) switchPortValidation(ctx context.Context, switchID, portID uuid.UUID) (string, string, error) {
+	if switchID == [16]byte{} && portID == [16]byte{} {
+		return "", "", nil
+	}
+	if switchID == [16]byte{} {
+		return "EthernetSwitchID", "ethernet switch must be specified together with the port", nil
+	}
+	if portID == [16]byte{} {
+		return "EthernetSwitchPortID", "ethernet switch port must be specified together with the switch", nil
+	}
+	switchExist, err := d.switchRepo.IsExist(ctx, switchID, nil)
+	if err != nil {
+		return "", "", errors.Internal.Wrap(err, "failed to check ethernet switch existence")
+	}
+	if !switchExist {
+		return "EthernetSwitchID", "ethernet switch not found", nil
+	}
+	queryBuilder := d.portRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("EthernetSwitchID", "==", switchID)
+	portExist, err := d.portRepo.IsExist(ctx, portID, queryBuilder)
+	if err != nil {
+		return "", "", errors.Internal.Wrap(err, "failed to check ethernet switch port existence")
+	}
+	if !portExist {
+		return "EthernetSwitchPortID", "ethernet switch port not found on this switch", nil
+	}
+	return "", "", nil
+}
+
+//networkInterfaceDataValidation checks mac address uniqueness and that the cabled switch and port exist
+func (d *DeviceService) networkInterfaceDataValidation(ctx context.Context, baseDto dtos.DeviceNetworkInterfaceBaseDto, interfaceID uuid.UUID) error {
+	var validationErr error
+	addValidationErr := func(key, value string) {
+		if validationErr == nil {
+			validationErr = errors.Validation.New(errors.ValidationErrorMessage)
+		}
+		validationErr = errors.AddErrorContext(validationErr, key, value)
+	}
+	if baseDto.MAC != "" {
+		uniqMac, err := d.macIsUnique(ctx, baseDto.MAC, interfaceID)
+		if err != nil {
+			return err
+		}
+		if !uniqMac {
+			addValidationErr("MAC", "network interface with this mac address already exist")
+		}
+	}
+	field, message, err := d.switchPortValidation(ctx, baseDto.EthernetSwitchID, baseDto.EthernetSwitchPortID)
+	if err != nil {
+		return err
+	}
+	if field != "" {
+		addValidationErr(field, message)
+	} else if baseDto.EthernetSwitchPortID != [16]byte{} {
+		portFree, err := d.portIsFree(ctx, baseDto.EthernetSwitchPortID, interfaceID)
+		if err != nil {
+			return err
+		}
+		if !portFree {
+			addValidationErr("EthernetSwitchPortID", "ethernet switch port is already cabled to another network interface")
+		}
+	}
+	return validationErr
+}
+
+//GetNetworkInterfaces Get list of device network interfaces with pagination
+//Params
+//	ctx - context is used only for logging
+//	deviceID - device ID
+//	orderBy - order by entity field name
+//	orderDirection - ascending or descending order
+//	page - page number
+//	pageSize - page size
+//Return
+//	dtos.PaginatedItemsDto[dtos.DeviceNetworkInterfaceDto] - paginated list of device network interfaces
+//	error - if an error occurs, otherwise nil
+func (d *DeviceService) GetNetworkInterfaces(ctx context.Context, deviceID uuid.UUID, orderBy, orderDirection string,
+	page, pageSize int) (dtos.PaginatedItemsDto[dtos.DeviceNetworkInterfaceDto], error) {
+	err := d.deviceExistenceCheck(ctx, deviceID)
+	if err != nil {
+		return dtos.NewEmptyPaginatedItemsDto[dtos.DeviceNetworkInterfaceDto](), err
+	}
+	queryBuilder := d.interfaceRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DeviceID", "==", deviceID)
+	return GetListExtended[dtos.DeviceNetworkInterfaceDto](ctx, d.interfaceRepo, queryBuilder, orderBy, orderDirection, page, pageSize)
+}
+
+//GetNetworkInterfaceByID Get device network interface by device ID and interface ID
+//Params
+//	ctx - context is used only for logging
+//	deviceID - device ID
+//	id - network interface ID
+//Return
+//	dtos.DeviceNetworkInterfaceDto - device network interface dto
+//	error - if an error occurs, otherwise nil
+func (d *DeviceService) GetNetworkInterfaceByID(ctx context.Context, deviceID, id uuid.UUID) (dtos.DeviceNetworkInterfaceDto, error) {
+	err := d.deviceExistenceCheck(ctx, deviceID)
+	if err != nil {
+		return dtos.DeviceNetworkInterfaceDto{}, err
+	}
+	queryBuilder := d.interfaceRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DeviceID", "==", deviceID)
+	return GetByID[dtos.DeviceNetworkInterfaceDto](ctx, d.interfaceRepo, id, queryBuilder)
+}
+
+//UpdateNetworkInterface update device network interface mac address and cabling
+//Params
+//	ctx - context is used only for logging
+//	deviceID - device ID
+//	id - network interface ID
+//	updateDto - device network interface update dto
+//Return
+//	dtos.DeviceNetworkInterfaceDto - updated device network interface dto
+//	error - if an error occurs, otherwise nil
+func (d *DeviceService) UpdateNetworkInterface(ctx context.Context, deviceID, id uuid.UUID,
+	updateDto dtos.DeviceNetworkInterfaceUpdateDto) (dtos.DeviceNetworkInterfaceDto, error) {
+	dto := dtos.DeviceNetworkInterfaceDto{}
+	err := validators.ValidateDeviceNetworkInterfaceUpdateDto(updateDto)
+	if err != nil {
+		return dto, err
+	}
+	updateDto.MAC = normalizeMAC(updateDto.MAC)
+	dto, err = d.GetNetworkInterfaceByID(ctx, deviceID, id)
+	if err != nil {
+		return dto, err
+	}
+	err = d.networkInterfaceDataValidation(ctx, updateDto.DeviceNetworkInterfaceBaseDto, id)
+	if err != nil {
+		return dto, err
+	}
+	queryBuilder := d.interfaceRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DeviceID", "==", deviceID)
+	return Update[dtos.DeviceNetworkInterfaceDto](ctx, d.interfaceRepo, updateDto, id, queryBuilder)
+}
@@ -0,0 +1,283 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/domain"
+	"rol/dtos"
+	"strings"
+	"time"
+)
+
+const (
+	//devicePowerControlPOE template power control type for devices powered over ethernet
+	devicePowerControlPOE = "POE"
+	//devicePowerCycleDelay delay between power off and power on during device power cycle
+	devicePowerCycleDelay = 3 * time.Second
+	//devicePowerOnAttempts number of power on attempts at the end of the device power cycle
+	devicePowerOnAttempts = 3
+)
+
+//getDevicePOEPort resolves the switch port that powers the device through its POEIn network interface
+func (d *DeviceService) getDevicePOEPort(ctx context.Context, device domain.Device) (domain.EthernetSwitchPort, error) {
+	port := domain.EthernetSwitchPort{}
+	template, err := d.templateStorage.GetByName(ctx, device.DeviceTemplate)
+	if err != nil {
+		return port, errors.Internal.Wrap(err, "failed to get device template from storage")
+	}
+	if !strings.EqualFold(template.Control.Power, devicePowerControlPOE) {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return port, errors.AddErrorContext(err, "DeviceTemplate", "device template does not declare POE power control")
+	}
+	poeInterfaceName := ""
+	for _, templateInterface := range template.NetworkInterfaces {
+		if templateInterface.POEIn {
+			poeInterfaceName = templateInterface.Name
+			break
+		}
+	}
+	if poeInterfaceName == "" {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return port, errors.AddErrorContext(err, "DeviceTemplate", "device template has no POEIn network interface")
+	}
+	queryBuilder := d.interfaceRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DeviceID", "==", device.ID)
+	queryBuilder.Where("Name", "==", poeInterfaceName)
+	networkInterfaces, err := d.interfaceRepo.GetList(ctx, "", "", 1, 1, queryBuilder)
+	if err != nil {
+		return port, errors.Internal.Wrap(err, "failed to get device network interfaces list")
+	}
+	if len(networkInterfaces) == 0 || networkInterfaces[0].EthernetSwitchPortID == [16]byte{} {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return port, errors.AddErrorContext(err, "NetworkInterfaces", "POEIn network interface is not cabled to ethernet switch port")
+	}
+	port, err = d.portRepo.GetByID(ctx, networkInterfaces[0].EthernetSwitchPortID)
+	if err != nil {
+		return port, errors.Internal.Wrap(err, "failed to get ethernet switch port from repository")
+	}
+	if port.POEType == "none" {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return port, errors.AddErrorContext(err, "NetworkInterfaces", "cabled ethernet switch port does not support POE")
+	}
+	return port, nil
+}
+
+//getDevicePOEManager gets POE manager of the switch that powers the device
+//
+//Return
+//	interfaces.IEthernetSwitchPOEManager - switch POE manager
+//	error - validation error if the switch has no manager or it does not support POE
+func (d *DeviceService) getDevicePOEManager(ctx context.Context, port domain.EthernetSwitchPort) (interfaces.IEthernetSwitchPOEManager, error) {
+	switchManager, err := d.managers.Get(ctx, port.EthernetSwitchID)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "can't get ethernet switch manager")
+	}
+	if switchManager == nil {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return nil, errors.AddErrorContext(err, "NetworkInterfaces", "switch of the device has no manager, power can't be controlled")
+	}
+	poeManager, ok := switchManager.(interfaces.IEthernetSwitchPOEManager)
+	if !ok {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return nil, errors.AddErrorContext(err, "NetworkInterfaces", "ethernet switch does not support POE")
+	}
+	return poeManager, nil
+}
+
+func (d *DeviceService) setPOEPortStatus(ctx context.Context, port domain.EthernetSwitchPort, enabled bool) error {
+	poeManager, err := d.getDevicePOEManager(ctx, port)
+	if err != nil {
+		return err
+	}
+	if enabled {
+		err = poeManager.EnablePOEPort(port.Name, port.POEType)
+		if err != nil {
+			return errors.Internal.Wrap(err, "enable poe on port failed")
+		}
+	} else {
+		err = poeManager.DisablePOEPort(port.Name)
+		if err != nil {
+			return errors.Internal.Wrap(err, "disable poe on port failed")
+		}
+	}
+	port.POEEnabled = enabled
+	_, err = d.portRepo.Update(ctx, port)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to update ethernet switch port in repository")
+	}
+	return nil
+}
+
+func (d *DeviceService) setPowerState(ctx context.Context, id uuid.UUID, powerState domain.DevicePowerState) (dtos.DeviceDto, error) {
+	device, err := d.deviceRepo.GetByID(ctx, id)
+	if err != nil {
+		return dtos.DeviceDto{}, err
+	}
+	port, err := d.getDevicePOEPort(ctx, device)
+	if err != nil {
+		return dtos.DeviceDto{}, err
+	}
+	if powerState == domain.DevicePowerStateOn {
+		err = d.setPOEPortStatus(ctx, port, true)
+	} else {
+		err = d.setPOEPortStatus(ctx, port, false)
+	}
+	if err != nil {
+		return dtos.DeviceDto{}, err
+	}
+	device.PowerState = powerState
+	_, err = d.deviceRepo.Update(ctx, device)
+	if err != nil {
+		return dtos.DeviceDto{}, errors.Internal.Wrap(err, "failed to update device power state in repository")
+	}
+	return d.GetByID(ctx, id)
+}
+
+func newPowerCycleInProgressError() error {
+	err := errors.Validation.New(errors.ValidationErrorMessage)
+	return errors.AddErrorContext(err, "PowerState", "device power cycle is in progress")
+}
+
+//lockPowerCycle marks device power cycle as running
+//
+//Return
+//	error - validation error if power cycle of the device is already running
+func (d *DeviceService) lockPowerCycle(id uuid.UUID) error {
+	d.powerCyclesMutex.Lock()
+	defer d.powerCyclesMutex.Unlock()
+	if d.powerCycles[id] {
+		return newPowerCycleInProgressError()
+	}
+	d.powerCycles[id] = true
+	return nil
+}
+
+func (d *DeviceService) unlockPowerCycle(id uuid.UUID) {
+	d.powerCyclesMutex.Lock()
+	defer d.powerCyclesMutex.Unlock()
+	delete(d.powerCycles, id)
+}
+
+//powerCycleCheck checks that power of the device is not changed by the running power cycle
+func (d *DeviceService) powerCycleCheck(id uuid.UUID) error {
+	d.powerCyclesMutex.Lock()
+	defer d.powerCyclesMutex.Unlock()
+	if d.powerCycles[id] {
+		return newPowerCycleInProgressError()
+	}
+	return nil
+}
+
+//powerOnAfterCycle powers on the device after the power cycle delay, failed power on is retried
+//and the device is left powered off if all attempts fail
+func (d *DeviceService) powerOnAfterCycle(ctx context.Context, id uuid.UUID) (dtos.DeviceDto, error) {
+	var err error
+	for attempt := 1; attempt <= devicePowerOnAttempts; attempt++ {
+		time.Sleep(devicePowerCycleDelay)
+		dto, powerErr := d.setPowerState(ctx, id, domain.DevicePowerStateOn)
+		if powerErr == nil {
+			return dto, nil
+		}
+		err = powerErr
+		d.log(ctx, "warning", fmt.Sprintf("device %s power on attempt %d of %d failed: %s",
+			id, attempt, devicePowerOnAttempts, err.Error()))
+	}
+	return dtos.DeviceDto{}, errors.Internal.Wrap(err, "device is left powered off, power on failed")
+}
+
+//powerCycle power off the device and power it on again, returns when the device is powered on
+func (d *DeviceService) powerCycle(ctx context.Context, id uuid.UUID) (dtos.DeviceDto, error) {
+	err := d.lockPowerCycle(id)
+	if err != nil {
+		return dtos.DeviceDto{}, err
+	}
+	defer d.unlockPowerCycle(id)
+	_, err = d.setPowerState(ctx, id, domain.DevicePowerStateOff)
+	if err != nil {
+		return dtos.DeviceDto{}, err
+	}
+	return d.powerOnAfterCycle(ctx, id)
+}
+
+//PowerOn power on the device by enabling POE on the switch port cabled to its POEIn network interface
+//Params
+//	ctx - context is used only for logging
+//	id - device id
+//Return
+//	dtos.DeviceDto - device dto with new power state
+//	error - if an error occurs, otherwise nil
+func (d *DeviceService) PowerOn(ctx context.Context, id uuid.UUID) (dtos.DeviceDto, error) {
+	err := d.powerCycleCheck(id)
+	if err != nil {
+		return dtos.DeviceDto{}, err
+	}
+	return d.setPowerState(ctx, id, domain.DevicePowerStateOn)
+}
+
+//PowerOff power off the device by disabling POE on the switch port cabled to its POEIn network interface
+//Params
+//	ctx - context is used only for logging
+//	id - device id
+//Return
+//	dtos.DeviceDto - device dto with new power state
+//	error - if an error occurs, otherwise nil
+func (d *DeviceService) PowerOff(ctx context.Context, id uuid.UUID) (dtos.DeviceDto, error) {
+	err := d.powerCycleCheck(id)
+	if err != nil {
+		return dtos.DeviceDto{}, err
+	}
+	return d.setPowerState(ctx, id, domain.DevicePowerStateOff)
+}
+
+//PowerCycle power off the device and power it on again in background after a delay, failed power on is retried.
+//Device power state is changed to on when it is powered on, it stays off if all power on attempts fail
+//Params
+//	ctx - context is used only for logging
+//	id - device id
+//Return
+//	dtos.DeviceDto - device dto with off power state
+//	error - if an error occurs, otherwise nil
+func (d *DeviceService) PowerCycle(ctx context.Context, id uuid.UUID) (dtos.DeviceDto, error) {
+	err := d.lockPowerCycle(id)
+	if err != nil {
+		return dtos.DeviceDto{}, err
+	}
+	dto, err := d.setPowerState(ctx, id, domain.DevicePowerStateOff)
+	if err != nil {
+		d.unlockPowerCycle(id)
+		return dto, err
+	}
+	go func() {
+		defer d.unlockPowerCycle(id)
+		//request context is not used, because the request is finished before the device is powered on
+		powerCtx := context.Background()
+		_, powerErr := d.powerOnAfterCycle(powerCtx, id)
+		if powerErr != nil {
+			d.log(powerCtx, "error", fmt.Sprintf("device %s power cycle failed: %s", id, powerErr.Error()))
+		}
+	}()
+	return dto, nil
+}
+
+//CheckPowerControl checks that the device power can be controlled: POE switch port is resolved
+//and ethernet switch manager is available
+//Params
+//	ctx - context is used only for logging
+//	id - device id
+//Return
+//	error - if power can't be controlled, otherwise nil
+func (d *DeviceService) CheckPowerControl(ctx context.Context, id uuid.UUID) error {
+	device, err := d.deviceRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	port, err := d.getDevicePOEPort(ctx, device)
+	if err != nil {
+		return err
+	}
+	_, err = d.getDevicePOEManager(ctx, port)
+	return err
+}
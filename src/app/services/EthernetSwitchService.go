@@ -10,15 +10,31 @@ import (
 	"rol/app/validators"
 	"rol/domain"
 	"rol/dtos"
+	"sync"
 )
 
 //EthernetSwitchService service structure for EthernetSwitch entity
 type EthernetSwitchService struct {
-	switchRepo    interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitch]
-	portRepo      interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitchPort]
-	vlanRepo      interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitchVLAN]
-	supportedList *[]domain.EthernetSwitchModel
+	switchRepo interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitch]
+	portRepo   interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitchPort]
+	vlanRepo   interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitchVLAN]
+	backupRepo interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitchConfigBackup]
+	//leaseRepo DHCP v4 leases, they are matched with MAC addresses learned on the switch ports
+	leaseRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease]
+	//interfaceRepo devices network interfaces, they are matched with MAC addresses learned on the switch ports
+	interfaceRepo interfaces.IGenericRepository[uuid.UUID, domain.DeviceNetworkInterface]
+	drivers       interfaces.IEthernetSwitchDriverRegistry
 	managers      interfaces.IEthernetSwitchManagerProvider
+	//driftReports last detected drift of the switches by switch ID
+	driftReports map[uuid.UUID]domain.EthernetSwitchDrift
+	//driftMutex guards drift reports
+	driftMutex sync.RWMutex
+	//backupMutex guards versions of the config backups
+	backupMutex sync.Mutex
+	//capabilities last read capabilities of the switches by switch ID, they are returned with the switch dto
+	capabilities map[uuid.UUID]domain.EthernetSwitchCapabilities
+	//capabilitiesMutex guards capabilities
+	capabilitiesMutex sync.RWMutex
 }
 
 //NewEthernetSwitchService constructor for domain.EthernetSwitch service
@@ -30,42 +46,42 @@ type EthernetSwitchService struct {
 func NewEthernetSwitchService(switchRepo interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitch],
 	portRepo interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitchPort],
 	vlanRepo interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitchVLAN],
-	managersProvider interfaces.IEthernetSwitchManagerProvider) (*EthernetSwitchService, error) {
+	backupRepo interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitchConfigBackup],
+	leaseRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease],
+	interfaceRepo interfaces.IGenericRepository[uuid.UUID, domain.DeviceNetworkInterface],
+	managersProvider interfaces.IEthernetSwitchManagerProvider,
+	drivers interfaces.IEthernetSwitchDriverRegistry) (*EthernetSwitchService, error) {
 	ethernetSwitchService := &EthernetSwitchService{
 		switchRepo:    switchRepo,
 		portRepo:      portRepo,
 		vlanRepo:      vlanRepo,
-		supportedList: &[]domain.EthernetSwitchModel{},
+		backupRepo:    backupRepo,
+		leaseRepo:     leaseRepo,
+		interfaceRepo: interfaceRepo,
+		drivers:       drivers,
 		managers:      managersProvider,
+		driftReports:  map[uuid.UUID]domain.EthernetSwitchDrift{},
+		capabilities:  map[uuid.UUID]domain.EthernetSwitchCapabilities{},
 	}
 	return ethernetSwitchService, nil
 }
 
-//EthernetSwitchServiceInit do all that we need to do after dependency init
-func EthernetSwitchServiceInit(service *EthernetSwitchService) error {
-	service.initSupportedList()
-	return nil
-}
-
-func (e *EthernetSwitchService) initSupportedList() {
-
-	//Ubiquity UniFi Switch US-24-250W
-	ubiquityUnifiSwitchUs24250W := domain.EthernetSwitchModel{
-		Model:        "UniFi Switch US-24-250W",
-		Manufacturer: "Ubiquity",
-		Code:         "unifi_switch_us-24-250w",
+//modelRequirementsCheck checks that the switch has credentials required by its model manager
+func (e *EthernetSwitchService) modelRequirementsCheck(model, snmpVersion string) error {
+	requiresSNMP, err := e.drivers.RequiresSNMP(model)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to get switch model requirements")
+	}
+	if requiresSNMP && snmpVersion == "" {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return errors.AddErrorContext(err, "SNMPVersion", "SNMP version is required for this model")
 	}
-	*e.supportedList = append(*e.supportedList, ubiquityUnifiSwitchUs24250W)
+	return nil
 }
 
 func (e *EthernetSwitchService) modelIsSupported(model string) bool {
-	modelIsSupported := false
-	for _, supportedModel := range *e.supportedList {
-		if model == supportedModel.Code {
-			modelIsSupported = true
-		}
-	}
-	return modelIsSupported
+	_, err := e.drivers.GetModel(model)
+	return err == nil
 }
 
 func (e *EthernetSwitchService) serialIsUnique(serial string, id uuid.UUID) asynctask.AsyncFunc[bool] {
@@ -144,7 +160,14 @@ func (e *EthernetSwitchService) switchUniquenessCheck(ctx context.Context, addre
 //	dtos.PaginatedItemsDto[dtos.EthernetSwitchDto] - pointer to paginated list of ethernet switches
 //	error - if an error occurs, otherwise nil
 func (e *EthernetSwitchService) GetList(ctx context.Context, search, orderBy, orderDirection string, page, pageSize int) (dtos.PaginatedItemsDto[dtos.EthernetSwitchDto], error) {
-	return GetList[dtos.EthernetSwitchDto](ctx, e.switchRepo, search, orderBy, orderDirection, page, pageSize)
+	paginatedDto, err := GetList[dtos.EthernetSwitchDto](ctx, e.switchRepo, search, orderBy, orderDirection, page, pageSize)
+	if err != nil {
+		return paginatedDto, err
+	}
+	for i := range paginatedDto.Items {
+		paginatedDto.Items[i].Capabilities = e.getCachedCapabilitiesDto(paginatedDto.Items[i].ID)
+	}
+	return paginatedDto, nil
 }
 
 //GetByID Get ethernet switch by ID
@@ -155,7 +178,12 @@ func (e *EthernetSwitchService) GetList(ctx context.Context, search, orderBy, or
 //	dtos.EthernetSwitchDto - point to ethernet switch dto
 //	error - if an error occurs, otherwise nil
 func (e *EthernetSwitchService) GetByID(ctx context.Context, id uuid.UUID) (dtos.EthernetSwitchDto, error) {
-	return GetByID[dtos.EthernetSwitchDto](ctx, e.switchRepo, id, nil)
+	dto, err := GetByID[dtos.EthernetSwitchDto](ctx, e.switchRepo, id, nil)
+	if err != nil {
+		return dto, err
+	}
+	dto.Capabilities = e.getCachedCapabilitiesDto(id)
+	return dto, nil
 }
 
 //Update save the changes to the existing ethernet switch
@@ -176,11 +204,21 @@ func (e *EthernetSwitchService) Update(ctx context.Context, updateDto dtos.Ether
 		err = errors.Validation.New(errors.ValidationErrorMessage)
 		return dto, errors.AddErrorContext(err, "SwitchModel", "this model is not supported")
 	}
+	err = e.modelRequirementsCheck(updateDto.SwitchModel, updateDto.SNMPVersion)
+	if err != nil {
+		return dto, err
+	}
 	err = e.switchUniquenessCheck(ctx, updateDto.Address, updateDto.Serial, id)
 	if err != nil {
 		return dto, err
 	}
-	return Update[dtos.EthernetSwitchDto](ctx, e.switchRepo, updateDto, id, nil)
+	dto, err = Update[dtos.EthernetSwitchDto](ctx, e.switchRepo, updateDto, id, nil)
+	if err != nil {
+		return dto, err
+	}
+	//switch model or address can be changed, capabilities are read again on the next switch request
+	e.removeCachedCapabilities(id)
+	return dto, nil
 }
 
 //Create add new ethernet switch
@@ -194,12 +232,16 @@ func (e *EthernetSwitchService) Create(ctx context.Context, createDto dtos.Ether
 	dto := dtos.EthernetSwitchDto{}
 	err := validators.ValidateEthernetSwitchCreateDto(createDto)
 	if err != nil {
-		return dtos.EthernetSwitchDto{}, errors.Validation.Wrap(err, "validation failed")
+		return dto, err // we already wrap error in validators
 	}
 	if !e.modelIsSupported(createDto.SwitchModel) {
 		err = errors.Validation.New(errors.ValidationErrorMessage)
 		return dto, errors.AddErrorContext(err, "SwitchModel", "this model is not supported")
 	}
+	err = e.modelRequirementsCheck(createDto.SwitchModel, createDto.SNMPVersion)
+	if err != nil {
+		return dto, err
+	}
 	err = e.switchUniquenessCheck(ctx, createDto.Address, createDto.Serial, [16]byte{})
 	if err != nil {
 		return dto, err
@@ -208,17 +250,33 @@ func (e *EthernetSwitchService) Create(ctx context.Context, createDto dtos.Ether
 	if err != nil {
 		return dto, errors.Internal.Wrap(err, "service failed to create entity")
 	}
+	if createDto.Discover {
+		_, err = e.Discover(ctx, dto.ID)
+		if err != nil {
+			//switch is not created if its ports and VLANs can't be discovered
+			deleteErr := e.deleteSwitchRecords(ctx, dto.ID)
+			if deleteErr != nil {
+				return dtos.EthernetSwitchDto{}, errors.Internal.Wrap(deleteErr, "failed to delete switch after discovery failure")
+			}
+			return dtos.EthernetSwitchDto{}, err
+		}
+		dto.Capabilities = e.getCachedCapabilitiesDto(dto.ID)
+	}
 	return dto, nil
 }
 
-//Delete mark ethernet switch as deleted
+//Delete mark ethernet switch as deleted, switch cabled to device network interfaces can't be deleted
 //Params
 //	ctx - context is used only for logging
 //	id - ethernet switch id
 //Return
 //	error - if an error occurs, otherwise nil
 func (e *EthernetSwitchService) Delete(ctx context.Context, id uuid.UUID) error {
-	err := e.deleteAllVLANsBySwitchID(ctx, id)
+	err := e.switchCablingCheck(ctx, id)
+	if err != nil {
+		return err
+	}
+	err = e.deleteAllVLANsBySwitchID(ctx, id)
 	if err != nil {
 		return errors.Internal.Wrap(err, "failed to remove switch VLANs")
 	}
@@ -226,10 +284,20 @@ func (e *EthernetSwitchService) Delete(ctx context.Context, id uuid.UUID) error
 	if err != nil {
 		return errors.Internal.Wrap(err, "failed to remove switch ports")
 	}
+	err = e.deleteAllConfigBackupsBySwitchID(ctx, id)
+	if err != nil {
+		return err
+	}
 	err = e.switchRepo.Delete(ctx, id)
 	if err != nil {
 		return errors.Internal.Wrap(err, "failed to delete entity from repository")
 	}
+	e.removeDriftReport(id)
+	e.removeCachedCapabilities(id)
+	err = e.managers.Release(id)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to release switch manager")
+	}
 	return nil
 }
 
@@ -238,7 +306,7 @@ func (e *EthernetSwitchService) Delete(ctx context.Context, id uuid.UUID) error
 //	*[]dtos.EthernetSwitchModelDto - Ethernet switch model DTO's that supported by system
 func (e *EthernetSwitchService) GetSupportedModels() []dtos.EthernetSwitchModelDto {
 	supportedModelsDtos := []dtos.EthernetSwitchModelDto{}
-	for _, model := range *e.supportedList {
+	for _, model := range e.drivers.GetModels() {
 		modelDto := dtos.EthernetSwitchModelDto{}
 		mappers.MapEthernetSwitchModelToDto(model, &modelDto)
 		supportedModelsDtos = append(supportedModelsDtos, modelDto)
@@ -246,8 +314,23 @@ func (e *EthernetSwitchService) GetSupportedModels() []dtos.EthernetSwitchModelD
 	return supportedModelsDtos
 }
 
+//switchCablingCheck returns validation error if any device network interface is cabled to the switch
+func (e *EthernetSwitchService) switchCablingCheck(ctx context.Context, switchID uuid.UUID) error {
+	queryBuilder := e.interfaceRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("EthernetSwitchID", "==", switchID)
+	count, err := e.interfaceRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to count device network interfaces cabled to the switch")
+	}
+	if count > 0 {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return errors.AddErrorContext(err, "ID", "switch is cabled to device network interfaces, uncable them first")
+	}
+	return nil
+}
+
 func (e *EthernetSwitchService) switchIsExist(ctx context.Context, switchID uuid.UUID) (bool, error) {
-	_, err := e.GetByID(ctx, switchID)
+	_, err := e.switchRepo.GetByID(ctx, switchID)
 	if err != nil {
 		if !errors.As(err, errors.NotFound) {
 			return false, errors.Internal.Wrap(err, "failed to get ethernet switch from repository")
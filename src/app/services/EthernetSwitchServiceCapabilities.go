@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/mappers"
+	"rol/app/utils"
+	"rol/domain"
+	"rol/dtos"
+)
+
+//getSwitchCapabilities gets capabilities of the switch from its manager
+//
+//Return
+//	domain.EthernetSwitchCapabilities - switch capabilities
+//	interfaces.IEthernetSwitchManager - switch manager, nil if the switch has no manager
+//	error - if an error occurs, otherwise nil
+func (e *EthernetSwitchService) getSwitchCapabilities(ctx context.Context, switchID uuid.UUID) (domain.EthernetSwitchCapabilities,
+	interfaces.IEthernetSwitchManager, error) {
+	capabilities := domain.EthernetSwitchCapabilities{}
+	switchManager, err := e.managers.Get(ctx, switchID)
+	if err != nil {
+		return capabilities, nil, errors.Internal.Wrap(err, errorGetManager)
+	}
+	if switchManager == nil {
+		return capabilities, nil, nil
+	}
+	capabilities, err = switchManager.GetCapabilities()
+	if err != nil {
+		return capabilities, switchManager, errors.Internal.Wrap(err, "failed to get switch capabilities")
+	}
+	e.capabilitiesMutex.Lock()
+	e.capabilities[switchID] = capabilities
+	e.capabilitiesMutex.Unlock()
+	return capabilities, switchManager, nil
+}
+
+//modelCapabilityCheck checks that the model of the switch supports the feature
+//
+//Params
+//	ctx - context
+//	switchID - ethernet switch ID
+//	capability - feature, see domain.EthernetSwitchCapability constants
+//	message - validation error message if the feature is not supported
+//Return
+//	error - validation error if the switch model does not support the feature
+func (e *EthernetSwitchService) modelCapabilityCheck(ctx context.Context, switchID uuid.UUID, capability, message string) error {
+	ethernetSwitch, err := e.switchRepo.GetByID(ctx, switchID)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to get ethernet switch")
+	}
+	model, err := e.drivers.GetModel(ethernetSwitch.SwitchModel)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to get switch model")
+	}
+	if !utils.SliceContainsElement(model.Capabilities, capability) {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return errors.AddErrorContext(err, "SwitchModel", message)
+	}
+	return nil
+}
+
+//getCachedCapabilitiesDto gets capabilities dto of the switch from the cache without the switch request,
+//nil if capabilities of the switch were not read yet
+func (e *EthernetSwitchService) getCachedCapabilitiesDto(switchID uuid.UUID) *dtos.EthernetSwitchCapabilitiesDto {
+	e.capabilitiesMutex.RLock()
+	defer e.capabilitiesMutex.RUnlock()
+	capabilities, ok := e.capabilities[switchID]
+	if !ok {
+		return nil
+	}
+	dto := &dtos.EthernetSwitchCapabilitiesDto{}
+	mappers.MapEthernetSwitchCapabilitiesToDto(capabilities, dto)
+	return dto
+}
+
+func (e *EthernetSwitchService) removeCachedCapabilities(switchID uuid.UUID) {
+	e.capabilitiesMutex.Lock()
+	defer e.capabilitiesMutex.Unlock()
+	delete(e.capabilities, switchID)
+}
+
+//GetCapabilities reads capabilities of the switch from its manager and updates the cached capabilities
+//that are returned with the switch dto
+//
+//Params
+//	ctx - context
+//	switchID - ethernet switch ID
+//Return
+//	dtos.EthernetSwitchCapabilitiesDto - switch capabilities
+//	error - if an error occurs, otherwise nil
+func (e *EthernetSwitchService) GetCapabilities(ctx context.Context, switchID uuid.UUID) (dtos.EthernetSwitchCapabilitiesDto, error) {
+	dto := dtos.EthernetSwitchCapabilitiesDto{}
+	switchExist, err := e.switchIsExist(ctx, switchID)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, errorSwitchExistence)
+	}
+	if !switchExist {
+		return dto, errors.NotFound.New(errorSwitchNotFound)
+	}
+	capabilities, switchManager, err := e.getSwitchCapabilities(ctx, switchID)
+	if err != nil {
+		return dto, err
+	}
+	if switchManager == nil {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return dto, errors.AddErrorContext(err, "SwitchModel", errorSwitchNotManaged)
+	}
+	mappers.MapEthernetSwitchCapabilitiesToDto(capabilities, &dto)
+	return dto, nil
+}
+
+//portCapabilitiesCheck checks that the switch supports requested port features, switch without manager is not checked
+//
+//Params
+//	ctx - context
+//	switchID - ethernet switch ID
+//	portName - switch port name
+//	POEType - requested poe type
+//	POEEnabled - requested poe status
+//Return
+//	error - validation error if the switch does not support requested features
+func (e *EthernetSwitchService) portCapabilitiesCheck(ctx context.Context, switchID uuid.UUID, portName, POEType string, POEEnabled bool) error {
+	capabilities, switchManager, err := e.getSwitchCapabilities(ctx, switchID)
+	if err != nil {
+		return err
+	}
+	if switchManager == nil {
+		return nil
+	}
+	validationErr := errors.Validation.New(errors.ValidationErrorMessage)
+	hasErrors := false
+	if len(capabilities.Ports) > 0 && !utils.SliceContainsElement(capabilities.Ports, portName) {
+		validationErr = errors.AddErrorContext(validationErr, "Name", "port is not found on the switch")
+		hasErrors = true
+	}
+	if POEEnabled {
+		_, isPOEManager := switchManager.(interfaces.IEthernetSwitchPOEManager)
+		switch {
+		case !isPOEManager || len(capabilities.POEPorts) == 0:
+			validationErr = errors.AddErrorContext(validationErr, "POEEnabled", "switch does not support PoE")
+			hasErrors = true
+		case !utils.SliceContainsElement(capabilities.POEPorts, portName):
+			validationErr = errors.AddErrorContext(validationErr, "POEEnabled", "port does not support PoE")
+			hasErrors = true
+		case !utils.SliceContainsElement(capabilities.POETypes, POEType):
+			validationErr = errors.AddErrorContext(validationErr, "POEType", "PoE type is not supported by the switch")
+			hasErrors = true
+		}
+	}
+	if hasErrors {
+		return validationErr
+	}
+	return nil
+}
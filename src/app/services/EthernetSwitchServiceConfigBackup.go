@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/pmezard/go-difflib/difflib"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/domain"
+	"rol/dtos"
+	"time"
+)
+
+const (
+	//ethernetSwitchConfigBackupInterval interval between scheduled running config backups of all switches
+	ethernetSwitchConfigBackupInterval = time.Hour
+	//ethernetSwitchConfigDiffContext number of the unchanged lines around the changes in the config diff
+	ethernetSwitchConfigDiffContext = 3
+	//errorConfigBackupNotSupported switch manager can't read running config
+	errorConfigBackupNotSupported = "switch manager doesn't support running config backup"
+)
+
+//configBackupScheduler periodically backs up running config of all switches
+func (e *EthernetSwitchService) configBackupScheduler() {
+	ticker := time.NewTicker(ethernetSwitchConfigBackupInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		e.backupAllSwitchesConfig(context.Background())
+	}
+}
+
+//backupAllSwitchesConfig backs up running config of all switches that support it
+func (e *EthernetSwitchService) backupAllSwitchesConfig(ctx context.Context) {
+	count, err := e.switchRepo.Count(ctx, nil)
+	if err != nil || count == 0 {
+		return
+	}
+	switches, err := e.switchRepo.GetList(ctx, "", "", 1, count, nil)
+	if err != nil {
+		return
+	}
+	for _, ethernetSwitch := range switches {
+		//switch without config backup support or unreachable switch, it is backed up on the next schedule
+		_, _ = e.backupConfig(ctx, ethernetSwitch.ID, domain.EthernetSwitchConfigBackupScheduled)
+	}
+}
+
+//getConfigManager gets switch manager that supports running config backup
+//
+//Return
+//	interfaces.IEthernetSwitchManager - switch manager
+//	interfaces.IEthernetSwitchConfigManager - switch config manager
+//	error - validation error if the switch manager doesn't support backups, otherwise nil
+func (e *EthernetSwitchService) getConfigManager(ctx context.Context, switchID uuid.UUID) (interfaces.IEthernetSwitchManager,
+	interfaces.IEthernetSwitchConfigManager, error) {
+	switchManager, err := e.managers.Get(ctx, switchID)
+	if err != nil {
+		return nil, nil, errors.Internal.Wrap(err, errorGetManager)
+	}
+	configManager, ok := switchManager.(interfaces.IEthernetSwitchConfigManager)
+	if !ok {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return nil, nil, errors.AddErrorContext(err, "SwitchModel", errorConfigBackupNotSupported)
+	}
+	return switchManager, configManager, nil
+}
+
+//getLastConfigBackup gets the latest backup of the switch, nil if the switch has no backups
+func (e *EthernetSwitchService) getLastConfigBackup(ctx context.Context, switchID uuid.UUID) (*domain.EthernetSwitchConfigBackup, error) {
+	queryBuilder := e.backupRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("EthernetSwitchID", "==", switchID)
+	backups, err := e.backupRepo.GetList(ctx, "Version", "desc", 1, 1, queryBuilder)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to get last config backup")
+	}
+	if len(backups) == 0 {
+		return nil, nil
+	}
+	return &backups[0], nil
+}
+
+//getConfigBackupByVersion gets switch backup by version
+func (e *EthernetSwitchService) getConfigBackupByVersion(ctx context.Context, switchID uuid.UUID, version int) (domain.EthernetSwitchConfigBackup, error) {
+	queryBuilder := e.backupRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("EthernetSwitchID", "==", switchID).Where("Version", "==", version)
+	backups, err := e.backupRepo.GetList(ctx, "", "", 1, 1, queryBuilder)
+	if err != nil {
+		return domain.EthernetSwitchConfigBackup{}, errors.Internal.Wrap(err, "failed to get config backup")
+	}
+	if len(backups) == 0 {
+		return domain.EthernetSwitchConfigBackup{}, errors.NotFound.Newf("config backup version %d not found", version)
+	}
+	return backups[0], nil
+}
+
+//backupConfig saves switch running config as the new backup version.
+//New version is not created if the config is equal to the latest backup, the latest backup is returned instead
+func (e *EthernetSwitchService) backupConfig(ctx context.Context, switchID uuid.UUID, reason string) (domain.EthernetSwitchConfigBackup, error) {
+	_, configManager, err := e.getConfigManager(ctx, switchID)
+	if err != nil {
+		return domain.EthernetSwitchConfigBackup{}, err
+	}
+	config, err := configManager.GetRunningConfig()
+	if err != nil {
+		return domain.EthernetSwitchConfigBackup{}, errors.Internal.Wrap(err, "failed to get running config from switch")
+	}
+	e.backupMutex.Lock()
+	defer e.backupMutex.Unlock()
+	lastBackup, err := e.getLastConfigBackup(ctx, switchID)
+	if err != nil {
+		return domain.EthernetSwitchConfigBackup{}, err
+	}
+	version := 1
+	if lastBackup != nil {
+		if lastBackup.Config == config {
+			return *lastBackup, nil
+		}
+		version = lastBackup.Version + 1
+	}
+	backup, err := e.backupRepo.Insert(ctx, domain.EthernetSwitchConfigBackup{
+		EthernetSwitchID: switchID,
+		Version:          version,
+		Reason:           reason,
+		Config:           config,
+	})
+	if err != nil {
+		return domain.EthernetSwitchConfigBackup{}, errors.Internal.Wrap(err, "repository failed to insert config backup")
+	}
+	return backup, nil
+}
+
+//backupConfigAfterChange backs up running config after the switch configuration change.
+//Backup error doesn't fail the applied change, the config is backed up again by schedule
+func (e *EthernetSwitchService) backupConfigAfterChange(ctx context.Context, switchID uuid.UUID) {
+	_, _ = e.backupConfig(ctx, switchID, domain.EthernetSwitchConfigBackupChange)
+}
+
+//deleteAllConfigBackupsBySwitchID deletes all backups of the switch
+func (e *EthernetSwitchService) deleteAllConfigBackupsBySwitchID(ctx context.Context, switchID uuid.UUID) error {
+	queryBuilder := e.backupRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("EthernetSwitchID", "==", switchID)
+	err := e.backupRepo.DeleteAll(ctx, queryBuilder)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to delete switch config backups")
+	}
+	return nil
+}
+
+//GetConfigBackups Get list of ethernet switch running config backups with pagination
+//
+//Params
+//	ctx - context is used only for logging
+//	switchID - uuid of the ethernet switch
+//	orderBy - order by backup field name
+//	orderDirection - ascending or descending order
+//	page - page number
+//	pageSize - page size
+//Return
+//	dtos.PaginatedItemsDto[dtos.EthernetSwitchConfigBackupDto] - paginated list of ethernet switch config backups
+//	error - if an error occurs, otherwise nil
+func (e *EthernetSwitchService) GetConfigBackups(ctx context.Context, switchID uuid.UUID, orderBy, orderDirection string,
+	page, pageSize int) (dtos.PaginatedItemsDto[dtos.EthernetSwitchConfigBackupDto], error) {
+	dto := dtos.PaginatedItemsDto[dtos.EthernetSwitchConfigBackupDto]{}
+	switchExist, err := e.switchIsExist(ctx, switchID)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, errorSwitchExistence)
+	}
+	if !switchExist {
+		return dto, errors.NotFound.New(errorSwitchNotFound)
+	}
+	queryBuilder := e.backupRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("EthernetSwitchID", "==", switchID)
+	return GetListExtended[dtos.EthernetSwitchConfigBackupDto](ctx, e.backupRepo, queryBuilder, orderBy, orderDirection, page, pageSize)
+}
+
+//GetConfigBackupByID Get ethernet switch running config backup by ID
+//
+//Params
+//	ctx - context is used only for logging
+//	switchID - ethernet switch ID
+//	id - backup ID
+//Return
+//	dtos.EthernetSwitchConfigBackupDto - config backup
+//	error - if an error occurs, otherwise nil
+func (e *EthernetSwitchService) GetConfigBackupByID(ctx context.Context, switchID, id uuid.UUID) (dtos.EthernetSwitchConfigBackupDto, error) {
+	dto := dtos.EthernetSwitchConfigBackupDto{}
+	switchExist, err := e.switchIsExist(ctx, switchID)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, errorSwitchExistence)
+	}
+	if !switchExist {
+		return dto, errors.NotFound.New(errorSwitchNotFound)
+	}
+	queryBuilder := e.backupRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("EthernetSwitchID", "==", switchID)
+	return GetByID[dtos.EthernetSwitchConfigBackupDto, uuid.UUID, domain.EthernetSwitchConfigBackup](ctx, e.backupRepo, id, queryBuilder)
+}
+
+//GetConfigBackupsDiff Get unified diff between two ethernet switch running config backups
+//
+//Params
+//	ctx - context is used only for logging
+//	switchID - ethernet switch ID
+//	fromVersion - version of the original backup
+//	toVersion - version of the changed backup
+//Return
+//	dtos.EthernetSwitchConfigDiffDto - unified diff
+//	error - if an error occurs, otherwise nil
+func (e *EthernetSwitchService) GetConfigBackupsDiff(ctx context.Context, switchID uuid.UUID, fromVersion, toVersion int) (dtos.EthernetSwitchConfigDiffDto, error) {
+	dto := dtos.EthernetSwitchConfigDiffDto{FromVersion: fromVersion, ToVersion: toVersion}
+	switchExist, err := e.switchIsExist(ctx, switchID)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, errorSwitchExistence)
+	}
+	if !switchExist {
+		return dto, errors.NotFound.New(errorSwitchNotFound)
+	}
+	fromBackup, err := e.getConfigBackupByVersion(ctx, switchID, fromVersion)
+	if err != nil {
+		return dto, err
+	}
+	toBackup, err := e.getConfigBackupByVersion(ctx, switchID, toVersion)
+	if err != nil {
+		return dto, err
+	}
+	dto.Diff, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(fromBackup.Config),
+		B:        difflib.SplitLines(toBackup.Config),
+		FromFile: fmt.Sprintf("version %d", fromVersion),
+		FromDate: fromBackup.CreatedAt.Format(time.RFC3339),
+		ToFile:   fmt.Sprintf("version %d", toVersion),
+		ToDate:   toBackup.CreatedAt.Format(time.RFC3339),
+		Context:  ethernetSwitchConfigDiffContext,
+	})
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to get config diff")
+	}
+	return dto, nil
+}
+
+//RestoreConfigBackup applies running config backup to the switch and saves switch configuration.
+//Switch VLANs and ports PoE status are imported to the database after restore, restored config is saved as the new backup
+//version if it differs from the latest backup
+//
+//Params
+//	ctx - context is used only for logging
+//	switchID - ethernet switch ID
+//	id - backup ID
+//Return
+//	dtos.EthernetSwitchConfigBackupDto - backup of the restored config
+//	error - if an error occurs, otherwise nil
+func (e *EthernetSwitchService) RestoreConfigBackup(ctx context.Context, switchID, id uuid.UUID) (dtos.EthernetSwitchConfigBackupDto, error) {
+	dto := dtos.EthernetSwitchConfigBackupDto{}
+	backup, err := e.GetConfigBackupByID(ctx, switchID, id)
+	if err != nil {
+		return dto, err
+	}
+	switchManager, configManager, err := e.getConfigManager(ctx, switchID)
+	if err != nil {
+		return dto, err
+	}
+	err = configManager.RestoreConfig(backup.Config)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to restore switch config")
+	}
+	err = switchManager.SaveConfig()
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "save switch config failed")
+	}
+	_, err = e.Reconcile(ctx, switchID, dtos.EthernetSwitchReconcileDto{Mode: domain.EthernetSwitchReconcileImport})
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to import restored switch state")
+	}
+	restoredBackup, err := e.backupConfig(ctx, switchID, domain.EthernetSwitchConfigBackupRestore)
+	if err != nil {
+		return dto, err
+	}
+	return e.GetConfigBackupByID(ctx, switchID, restoredBackup.ID)
+}
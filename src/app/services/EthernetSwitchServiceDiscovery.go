@@ -0,0 +1,244 @@
+package services
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/mappers"
+	"rol/app/utils"
+	"rol/domain"
+	"rol/dtos"
+)
+
+//ethernetSwitchPOETypeNone poe type of the ports without PoE
+const ethernetSwitchPOETypeNone = "none"
+
+//discoveredPort switch port state read from the switch
+type discoveredPort struct {
+	name       string
+	pvid       int
+	untagged   int
+	tagged     []int
+	poeEnabled bool
+	poeType    string
+}
+
+//discoverPorts reads state of all physical ports from the switch
+func discoverPorts(manager interfaces.IEthernetSwitchManager, capabilities domain.EthernetSwitchCapabilities,
+	existingPorts map[string]domain.EthernetSwitchPort) ([]discoveredPort, error) {
+	poeManager, isPOEManager := manager.(interfaces.IEthernetSwitchPOEManager)
+	ports := []discoveredPort{}
+	for _, portName := range capabilities.Ports {
+		port := discoveredPort{name: portName, poeType: ethernetSwitchPOETypeNone}
+		var err error
+		port.untagged, port.tagged, err = manager.GetVLANsOnPort(portName)
+		if err != nil {
+			return nil, errors.Internal.Wrapf(err, "failed to get VLANs of the port %s from switch", portName)
+		}
+		port.pvid, err = manager.GetPortPVID(portName)
+		if err != nil {
+			return nil, errors.Internal.Wrapf(err, "failed to get PVID of the port %s from switch", portName)
+		}
+		if isPOEManager && utils.SliceContainsElement(capabilities.POEPorts, portName) {
+			status, err := poeManager.GetPOEPortStatus(portName)
+			if err != nil {
+				return nil, errors.Internal.Wrapf(err, "failed to get PoE status of the port %s from switch", portName)
+			}
+			port.poeEnabled = status == "enable"
+			port.poeType = getDiscoveredPOEType(capabilities, existingPorts[portName].POEType)
+		}
+		ports = append(ports, port)
+	}
+	return ports, nil
+}
+
+//getDiscoveredPOEType gets PoE type of the PoE port: type from the database if the switch supports it,
+//otherwise the first type supported by the switch
+func getDiscoveredPOEType(capabilities domain.EthernetSwitchCapabilities, existingType string) string {
+	if existingType != "" && existingType != ethernetSwitchPOETypeNone &&
+		utils.SliceContainsElement(capabilities.POETypes, existingType) {
+		return existingType
+	}
+	if len(capabilities.POETypes) > 0 {
+		return capabilities.POETypes[0]
+	}
+	return ethernetSwitchPOETypeNone
+}
+
+//savePorts creates or updates ports records with the discovered state
+func (e *EthernetSwitchService) savePorts(ctx context.Context, switchID uuid.UUID, ports []discoveredPort,
+	existingPorts map[string]domain.EthernetSwitchPort) ([]domain.EthernetSwitchPort, error) {
+	savedPorts := []domain.EthernetSwitchPort{}
+	for _, port := range ports {
+		entity, exist := existingPorts[port.name]
+		if !exist {
+			entity = domain.EthernetSwitchPort{Name: port.name, EthernetSwitchID: switchID}
+		}
+		entity.PVID = port.pvid
+		entity.POEEnabled = port.poeEnabled
+		entity.POEType = port.poeType
+		var err error
+		if exist {
+			entity, err = e.portRepo.Update(ctx, entity)
+		} else {
+			entity, err = e.portRepo.Insert(ctx, entity)
+		}
+		if err != nil {
+			return nil, errors.Internal.Wrapf(err, "failed to save port %s", port.name)
+		}
+		savedPorts = append(savedPorts, entity)
+	}
+	return savedPorts, nil
+}
+
+//saveVLANs creates or updates VLANs records with the discovered ports membership, default VLAN is skipped
+func (e *EthernetSwitchService) saveVLANs(ctx context.Context, switchID uuid.UUID, vlansIDs []int, ports []discoveredPort,
+	savedPorts []domain.EthernetSwitchPort) ([]dtos.EthernetSwitchVLANDto, error) {
+	vlans, err := e.getAllSwitchVLANs(ctx, switchID)
+	if err != nil {
+		return nil, err
+	}
+	existingVLANs := map[int]dtos.EthernetSwitchVLANDto{}
+	for _, vlan := range vlans {
+		existingVLANs[vlan.VlanID] = vlan
+	}
+	savedVLANs := []dtos.EthernetSwitchVLANDto{}
+	for _, vlanID := range vlansIDs {
+		if vlanID == ethernetSwitchDefaultVLANID {
+			continue
+		}
+		base := dtos.EthernetSwitchVLANBaseDto{TaggedPorts: []uuid.UUID{}, UntaggedPorts: []uuid.UUID{}}
+		for i, port := range ports {
+			if port.untagged == vlanID {
+				base.UntaggedPorts = append(base.UntaggedPorts, savedPorts[i].ID)
+			} else if utils.SliceContainsElement(port.tagged, vlanID) {
+				base.TaggedPorts = append(base.TaggedPorts, savedPorts[i].ID)
+			}
+		}
+		var vlan dtos.EthernetSwitchVLANDto
+		if existingVLAN, exist := existingVLANs[vlanID]; exist {
+			queryBuilder := e.vlanRepo.NewQueryBuilder(ctx)
+			queryBuilder.Where("EthernetSwitchID", "==", switchID)
+			vlan, err = Update[dtos.EthernetSwitchVLANDto](ctx, e.vlanRepo,
+				dtos.EthernetSwitchVLANUpdateDto{EthernetSwitchVLANBaseDto: base}, existingVLAN.ID, queryBuilder)
+		} else {
+			vlan, err = e.insertVLAN(ctx, switchID, dtos.EthernetSwitchVLANCreateDto{EthernetSwitchVLANBaseDto: base, VlanID: vlanID})
+		}
+		if err != nil {
+			return nil, errors.Internal.Wrapf(err, "failed to save VLAN %d", vlanID)
+		}
+		savedVLANs = append(savedVLANs, vlan)
+	}
+	return savedVLANs, nil
+}
+
+//insertVLAN inserts VLAN record without changes on the switch
+func (e *EthernetSwitchService) insertVLAN(ctx context.Context, switchID uuid.UUID, createDto dtos.EthernetSwitchVLANCreateDto) (dtos.EthernetSwitchVLANDto, error) {
+	dto := dtos.EthernetSwitchVLANDto{}
+	entity := new(domain.EthernetSwitchVLAN)
+	err := mappers.MapDtoToEntity(createDto, entity)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to map ethernet switch VLAN dto to entity")
+	}
+	entity.EthernetSwitchID = switchID
+	newVLAN, err := e.vlanRepo.Insert(ctx, *entity)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "repository failed to insert VLAN")
+	}
+	err = mappers.MapEntityToDto(newVLAN, &dto)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to map vlan entity to dto")
+	}
+	return dto, nil
+}
+
+//Discover reads physical ports with their PVID, PoE state and VLANs membership from the switch and saves them
+//to the ports and VLANs records. Existing records are updated, records that are not found on the switch are kept
+//
+//Params
+//	ctx - context
+//	switchID - ethernet switch ID
+//Return
+//	dtos.EthernetSwitchDiscoveryDto - created or updated ports and VLANs
+//	error - if an error occurs, otherwise nil
+func (e *EthernetSwitchService) Discover(ctx context.Context, switchID uuid.UUID) (dtos.EthernetSwitchDiscoveryDto, error) {
+	dto := dtos.EthernetSwitchDiscoveryDto{Ports: []dtos.EthernetSwitchPortDto{}, VLANs: []dtos.EthernetSwitchVLANDto{}}
+	switchExist, err := e.switchIsExist(ctx, switchID)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, errorSwitchExistence)
+	}
+	if !switchExist {
+		return dto, errors.NotFound.New(errorSwitchNotFound)
+	}
+	capabilities, switchManager, err := e.getSwitchCapabilities(ctx, switchID)
+	if err != nil {
+		return dto, err
+	}
+	if switchManager == nil {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return dto, errors.AddErrorContext(err, "SwitchModel", errorSwitchNotManaged)
+	}
+	if len(capabilities.Ports) == 0 {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return dto, errors.AddErrorContext(err, "SwitchModel", "switch manager doesn't report physical ports")
+	}
+	existingPorts := map[string]domain.EthernetSwitchPort{}
+	ports, err := e.getAllSwitchPorts(ctx, switchID)
+	if err != nil {
+		return dto, err
+	}
+	for _, port := range ports {
+		existingPorts[port.Name] = port
+	}
+	discoveredPorts, err := discoverPorts(switchManager, capabilities, existingPorts)
+	if err != nil {
+		return dto, err
+	}
+	vlansIDs, err := switchManager.GetVLANs()
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to get VLANs from switch")
+	}
+	savedPorts, err := e.savePorts(ctx, switchID, discoveredPorts, existingPorts)
+	if err != nil {
+		return dto, err
+	}
+	dto.VLANs, err = e.saveVLANs(ctx, switchID, vlansIDs, discoveredPorts, savedPorts)
+	if err != nil {
+		return dto, err
+	}
+	for _, port := range savedPorts {
+		portDto := dtos.EthernetSwitchPortDto{}
+		err = mappers.MapEntityToDto(port, &portDto)
+		if err != nil {
+			return dto, errors.Internal.Wrap(err, "failed to map entity to dto")
+		}
+		dto.Ports = append(dto.Ports, portDto)
+	}
+	return dto, nil
+}
+
+//deleteSwitchRecords deletes switch with its ports, VLANs and config backups records without changes on the switch
+func (e *EthernetSwitchService) deleteSwitchRecords(ctx context.Context, switchID uuid.UUID) error {
+	vlansQueryBuilder := e.vlanRepo.NewQueryBuilder(ctx)
+	vlansQueryBuilder.Where("EthernetSwitchID", "==", switchID)
+	err := e.vlanRepo.DeleteAll(ctx, vlansQueryBuilder)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to delete switch VLANs")
+	}
+	portsQueryBuilder := e.portRepo.NewQueryBuilder(ctx)
+	portsQueryBuilder.Where("EthernetSwitchID", "==", switchID)
+	err = e.portRepo.DeleteAll(ctx, portsQueryBuilder)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to delete switch ports")
+	}
+	err = e.deleteAllConfigBackupsBySwitchID(ctx, switchID)
+	if err != nil {
+		return err
+	}
+	err = e.switchRepo.Delete(ctx, switchID)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to delete switch")
+	}
+	return nil
+}
@@ -0,0 +1,583 @@
+package services
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"reflect"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/mappers"
+	"rol/app/utils"
+	"rol/app/validators"
+	"rol/domain"
+	"rol/dtos"
+	"sort"
+	"time"
+)
+
+const (
+	//ethernetSwitchDriftCheckInterval interval between drift checks of all switches
+	ethernetSwitchDriftCheckInterval = 10 * time.Minute
+	//ethernetSwitchDefaultVLANID default VLAN is present on every switch and is not stored in the database
+	ethernetSwitchDefaultVLANID = 1
+	//errorSwitchNotManaged switch model is stored only in the database
+	errorSwitchNotManaged = "switch model has no manager, its state can't be read"
+)
+
+//ethernetSwitchState configuration of the switch in the database and actual state of the switch
+type ethernetSwitchState struct {
+	manager interfaces.IEthernetSwitchManager
+	//ports switch ports from the database sorted by name
+	ports []domain.EthernetSwitchPort
+	//portsByName switch ports from the database by name
+	portsByName map[string]domain.EthernetSwitchPort
+	//vlans switch VLANs from the database by VLAN ID
+	vlans map[int]dtos.EthernetSwitchVLANDto
+	//switchVLANs VLANs on the switch without default VLAN
+	switchVLANs []int
+	//switchTagged names of the database ports that are tagged on the switch by VLAN ID
+	switchTagged map[int][]string
+	//switchUntagged names of the database ports that are untagged on the switch by VLAN ID
+	switchUntagged map[int][]string
+	//missingPorts names of the database ports that are not present on the switch
+	missingPorts map[string]bool
+	//switchPOE PoE status of the database ports on the switch, only for PoE ports of the switches with PoE support
+	switchPOE map[string]bool
+	//switchPVID PVID of the database ports on the switch
+	switchPVID map[string]int
+}
+
+//EthernetSwitchServiceInit initialize ethernet switch service, starts periodic drift detection and config backup of the switches
+func EthernetSwitchServiceInit(s *EthernetSwitchService) error {
+	go s.driftDetector()
+	go s.configBackupScheduler()
+	return nil
+}
+
+//driftDetector periodically detects drift of all managed switches
+func (e *EthernetSwitchService) driftDetector() {
+	ticker := time.NewTicker(ethernetSwitchDriftCheckInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		e.detectAllSwitchesDrift(context.Background())
+	}
+}
+
+//detectAllSwitchesDrift detects drift of all managed switches and replaces saved drift reports
+func (e *EthernetSwitchService) detectAllSwitchesDrift(ctx context.Context) {
+	count, err := e.switchRepo.Count(ctx, nil)
+	if err != nil || count == 0 {
+		return
+	}
+	switches, err := e.switchRepo.GetList(ctx, "", "", 1, count, nil)
+	if err != nil {
+		return
+	}
+	reports := map[uuid.UUID]domain.EthernetSwitchDrift{}
+	for _, ethernetSwitch := range switches {
+		drift, err := e.detectDrift(ctx, ethernetSwitch.ID)
+		if err != nil {
+			//switch without manager or deleted switch
+			continue
+		}
+		reports[ethernetSwitch.ID] = drift
+	}
+	e.driftMutex.Lock()
+	e.driftReports = reports
+	e.driftMutex.Unlock()
+}
+
+func (e *EthernetSwitchService) getAllSwitchPorts(ctx context.Context, switchID uuid.UUID) ([]domain.EthernetSwitchPort, error) {
+	queryBuilder := e.portRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("EthernetSwitchID", "==", switchID)
+	count, err := e.portRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to count ports")
+	}
+	if count == 0 {
+		return []domain.EthernetSwitchPort{}, nil
+	}
+	ports, err := e.portRepo.GetList(ctx, "Name", "asc", 1, count, queryBuilder)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to get ports")
+	}
+	return ports, nil
+}
+
+func (e *EthernetSwitchService) getAllSwitchVLANs(ctx context.Context, switchID uuid.UUID) ([]dtos.EthernetSwitchVLANDto, error) {
+	queryBuilder := e.vlanRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("EthernetSwitchID", "==", switchID)
+	count, err := e.vlanRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to count VLANs")
+	}
+	if count == 0 {
+		return []dtos.EthernetSwitchVLANDto{}, nil
+	}
+	vlans, err := GetListExtended[dtos.EthernetSwitchVLANDto](ctx, e.vlanRepo, queryBuilder, "VlanID", "asc", 1, count)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to get VLANs")
+	}
+	return vlans.Items, nil
+}
+
+//readSwitchState reads switch configuration from the repositories and actual state from the switch
+//
+//Return
+//	*ethernetSwitchState - switch state
+//	error - not found error if the switch doesn't exist, validation error if the switch has no manager,
+//	internal error if the state reading fails
+func (e *EthernetSwitchService) readSwitchState(ctx context.Context, switchID uuid.UUID) (*ethernetSwitchState, error) {
+	switchExist, err := e.switchIsExist(ctx, switchID)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, errorSwitchExistence)
+	}
+	if !switchExist {
+		return nil, errors.NotFound.New(errorSwitchNotFound)
+	}
+	capabilities, switchManager, err := e.getSwitchCapabilities(ctx, switchID)
+	if err != nil {
+		return nil, err
+	}
+	if switchManager == nil {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return nil, errors.AddErrorContext(err, "SwitchModel", errorSwitchNotManaged)
+	}
+	state := &ethernetSwitchState{
+		manager:        switchManager,
+		portsByName:    map[string]domain.EthernetSwitchPort{},
+		vlans:          map[int]dtos.EthernetSwitchVLANDto{},
+		switchVLANs:    []int{},
+		switchTagged:   map[int][]string{},
+		switchUntagged: map[int][]string{},
+		missingPorts:   map[string]bool{},
+		switchPOE:      map[string]bool{},
+		switchPVID:     map[string]int{},
+	}
+	state.ports, err = e.getAllSwitchPorts(ctx, switchID)
+	if err != nil {
+		return nil, err
+	}
+	vlans, err := e.getAllSwitchVLANs(ctx, switchID)
+	if err != nil {
+		return nil, err
+	}
+	for _, vlan := range vlans {
+		state.vlans[vlan.VlanID] = vlan
+	}
+	switchVLANs, err := switchManager.GetVLANs()
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to get VLANs from switch")
+	}
+	for _, vlanID := range switchVLANs {
+		if vlanID != ethernetSwitchDefaultVLANID {
+			state.switchVLANs = append(state.switchVLANs, vlanID)
+		}
+	}
+	poeManager, isPOEManager := switchManager.(interfaces.IEthernetSwitchPOEManager)
+	for _, port := range state.ports {
+		state.portsByName[port.Name] = port
+		if len(capabilities.Ports) > 0 && !utils.SliceContainsElement(capabilities.Ports, port.Name) {
+			state.missingPorts[port.Name] = true
+			continue
+		}
+		untagged, tagged, err := switchManager.GetVLANsOnPort(port.Name)
+		if err != nil {
+			return nil, errors.Internal.Wrapf(err, "failed to get VLANs of the port %s from switch", port.Name)
+		}
+		if untagged != 0 && untagged != ethernetSwitchDefaultVLANID {
+			state.switchUntagged[untagged] = append(state.switchUntagged[untagged], port.Name)
+		}
+		for _, vlanID := range tagged {
+			if vlanID != ethernetSwitchDefaultVLANID {
+				state.switchTagged[vlanID] = append(state.switchTagged[vlanID], port.Name)
+			}
+		}
+		state.switchPVID[port.Name], err = switchManager.GetPortPVID(port.Name)
+		if err != nil {
+			return nil, errors.Internal.Wrapf(err, "failed to get PVID of the port %s from switch", port.Name)
+		}
+		if isPOEManager && utils.SliceContainsElement(capabilities.POEPorts, port.Name) {
+			status, err := poeManager.GetPOEPortStatus(port.Name)
+			if err != nil {
+				return nil, errors.Internal.Wrapf(err, "failed to get PoE status of the port %s from switch", port.Name)
+			}
+			state.switchPOE[port.Name] = status == "enable"
+		}
+	}
+	return state, nil
+}
+
+//getPortsNames gets sorted names of the database ports that are present on the switch
+func (s *ethernetSwitchState) getPortsNames(portsIDs []uuid.UUID) []string {
+	names := []string{}
+	for _, port := range s.ports {
+		if utils.SliceContainsElement(portsIDs, port.ID) && !s.missingPorts[port.Name] {
+			names = append(names, port.Name)
+		}
+	}
+	return names
+}
+
+//getPortsIDs gets IDs of the database ports by names
+func (s *ethernetSwitchState) getPortsIDs(portsNames []string) []uuid.UUID {
+	ids := []uuid.UUID{}
+	for _, name := range portsNames {
+		ids = append(ids, s.portsByName[name].ID)
+	}
+	return ids
+}
+
+//getMissingPortsIDs gets IDs of the ports that are not present on the switch
+func (s *ethernetSwitchState) getMissingPortsIDs(portsIDs []uuid.UUID) []uuid.UUID {
+	ids := []uuid.UUID{}
+	for _, id := range portsIDs {
+		for name := range s.missingPorts {
+			if s.portsByName[name].ID == id {
+				ids = append(ids, id)
+			}
+		}
+	}
+	return ids
+}
+
+func (s *ethernetSwitchState) getVLANDrift(vlanID int) *domain.EthernetSwitchVLANDrift {
+	vlan, inDatabase := s.vlans[vlanID]
+	onSwitch := utils.SliceContainsElement(s.switchVLANs, vlanID)
+	drift := &domain.EthernetSwitchVLANDrift{
+		VlanID:                vlanID,
+		DatabaseTaggedPorts:   []string{},
+		DatabaseUntaggedPorts: []string{},
+		SwitchTaggedPorts:     []string{},
+		SwitchUntaggedPorts:   []string{},
+	}
+	if inDatabase {
+		drift.DatabaseTaggedPorts = s.getPortsNames(vlan.TaggedPorts)
+		drift.DatabaseUntaggedPorts = s.getPortsNames(vlan.UntaggedPorts)
+	}
+	if onSwitch {
+		drift.SwitchTaggedPorts = append(drift.SwitchTaggedPorts, s.switchTagged[vlanID]...)
+		drift.SwitchUntaggedPorts = append(drift.SwitchUntaggedPorts, s.switchUntagged[vlanID]...)
+	}
+	switch {
+	case !onSwitch:
+		drift.State = domain.EthernetSwitchDriftMissingOnSwitch
+	case !inDatabase:
+		drift.State = domain.EthernetSwitchDriftMissingInDatabase
+	case !reflect.DeepEqual(drift.DatabaseTaggedPorts, drift.SwitchTaggedPorts) ||
+		!reflect.DeepEqual(drift.DatabaseUntaggedPorts, drift.SwitchUntaggedPorts):
+		drift.State = domain.EthernetSwitchDriftPortsMismatch
+	default:
+		return nil
+	}
+	return drift
+}
+
+//getDrift compares switch configuration in the database with the actual switch state
+func (s *ethernetSwitchState) getDrift(switchID uuid.UUID) domain.EthernetSwitchDrift {
+	drift := domain.EthernetSwitchDrift{
+		SwitchID:  switchID,
+		CheckedAt: time.Now(),
+		VLANs:     []domain.EthernetSwitchVLANDrift{},
+		Ports:     []domain.EthernetSwitchPortDrift{},
+	}
+	vlansIDs := append([]int{}, s.switchVLANs...)
+	for vlanID := range s.vlans {
+		if !utils.SliceContainsElement(vlansIDs, vlanID) {
+			vlansIDs = append(vlansIDs, vlanID)
+		}
+	}
+	sort.Ints(vlansIDs)
+	for _, vlanID := range vlansIDs {
+		if vlanDrift := s.getVLANDrift(vlanID); vlanDrift != nil {
+			drift.VLANs = append(drift.VLANs, *vlanDrift)
+		}
+	}
+	for _, port := range s.ports {
+		drift.Ports = append(drift.Ports, s.getPortDrift(port)...)
+	}
+	return drift
+}
+
+//getPortDrift gets differences of the port, there is an entry for each mismatched setting
+func (s *ethernetSwitchState) getPortDrift(port domain.EthernetSwitchPort) []domain.EthernetSwitchPortDrift {
+	portDrift := domain.EthernetSwitchPortDrift{
+		PortID:             port.ID,
+		PortName:           port.Name,
+		DatabasePOEEnabled: port.POEEnabled,
+		DatabasePVID:       port.PVID,
+	}
+	if s.missingPorts[port.Name] {
+		portDrift.State = domain.EthernetSwitchDriftMissingOnSwitch
+		return []domain.EthernetSwitchPortDrift{portDrift}
+	}
+	drifts := []domain.EthernetSwitchPortDrift{}
+	if switchPOEEnabled, poeIsKnown := s.switchPOE[port.Name]; poeIsKnown && switchPOEEnabled != port.POEEnabled {
+		poeDrift := portDrift
+		poeDrift.State = domain.EthernetSwitchDriftPOEMismatch
+		poeDrift.SwitchPOEEnabled = switchPOEEnabled
+		drifts = append(drifts, poeDrift)
+	}
+	if switchPVID := s.switchPVID[port.Name]; switchPVID != port.PVID {
+		pvidDrift := portDrift
+		pvidDrift.State = domain.EthernetSwitchDriftPVIDMismatch
+		pvidDrift.SwitchPVID = switchPVID
+		drifts = append(drifts, pvidDrift)
+	}
+	return drifts
+}
+
+//detectDrift reads switch state and compares it with the database, state reading error is saved in the drift
+//
+//Return
+//	domain.EthernetSwitchDrift - switch drift
+//	error - not found error if the switch doesn't exist, validation error if the switch has no manager
+func (e *EthernetSwitchService) detectDrift(ctx context.Context, switchID uuid.UUID) (domain.EthernetSwitchDrift, error) {
+	state, err := e.readSwitchState(ctx, switchID)
+	if err != nil {
+		if errors.As(err, errors.NotFound) || errors.As(err, errors.Validation) {
+			return domain.EthernetSwitchDrift{}, err
+		}
+		return domain.EthernetSwitchDrift{SwitchID: switchID, CheckedAt: time.Now(), Error: err.Error()}, nil
+	}
+	return state.getDrift(switchID), nil
+}
+
+//GetDrift detects differences between the switch configuration in the database and the actual switch state
+//
+//Params
+//	ctx - context
+//	switchID - ethernet switch ID
+//Return
+//	dtos.EthernetSwitchDriftDto - switch drift, error of the switch state reading is returned in the dto
+//	error - if an error occurs, otherwise nil
+func (e *EthernetSwitchService) GetDrift(ctx context.Context, switchID uuid.UUID) (dtos.EthernetSwitchDriftDto, error) {
+	dto := dtos.EthernetSwitchDriftDto{}
+	drift, err := e.detectDrift(ctx, switchID)
+	if err != nil {
+		return dto, err
+	}
+	e.driftMutex.Lock()
+	e.driftReports[switchID] = drift
+	e.driftMutex.Unlock()
+	mappers.MapEthernetSwitchDriftToDto(drift, &dto)
+	return dto, nil
+}
+
+//GetDriftReports gets last drift reports of the switches detected by the periodic check or by the drift request
+//
+//Return
+//	[]dtos.EthernetSwitchDriftDto - drift reports sorted by switch ID
+func (e *EthernetSwitchService) GetDriftReports() []dtos.EthernetSwitchDriftDto {
+	e.driftMutex.RLock()
+	defer e.driftMutex.RUnlock()
+	reports := []dtos.EthernetSwitchDriftDto{}
+	for _, drift := range e.driftReports {
+		dto := dtos.EthernetSwitchDriftDto{}
+		mappers.MapEthernetSwitchDriftToDto(drift, &dto)
+		reports = append(reports, dto)
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].SwitchID.String() < reports[j].SwitchID.String()
+	})
+	return reports
+}
+
+func (e *EthernetSwitchService) removeDriftReport(switchID uuid.UUID) {
+	e.driftMutex.Lock()
+	defer e.driftMutex.Unlock()
+	delete(e.driftReports, switchID)
+}
+
+//Reconcile eliminates differences between the switch configuration in the database and the actual switch state.
+//Ports that are not present on the switch can't be reconciled, they are kept in the database
+//
+//Params
+//	ctx - context
+//	switchID - ethernet switch ID
+//	reconcileDto - reconciliation mode
+//Return
+//	dtos.EthernetSwitchDriftDto - switch drift after reconciliation
+//	error - if an error occurs, otherwise nil
+func (e *EthernetSwitchService) Reconcile(ctx context.Context, switchID uuid.UUID, reconcileDto dtos.EthernetSwitchReconcileDto) (dtos.EthernetSwitchDriftDto, error) {
+	dto := dtos.EthernetSwitchDriftDto{}
+	err := validators.ValidateEthernetSwitchReconcileDto(reconcileDto)
+	if err != nil {
+		return dto, err //we already wrap error in validators
+	}
+	state, err := e.readSwitchState(ctx, switchID)
+	if err != nil {
+		return dto, err
+	}
+	drift := state.getDrift(switchID)
+	if reconcileDto.Mode == domain.EthernetSwitchReconcileImport {
+		err = e.importSwitchState(ctx, switchID, state, drift)
+	} else {
+		err = e.pushSwitchState(state, drift)
+	}
+	if err != nil {
+		return dto, err
+	}
+	if reconcileDto.Mode == domain.EthernetSwitchReconcilePush {
+		e.backupConfigAfterChange(ctx, switchID)
+	}
+	return e.GetDrift(ctx, switchID)
+}
+
+//importSwitchState writes actual switch state to the repositories
+func (e *EthernetSwitchService) importSwitchState(ctx context.Context, switchID uuid.UUID, state *ethernetSwitchState,
+	drift domain.EthernetSwitchDrift) error {
+	for _, vlanDrift := range drift.VLANs {
+		vlan := state.vlans[vlanDrift.VlanID]
+		switch vlanDrift.State {
+		case domain.EthernetSwitchDriftMissingOnSwitch:
+			err := e.vlanRepo.Delete(ctx, vlan.ID)
+			if err != nil {
+				return errors.Internal.Wrapf(err, "failed to delete VLAN %d", vlanDrift.VlanID)
+			}
+		case domain.EthernetSwitchDriftMissingInDatabase:
+			createDto := dtos.EthernetSwitchVLANCreateDto{
+				EthernetSwitchVLANBaseDto: dtos.EthernetSwitchVLANBaseDto{
+					TaggedPorts:   state.getPortsIDs(vlanDrift.SwitchTaggedPorts),
+					UntaggedPorts: state.getPortsIDs(vlanDrift.SwitchUntaggedPorts),
+				},
+				VlanID: vlanDrift.VlanID,
+			}
+			_, err := e.insertVLAN(ctx, switchID, createDto)
+			if err != nil {
+				return errors.Internal.Wrapf(err, "failed to insert VLAN %d", vlanDrift.VlanID)
+			}
+		case domain.EthernetSwitchDriftPortsMismatch:
+			updateDto := dtos.EthernetSwitchVLANUpdateDto{
+				EthernetSwitchVLANBaseDto: dtos.EthernetSwitchVLANBaseDto{
+					TaggedPorts: append(state.getPortsIDs(vlanDrift.SwitchTaggedPorts),
+						state.getMissingPortsIDs(vlan.TaggedPorts)...),
+					UntaggedPorts: append(state.getPortsIDs(vlanDrift.SwitchUntaggedPorts),
+						state.getMissingPortsIDs(vlan.UntaggedPorts)...),
+				},
+			}
+			queryBuilder := e.vlanRepo.NewQueryBuilder(ctx)
+			queryBuilder.Where("EthernetSwitchID", "==", switchID)
+			_, err := Update[dtos.EthernetSwitchVLANDto](ctx, e.vlanRepo, updateDto, vlan.ID, queryBuilder)
+			if err != nil {
+				return errors.Internal.Wrapf(err, "failed to update VLAN %d", vlanDrift.VlanID)
+			}
+		}
+	}
+	//port with several differences is updated once
+	updatedPorts := []string{}
+	for _, portDrift := range drift.Ports {
+		port := state.portsByName[portDrift.PortName]
+		switch portDrift.State {
+		case domain.EthernetSwitchDriftPOEMismatch:
+			port.POEEnabled = portDrift.SwitchPOEEnabled
+		case domain.EthernetSwitchDriftPVIDMismatch:
+			port.PVID = portDrift.SwitchPVID
+		default:
+			continue
+		}
+		state.portsByName[portDrift.PortName] = port
+		if !utils.SliceContainsElement(updatedPorts, portDrift.PortName) {
+			updatedPorts = append(updatedPorts, portDrift.PortName)
+		}
+	}
+	for _, portName := range updatedPorts {
+		_, err := e.portRepo.Update(ctx, state.portsByName[portName])
+		if err != nil {
+			return errors.Internal.Wrapf(err, "failed to update port %s", portName)
+		}
+	}
+	return nil
+}
+
+//getVLANPortsOperations gets operations that change VLAN ports on the switch to the database ports
+func getVLANPortsOperations(vlanDrift domain.EthernetSwitchVLANDrift) []domain.EthernetSwitchOperation {
+	portsNames := []string{}
+	for _, names := range [][]string{vlanDrift.DatabaseTaggedPorts, vlanDrift.DatabaseUntaggedPorts,
+		vlanDrift.SwitchTaggedPorts, vlanDrift.SwitchUntaggedPorts} {
+		for _, name := range names {
+			if !utils.SliceContainsElement(portsNames, name) {
+				portsNames = append(portsNames, name)
+			}
+		}
+	}
+	sort.Strings(portsNames)
+	operations := []domain.EthernetSwitchOperation{}
+	for _, name := range portsNames {
+		databaseTagged := utils.SliceContainsElement(vlanDrift.DatabaseTaggedPorts, name)
+		databaseUntagged := utils.SliceContainsElement(vlanDrift.DatabaseUntaggedPorts, name)
+		switchTagged := utils.SliceContainsElement(vlanDrift.SwitchTaggedPorts, name)
+		switchUntagged := utils.SliceContainsElement(vlanDrift.SwitchUntaggedPorts, name)
+		if databaseTagged == switchTagged && databaseUntagged == switchUntagged {
+			continue
+		}
+		operation := domain.EthernetSwitchOperation{PortName: name, VLANID: vlanDrift.VlanID}
+		if switchTagged || switchUntagged {
+			operation.Type = domain.EthernetSwitchOperationRemoveVLAN
+			operations = append(operations, operation)
+		}
+		if databaseTagged {
+			operation.Type = domain.EthernetSwitchOperationAddTaggedVLAN
+			operations = append(operations, operation)
+		} else if databaseUntagged {
+			operation.Type = domain.EthernetSwitchOperationAddUntaggedVLAN
+			operations = append(operations, operation)
+		}
+	}
+	return operations
+}
+
+//pushSwitchState applies configuration from the database to the switch and saves switch config
+func (e *EthernetSwitchService) pushSwitchState(state *ethernetSwitchState, drift domain.EthernetSwitchDrift) error {
+	operations := []domain.EthernetSwitchOperation{}
+	for _, vlanDrift := range drift.VLANs {
+		switch vlanDrift.State {
+		case domain.EthernetSwitchDriftMissingOnSwitch:
+			operations = append(operations, domain.EthernetSwitchOperation{
+				Type:   domain.EthernetSwitchOperationCreateVLAN,
+				VLANID: vlanDrift.VlanID,
+			})
+			operations = append(operations, getVLANPortsOperations(vlanDrift)...)
+		case domain.EthernetSwitchDriftMissingInDatabase:
+			operations = append(operations, domain.EthernetSwitchOperation{
+				Type:   domain.EthernetSwitchOperationDeleteVLAN,
+				VLANID: vlanDrift.VlanID,
+			})
+		case domain.EthernetSwitchDriftPortsMismatch:
+			operations = append(operations, getVLANPortsOperations(vlanDrift)...)
+		}
+	}
+	for _, portDrift := range drift.Ports {
+		switch portDrift.State {
+		case domain.EthernetSwitchDriftPOEMismatch:
+			operation := domain.EthernetSwitchOperation{
+				Type:     domain.EthernetSwitchOperationDisablePOE,
+				PortName: portDrift.PortName,
+			}
+			if portDrift.DatabasePOEEnabled {
+				operation.Type = domain.EthernetSwitchOperationEnablePOE
+				operation.POEType = state.portsByName[portDrift.PortName].POEType
+			}
+			operations = append(operations, operation)
+		case domain.EthernetSwitchDriftPVIDMismatch:
+			//VLANs are created above, so PVID is set after them
+			operations = append(operations, domain.EthernetSwitchOperation{
+				Type:     domain.EthernetSwitchOperationSetPVID,
+				PortName: portDrift.PortName,
+				VLANID:   portDrift.DatabasePVID,
+			})
+		}
+	}
+	if len(operations) == 0 {
+		return nil
+	}
+	err := state.manager.ApplyOperations(operations)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to push configuration to switch")
+	}
+	err = state.manager.SaveConfig()
+	if err != nil {
+		return errors.Internal.Wrap(err, "save switch config failed")
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"rol/app/errors"
+	"rol/app/mappers"
+	"rol/domain"
+	"rol/dtos"
+)
+
+//getCablingSuggestion gets device that is probably plugged into the port by the MAC address learned on it
+//
+//Params
+//	ctx - context
+//	portID - ethernet switch port ID
+//	mac - MAC address learned on the port
+//Return
+//	*dtos.EthernetSwitchCablingSuggestionDto - suggestion, nil if MAC address has no DHCP leases
+//	error - if an error occurs, otherwise nil
+func (e *EthernetSwitchService) getCablingSuggestion(ctx context.Context, portID uuid.UUID, mac string) (*dtos.EthernetSwitchCablingSuggestionDto, error) {
+	leaseQueryBuilder := e.leaseRepo.NewQueryBuilder(ctx)
+	leaseQueryBuilder.Where("MAC", "==", mac)
+	leases, err := e.leaseRepo.GetList(ctx, "Expires", "desc", 1, 1, leaseQueryBuilder)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to get dhcp leases")
+	}
+	if len(leases) == 0 {
+		return nil, nil
+	}
+	suggestion := &dtos.EthernetSwitchCablingSuggestionDto{
+		MAC:           mac,
+		IP:            leases[0].IP,
+		DHCP4ServerID: leases[0].DHCP4ConfigID,
+	}
+	interfaceQueryBuilder := e.interfaceRepo.NewQueryBuilder(ctx)
+	interfaceQueryBuilder.Where("MAC", "==", mac)
+	networkInterfaces, err := e.interfaceRepo.GetList(ctx, "", "", 1, 1, interfaceQueryBuilder)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to get device network interfaces")
+	}
+	if len(networkInterfaces) > 0 {
+		suggestion.DeviceID = networkInterfaces[0].DeviceID
+		suggestion.DeviceNetworkInterfaceID = networkInterfaces[0].ID
+		suggestion.Cabled = networkInterfaces[0].EthernetSwitchPortID == portID
+	}
+	return suggestion, nil
+}
+
+//GetPortNeighbors gets MAC addresses and LLDP neighbors that are visible on the switch port, learned MAC addresses
+//are matched with DHCP leases and devices network interfaces to suggest which device is plugged into the port
+//
+//Params
+//	ctx - context
+//	switchID - ethernet switch ID
+//	portID - ethernet switch port ID
+//Return
+//	dtos.EthernetSwitchPortNeighborsDto - port neighbors
+//	error - not found error if the switch or port doesn't exist, validation error if the switch has no manager
+//	or its model does not support LLDP, internal error if neighbors reading fails
+func (e *EthernetSwitchService) GetPortNeighbors(ctx context.Context, switchID, portID uuid.UUID) (dtos.EthernetSwitchPortNeighborsDto, error) {
+	dto := dtos.EthernetSwitchPortNeighborsDto{
+		MACAddresses:  []dtos.EthernetSwitchMACAddressDto{},
+		LLDPNeighbors: []dtos.EthernetSwitchLLDPNeighborDto{},
+		Suggestions:   []dtos.EthernetSwitchCablingSuggestionDto{},
+	}
+	port, err := e.GetPortByID(ctx, switchID, portID)
+	if err != nil {
+		return dto, err
+	}
+	err = e.modelCapabilityCheck(ctx, switchID, domain.EthernetSwitchCapabilityLLDP, "switch model does not support LLDP neighbors")
+	if err != nil {
+		return dto, err
+	}
+	switchManager, err := e.managers.Get(ctx, switchID)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, errorGetManager)
+	}
+	if switchManager == nil {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return dto, errors.AddErrorContext(err, "SwitchModel", errorSwitchNotManaged)
+	}
+	table, err := switchManager.GetMACAddressTable()
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to get switch mac address table")
+	}
+	neighbors, err := switchManager.GetLLDPNeighbors()
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to get switch lldp neighbors")
+	}
+	suggested := map[string]bool{}
+	for _, entry := range table {
+		if entry.PortName != port.Name {
+			continue
+		}
+		macDto := dtos.EthernetSwitchMACAddressDto{}
+		mappers.MapEthernetSwitchMACAddressToDto(entry, &macDto)
+		dto.MACAddresses = append(dto.MACAddresses, macDto)
+		//the same address can be learned in several VLANs of the port
+		if suggested[entry.MAC] {
+			continue
+		}
+		suggested[entry.MAC] = true
+		suggestion, err := e.getCablingSuggestion(ctx, portID, entry.MAC)
+		if err != nil {
+			return dto, err
+		}
+		if suggestion != nil {
+			dto.Suggestions = append(dto.Suggestions, *suggestion)
+		}
+	}
+	for _, neighbor := range neighbors {
+		if neighbor.PortName != port.Name {
+			continue
+		}
+		neighborDto := dtos.EthernetSwitchLLDPNeighborDto{}
+		mappers.MapEthernetSwitchLLDPNeighborToDto(neighbor, &neighborDto)
+		dto.LLDPNeighbors = append(dto.LLDPNeighbors, neighborDto)
+	}
+	return dto, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"github.com/google/uuid"
 	"rol/app/errors"
+	"rol/app/interfaces"
 	"rol/app/mappers"
 	"rol/app/utils"
 	"rol/app/validators"
@@ -16,16 +17,18 @@ func (e *EthernetSwitchService) syncPortPOEStatus(ctx context.Context, switchID
 	if err != nil {
 		return errors.Internal.Wrap(err, errorGetManager)
 	}
-	if switchManager == nil {
+	poeManager, ok := switchManager.(interfaces.IEthernetSwitchPOEManager)
+	if !ok {
+		//switch without PoE support or without manager, enabling is rejected by portCapabilitiesCheck
 		return nil
 	}
 	if POEEnabled {
-		err = switchManager.EnablePOEPort(portName, POEType)
+		err = poeManager.EnablePOEPort(portName, POEType)
 		if err != nil {
 			return errors.Internal.Wrap(err, "enable poe on port failed")
 		}
 	} else {
-		err = switchManager.DisablePOEPort(portName)
+		err = poeManager.DisablePOEPort(portName)
 		if err != nil {
 			return errors.Internal.Wrap(err, "disable poe on port failed")
 		}
@@ -229,6 +232,10 @@ func (e *EthernetSwitchService) CreatePort(ctx context.Context, switchID uuid.UU
 	if err != nil {
 		return dto, err //we already wrap error
 	}
+	err = e.portCapabilitiesCheck(ctx, switchID, createDto.Name, createDto.POEType, createDto.POEEnabled)
+	if err != nil {
+		return dto, err
+	}
 	entity := new(domain.EthernetSwitchPort)
 	entity.EthernetSwitchID = switchID
 	err = mappers.MapDtoToEntity(createDto, entity)
@@ -243,7 +250,12 @@ func (e *EthernetSwitchService) CreatePort(ctx context.Context, switchID uuid.UU
 	if err != nil {
 		return dto, errors.Internal.Wrap(err, "failed to map entity to dto")
 	}
-	return dto, e.syncPortConfOnSwitch(ctx, switchID, dto.Name, dto.POEType, dto.POEEnabled, dto.PVID)
+	err = e.syncPortConfOnSwitch(ctx, switchID, dto.Name, dto.POEType, dto.POEEnabled, dto.PVID)
+	if err != nil {
+		return dto, err
+	}
+	e.backupConfigAfterChange(ctx, switchID)
+	return dto, nil
 }
 
 //UpdatePort Update ethernet switch port
@@ -265,13 +277,22 @@ func (e *EthernetSwitchService) UpdatePort(ctx context.Context, switchID, id uui
 	if err != nil {
 		return dto, err // we already wrap error
 	}
+	err = e.portCapabilitiesCheck(ctx, switchID, updateDto.Name, updateDto.POEType, updateDto.POEEnabled)
+	if err != nil {
+		return dto, err
+	}
 	queryBuilder := e.portRepo.NewQueryBuilder(ctx)
 	queryBuilder.Where("EthernetSwitchId", "==", switchID)
 	updatedPort, err := Update[dtos.EthernetSwitchPortDto](ctx, e.portRepo, updateDto, id, queryBuilder)
 	if err != nil {
 		return dto, err // we already wrap error in Update()
 	}
-	return updatedPort, e.syncPortConfOnSwitch(ctx, switchID, dto.Name, dto.POEType, dto.POEEnabled, dto.PVID)
+	err = e.syncPortConfOnSwitch(ctx, switchID, updatedPort.Name, updatedPort.POEType, updatedPort.POEEnabled, updatedPort.PVID)
+	if err != nil {
+		return updatedPort, err
+	}
+	e.backupConfigAfterChange(ctx, switchID)
+	return updatedPort, nil
 }
 
 //GetPorts Get list of ethernet switch ports with filtering and pagination
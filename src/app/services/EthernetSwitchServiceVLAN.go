@@ -17,8 +17,6 @@ const (
 	errorPortExistence   = "error when checking the existence of the switch port"
 	errorSwitchNotFound  = "switch is not found"
 	errorGetPortByID     = "get port by id failed"
-	errorAddTaggedVLAN   = "add tagged VLAN on port failed"
-	errorRemoveVLAN      = "failed to remove VLAN from port"
 	errorGetManager      = "can't get ethernet switch manager"
 )
 
@@ -86,29 +84,32 @@ func (e *EthernetSwitchService) createVlanOnSwitch(ctx context.Context, switchID
 	if switchManager == nil {
 		return nil
 	}
-	err = switchManager.CreateVLAN(dto.VlanID)
+	operations := []domain.EthernetSwitchOperation{{Type: domain.EthernetSwitchOperationCreateVLAN, VLANID: dto.VlanID}}
+	taggedPortsNames, err := e.getPortsNames(ctx, dto.TaggedPorts)
 	if err != nil {
-		return errors.Internal.Wrap(err, "create VLAN on switch failed")
+		return err
 	}
-	for _, taggedPortID := range dto.TaggedPorts {
-		switchPort, err := e.portRepo.GetByID(ctx, taggedPortID)
-		if err != nil {
-			return errors.Internal.Wrap(err, errorGetPortByID)
-		}
-		err = switchManager.AddTaggedVLANOnPort(switchPort.Name, dto.VlanID)
-		if err != nil {
-			return errors.Internal.Wrap(err, errorAddTaggedVLAN)
-		}
+	for _, portName := range taggedPortsNames {
+		operations = append(operations, domain.EthernetSwitchOperation{
+			Type:     domain.EthernetSwitchOperationAddTaggedVLAN,
+			PortName: portName,
+			VLANID:   dto.VlanID,
+		})
 	}
-	for _, untaggedPortID := range dto.UntaggedPorts {
-		switchPort, err := e.portRepo.GetByID(ctx, untaggedPortID)
-		if err != nil {
-			return errors.Internal.Wrap(err, errorGetPortByID)
-		}
-		err = switchManager.AddUntaggedVLANOnPort(switchPort.Name, dto.VlanID)
-		if err != nil {
-			return errors.Internal.Wrap(err, errorAddTaggedVLAN)
-		}
+	untaggedPortsNames, err := e.getPortsNames(ctx, dto.UntaggedPorts)
+	if err != nil {
+		return err
+	}
+	for _, portName := range untaggedPortsNames {
+		operations = append(operations, domain.EthernetSwitchOperation{
+			Type:     domain.EthernetSwitchOperationAddUntaggedVLAN,
+			PortName: portName,
+			VLANID:   dto.VlanID,
+		})
+	}
+	err = switchManager.ApplyOperations(operations)
+	if err != nil {
+		return errors.Internal.Wrap(err, "create VLAN on switch failed")
 	}
 	return nil
 }
@@ -222,6 +223,7 @@ func (e *EthernetSwitchService) CreateVLAN(ctx context.Context, switchID uuid.UU
 		if err != nil {
 			return dto, errors.Internal.Wrap(err, "save switch config failed")
 		}
+		e.backupConfigAfterChange(ctx, switchID)
 	}
 
 	//Convert configuration to dto
@@ -272,6 +274,7 @@ func (e *EthernetSwitchService) UpdateVLAN(ctx context.Context, switchID, id uui
 		if err != nil {
 			return dto, errors.Internal.Wrap(err, "save switch config failed")
 		}
+		e.backupConfigAfterChange(ctx, switchID)
 	}
 
 	// Map entity to dto
@@ -317,13 +320,14 @@ func (e *EthernetSwitchService) DeleteVLAN(ctx context.Context, switchID, id uui
 		if err != nil {
 			return errors.Internal.Wrap(err, "failed to save config")
 		}
+		e.backupConfigAfterChange(ctx, switchID)
 	}
 
 	//Remove from repository
 	return e.vlanRepo.Delete(ctx, id)
 }
 
-//syncVlanChangesOnSwitch apply vlan configuration to ethernet switch
+//syncVlanChangesOnSwitch apply vlan configuration to ethernet switch, all port changes are applied in one batch
 func (e *EthernetSwitchService) syncVlanChangesOnSwitch(ctx context.Context, VLAN dtos.EthernetSwitchVLANDto, updateDto dtos.EthernetSwitchVLANUpdateDto, switchID uuid.UUID) error {
 	switchManager, err := e.managers.Get(ctx, switchID)
 	if err != nil {
@@ -332,49 +336,49 @@ func (e *EthernetSwitchService) syncVlanChangesOnSwitch(ctx context.Context, VLA
 	if switchManager == nil {
 		return nil
 	}
+	operations := []domain.EthernetSwitchOperation{}
 	diffTaggedToRemove, diffTaggedToAdd := utils.SliceDiffElements[uuid.UUID](VLAN.TaggedPorts, updateDto.TaggedPorts)
-	for _, id := range diffTaggedToRemove {
-		switchPort, err := e.portRepo.GetByID(ctx, id)
-		if err != nil {
-			return errors.Internal.Wrap(err, errorGetPortByID)
-		}
-		err = switchManager.RemoveVLANFromPort(switchPort.Name, VLAN.VlanID)
-		if err != nil {
-			return errors.Internal.Wrap(err, errorRemoveVLAN)
-		}
-	}
-	for _, id := range diffTaggedToAdd {
-		switchPort, err := e.portRepo.GetByID(ctx, id)
-		if err != nil {
-			return errors.Internal.Wrap(err, errorGetPortByID)
-		}
-		err = switchManager.AddTaggedVLANOnPort(switchPort.Name, VLAN.VlanID)
-		if err != nil {
-			return errors.Internal.Wrap(err, "failed to add tagged VLAN on port")
-		}
-	}
 	diffUntaggedToRemove, diffUntaggedToAdd := utils.SliceDiffElements[uuid.UUID](VLAN.UntaggedPorts, updateDto.UntaggedPorts)
-	for _, id := range diffUntaggedToRemove {
-		switchPort, err := e.portRepo.GetByID(ctx, id)
+	portsOperations := []struct {
+		portsIDs      []uuid.UUID
+		operationType string
+	}{
+		{diffTaggedToRemove, domain.EthernetSwitchOperationRemoveVLAN},
+		{diffTaggedToAdd, domain.EthernetSwitchOperationAddTaggedVLAN},
+		{diffUntaggedToRemove, domain.EthernetSwitchOperationRemoveVLAN},
+		{diffUntaggedToAdd, domain.EthernetSwitchOperationAddUntaggedVLAN},
+	}
+	for _, portsOperation := range portsOperations {
+		portsNames, err := e.getPortsNames(ctx, portsOperation.portsIDs)
 		if err != nil {
-			return errors.Internal.Wrap(err, errorGetPortByID)
+			return err
 		}
-		err = switchManager.RemoveVLANFromPort(switchPort.Name, VLAN.VlanID)
-		if err != nil {
-			return errors.Internal.Wrap(err, errorRemoveVLAN)
+		for _, portName := range portsNames {
+			operations = append(operations, domain.EthernetSwitchOperation{
+				Type:     portsOperation.operationType,
+				PortName: portName,
+				VLANID:   VLAN.VlanID,
+			})
 		}
 	}
-	for _, id := range diffUntaggedToAdd {
+	err = switchManager.ApplyOperations(operations)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to apply VLAN changes on switch ports")
+	}
+	return nil
+}
+
+//getPortsNames gets switch ports names by their IDs
+func (e *EthernetSwitchService) getPortsNames(ctx context.Context, portsIDs []uuid.UUID) ([]string, error) {
+	portsNames := []string{}
+	for _, id := range portsIDs {
 		switchPort, err := e.portRepo.GetByID(ctx, id)
 		if err != nil {
-			return errors.Internal.Wrap(err, errorGetPortByID)
-		}
-		err = switchManager.AddUntaggedVLANOnPort(switchPort.Name, VLAN.VlanID)
-		if err != nil {
-			return errors.Internal.Wrap(err, "failed to add untagged VLAN on port")
+			return nil, errors.Internal.Wrap(err, errorGetPortByID)
 		}
+		portsNames = append(portsNames, switchPort.Name)
 	}
-	return nil
+	return portsNames, nil
 }
 
 func (e *EthernetSwitchService) deleteAllVLANsBySwitchID(ctx context.Context, switchID uuid.UUID) error {
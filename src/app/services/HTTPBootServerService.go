@@ -0,0 +1,355 @@
+// Package services stores business logic for each entity
+package services
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"reflect"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/mappers"
+	"rol/app/validators"
+	"rol/domain"
+	"rol/dtos"
+	"sync"
+)
+
+//HTTPBootServerService service structure for HTTP boot file servers
+type HTTPBootServerService struct {
+	configsRepo interfaces.IGenericRepository[uuid.UUID, domain.HTTPBootConfig]
+	pathsRepo   interfaces.IGenericRepository[uuid.UUID, domain.HTTPBootPathRatio]
+	factory     interfaces.IHTTPBootServerFactory
+	servers     map[uuid.UUID]interfaces.IHTTPBootServer
+	//serversMutex guards servers map
+	serversMutex sync.RWMutex
+	logger       *logrus.Logger
+	//logSourceName - logger recording source
+	logSourceName string
+}
+
+//NewHTTPBootServerService constructor for HTTP boot server service
+//
+//Params
+//	configsRepo - generic repository with domain.HTTPBootConfig entity
+//	pathsRepo - generic repository with domain.HTTPBootPathRatio entity
+//	factory - http boot server factory
+//	logger - logrus logger
+//Return
+//	New HTTP boot server service
+func NewHTTPBootServerService(configsRepo interfaces.IGenericRepository[uuid.UUID, domain.HTTPBootConfig],
+	pathsRepo interfaces.IGenericRepository[uuid.UUID, domain.HTTPBootPathRatio],
+	factory interfaces.IHTTPBootServerFactory, logger *logrus.Logger) *HTTPBootServerService {
+	return &HTTPBootServerService{
+		configsRepo:   configsRepo,
+		pathsRepo:     pathsRepo,
+		factory:       factory,
+		logger:        logger,
+		logSourceName: reflect.TypeOf(HTTPBootServerService{}).Name(),
+		servers:       map[uuid.UUID]interfaces.IHTTPBootServer{},
+	}
+}
+
+func (s *HTTPBootServerService) log(ctx context.Context, level, message string) {
+	if ctx != nil {
+		actionID := uuid.UUID{}
+		if ctx.Value("requestID") != nil {
+			actionID = ctx.Value("requestID").(uuid.UUID)
+		}
+
+		entry := s.logger.WithFields(logrus.Fields{
+			"actionID": actionID,
+			"source":   s.logSourceName,
+		})
+		switch level {
+		case "err", "error":
+			entry.Error(message)
+		case "info":
+			entry.Info(message)
+		case "warn", "warning":
+			entry.Warn(message)
+		case "debug":
+			entry.Debug(message)
+		}
+	}
+}
+
+func (s *HTTPBootServerService) getServer(id uuid.UUID) (interfaces.IHTTPBootServer, bool) {
+	s.serversMutex.RLock()
+	defer s.serversMutex.RUnlock()
+	server, ok := s.servers[id]
+	return server, ok
+}
+
+func (s *HTTPBootServerService) setServer(id uuid.UUID, server interfaces.IHTTPBootServer) {
+	s.serversMutex.Lock()
+	defer s.serversMutex.Unlock()
+	s.servers[id] = server
+}
+
+func (s *HTTPBootServerService) removeServer(id uuid.UUID) {
+	s.serversMutex.Lock()
+	defer s.serversMutex.Unlock()
+	delete(s.servers, id)
+}
+
+func (s *HTTPBootServerService) getServerState(configID uuid.UUID) domain.HTTPBootServerState {
+	if server, ok := s.getServer(configID); ok {
+		return server.GetState()
+	}
+	return domain.HTTPBootStateStopped
+}
+
+func (s *HTTPBootServerService) serverExistenceCheck(ctx context.Context, id uuid.UUID) error {
+	exist, err := s.configsRepo.IsExist(ctx, id, nil)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to check existence of http boot server")
+	}
+	if !exist {
+		return errors.NotFound.New("http boot server with this id is not found")
+	}
+	return nil
+}
+
+func (s *HTTPBootServerService) getQueryBuilderWithConfigID(ctx context.Context, configID uuid.UUID) interfaces.IQueryBuilder {
+	queryBuilder := s.pathsRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("HTTPBootConfigID", "==", configID)
+	return queryBuilder
+}
+
+func (s *HTTPBootServerService) getServerPaths(ctx context.Context, id uuid.UUID) ([]domain.HTTPBootPathRatio, error) {
+	queryBuilder := s.getQueryBuilderWithConfigID(ctx, id)
+	pathsCount, err := s.pathsRepo.Count(ctx, queryBuilder)
+	if err != nil {
+		return []domain.HTTPBootPathRatio{}, errors.Internal.Wrap(err, "failed to count http boot paths")
+	}
+	return s.pathsRepo.GetList(ctx, "", "", 1, int(pathsCount), queryBuilder)
+}
+
+//updateRuntimeServerPaths reloads paths of the runtime server on the fly
+func (s *HTTPBootServerService) updateRuntimeServerPaths(ctx context.Context, id uuid.UUID) error {
+	server, ok := s.getServer(id)
+	if !ok {
+		return nil
+	}
+	paths, err := s.getServerPaths(ctx, id)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to get http boot server paths")
+	}
+	err = server.ReloadPaths(paths)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to reload http boot server paths")
+	}
+	return nil
+}
+
+//updateRuntimeServer creates or restarts runtime server with the new config.
+//Runtime server is kept for the disabled config as well, so its download statistics are not lost
+func (s *HTTPBootServerService) updateRuntimeServer(ctx context.Context, config domain.HTTPBootConfig) error {
+	server, ok := s.getServer(config.ID)
+	if !ok {
+		var err error
+		server, err = s.factory.Create(config)
+		if err != nil {
+			return errors.Internal.Wrapf(err, "failed to create http boot server with id: %s", config.ID.String())
+		}
+		s.setServer(config.ID, server)
+	}
+	server.Stop()
+	err := server.ReloadConfig(config)
+	if err != nil {
+		return errors.Internal.Wrapf(err, "failed to reload http boot server config with id: %s", config.ID.String())
+	}
+	err = s.updateRuntimeServerPaths(ctx, config.ID)
+	if err != nil {
+		return err
+	}
+	if !config.Enabled {
+		return nil
+	}
+	//address can be busy or absent, so error state is kept and reported through the server state
+	err = server.Start()
+	if err != nil {
+		s.log(ctx, "error", errors.Internal.Wrapf(err, "failed to start http boot server with id: %s", config.ID.String()).Error())
+	}
+	return nil
+}
+
+//HTTPBootServerServiceInit creates runtime servers for all configs and starts enabled ones
+func HTTPBootServerServiceInit(s *HTTPBootServerService) error {
+	ctx := context.Background()
+	configsCount, err := s.configsRepo.Count(ctx, nil)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to count http boot servers configs")
+	}
+	configs, err := s.configsRepo.GetList(ctx, "", "", 1, int(configsCount), nil)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to get http boot servers configs list")
+	}
+	for _, config := range configs {
+		err = s.updateRuntimeServer(ctx, config)
+		if err != nil {
+			return errors.Internal.Wrapf(err, "reload config for http boot server with id %s failed", config.ID.String())
+		}
+	}
+	return nil
+}
+
+//GetServerByID get HTTP boot server by ID
+//
+//Params
+//	ctx - context
+//	id - HTTP boot server id
+//Return
+//	dtos.HTTPBootServerDto - HTTP boot server dto
+//	error - if an error occurs, otherwise nil
+func (s *HTTPBootServerService) GetServerByID(ctx context.Context, id uuid.UUID) (dtos.HTTPBootServerDto, error) {
+	dto, err := GetByID[dtos.HTTPBootServerDto](ctx, s.configsRepo, id, nil)
+	if err != nil {
+		return dto, err
+	}
+	dto.State = s.getServerState(dto.ID).String()
+	return dto, nil
+}
+
+//GetServerList get list of HTTP boot servers with filtering and pagination
+//
+//Params
+//	ctx - context
+//	search - string for search in entity string fields
+//	orderBy - order by entity field name
+//	orderDirection - ascending or descending order
+//	page - page number
+//	pageSize - page size
+//Return
+//	dtos.PaginatedItemsDto[dtos.HTTPBootServerDto] - paginated list of HTTP boot servers
+//	error - if an error occurs, otherwise nil
+func (s *HTTPBootServerService) GetServerList(ctx context.Context, search, orderBy, orderDirection string, page, pageSize int) (dtos.PaginatedItemsDto[dtos.HTTPBootServerDto], error) {
+	servers, err := GetList[dtos.HTTPBootServerDto](ctx, s.configsRepo, search, orderBy, orderDirection, page, pageSize)
+	if err != nil {
+		return servers, err
+	}
+	for i, server := range servers.Items {
+		(&servers.Items[i]).State = s.getServerState(server.ID).String()
+	}
+	return servers, nil
+}
+
+//CreateServer add new HTTP boot server
+//
+//Params
+//	ctx - context
+//	createDto - HTTP boot server create dto
+//Return
+//	dtos.HTTPBootServerDto - created HTTP boot server
+//	error - if an error occurs, otherwise nil
+func (s *HTTPBootServerService) CreateServer(ctx context.Context, createDto dtos.HTTPBootServerCreateDto) (dtos.HTTPBootServerDto, error) {
+	dto := dtos.HTTPBootServerDto{}
+	err := validators.ValidateHTTPBootServerCreateDto(createDto)
+	if err != nil {
+		return dto, err
+	}
+	entity := new(domain.HTTPBootConfig)
+	err = mappers.MapDtoToEntity(createDto, entity)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "error map entity to dto")
+	}
+	config, err := s.configsRepo.Insert(ctx, *entity)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "create http boot server config error")
+	}
+	err = s.updateRuntimeServer(ctx, config)
+	if err != nil {
+		return dto, err
+	}
+	err = mappers.MapEntityToDto(config, &dto)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "error map dto to entity")
+	}
+	dto.State = s.getServerState(config.ID).String()
+	return dto, nil
+}
+
+//UpdateServer save the changes to the existing HTTP boot server and restart it
+//
+//Params
+//	ctx - context is used only for logging
+//	id - HTTP boot server id
+//	updateDto - HTTP boot server update dto
+//Return
+//	dtos.HTTPBootServerDto - updated HTTP boot server
+//	error - if an error occurs, otherwise nil
+func (s *HTTPBootServerService) UpdateServer(ctx context.Context, id uuid.UUID, updateDto dtos.HTTPBootServerUpdateDto) (dtos.HTTPBootServerDto, error) {
+	dto := dtos.HTTPBootServerDto{}
+	err := validators.ValidateHTTPBootServerUpdateDto(updateDto)
+	if err != nil {
+		return dto, err
+	}
+	dto, err = Update[dtos.HTTPBootServerDto](ctx, s.configsRepo, updateDto, id, nil)
+	if err != nil {
+		return dto, err
+	}
+	config, err := s.configsRepo.GetByID(ctx, id)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "failed to get http boot server config")
+	}
+	err = s.updateRuntimeServer(ctx, config)
+	if err != nil {
+		return dto, err
+	}
+	dto.State = s.getServerState(config.ID).String()
+	return dto, nil
+}
+
+//DeleteServer stop and delete HTTP boot server with its paths
+//
+//Params
+//	ctx - context is used only for logging
+//	id - HTTP boot server id
+//Return
+//	error - if an error occurs, otherwise nil
+func (s *HTTPBootServerService) DeleteServer(ctx context.Context, id uuid.UUID) error {
+	err := s.serverExistenceCheck(ctx, id)
+	if err != nil {
+		return err
+	}
+	if server, ok := s.getServer(id); ok {
+		server.Stop()
+		s.removeServer(id)
+	}
+	err = s.pathsRepo.DeleteAll(ctx, s.getQueryBuilderWithConfigID(ctx, id))
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to remove all http boot server paths ratios")
+	}
+	err = s.configsRepo.Delete(ctx, id)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to delete http boot server config")
+	}
+	return nil
+}
+
+//GetFileStats get download statistics of the files served by the HTTP boot server since its start
+//
+//Params
+//	ctx - context is used only for logging
+//	id - HTTP boot server id
+//Return
+//	[]dtos.HTTPBootFileStatsDto - statistics sorted by virtual path
+//	error - if an error occurs, otherwise nil
+func (s *HTTPBootServerService) GetFileStats(ctx context.Context, id uuid.UUID) ([]dtos.HTTPBootFileStatsDto, error) {
+	statsDtos := []dtos.HTTPBootFileStatsDto{}
+	err := s.serverExistenceCheck(ctx, id)
+	if err != nil {
+		return statsDtos, err
+	}
+	server, ok := s.getServer(id)
+	if !ok {
+		return statsDtos, nil
+	}
+	for _, stats := range server.GetFileStats() {
+		statsDto := dtos.HTTPBootFileStatsDto{}
+		mappers.MapHTTPBootFileStatsToDto(stats, &statsDto)
+		statsDtos = append(statsDtos, statsDto)
+	}
+	return statsDtos, nil
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"rol/app/errors"
+	"rol/app/mappers"
+	"rol/app/validators"
+	"rol/domain"
+	"rol/dtos"
+)
+
+//GetPathsList get list of HTTP boot server paths with pagination
+//
+//Params
+//	ctx - context is used only for logging
+//	configID - http boot server id
+//	orderBy - order by entity field name
+//	orderDirection - ascending or descending order
+//	page - page number
+//	pageSize - page size
+//Return
+//	dtos.PaginatedItemsDto[dtos.HTTPBootPathDto] - paginated list of HTTP boot server paths
+//	error - if an error occurs, otherwise nil
+func (s *HTTPBootServerService) GetPathsList(ctx context.Context, configID uuid.UUID, orderBy, orderDirection string, page, pageSize int) (dtos.PaginatedItemsDto[dtos.HTTPBootPathDto], error) {
+	err := s.serverExistenceCheck(ctx, configID)
+	if err != nil {
+		return dtos.NewEmptyPaginatedItemsDto[dtos.HTTPBootPathDto](), err
+	}
+	return GetListExtended[dtos.HTTPBootPathDto](ctx, s.pathsRepo, s.getQueryBuilderWithConfigID(ctx, configID), orderBy, orderDirection, page, pageSize)
+}
+
+//GetPathByID get HTTP boot server path by id
+//
+//Params
+//	ctx - context
+//	configID - HTTP boot server id
+//	pathID - HTTP boot path id
+//Return
+//	dtos.HTTPBootPathDto - HTTP boot path dto
+//	error - if an error occurs, otherwise nil
+func (s *HTTPBootServerService) GetPathByID(ctx context.Context, configID, pathID uuid.UUID) (dtos.HTTPBootPathDto, error) {
+	return GetByID[dtos.HTTPBootPathDto](ctx, s.pathsRepo, pathID, s.getQueryBuilderWithConfigID(ctx, configID))
+}
+
+//CreatePath add new HTTP boot server path
+//
+//Params
+//	ctx - context
+//	configID - http boot server id
+//	createDto - HTTP boot path create dto
+//Return
+//	dtos.HTTPBootPathDto - created HTTP boot path
+//	error - if an error occurs, otherwise nil
+func (s *HTTPBootServerService) CreatePath(ctx context.Context, configID uuid.UUID, createDto dtos.HTTPBootPathCreateDto) (dtos.HTTPBootPathDto, error) {
+	dto := dtos.HTTPBootPathDto{}
+	err := validators.ValidateHTTPBootPathCreateDto(createDto)
+	if err != nil {
+		return dto, err
+	}
+	err = s.serverExistenceCheck(ctx, configID)
+	if err != nil {
+		return dto, err
+	}
+	entity := new(domain.HTTPBootPathRatio)
+	err = mappers.MapDtoToEntity(createDto, entity)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "error map entity to dto")
+	}
+	entity.HTTPBootConfigID = configID
+	newEntity, err := s.pathsRepo.Insert(ctx, *entity)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "create entity error")
+	}
+	err = s.updateRuntimeServerPaths(ctx, configID)
+	if err != nil {
+		s.log(ctx, "error", err.Error())
+	}
+	err = mappers.MapEntityToDto(newEntity, &dto)
+	if err != nil {
+		return dto, errors.Internal.Wrap(err, "error map dto to entity")
+	}
+	return dto, nil
+}
+
+//UpdatePath update HTTP boot server path
+//
+//Params
+//	ctx - context
+//	configID - http boot server id
+//	pathID - http boot path id
+//	updateDto - HTTP boot path update dto
+//Return
+//	dtos.HTTPBootPathDto - updated HTTP boot path
+//	error - if an error occurs, otherwise nil
+func (s *HTTPBootServerService) UpdatePath(ctx context.Context, configID, pathID uuid.UUID, updateDto dtos.HTTPBootPathUpdateDto) (dtos.HTTPBootPathDto, error) {
+	dto := dtos.HTTPBootPathDto{}
+	err := validators.ValidateHTTPBootPathUpdateDto(updateDto)
+	if err != nil {
+		return dto, err
+	}
+	dto, err = Update[dtos.HTTPBootPathDto](ctx, s.pathsRepo, updateDto, pathID, s.getQueryBuilderWithConfigID(ctx, configID))
+	if err != nil {
+		return dto, err
+	}
+	err = s.updateRuntimeServerPaths(ctx, configID)
+	if err != nil {
+		s.log(ctx, "error", err.Error())
+	}
+	return dto, nil
+}
+
+//DeletePath delete HTTP boot server path
+//
+//Params
+//	ctx - context is used only for logging
+//	configID - http boot server id
+//	pathID - HTTP boot path id
+//Return
+//	error - if an error occurs, otherwise nil
+func (s *HTTPBootServerService) DeletePath(ctx context.Context, configID, pathID uuid.UUID) error {
+	exist, err := s.pathsRepo.IsExist(ctx, pathID, s.getQueryBuilderWithConfigID(ctx, configID))
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to check existence of http boot path")
+	}
+	if !exist {
+		return errors.NotFound.New("path ratio not found on http boot server")
+	}
+	err = s.pathsRepo.Delete(ctx, pathID)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to delete http boot path")
+	}
+	err = s.updateRuntimeServerPaths(ctx, configID)
+	if err != nil {
+		s.log(ctx, "error", err.Error())
+	}
+	return nil
+}
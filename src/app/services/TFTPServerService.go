@@ -9,8 +9,11 @@ import (
 	"rol/app/errors"
 	"rol/app/interfaces"
 	"rol/app/mappers"
+	"rol/app/validators"
 	"rol/domain"
 	"rol/dtos"
+	"strings"
+	"sync"
 )
 
 //TFTPServerService service structure for TFTP server
@@ -19,7 +22,11 @@ type TFTPServerService struct {
 	pathsRepo   interfaces.IGenericRepository[uuid.UUID, domain.TFTPPathRatio]
 	factory     interfaces.ITFTPServerFactory
 	servers     map[uuid.UUID]interfaces.ITFTPServer
-	logger      *logrus.Logger
+	//serversMutex guards servers map and serializes start and stop of the runtime servers with the supervisor
+	serversMutex sync.RWMutex
+	//restarts backoff of the failed servers restarts, used only by the supervisor
+	restarts map[uuid.UUID]*tftpRestartBackoff
+	logger   *logrus.Logger
 	//logSourceName - logger recording source
 	logSourceName string
 }
@@ -43,6 +50,7 @@ func NewTFTPServerService(configsRepo interfaces.IGenericRepository[uuid.UUID, d
 		logger:        logger,
 		logSourceName: reflect.TypeOf(TFTPServerService{}).Name(),
 		servers:       map[uuid.UUID]interfaces.ITFTPServer{},
+		restarts:      map[uuid.UUID]*tftpRestartBackoff{},
 	}
 }
 
@@ -70,32 +78,42 @@ func (s *TFTPServerService) log(ctx context.Context, level, message string) {
 	}
 }
 
-func (s *TFTPServerService) getServerState(configID uuid.UUID) domain.TFTPServerState {
-	if server, ok := s.servers[configID]; ok {
-		return server.GetState()
+func (s *TFTPServerService) getServer(id uuid.UUID) (interfaces.ITFTPServer, bool) {
+	s.serversMutex.RLock()
+	defer s.serversMutex.RUnlock()
+	server, ok := s.servers[id]
+	return server, ok
+}
+
+//setRuntimeState writes state and last error of the runtime server to the dto
+func (s *TFTPServerService) setRuntimeState(dto *dtos.TFTPServerDto) {
+	dto.State = domain.TFTPStateStopped.String()
+	dto.LastError = ""
+	if server, ok := s.getServer(dto.ID); ok {
+		dto.State = server.GetState().String()
+		dto.LastError = server.GetLastError()
 	}
-	return domain.TFTPStateStopped
 }
 
+//updateRuntimeServerConfig creates, restarts or removes runtime server according to the config.
+//Server that failed to start stays in the pool in the error state, so the supervisor can restart it
 func (s *TFTPServerService) updateRuntimeServerConfig(config domain.TFTPConfig) error {
+	s.serversMutex.Lock()
+	defer s.serversMutex.Unlock()
+	server, ok := s.servers[config.ID]
 	//server is not existed in the pool
-	if _, ok := s.servers[config.ID]; !ok {
+	if !ok {
 		if !config.Enabled {
 			return nil
 		}
-		server, err := s.factory.Create(config)
+		var err error
+		server, err = s.factory.Create(config)
 		if err != nil {
 			return errors.Internal.Wrapf(err, "failed to create server with id: %s", config.ID.String())
 		}
 		s.servers[config.ID] = server
-		err = server.Start()
-		if err != nil {
-			return errors.Internal.Wrapf(err, "failed to start tftp server with config id: %s", config.ID.String())
-		}
-		return nil
-	}
-	//server existed in the pool
-	if server, ok := s.servers[config.ID]; ok {
+	} else {
+		//server existed in the pool
 		server.Stop()
 		if !config.Enabled {
 			delete(s.servers, config.ID)
@@ -105,10 +123,10 @@ func (s *TFTPServerService) updateRuntimeServerConfig(config domain.TFTPConfig)
 		if err != nil {
 			return errors.Internal.Wrapf(err, "failed to tftp server config with id: %s", config.ID.String())
 		}
-		err = server.Start()
-		if err != nil {
-			return errors.Internal.Wrapf(err, "failed to start tftp server with config id: %s", config.ID.String())
-		}
+	}
+	err := server.Start()
+	if err != nil {
+		return errors.Internal.Wrapf(err, "failed to start tftp server with config id: %s", config.ID.String())
 	}
 	return nil
 }
@@ -124,11 +142,8 @@ func (s *TFTPServerService) getServerPaths(ctx context.Context, id uuid.UUID) ([
 }
 
 func (s *TFTPServerService) updateRuntimeServerPaths(ctx context.Context, id uuid.UUID) error {
-	var (
-		server interfaces.ITFTPServer
-		ok     bool
-	)
-	if server, ok = s.servers[id]; !ok {
+	server, ok := s.getServer(id)
+	if !ok {
 		return nil
 	}
 	paths, err := s.getServerPaths(ctx, id)
@@ -156,15 +171,17 @@ func TFTPServerServiceInit(s *TFTPServerService) error {
 		return errors.Internal.Wrap(err, "failed to get tftp server configs list")
 	}
 	for _, config := range enabledServersConfigs {
+		//server that failed to start is restarted by the supervisor, so it doesn't break the app start
 		err = s.updateRuntimeServerConfig(config)
 		if err != nil {
-			return errors.Internal.Wrapf(err, "reload config for tftp server with id %s failed", config.ID.String())
+			s.log(ctx, "err", errors.Internal.Wrapf(err, "reload config for tftp server with id %s failed", config.ID.String()).Error())
 		}
 		err = s.updateRuntimeServerPaths(ctx, config.ID)
 		if err != nil {
 			return errors.Internal.Wrapf(err, "reload path ratios for tftp server with id %s failed", config.ID.String())
 		}
 	}
+	go s.supervisor()
 	return nil
 }
 
@@ -181,7 +198,7 @@ func (s *TFTPServerService) GetServerByID(ctx context.Context, id uuid.UUID) (dt
 	if err != nil {
 		return dto, errors.Internal.Wrap(err, "failed to get tftp server config")
 	}
-	dto.State = s.getServerState(dto.ID).String()
+	s.setRuntimeState(&dto)
 	return dto, err
 }
 
@@ -202,8 +219,8 @@ func (s *TFTPServerService) GetServerList(ctx context.Context, search, orderBy,
 	if err != nil {
 		return servers, errors.Internal.Wrap(err, "failed to get tftp server configs list")
 	}
-	for i, server := range servers.Items {
-		(&servers.Items[i]).State = s.getServerState(server.ID).String()
+	for i := range servers.Items {
+		s.setRuntimeState(&servers.Items[i])
 	}
 	return servers, nil
 }
@@ -219,9 +236,13 @@ func (s *TFTPServerService) GetServerList(ctx context.Context, search, orderBy,
 func (s *TFTPServerService) CreateServer(ctx context.Context, createDto dtos.TFTPServerCreateDto) (dtos.TFTPServerDto, error) {
 	//prepare dto and entity
 	dto := dtos.TFTPServerDto{}
+	err := validators.ValidateTFTPServerCreateDto(createDto)
+	if err != nil {
+		return dto, err
+	}
 	entity := new(domain.TFTPConfig)
 	//map create config dto fields to config entity
-	err := mappers.MapDtoToEntity(createDto, entity)
+	err = mappers.MapDtoToEntity(createDto, entity)
 	if err != nil {
 		return dto, errors.Internal.Wrap(err, "error map entity to dto")
 	}
@@ -230,17 +251,17 @@ func (s *TFTPServerService) CreateServer(ctx context.Context, createDto dtos.TFT
 	if err != nil {
 		return dto, errors.Internal.Wrap(err, "create tftp server config error")
 	}
-	//start runtime tftp server and set state to dto.State
+	//start runtime tftp server, start error is returned in dto.LastError
 	err = s.updateRuntimeServerConfig(config)
 	if err != nil {
-		return dto, errors.Internal.Wrapf(err, "reload config for tftp server with id %s failed", config.ID.String())
+		s.log(ctx, "err", errors.Internal.Wrapf(err, "reload config for tftp server with id %s failed", config.ID.String()).Error())
 	}
-	dto.State = s.getServerState(config.ID).String()
 	//map config fields to dto
 	err = mappers.MapEntityToDto(config, &dto)
 	if err != nil {
 		return dto, errors.Internal.Wrap(err, "error map dto to entity")
 	}
+	s.setRuntimeState(&dto)
 	return dto, nil
 }
 
@@ -255,6 +276,10 @@ func (s *TFTPServerService) CreateServer(ctx context.Context, createDto dtos.TFT
 //	error - if an error occurs, otherwise nil
 func (s *TFTPServerService) UpdateServer(ctx context.Context, updateDto dtos.TFTPServerUpdateDto, id uuid.UUID) (dtos.TFTPServerDto, error) {
 	dto := dtos.TFTPServerDto{}
+	err := validators.ValidateTFTPServerUpdateDto(updateDto)
+	if err != nil {
+		return dto, err
+	}
 	config, err := s.configsRepo.GetByID(ctx, id)
 	if err != nil {
 		return dto, err
@@ -272,7 +297,6 @@ func (s *TFTPServerService) UpdateServer(ctx context.Context, updateDto dtos.TFT
 	if err != nil {
 		s.log(ctx, "err", errors.Internal.Wrapf(err, "reload config for tftp server with id %s failed", config.ID.String()).Error())
 	}
-	dto.State = s.getServerState(config.ID).String()
 	err = s.updateRuntimeServerPaths(ctx, config.ID)
 	if err != nil {
 		s.log(ctx, "err", errors.Internal.Wrapf(err, "reload path ratios for tftp server with id %s failed", config.ID.String()).Error())
@@ -281,6 +305,7 @@ func (s *TFTPServerService) UpdateServer(ctx context.Context, updateDto dtos.TFT
 	if err != nil {
 		return dto, errors.Internal.Wrap(err, "error map tftp config to server dto")
 	}
+	s.setRuntimeState(&dto)
 	return dto, nil
 }
 
@@ -307,6 +332,8 @@ func (s *TFTPServerService) DeleteServer(ctx context.Context, id uuid.UUID) erro
 	if err != nil {
 		return errors.Internal.Wrap(err, "failed to delete tftp server config")
 	}
+	s.serversMutex.Lock()
+	defer s.serversMutex.Unlock()
 	if server, ok := s.servers[id]; ok {
 		server.Stop()
 		delete(s.servers, id)
@@ -361,7 +388,13 @@ func (s *TFTPServerService) GetPathByID(ctx context.Context, configID uuid.UUID,
 func (s *TFTPServerService) CreatePath(ctx context.Context, configID uuid.UUID, createDto dtos.TFTPPathCreateDto) (dtos.TFTPPathDto, error) {
 	entity := new(domain.TFTPPathRatio)
 	outDto := new(dtos.TFTPPathDto)
-	err := mappers.MapDtoToEntity(createDto, entity)
+	err := validators.ValidateTFTPPathCreateDto(createDto)
+	if err != nil {
+		return *outDto, err
+	}
+	//the server looks client mac addresses up in lower case
+	createDto.ClientMAC = strings.ToLower(createDto.ClientMAC)
+	err = mappers.MapDtoToEntity(createDto, entity)
 	if err != nil {
 		return *outDto, errors.Internal.Wrap(err, "error map entity to dto")
 	}
@@ -405,6 +438,11 @@ func (s *TFTPServerService) existencePathCheck(ctx context.Context, configID uui
 //	error - if an error occurs, otherwise nil
 func (s *TFTPServerService) UpdatePath(ctx context.Context, configID, pathID uuid.UUID, updateDto dtos.TFTPPathUpdateDto) (dtos.TFTPPathDto, error) {
 	dto := new(dtos.TFTPPathDto)
+	err := validators.ValidateTFTPPathUpdateDto(updateDto)
+	if err != nil {
+		return *dto, err
+	}
+	updateDto.ClientMAC = strings.ToLower(updateDto.ClientMAC)
 	pathRatio, err := s.pathsRepo.GetByIDExtended(ctx, pathID, s.getQueryBuilderWithConfigID(ctx, configID))
 	if err != nil {
 		return *dto, err
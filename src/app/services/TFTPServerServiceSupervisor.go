@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"rol/app/interfaces"
+	"rol/domain"
+	"time"
+)
+
+const (
+	//tftpSupervisorInterval interval between checks of the runtime servers states
+	tftpSupervisorInterval = time.Second
+	//tftpRestartMinDelay delay before the first restart of the failed server
+	tftpRestartMinDelay = time.Second
+	//tftpRestartMaxDelay max delay between restarts of the failed server
+	tftpRestartMaxDelay = time.Minute
+)
+
+//tftpRestartBackoff restart attempts of the failed server
+type tftpRestartBackoff struct {
+	attempts    int
+	nextAttempt time.Time
+}
+
+//getTFTPRestartDelay delay before the next restart, it's doubled after each failed attempt up to the max delay
+func getTFTPRestartDelay(attempts int) time.Duration {
+	delay := tftpRestartMinDelay
+	for i := 0; i < attempts && delay < tftpRestartMaxDelay; i++ {
+		delay *= 2
+	}
+	if delay > tftpRestartMaxDelay {
+		delay = tftpRestartMaxDelay
+	}
+	return delay
+}
+
+//restartFailedServers tries to start runtime servers that are in the error state when their backoff delay is expired
+func (s *TFTPServerService) restartFailedServers(ctx context.Context, now time.Time) {
+	s.serversMutex.Lock()
+	defer s.serversMutex.Unlock()
+	for id := range s.restarts {
+		if _, ok := s.servers[id]; !ok {
+			delete(s.restarts, id)
+		}
+	}
+	for id, server := range s.servers {
+		if server.GetState() != domain.TFTPStateError {
+			delete(s.restarts, id)
+			continue
+		}
+		backoff, ok := s.restarts[id]
+		if !ok {
+			backoff = &tftpRestartBackoff{nextAttempt: now.Add(getTFTPRestartDelay(0))}
+			s.restarts[id] = backoff
+		}
+		if now.Before(backoff.nextAttempt) {
+			continue
+		}
+		s.restartServer(ctx, id, server, backoff, now)
+	}
+}
+
+func (s *TFTPServerService) restartServer(ctx context.Context, id uuid.UUID, server interfaces.ITFTPServer, backoff *tftpRestartBackoff, now time.Time) {
+	backoff.attempts++
+	err := server.Start()
+	if err != nil {
+		delay := getTFTPRestartDelay(backoff.attempts)
+		backoff.nextAttempt = now.Add(delay)
+		s.log(ctx, "warn", fmt.Sprintf("restart attempt %d of tftp server with id %s failed, next attempt in %s: %s",
+			backoff.attempts, id.String(), delay.String(), err.Error()))
+		return
+	}
+	delete(s.restarts, id)
+	s.log(ctx, "info", fmt.Sprintf("tftp server with id %s is restarted after %d attempts", id.String(), backoff.attempts))
+}
+
+//supervisor restarts failed runtime servers with backoff
+func (s *TFTPServerService) supervisor() {
+	ticker := time.NewTicker(tftpSupervisorInterval)
+	defer ticker.Stop()
+	for now := range ticker.C {
+		s.restartFailedServers(context.Background(), now)
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/mappers"
+	"rol/dtos"
+)
+
+//getRuntimeServer returns runtime server of the existing config, nil if server is not running
+func (s *TFTPServerService) getRuntimeServer(ctx context.Context, configID uuid.UUID) (interfaces.ITFTPServer, error) {
+	exist, err := s.configsRepo.IsExist(ctx, configID, nil)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to check existence of tftp server")
+	}
+	if !exist {
+		return nil, errors.NotFound.New("tftp server with this id is not found")
+	}
+	server, _ := s.getServer(configID)
+	return server, nil
+}
+
+//GetTransferList get active and recent finished transfers of the TFTP server, the newest first.
+//Transfers are kept in memory of the runtime server, so they are lost when server is disabled
+//
+//Params
+//	ctx - context is used only for logging
+//	configID - tftp config id
+//	state - if not empty, only transfers in this state are returned: active, succeeded or failed
+//Return
+//	[]dtos.TFTPTransferDto - transfers
+//	error - if an error occurs, otherwise nil
+func (s *TFTPServerService) GetTransferList(ctx context.Context, configID uuid.UUID, state string) ([]dtos.TFTPTransferDto, error) {
+	transfers := []dtos.TFTPTransferDto{}
+	server, err := s.getRuntimeServer(ctx, configID)
+	if err != nil || server == nil {
+		return transfers, err
+	}
+	for _, transfer := range server.GetTransfers() {
+		if state != "" && transfer.State.String() != state {
+			continue
+		}
+		dto := dtos.TFTPTransferDto{}
+		mappers.MapTFTPTransferToDto(transfer, &dto)
+		transfers = append(transfers, dto)
+	}
+	return transfers, nil
+}
+
+//GetPathStatsList get transfer counters for each file requested from the TFTP server
+//
+//Params
+//	ctx - context is used only for logging
+//	configID - tftp config id
+//Return
+//	[]dtos.TFTPPathStatsDto - counters sorted by virtual path
+//	error - if an error occurs, otherwise nil
+func (s *TFTPServerService) GetPathStatsList(ctx context.Context, configID uuid.UUID) ([]dtos.TFTPPathStatsDto, error) {
+	statsDtos := []dtos.TFTPPathStatsDto{}
+	server, err := s.getRuntimeServer(ctx, configID)
+	if err != nil || server == nil {
+		return statsDtos, err
+	}
+	for _, stats := range server.GetPathStats() {
+		dto := dtos.TFTPPathStatsDto{}
+		mappers.MapTFTPPathStatsToDto(stats, &dto)
+		statsDtos = append(statsDtos, dto)
+	}
+	return statsDtos, nil
+}
@@ -0,0 +1,133 @@
+package services
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"rol/app/errors"
+	"rol/app/utils"
+	"rol/dtos"
+	"sort"
+	"strings"
+)
+
+func (s *TFTPServerService) getUploadDir(ctx context.Context, configID uuid.UUID) (string, error) {
+	config, err := s.configsRepo.GetByID(ctx, configID)
+	if err != nil {
+		if errors.As(err, errors.NotFound) {
+			return "", errors.NotFound.New("tftp server with this id is not found")
+		}
+		return "", errors.Internal.Wrap(err, "failed to get tftp server config")
+	}
+	if config.UploadDir == "" {
+		return "", errors.NotFound.New("upload directory is not set for this tftp server")
+	}
+	return config.UploadDir, nil
+}
+
+//getUploadFilePath returns actual path of the uploaded file, name must be in the sanitized form
+func (s *TFTPServerService) getUploadFilePath(ctx context.Context, configID uuid.UUID, name string) (string, error) {
+	uploadDir, err := s.getUploadDir(ctx, configID)
+	if err != nil {
+		return "", err
+	}
+	name = strings.TrimPrefix(name, "/")
+	relativePath, ok := utils.SanitizeRelativePath(name)
+	if !ok || relativePath != name {
+		err = errors.Validation.New(errors.ValidationErrorMessage)
+		return "", errors.AddErrorContext(err, "Name", "incorrect uploaded file name")
+	}
+	actualPath := filepath.Join(uploadDir, filepath.FromSlash(relativePath))
+	info, err := os.Stat(actualPath)
+	if err != nil || info.IsDir() {
+		return "", errors.NotFound.Newf("uploaded file %s is not found", name)
+	}
+	return actualPath, nil
+}
+
+//GetUploadList get list of the files uploaded to the TFTP server
+//
+//Params
+//	ctx - context is used only for logging
+//	configID - tftp config id
+//Return
+//	[]dtos.TFTPUploadDto - uploaded files sorted by name
+//	error - if an error occurs, otherwise nil
+func (s *TFTPServerService) GetUploadList(ctx context.Context, configID uuid.UUID) ([]dtos.TFTPUploadDto, error) {
+	uploads := []dtos.TFTPUploadDto{}
+	uploadDir, err := s.getUploadDir(ctx, configID)
+	if err != nil {
+		return uploads, err
+	}
+	if _, err = os.Stat(uploadDir); os.IsNotExist(err) {
+		return uploads, nil
+	}
+	err = filepath.WalkDir(uploadDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		//temporary files of the uploads in progress
+		if strings.HasPrefix(entry.Name(), ".") {
+			return nil
+		}
+		if entry.IsDir() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		relativePath, err := filepath.Rel(uploadDir, filePath)
+		if err != nil {
+			return err
+		}
+		uploads = append(uploads, dtos.TFTPUploadDto{
+			Name:       filepath.ToSlash(relativePath),
+			Size:       info.Size(),
+			ModifiedAt: info.ModTime(),
+		})
+		return nil
+	})
+	if err != nil {
+		return uploads, errors.Internal.Wrap(err, "failed to read tftp upload directory")
+	}
+	sort.Slice(uploads, func(i, j int) bool {
+		return uploads[i].Name < uploads[j].Name
+	})
+	return uploads, nil
+}
+
+//GetUploadFilePath get actual path of the file uploaded to the TFTP server
+//
+//Params
+//	ctx - context is used only for logging
+//	configID - tftp config id
+//	name - uploaded file name relative to the upload directory
+//Return
+//	string - actual file path
+//	error - if an error occurs, otherwise nil
+func (s *TFTPServerService) GetUploadFilePath(ctx context.Context, configID uuid.UUID, name string) (string, error) {
+	return s.getUploadFilePath(ctx, configID, name)
+}
+
+//DeleteUpload delete file uploaded to the TFTP server
+//
+//Params
+//	ctx - context is used only for logging
+//	configID - tftp config id
+//	name - uploaded file name relative to the upload directory
+//Return
+//	error - if an error occurs, otherwise nil
+func (s *TFTPServerService) DeleteUpload(ctx context.Context, configID uuid.UUID, name string) error {
+	actualPath, err := s.getUploadFilePath(ctx, configID, name)
+	if err != nil {
+		return err
+	}
+	err = os.Remove(actualPath)
+	if err != nil {
+		return errors.Internal.Wrapf(err, "failed to remove uploaded file %s", name)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"path"
+	"strings"
+)
+
+//SanitizeRelativePath converts client provided file path to the safe relative path.
+//Path separators are normalized, ".." elements can't leave the root, leading separators are removed,
+//every char except latin letters, digits, '.', '-' and '_' is replaced by '_',
+//names that start with '.' get '_' prefix.
+//
+//Params:
+//	filePath - client provided file path
+//Return:
+//	string - safe relative path, that can be joined to the root directory
+//	bool - false if the path is empty
+func SanitizeRelativePath(filePath string) (string, bool) {
+	cleaned := path.Clean("/" + strings.ReplaceAll(filePath, "\\", "/"))
+	cleaned = strings.TrimPrefix(cleaned, "/")
+	if cleaned == "" {
+		return "", false
+	}
+	segments := strings.Split(cleaned, "/")
+	for i, segment := range segments {
+		sanitized := []byte(segment)
+		for j, char := range sanitized {
+			isLetter := (char >= 'a' && char <= 'z') || (char >= 'A' && char <= 'Z')
+			isDigit := char >= '0' && char <= '9'
+			if !isLetter && !isDigit && char != '.' && char != '-' && char != '_' {
+				sanitized[j] = '_'
+			}
+		}
+		segments[i] = string(sanitized)
+		if strings.HasPrefix(segments[i], ".") {
+			segments[i] = "_" + segments[i]
+		}
+	}
+	return strings.Join(segments, "/"), true
+}
@@ -0,0 +1,43 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"regexp"
+	"rol/app/errors"
+	"rol/dtos"
+)
+
+//ValidateDHCP4BootRuleUpdateDto validates dhcp v4 boot rule update dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateDHCP4BootRuleUpdateDto(dto dtos.DHCP4BootRuleUpdateDto) error {
+	err := validation.ValidateStruct(&dto,
+		validation.Field(&dto.Priority, []validation.Rule{
+			validation.Min(0),
+		}...),
+		validation.Field(&dto.Architecture, []validation.Rule{
+			validation.Min(0),
+			validation.Max(0xffff),
+		}...),
+		validation.Field(&dto.VendorClass, []validation.Rule{
+			validation.Length(0, 64),
+		}...),
+		validation.Field(&dto.UserClass, []validation.Rule{
+			validation.Length(0, 64),
+		}...),
+		validation.Field(&dto.NextServer, []validation.Rule{
+			validation.Match(regexp.MustCompile(regexpIPv4)).
+				Error(regexpIPv4Desc),
+		}...),
+		validation.Field(&dto.BootFileName, []validation.Rule{
+			validation.Required,
+			validation.By(trimValidation),
+			validation.Length(1, 128),
+		}...),
+	)
+	if err == nil && dto.Architecture == nil && dto.VendorClass == "" && dto.UserClass == "" {
+		outErr := errors.Validation.New(errors.ValidationErrorMessage)
+		return errors.AddErrorContext(outErr, "Architecture", "at least one of architecture, vendor class or user class must be set")
+	}
+	return convertOzzoErrorToValidationError(err)
+}
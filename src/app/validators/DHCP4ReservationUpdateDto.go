@@ -0,0 +1,34 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"regexp"
+	"rol/dtos"
+)
+
+//ValidateDHCP4ReservationUpdateDto validates dhcp v4 reservation update dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateDHCP4ReservationUpdateDto(dto dtos.DHCP4ReservationUpdateDto) error {
+	err := validation.ValidateStruct(&dto,
+		validation.Field(&dto.IP, []validation.Rule{
+			validation.Required,
+			validation.Match(regexp.MustCompile(regexpIPv4)).
+				Error(regexpIPv4Desc),
+		}...),
+		validation.Field(&dto.MAC, []validation.Rule{
+			validation.Required,
+			validation.Match(regexp.MustCompile(regexpMac)).
+				Error(regexpMacDesc),
+		}...),
+		validation.Field(&dto.Hostname, []validation.Rule{
+			validation.By(containsSpacesValidation),
+			validation.Length(0, 64),
+		}...),
+		validation.Field(&dto.BootFileName, []validation.Rule{
+			validation.By(trimValidation),
+			validation.Length(0, 128),
+		}...),
+	)
+	return convertOzzoErrorToValidationError(err)
+}
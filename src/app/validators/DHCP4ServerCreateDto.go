@@ -45,6 +45,14 @@ func ValidateDHCP4ServerCreateDto(dto dtos.DHCP4ServerCreateDto) error {
 		validation.Field(&dto.DNS, []validation.Rule{
 			validation.Required,
 		}...),
+		validation.Field(&dto.NextServer, []validation.Rule{
+			validation.Match(regexp.MustCompile(regexpIPv4)).
+				Error(regexpIPv4Desc),
+		}...),
+		validation.Field(&dto.BootFileName, []validation.Rule{
+			validation.By(trimValidation),
+			validation.Length(0, 128),
+		}...),
 		validation.Field(&dto.Port, []validation.Rule{
 			validation.Required,
 		}...),
@@ -19,6 +19,14 @@ func ValidateDHCP4ServerUpdateDto(dto dtos.DHCP4ServerUpdateDto) error {
 			validation.Match(regexp.MustCompile(regexpIPv4)).
 				Error(regexpIPv4Desc),
 		}...),
+		validation.Field(&dto.NextServer, []validation.Rule{
+			validation.Match(regexp.MustCompile(regexpIPv4)).
+				Error(regexpIPv4Desc),
+		}...),
+		validation.Field(&dto.BootFileName, []validation.Rule{
+			validation.By(trimValidation),
+			validation.Length(0, 128),
+		}...),
 		validation.Field(&dto.Port, []validation.Rule{
 			validation.Required,
 		}...),
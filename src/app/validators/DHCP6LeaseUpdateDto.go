@@ -0,0 +1,32 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"regexp"
+	"rol/dtos"
+)
+
+//ValidateDHCP6LeaseUpdateDto validates dhcp v6 lease update dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateDHCP6LeaseUpdateDto(dto dtos.DHCP6LeaseUpdateDto) error {
+	err := validation.ValidateStruct(&dto,
+		validation.Field(&dto.IP, []validation.Rule{
+			validation.Required,
+			validation.By(ipv6Validation),
+		}...),
+		validation.Field(&dto.DUID, []validation.Rule{
+			validation.Required,
+			validation.Match(regexp.MustCompile(regexpHex)).
+				Error(regexpHexDesc),
+		}...),
+		validation.Field(&dto.MAC, []validation.Rule{
+			validation.Match(regexp.MustCompile(regexpMac)).
+				Error(regexpMacDesc),
+		}...),
+		validation.Field(&dto.Expires, []validation.Rule{
+			validation.Required,
+		}...),
+	)
+	return convertOzzoErrorToValidationError(err)
+}
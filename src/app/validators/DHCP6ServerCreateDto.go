@@ -0,0 +1,34 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"rol/dtos"
+)
+
+//ValidateDHCP6ServerCreateDto validates dhcp v6 server create dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateDHCP6ServerCreateDto(dto dtos.DHCP6ServerCreateDto) error {
+	err := validation.ValidateStruct(&dto,
+		validation.Field(&dto.Interface, []validation.Rule{
+			validation.Required,
+			validation.By(trimValidation),
+			validation.By(containsSpacesValidation),
+		}...),
+		validation.Field(&dto.Range, []validation.Rule{
+			validation.Required,
+			validation.By(ipv6RangeValidation),
+		}...),
+		validation.Field(&dto.DNS, []validation.Rule{
+			validation.By(ipv6ListValidation),
+		}...),
+		validation.Field(&dto.Port, []validation.Rule{
+			validation.Required,
+		}...),
+		validation.Field(&dto.LeaseTime, []validation.Rule{
+			validation.Required,
+			validation.Min(60),
+		}...),
+	)
+	return convertOzzoErrorToValidationError(err)
+}
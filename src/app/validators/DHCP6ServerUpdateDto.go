@@ -0,0 +1,25 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"rol/dtos"
+)
+
+//ValidateDHCP6ServerUpdateDto validates dhcp v6 server update dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateDHCP6ServerUpdateDto(dto dtos.DHCP6ServerUpdateDto) error {
+	err := validation.ValidateStruct(&dto,
+		validation.Field(&dto.DNS, []validation.Rule{
+			validation.By(ipv6ListValidation),
+		}...),
+		validation.Field(&dto.Port, []validation.Rule{
+			validation.Required,
+		}...),
+		validation.Field(&dto.LeaseTime, []validation.Rule{
+			validation.Required,
+			validation.Min(60),
+		}...),
+	)
+	return convertOzzoErrorToValidationError(err)
+}
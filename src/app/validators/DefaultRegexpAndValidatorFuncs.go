@@ -5,7 +5,9 @@ import (
 	validation "github.com/go-ozzo/ozzo-validation"
 	"github.com/google/uuid"
 	"net"
+	"path/filepath"
 	"rol/app/errors"
+	"rol/app/utils"
 	"strings"
 )
 
@@ -20,6 +22,21 @@ const regexpIPv4Desc = "wrong IPv4 format"
 const regexpMac = `^([0-9A-Fa-f]{2}[:]){5}([0-9A-Fa-f]{2})$`
 const regexpMacDesc = "wrong mac address format, expect 0f:0f:f0:f0:f0"
 
+//regexpPort network port number
+const regexpPort = `^([1-9]\d{0,3}|[1-5]\d{4}|6[0-4]\d{3}|65[0-4]\d{2}|655[0-2]\d|6553[0-5])$`
+const regexpPortDesc = "wrong port number, expect number from 1 to 65535"
+
+//regexpHex hex string with even length, used for DHCP v6 DUID
+const regexpHex = `^([0-9A-Fa-f]{2})+$`
+const regexpHexDesc = "wrong format, expect hex string"
+
+//regexpSSHFingerprint ssh host key SHA256 fingerprint in OpenSSH format
+const regexpSSHFingerprint = `^SHA256:[A-Za-z0-9+/]{43}$`
+const regexpSSHFingerprintDesc = "wrong fingerprint format, expect SHA256:<base64 hash> as printed by ssh-keygen -l"
+
+//maxIPv6RangeSize max number of addresses in ipv6 range
+const maxIPv6RangeSize = 1 << 16
+
 func convertOzzoErrorToValidationError(err error) error {
 	var custError error
 	if err != nil {
@@ -42,6 +59,57 @@ func sliceOfCidrStringsValidation(value interface{}) error {
 	return nil
 }
 
+func parseIPv6(s string) net.IP {
+	ip := net.ParseIP(s)
+	if ip == nil || ip.To4() != nil {
+		return nil
+	}
+	return ip
+}
+
+func ipv6Validation(value interface{}) error {
+	s, _ := value.(string)
+	if s != "" && parseIPv6(s) == nil {
+		return errors.Validation.New("wrong IPv6 format")
+	}
+	return nil
+}
+
+func ipv6ListValidation(value interface{}) error {
+	s, _ := value.(string)
+	if s == "" {
+		return nil
+	}
+	for _, address := range strings.Split(s, ";") {
+		if parseIPv6(address) == nil {
+			return errors.Validation.Newf("wrong IPv6 address: %s", address)
+		}
+	}
+	return nil
+}
+
+func ipv6RangeValidation(value interface{}) error {
+	s, _ := value.(string)
+	startEnd := strings.Split(s, "-")
+	if len(startEnd) != 2 {
+		return errors.Validation.New(`range should be two IPv6 addresses separated by "-"`)
+	}
+	start, end := parseIPv6(startEnd[0]), parseIPv6(startEnd[1])
+	if start == nil || end == nil {
+		return errors.Validation.New("wrong IPv6 format")
+	}
+	for i := 0; i < 14; i++ {
+		if start[i] != end[i] {
+			return errors.Validation.Newf("range is too big, max size is %d addresses", maxIPv6RangeSize)
+		}
+	}
+	size := int(end[14])<<8 | int(end[15]) - (int(start[14])<<8 | int(start[15])) + 1
+	if size < 1 {
+		return errors.Validation.New("start of the range can't be greater than the end of the range")
+	}
+	return nil
+}
+
 func trimValidation(value interface{}) error {
 	s, _ := value.(string)
 	if strings.TrimSpace(s) != s {
@@ -50,6 +118,59 @@ func trimValidation(value interface{}) error {
 	return nil
 }
 
+//uploadDirValidation returns validation func for the upload directory, that required and must be absolute if write is enabled
+func uploadDirValidation(writeEnabled bool) validation.RuleFunc {
+	return func(value interface{}) error {
+		s, _ := value.(string)
+		if s == "" {
+			if writeEnabled {
+				return errors.Validation.New("upload directory is required if write is enabled")
+			}
+			return nil
+		}
+		if !filepath.IsAbs(s) {
+			return errors.Validation.New("upload directory must be an absolute path")
+		}
+		return nil
+	}
+}
+
+//virtualPathValidation returns validation func for the tftp or http boot virtual path, only directory can be the root path
+func virtualPathValidation(isDirectory bool) validation.RuleFunc {
+	return func(value interface{}) error {
+		s, _ := value.(string)
+		if strings.Trim(s, "/") == "" && !isDirectory {
+			return errors.Validation.New("file virtual path cannot be the root path")
+		}
+		if utils.SliceContainsElement(strings.Split(strings.ReplaceAll(s, "\\", "/"), "/"), "..") {
+			return errors.Validation.New("virtual path cannot contain '..' elements")
+		}
+		return nil
+	}
+}
+
+//clientScopeValidation returns validation func for the client mac address, that can't be used together with the client ip address
+func clientScopeValidation(clientIP string) validation.RuleFunc {
+	return func(value interface{}) error {
+		s, _ := value.(string)
+		if s != "" && clientIP != "" {
+			return errors.Validation.New("only one of client ip or client mac address can be set")
+		}
+		return nil
+	}
+}
+
+//requiredIfValidation returns validation func for the field, that is required only if condition is true
+func requiredIfValidation(condition bool, message string) validation.RuleFunc {
+	return func(value interface{}) error {
+		s, _ := value.(string)
+		if condition && s == "" {
+			return errors.Validation.New(message)
+		}
+		return nil
+	}
+}
+
 func containsSpacesValidation(value interface{}) error {
 	s, _ := value.(string)
 	if strings.Contains(s, " ") {
@@ -58,6 +179,14 @@ func containsSpacesValidation(value interface{}) error {
 	return nil
 }
 
+func notEmptyUUIDValidation(value interface{}) error {
+	id, _ := value.(uuid.UUID)
+	if id == uuid.Nil {
+		return errors.Validation.New("cannot be blank")
+	}
+	return nil
+}
+
 func uuidSliceElemUniqueness(value interface{}) error {
 	s, _ := value.([]uuid.UUID)
 	keys := make(map[uuid.UUID]bool)
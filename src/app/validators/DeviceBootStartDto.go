@@ -0,0 +1,29 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"rol/dtos"
+)
+
+//maxDeviceBootStageTimeout max stage timeout of the device boot in seconds
+const maxDeviceBootStageTimeout = 24 * 60 * 60
+
+//ValidateDeviceBootStartDto validates device boot start dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateDeviceBootStartDto(dto dtos.DeviceBootStartDto) error {
+	err := validation.ValidateStruct(&dto,
+		validation.Field(&dto.BootType, []validation.Rule{
+			validation.Required,
+			validation.In("net", "disc", "usb").Error("boot type can be net, disc or usb"),
+		}...),
+		validation.Field(&dto.TFTPServerID, []validation.Rule{
+			validation.By(notEmptyUUIDValidation),
+		}...),
+		validation.Field(&dto.StageTimeout, []validation.Rule{
+			validation.Min(0),
+			validation.Max(maxDeviceBootStageTimeout),
+		}...),
+	)
+	return convertOzzoErrorToValidationError(err)
+}
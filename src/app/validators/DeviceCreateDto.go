@@ -0,0 +1,51 @@
+package validators
+
+import (
+	"fmt"
+	validation "github.com/go-ozzo/ozzo-validation"
+	"regexp"
+	"rol/app/errors"
+	"rol/dtos"
+)
+
+//ValidateDeviceCreateDto validates device create dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateDeviceCreateDto(dto dtos.DeviceCreateDto) error {
+	validationErr := validation.ValidateStruct(&dto,
+		validation.Field(&dto.Name, []validation.Rule{
+			validation.Required,
+			validation.By(trimValidation),
+		}...),
+		validation.Field(&dto.Serial, []validation.Rule{
+			validation.Required,
+			validation.By(trimValidation),
+			validation.By(containsSpacesValidation),
+		}...),
+		validation.Field(&dto.DeviceTemplate, []validation.Rule{
+			validation.Required,
+			validation.By(trimValidation),
+		}...))
+	err := convertOzzoErrorToValidationError(validationErr)
+	for i, networkInterface := range dto.NetworkInterfaces {
+		interfaceErr := validation.ValidateStruct(&networkInterface,
+			validation.Field(&networkInterface.Name, []validation.Rule{
+				validation.Required,
+				validation.By(trimValidation),
+			}...),
+			validation.Field(&networkInterface.MAC, []validation.Rule{
+				validation.Match(regexp.MustCompile(regexpMac)).
+					Error(regexpMacDesc),
+			}...))
+		if interfaceErr == nil {
+			continue
+		}
+		if err == nil {
+			err = errors.Validation.New(errors.ValidationErrorMessage)
+		}
+		for key, value := range interfaceErr.(validation.Errors) {
+			err = errors.AddErrorContext(err, fmt.Sprintf("NetworkInterfaces[%d].%s", i, key), value.Error())
+		}
+	}
+	return err
+}
@@ -0,0 +1,19 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"regexp"
+	"rol/dtos"
+)
+
+//ValidateDeviceNetworkInterfaceUpdateDto validates device network interface update dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateDeviceNetworkInterfaceUpdateDto(dto dtos.DeviceNetworkInterfaceUpdateDto) error {
+	err := validation.ValidateStruct(&dto,
+		validation.Field(&dto.MAC, []validation.Rule{
+			validation.Match(regexp.MustCompile(regexpMac)).
+				Error(regexpMacDesc),
+		}...))
+	return convertOzzoErrorToValidationError(err)
+}
@@ -0,0 +1,23 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"rol/dtos"
+)
+
+//ValidateDeviceUpdateDto validates device update dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateDeviceUpdateDto(dto dtos.DeviceUpdateDto) error {
+	err := validation.ValidateStruct(&dto,
+		validation.Field(&dto.Name, []validation.Rule{
+			validation.Required,
+			validation.By(trimValidation),
+		}...),
+		validation.Field(&dto.Serial, []validation.Rule{
+			validation.Required,
+			validation.By(trimValidation),
+			validation.By(containsSpacesValidation),
+		}...))
+	return convertOzzoErrorToValidationError(err)
+}
@@ -11,7 +11,9 @@ import (
 //	error - if an error occurs, otherwise nil
 func ValidateEthernetSwitchCreateDto(dto dtos.EthernetSwitchCreateDto) error {
 
-	err := validation.ValidateStruct(&dto,
+	snmpRules := ethernetSwitchSNMPFieldsRules(&dto.EthernetSwitchBaseDto, &dto.SNMPCommunity, &dto.SNMPAuthPassword, &dto.SNMPPrivPassword)
+	transportRules := ethernetSwitchTransportFieldsRules(&dto.EthernetSwitchBaseDto)
+	err := validation.ValidateStruct(&dto, append([]*validation.FieldRules{
 		validation.Field(&dto.Serial, []validation.Rule{
 			validation.Required,
 			validation.By(trimValidation),
@@ -39,6 +41,6 @@ func ValidateEthernetSwitchCreateDto(dto dtos.EthernetSwitchCreateDto) error {
 		validation.Field(&dto.SwitchModel, []validation.Rule{
 			validation.Required,
 			validation.By(trimValidation),
-		}...))
+		}...)}, append(snmpRules, transportRules...)...)...)
 	return convertOzzoErrorToValidationError(err)
 }
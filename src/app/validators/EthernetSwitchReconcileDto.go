@@ -0,0 +1,21 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"rol/domain"
+	"rol/dtos"
+)
+
+//ValidateEthernetSwitchReconcileDto validates ethernet switch reconcile dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateEthernetSwitchReconcileDto(dto dtos.EthernetSwitchReconcileDto) error {
+	err := validation.ValidateStruct(&dto,
+		validation.Field(&dto.Mode, []validation.Rule{
+			validation.Required,
+			validation.In(domain.EthernetSwitchReconcileImport, domain.EthernetSwitchReconcilePush).
+				Error("mode can be import or push"),
+		}...),
+	)
+	return convertOzzoErrorToValidationError(err)
+}
@@ -0,0 +1,54 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"rol/dtos"
+)
+
+//minSNMPPassphraseLength min length of the SNMP v3 passphrase, shorter passphrases are rejected by agents
+const minSNMPPassphraseLength = 8
+
+//ethernetSwitchSNMPFieldsRules rules for the SNMP credentials of the ethernet switch create and update dtos
+//
+//Params
+//	base - nested base switch dto of the validated dto
+//	community - SNMP v2c community field of the validated dto
+//	authPassword - SNMP v3 authentication passphrase field of the validated dto
+//	privPassword - SNMP v3 privacy passphrase field of the validated dto
+//Return
+//	[]*validation.FieldRules - SNMP fields rules
+func ethernetSwitchSNMPFieldsRules(base *dtos.EthernetSwitchBaseDto, community, authPassword, privPassword *string) []*validation.FieldRules {
+	isV3 := base.SNMPVersion == "3"
+	return []*validation.FieldRules{
+		validation.Field(&base.SNMPVersion, []validation.Rule{
+			validation.In("2c", "3"),
+		}...),
+		validation.Field(&base.SNMPPort, []validation.Rule{
+			validation.Min(0),
+			validation.Max(65535),
+		}...),
+		validation.Field(community, []validation.Rule{
+			validation.By(requiredIfValidation(base.SNMPVersion == "2c", "community is required for SNMP v2c")),
+		}...),
+		validation.Field(&base.SNMPUsername, []validation.Rule{
+			validation.By(requiredIfValidation(isV3, "user name is required for SNMP v3")),
+			validation.By(trimValidation),
+			validation.By(containsSpacesValidation),
+		}...),
+		validation.Field(&base.SNMPAuthProtocol, []validation.Rule{
+			validation.In("md5", "sha"),
+			validation.By(requiredIfValidation(base.SNMPPrivProtocol != "", "authentication protocol is required for the privacy protocol")),
+		}...),
+		validation.Field(authPassword, []validation.Rule{
+			validation.By(requiredIfValidation(isV3 && base.SNMPAuthProtocol != "", "passphrase is required for the authentication protocol")),
+			validation.Length(minSNMPPassphraseLength, 64),
+		}...),
+		validation.Field(&base.SNMPPrivProtocol, []validation.Rule{
+			validation.In("des", "aes"),
+		}...),
+		validation.Field(privPassword, []validation.Rule{
+			validation.By(requiredIfValidation(isV3 && base.SNMPPrivProtocol != "", "passphrase is required for the privacy protocol")),
+			validation.Length(minSNMPPassphraseLength, 64),
+		}...),
+	}
+}
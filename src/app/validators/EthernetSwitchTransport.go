@@ -0,0 +1,28 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"regexp"
+	"rol/domain"
+	"rol/dtos"
+)
+
+//ethernetSwitchTransportFieldsRules rules for the CLI transport fields of the ethernet switch create and update dtos
+//
+//Params
+//	base - nested base switch dto of the validated dto
+//Return
+//	[]*validation.FieldRules - transport fields rules
+func ethernetSwitchTransportFieldsRules(base *dtos.EthernetSwitchBaseDto) []*validation.FieldRules {
+	return []*validation.FieldRules{
+		validation.Field(&base.Transport, []validation.Rule{
+			validation.In(domain.EthernetSwitchTransportTelnet, domain.EthernetSwitchTransportSSH),
+		}...),
+		validation.Field(&base.SSHHostKeyFingerprint, []validation.Rule{
+			validation.By(requiredIfValidation(base.Transport == domain.EthernetSwitchTransportSSH,
+				"host key fingerprint is required for ssh transport")),
+			validation.Match(regexp.MustCompile(regexpSSHFingerprint)).
+				Error(regexpSSHFingerprintDesc),
+		}...),
+	}
+}
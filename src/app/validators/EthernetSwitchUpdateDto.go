@@ -10,7 +10,9 @@ import (
 //	Return
 //	error - if an error occurs, otherwise nil
 func ValidateEthernetSwitchUpdateDto(dto dtos.EthernetSwitchUpdateDto) error {
-	err := validation.ValidateStruct(&dto,
+	snmpRules := ethernetSwitchSNMPFieldsRules(&dto.EthernetSwitchBaseDto, &dto.SNMPCommunity, &dto.SNMPAuthPassword, &dto.SNMPPrivPassword)
+	transportRules := ethernetSwitchTransportFieldsRules(&dto.EthernetSwitchBaseDto)
+	err := validation.ValidateStruct(&dto, append([]*validation.FieldRules{
 		validation.Field(&dto.Name, []validation.Rule{
 			validation.Required,
 			validation.By(trimValidation),
@@ -38,6 +40,6 @@ func ValidateEthernetSwitchUpdateDto(dto dtos.EthernetSwitchUpdateDto) error {
 		validation.Field(&dto.SwitchModel, []validation.Rule{
 			validation.Required,
 			validation.By(trimValidation),
-		}...))
+		}...)}, append(snmpRules, transportRules...)...)...)
 	return convertOzzoErrorToValidationError(err)
 }
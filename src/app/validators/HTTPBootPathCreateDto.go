@@ -0,0 +1,24 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"rol/dtos"
+)
+
+//ValidateHTTPBootPathCreateDto validates http boot path create dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateHTTPBootPathCreateDto(dto dtos.HTTPBootPathCreateDto) error {
+	err := validation.ValidateStruct(&dto,
+		validation.Field(&dto.ActualPath, []validation.Rule{
+			validation.Required,
+			validation.By(trimValidation),
+		}...),
+		validation.Field(&dto.VirtualPath, []validation.Rule{
+			validation.Required,
+			validation.By(trimValidation),
+			validation.By(virtualPathValidation(dto.IsDirectory)),
+		}...),
+	)
+	return convertOzzoErrorToValidationError(err)
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"regexp"
+	"rol/dtos"
+)
+
+//ValidateHTTPBootServerCreateDto validates http boot server create dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateHTTPBootServerCreateDto(dto dtos.HTTPBootServerCreateDto) error {
+	err := validation.ValidateStruct(&dto,
+		validation.Field(&dto.Address, []validation.Rule{
+			validation.Required,
+			validation.Match(regexp.MustCompile(regexpIPv4)).
+				Error(regexpIPv4Desc),
+		}...),
+		validation.Field(&dto.Port, []validation.Rule{
+			validation.Required,
+			validation.Match(regexp.MustCompile(regexpPort)).
+				Error(regexpPortDesc),
+		}...),
+	)
+	return convertOzzoErrorToValidationError(err)
+}
@@ -0,0 +1,26 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"regexp"
+	"rol/dtos"
+)
+
+//ValidateHTTPBootServerUpdateDto validates http boot server update dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateHTTPBootServerUpdateDto(dto dtos.HTTPBootServerUpdateDto) error {
+	err := validation.ValidateStruct(&dto,
+		validation.Field(&dto.Address, []validation.Rule{
+			validation.Required,
+			validation.Match(regexp.MustCompile(regexpIPv4)).
+				Error(regexpIPv4Desc),
+		}...),
+		validation.Field(&dto.Port, []validation.Rule{
+			validation.Required,
+			validation.Match(regexp.MustCompile(regexpPort)).
+				Error(regexpPortDesc),
+		}...),
+	)
+	return convertOzzoErrorToValidationError(err)
+}
@@ -0,0 +1,34 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"regexp"
+	"rol/dtos"
+)
+
+//ValidateTFTPPathUpdateDto validates tftp path update dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateTFTPPathUpdateDto(dto dtos.TFTPPathUpdateDto) error {
+	err := validation.ValidateStruct(&dto,
+		validation.Field(&dto.ActualPath, []validation.Rule{
+			validation.Required,
+			validation.By(trimValidation),
+		}...),
+		validation.Field(&dto.VirtualPath, []validation.Rule{
+			validation.Required,
+			validation.By(trimValidation),
+			validation.By(virtualPathValidation(dto.IsDirectory)),
+		}...),
+		validation.Field(&dto.ClientIP, []validation.Rule{
+			validation.Match(regexp.MustCompile(regexpIPv4)).
+				Error(regexpIPv4Desc),
+		}...),
+		validation.Field(&dto.ClientMAC, []validation.Rule{
+			validation.Match(regexp.MustCompile(regexpMac)).
+				Error(regexpMacDesc),
+			validation.By(clientScopeValidation(dto.ClientIP)),
+		}...),
+	)
+	return convertOzzoErrorToValidationError(err)
+}
@@ -0,0 +1,33 @@
+package validators
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation"
+	"regexp"
+	"rol/dtos"
+)
+
+//ValidateTFTPServerCreateDto validates tftp server create dto with ozzo-validation
+//	Return
+//	error - if an error occurs, otherwise nil
+func ValidateTFTPServerCreateDto(dto dtos.TFTPServerCreateDto) error {
+	err := validation.ValidateStruct(&dto,
+		validation.Field(&dto.Address, []validation.Rule{
+			validation.Required,
+			validation.Match(regexp.MustCompile(regexpIPv4)).
+				Error(regexpIPv4Desc),
+		}...),
+		validation.Field(&dto.Port, []validation.Rule{
+			validation.Required,
+			validation.Match(regexp.MustCompile(regexpPort)).
+				Error(regexpPortDesc),
+		}...),
+		validation.Field(&dto.UploadDir, []validation.Rule{
+			validation.By(trimValidation),
+			validation.By(uploadDirValidation(dto.WriteEnabled)),
+		}...),
+		validation.Field(&dto.MaxUploadSize, []validation.Rule{
+			validation.Min(0),
+		}...),
+	)
+	return convertOzzoErrorToValidationError(err)
+}
@@ -37,4 +37,7 @@ type AppConfig struct {
 		Level          string `yaml:"level"`
 		LogsToDatabase bool   `yaml:"logsToDatabase"`
 	} `yaml:"logger"`
+	EthernetSwitchSimulator struct {
+		TelnetEnabled bool `yaml:"telnetEnabled"`
+	} `yaml:"ethernetSwitchSimulator"`
 }
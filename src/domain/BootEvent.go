@@ -0,0 +1,39 @@
+// Package domain stores the main structures of the program
+package domain
+
+import "time"
+
+//BootEventType type of the event observed from the booting device
+type BootEventType uint
+
+const (
+	//BootEventTFTPFetch file is successfully downloaded by the client from the TFTP server
+	BootEventTFTPFetch = BootEventType(iota)
+	//BootEventDHCPRequest DHCP v4 discover or request is received from the client
+	BootEventDHCPRequest
+)
+
+//String convert boot event type to string
+func (t BootEventType) String() string {
+	switch t {
+	case BootEventTFTPFetch:
+		return "tftp fetch"
+	case BootEventDHCPRequest:
+		return "dhcp request"
+	}
+	return "unknown"
+}
+
+//BootEvent event observed from the booting device by the network services
+type BootEvent struct {
+	//Type event type
+	Type BootEventType
+	//ClientMAC client mac address in lower case, empty if it's not resolved
+	ClientMAC string
+	//ClientIP client ip address, empty if it's not known
+	ClientIP string
+	//VirtualPath file name requested by the client, only for TFTP fetch
+	VirtualPath string
+	//Time event time
+	Time time.Time
+}
@@ -0,0 +1,24 @@
+package domain
+
+import "github.com/google/uuid"
+
+//DHCP4BootRule rule for choosing boot file and next server for the DHCP v4 client.
+//
+//Rule matches the client when all set criteria match, rules are checked by ascending priority
+type DHCP4BootRule struct {
+	//EntityUUID - nested base entity where ID type is uuid.UUID
+	EntityUUID
+	//Priority rule priority, lower value is checked first
+	Priority int `gorm:"index"`
+	//Architecture client system architecture code from option 93, nil matches any architecture
+	Architecture *int
+	//VendorClass prefix of the vendor class identifier from option 60, empty matches any vendor class
+	VendorClass string `gorm:"type:varchar(64)"`
+	//UserClass user class from option 77, like iPXE, empty matches any user class
+	UserClass string `gorm:"type:varchar(64)"`
+	//NextServer address of the boot server, overrides next server of the DHCP v4 server
+	NextServer string `gorm:"type:varchar(15)"`
+	//BootFileName boot file name, overrides boot file name of the DHCP v4 server
+	BootFileName  string    `gorm:"type:varchar(128)"`
+	DHCP4ConfigID uuid.UUID `gorm:"type:varchar(36);index"`
+}
@@ -8,12 +8,15 @@ type DHCP4Config struct {
 	Gateway   string `gorm:"type:varchar(15)"`
 	NTP       string `gorm:"type:varchar(15)"`
 	//ServerID server id DHCP option
-	ServerID   string `gorm:"type:varchar(15)"`
+	ServerID string `gorm:"type:varchar(15)"`
+	//NextServer address of the boot server (TFTP), sent as siaddr and option 66
 	NextServer string `gorm:"type:varchar(15)"`
-	Mask       string `gorm:"type:varchar(15)"`
-	DNS        string
-	Range      string
-	Enabled    bool
-	Port       int
-	LeaseTime  int
+	//BootFileName boot file name, sent as option 67
+	BootFileName string `gorm:"type:varchar(128)"`
+	Mask         string `gorm:"type:varchar(15)"`
+	DNS          string
+	Range        string
+	Enabled      bool
+	Port         int
+	LeaseTime    int
 }
@@ -0,0 +1,16 @@
+package domain
+
+import "github.com/google/uuid"
+
+//DHCP4Reservation static DHCP v4 reservation of the ip address for the MAC address
+type DHCP4Reservation struct {
+	//EntityUUID - nested base entity where ID type is uuid.UUID
+	EntityUUID
+	IP  string `gorm:"type:varchar(15);index"`
+	MAC string `gorm:"type:varchar(17);index"`
+	//Hostname host name, sent as option 12
+	Hostname string `gorm:"type:varchar(64)"`
+	//BootFileName per-host boot file name, overrides boot file name of the server
+	BootFileName  string    `gorm:"type:varchar(128)"`
+	DHCP4ConfigID uuid.UUID `gorm:"type:varchar(36);index"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+//DHCP6Config configuration for dhcp v6 server
+type DHCP6Config struct {
+	//EntityUUID - nested base entity where ID type is uuid.UUID
+	EntityUUID
+	Interface string `gorm:"type:varchar(64);index"`
+	//Range of ipv6 addresses for stateful assignment, separated by "-"
+	Range string
+	//DNS ipv6 addresses of DNS servers, separated by ";"
+	DNS       string
+	Enabled   bool
+	Port      int
+	LeaseTime int
+}
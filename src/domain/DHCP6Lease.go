@@ -0,0 +1,19 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+//DHCP6Lease information about DHCP v6 lease
+type DHCP6Lease struct {
+	//EntityUUID - nested base entity where ID type is uuid.UUID
+	EntityUUID
+	IP string `gorm:"type:varchar(39);index"`
+	//DUID client DUID in hex format
+	DUID string `gorm:"column:duid;type:varchar(260);index"`
+	//MAC client mac address, if it can be extracted from the client DUID or relay options
+	MAC           string `gorm:"type:varchar(17);index"`
+	Expires       time.Time
+	DHCP6ConfigID uuid.UUID `gorm:"type:varchar(36);index"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+//Device managed device entity, instantiated from the device template
+type Device struct {
+	//EntityUUID - nested base entity where ID type is uuid.UUID
+	EntityUUID
+	//Name - device name
+	Name string
+	//Serial - device serial number
+	Serial string
+	//DeviceTemplate - name of the device template from which the device was created
+	DeviceTemplate string
+	//PowerState - last power state set by the system
+	PowerState DevicePowerState
+}
@@ -0,0 +1,30 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+//DeviceBoot boot process of the device, it walks through the boot stages of the device template
+type DeviceBoot struct {
+	//EntityUUID - nested base entity where ID type is uuid.UUID
+	EntityUUID
+	//DeviceID - id of the booting device
+	DeviceID uuid.UUID `gorm:"type:varchar(36);index"`
+	//BootType - boot stages list of the device template: net, disc or usb
+	BootType string
+	//TFTPConfigID - id of the TFTP server where the stages files are installed
+	TFTPConfigID uuid.UUID `gorm:"type:varchar(36)"`
+	//StageTimeout - max time of waiting for the stage events in seconds
+	StageTimeout int
+	//StageIndex - index of the current stage
+	StageIndex int
+	//StageName - name of the current stage
+	StageName string
+	//State - boot state
+	State DeviceBootState
+	//FinishedAt - boot finish time, zero if boot is running
+	FinishedAt time.Time
+	//Error - boot error message, empty if boot is not failed
+	Error string
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+//DeviceBootStage executed stage of the device boot
+type DeviceBootStage struct {
+	//EntityUUID - nested base entity where ID type is uuid.UUID
+	EntityUUID
+	//DeviceBootID - id of the device boot
+	DeviceBootID uuid.UUID `gorm:"type:varchar(36);index"`
+	//Index - index of the stage in the boot stages list
+	Index int
+	//Name - stage name from the device template
+	Name string
+	//Action - stage action from the device template
+	Action string
+	//State - stage state
+	State DeviceBootState
+	//StartedAt - stage start time
+	StartedAt time.Time
+	//FinishedAt - stage finish time, zero if stage is running
+	FinishedAt time.Time
+	//Message - events observed on the stage or error message
+	Message string
+}
@@ -0,0 +1,31 @@
+// Package domain stores the main structures of the program
+package domain
+
+//DeviceBootState state of the device boot or its stage
+type DeviceBootState uint
+
+const (
+	//DeviceBootStateRunning boot or stage is in progress
+	DeviceBootStateRunning = DeviceBootState(iota)
+	//DeviceBootStateSucceeded boot or stage is finished successfully
+	DeviceBootStateSucceeded
+	//DeviceBootStateFailed boot or stage is failed or timed out
+	DeviceBootStateFailed
+	//DeviceBootStateCancelled boot or stage is cancelled by the user or by the app restart
+	DeviceBootStateCancelled
+)
+
+//String convert device boot state to string
+func (s DeviceBootState) String() string {
+	switch s {
+	case DeviceBootStateRunning:
+		return "running"
+	case DeviceBootStateSucceeded:
+		return "succeeded"
+	case DeviceBootStateFailed:
+		return "failed"
+	case DeviceBootStateCancelled:
+		return "cancelled"
+	}
+	return "unknown"
+}
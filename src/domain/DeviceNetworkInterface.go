@@ -0,0 +1,21 @@
+package domain
+
+import "github.com/google/uuid"
+
+//DeviceNetworkInterface device network interface entity
+type DeviceNetworkInterface struct {
+	//EntityUUID - nested base entity where ID type is uuid.UUID
+	EntityUUID
+	//DeviceID - id of the device to which the interface belongs
+	DeviceID uuid.UUID `gorm:"type:varchar(36);index"`
+	//Name - name of the network interface, matches DeviceTemplateNetworkInterface.Name
+	Name string
+	//MAC - network interface mac address
+	MAC string `gorm:"type:varchar(17);index"`
+	//EthernetSwitchID - id of the ethernet switch to which the interface is cabled,
+	//empty uuid if interface is not cabled
+	EthernetSwitchID uuid.UUID `gorm:"type:varchar(36);index"`
+	//EthernetSwitchPortID - id of the ethernet switch port to which the interface is cabled,
+	//empty uuid if interface is not cabled
+	EthernetSwitchPortID uuid.UUID `gorm:"type:varchar(36);index"`
+}
@@ -0,0 +1,24 @@
+package domain
+
+//DevicePowerState power state of the device
+type DevicePowerState uint
+
+const (
+	//DevicePowerStateUnknown device power state is unknown, power was never controlled by the system
+	DevicePowerStateUnknown = DevicePowerState(iota)
+	//DevicePowerStateOn device is powered on
+	DevicePowerStateOn
+	//DevicePowerStateOff device is powered off
+	DevicePowerStateOff
+)
+
+//String convert state to string
+func (s DevicePowerState) String() string {
+	switch s {
+	case DevicePowerStateOn:
+		return "on"
+	case DevicePowerStateOff:
+		return "off"
+	}
+	return "unknown"
+}
@@ -1,5 +1,21 @@
 package domain
 
+const (
+	//EthernetSwitchTransportTelnet switch CLI is managed over telnet
+	EthernetSwitchTransportTelnet = "telnet"
+	//EthernetSwitchTransportSSH switch CLI is managed over ssh
+	EthernetSwitchTransportSSH = "ssh"
+)
+
+const (
+	//EthernetSwitchCapabilityPOE switch can manage power over ethernet on ports
+	EthernetSwitchCapabilityPOE = "poe"
+	//EthernetSwitchCapabilityVLAN switch can manage VLANs and ports PVID
+	EthernetSwitchCapabilityVLAN = "vlan"
+	//EthernetSwitchCapabilityLLDP switch can report LLDP neighbors
+	EthernetSwitchCapabilityLLDP = "lldp"
+)
+
 //EthernetSwitch ethernet switch entity
 type EthernetSwitch struct {
 	//EntityUUID - nested base entity where ID type is uuid.UUID
@@ -16,6 +32,26 @@ type EthernetSwitch struct {
 	Username string
 	//	Password - switch management password
 	Password string
+	//	Transport - switch CLI management transport: "telnet" or "ssh"
+	Transport string
+	//	SSHHostKeyFingerprint - pinned SHA256 fingerprint of the switch ssh host key
+	SSHHostKeyFingerprint string
+	//	SNMPVersion - SNMP protocol version: "2c" or "3", empty if switch is not managed over SNMP
+	SNMPVersion string
+	//	SNMPPort - SNMP agent udp port, 161 is used if not set
+	SNMPPort int
+	//	SNMPCommunity - SNMP v2c community
+	SNMPCommunity string
+	//	SNMPUsername - SNMP v3 user name
+	SNMPUsername string
+	//	SNMPAuthProtocol - SNMP v3 authentication protocol: "md5", "sha" or empty for noAuth
+	SNMPAuthProtocol string
+	//	SNMPAuthPassword - SNMP v3 authentication passphrase
+	SNMPAuthPassword string
+	//	SNMPPrivProtocol - SNMP v3 privacy protocol: "des", "aes" or empty for noPriv
+	SNMPPrivProtocol string
+	//	SNMPPrivPassword - SNMP v3 privacy passphrase
+	SNMPPrivPassword string
 }
 
 //EthernetSwitchModel - Ethernet switch model info
@@ -26,4 +62,6 @@ type EthernetSwitchModel struct {
 	Manufacturer string
 	//Series - Switch model
 	Model string
+	//Capabilities - features the switch driver can manage, see EthernetSwitchCapability constants
+	Capabilities []string
 }
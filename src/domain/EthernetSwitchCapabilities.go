@@ -0,0 +1,15 @@
+package domain
+
+//EthernetSwitchCapabilities features of the ethernet switch reported by its manager
+type EthernetSwitchCapabilities struct {
+	//Ports - names of the switch physical ports
+	Ports []string
+	//MaxVLANs - max number of VLANs on the switch, 0 if VLANs are not supported
+	MaxVLANs int
+	//POEPorts - names of the ports that can supply PoE, empty if PoE is not supported
+	POEPorts []string
+	//POETypes - PoE types that can be enabled on POE ports: "poe", "poe+", "passive24"
+	POETypes []string
+	//POEBudget - total PoE power budget of the switch in watts, 0 if unknown
+	POEBudget int
+}
@@ -0,0 +1,26 @@
+package domain
+
+import "github.com/google/uuid"
+
+const (
+	//EthernetSwitchConfigBackupScheduled backup is taken by schedule
+	EthernetSwitchConfigBackupScheduled = "scheduled"
+	//EthernetSwitchConfigBackupChange backup is taken after the switch configuration change
+	EthernetSwitchConfigBackupChange = "change"
+	//EthernetSwitchConfigBackupRestore backup is taken after the restore of the other backup
+	EthernetSwitchConfigBackupRestore = "restore"
+)
+
+//EthernetSwitchConfigBackup ethernet switch running config backup entity
+type EthernetSwitchConfigBackup struct {
+	//EntityUUID - nested base entity where ID type is uuid.UUID
+	EntityUUID
+	//EthernetSwitchID - ethernet switch ID
+	EthernetSwitchID uuid.UUID `gorm:"index;size:36"`
+	//Version - backup version, versions of the switch backups start from 1
+	Version int `gorm:"index"`
+	//Reason - reason of the backup: "scheduled", "change" or "restore"
+	Reason string
+	//Config - switch running config
+	Config string `gorm:"type:text"`
+}
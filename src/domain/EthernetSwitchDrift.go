@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	//EthernetSwitchDriftMissingOnSwitch entity is in the database, but not on the switch
+	EthernetSwitchDriftMissingOnSwitch = "missingOnSwitch"
+	//EthernetSwitchDriftMissingInDatabase VLAN is on the switch, but not in the database
+	EthernetSwitchDriftMissingInDatabase = "missingInDatabase"
+	//EthernetSwitchDriftPortsMismatch VLAN ports on the switch differ from the database
+	EthernetSwitchDriftPortsMismatch = "portsMismatch"
+	//EthernetSwitchDriftPOEMismatch port PoE status on the switch differs from the database
+	EthernetSwitchDriftPOEMismatch = "poeMismatch"
+	//EthernetSwitchDriftPVIDMismatch port PVID on the switch differs from the database
+	EthernetSwitchDriftPVIDMismatch = "pvidMismatch"
+)
+
+const (
+	//EthernetSwitchReconcileImport switch state is imported into the database
+	EthernetSwitchReconcileImport = "import"
+	//EthernetSwitchReconcilePush database state is pushed to the switch
+	EthernetSwitchReconcilePush = "push"
+)
+
+//EthernetSwitchDrift differences between the switch configuration in the database and the actual switch state
+type EthernetSwitchDrift struct {
+	//SwitchID - ethernet switch ID
+	SwitchID uuid.UUID
+	//CheckedAt - time of the switch state reading
+	CheckedAt time.Time
+	//Error - error of the switch state reading, differences are empty if it is set
+	Error string
+	//VLANs - VLANs differences
+	VLANs []EthernetSwitchVLANDrift
+	//Ports - ports differences
+	Ports []EthernetSwitchPortDrift
+}
+
+//InSync switch state matches the database
+func (d EthernetSwitchDrift) InSync() bool {
+	return d.Error == "" && len(d.VLANs) == 0 && len(d.Ports) == 0
+}
+
+//EthernetSwitchVLANDrift VLAN difference, ports are compared only for the ports stored in the database
+type EthernetSwitchVLANDrift struct {
+	//VlanID - VLAN ID
+	VlanID int
+	//State - difference kind: "missingOnSwitch", "missingInDatabase" or "portsMismatch"
+	State string
+	//DatabaseTaggedPorts - names of the tagged ports in the database
+	DatabaseTaggedPorts []string
+	//DatabaseUntaggedPorts - names of the untagged ports in the database
+	DatabaseUntaggedPorts []string
+	//SwitchTaggedPorts - names of the tagged ports on the switch
+	SwitchTaggedPorts []string
+	//SwitchUntaggedPorts - names of the untagged ports on the switch
+	SwitchUntaggedPorts []string
+}
+
+//EthernetSwitchPortDrift port difference, port with several differences has an entry for each of them
+type EthernetSwitchPortDrift struct {
+	//PortID - switch port ID
+	PortID uuid.UUID
+	//PortName - switch port name
+	PortName string
+	//State - difference kind: "missingOnSwitch", "poeMismatch" or "pvidMismatch"
+	State string
+	//DatabasePOEEnabled - PoE status in the database
+	DatabasePOEEnabled bool
+	//SwitchPOEEnabled - PoE status on the switch
+	SwitchPOEEnabled bool
+	//DatabasePVID - port PVID in the database
+	DatabasePVID int
+	//SwitchPVID - port PVID on the switch
+	SwitchPVID int
+}
@@ -0,0 +1,17 @@
+package domain
+
+//EthernetSwitchLLDPNeighbor device that is seen by the switch port over LLDP
+type EthernetSwitchLLDPNeighbor struct {
+	//PortName - name of the switch port that received the neighbor advertisement
+	PortName string
+	//ChassisID - neighbor chassis ID, usually its MAC address
+	ChassisID string
+	//PortID - ID of the neighbor port, usually its interface name or MAC address
+	PortID string
+	//PortDescription - description of the neighbor port
+	PortDescription string
+	//SystemName - neighbor system name
+	SystemName string
+	//ManagementAddress - neighbor management IP address, empty if it is not advertised
+	ManagementAddress string
+}
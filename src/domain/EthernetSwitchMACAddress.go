@@ -0,0 +1,11 @@
+package domain
+
+//EthernetSwitchMACAddress entry of the switch MAC address forwarding table
+type EthernetSwitchMACAddress struct {
+	//MAC - learned MAC address in lower case with colons: 00:11:22:33:44:55
+	MAC string
+	//VLANID - VLAN in which the address was learned
+	VLANID int
+	//PortName - name of the switch port behind which the address was learned
+	PortName string
+}
@@ -0,0 +1,32 @@
+package domain
+
+const (
+	//EthernetSwitchOperationCreateVLAN create VLAN on the switch
+	EthernetSwitchOperationCreateVLAN = "createVLAN"
+	//EthernetSwitchOperationDeleteVLAN delete VLAN from the switch
+	EthernetSwitchOperationDeleteVLAN = "deleteVLAN"
+	//EthernetSwitchOperationAddTaggedVLAN add tagged VLAN on the port
+	EthernetSwitchOperationAddTaggedVLAN = "addTaggedVLAN"
+	//EthernetSwitchOperationAddUntaggedVLAN add untagged VLAN on the port
+	EthernetSwitchOperationAddUntaggedVLAN = "addUntaggedVLAN"
+	//EthernetSwitchOperationRemoveVLAN remove VLAN from the port
+	EthernetSwitchOperationRemoveVLAN = "removeVLAN"
+	//EthernetSwitchOperationSetPVID set PVID of the port
+	EthernetSwitchOperationSetPVID = "setPVID"
+	//EthernetSwitchOperationEnablePOE enable PoE on the port
+	EthernetSwitchOperationEnablePOE = "enablePOE"
+	//EthernetSwitchOperationDisablePOE disable PoE on the port
+	EthernetSwitchOperationDisablePOE = "disablePOE"
+)
+
+//EthernetSwitchOperation single change of the switch configuration, the changes are applied by manager in batches
+type EthernetSwitchOperation struct {
+	//Type - operation type, one of EthernetSwitchOperation constants
+	Type string
+	//PortName - switch port name, empty for the VLAN operations
+	PortName string
+	//VLANID - VLAN ID or PVID
+	VLANID int
+	//POEType - PoE type for enable PoE operation
+	POEType string
+}
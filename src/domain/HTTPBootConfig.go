@@ -0,0 +1,13 @@
+// Package domain stores the main structures of the program
+package domain
+
+//HTTPBootConfig HTTP boot file server config entity
+type HTTPBootConfig struct {
+	EntityUUID
+	//Address HTTP boot server IP address
+	Address string
+	//Port HTTP boot server port
+	Port string
+	//Enabled HTTP boot server startup status
+	Enabled bool
+}
@@ -0,0 +1,18 @@
+// Package domain stores the main structures of the program
+package domain
+
+import "time"
+
+//HTTPBootFileStats download statistics of the file served by the HTTP boot server
+type HTTPBootFileStats struct {
+	//VirtualPath requested virtual file path
+	VirtualPath string
+	//Requests count of the requests, including range requests
+	Requests int64
+	//Downloads count of the responses that sent the whole file
+	Downloads int64
+	//BytesSent total count of the sent bytes
+	BytesSent int64
+	//LastRequestAt time of the last request
+	LastRequestAt time.Time
+}
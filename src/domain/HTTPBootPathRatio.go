@@ -0,0 +1,17 @@
+// Package domain stores the main structures of the program
+package domain
+
+import "github.com/google/uuid"
+
+//HTTPBootPathRatio HTTP boot server path ratio entity
+type HTTPBootPathRatio struct {
+	EntityUUID
+	//HTTPBootConfigID HTTP boot server config ID
+	HTTPBootConfigID uuid.UUID `gorm:"type:varchar(36);index"`
+	//ActualPath actual file or directory path
+	ActualPath string
+	//VirtualPath virtual file or directory path, it's the url path of the file
+	VirtualPath string
+	//IsDirectory if true, all files under the VirtualPath directory are served from the ActualPath directory
+	IsDirectory bool
+}
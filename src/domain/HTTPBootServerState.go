@@ -0,0 +1,27 @@
+// Package domain stores the main structures of the program
+package domain
+
+//HTTPBootServerState state for HTTP boot servers
+type HTTPBootServerState uint
+
+const (
+	//HTTPBootStateLaunched http boot server in launched state
+	HTTPBootStateLaunched = HTTPBootServerState(iota)
+	//HTTPBootStateStopped http boot server in stopped state
+	HTTPBootStateStopped
+	//HTTPBootStateError http boot server failed to start or stopped with error
+	HTTPBootStateError
+)
+
+//String convert state to string
+func (s HTTPBootServerState) String() string {
+	switch s {
+	case HTTPBootStateLaunched:
+		return "launched"
+	case HTTPBootStateStopped:
+		return "stopped"
+	case HTTPBootStateError:
+		return "error"
+	}
+	return "unknown"
+}
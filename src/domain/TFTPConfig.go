@@ -10,4 +10,10 @@ type TFTPConfig struct {
 	Port string
 	//Enabled TFTP server startup status
 	Enabled bool
+	//WriteEnabled allows clients to upload files to the UploadDir
+	WriteEnabled bool
+	//UploadDir root directory for uploaded files
+	UploadDir string
+	//MaxUploadSize max size of the uploaded file in bytes, if 0 the default limit is used
+	MaxUploadSize int64
 }
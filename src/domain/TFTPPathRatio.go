@@ -8,8 +8,17 @@ type TFTPPathRatio struct {
 	EntityUUID
 	//TFTPConfigID TFTP config ID
 	TFTPConfigID uuid.UUID
-	//ActualPath actual file path
+	//ActualPath actual file or directory path
 	ActualPath string
-	//VirtualPath virtual file path
+	//VirtualPath virtual file or directory path
 	VirtualPath string
+	//IsDirectory if true, all files under the VirtualPath directory are served from the ActualPath directory
+	IsDirectory bool
+	//ClientIP if set, ratio is used only for the client with this ip address
+	ClientIP string
+	//ClientMAC if set, ratio is used only for the client with this mac address,
+	//client mac address is resolved by the ip address through the DHCP v4 leases and reservations
+	ClientMAC string
+	//IsTemplate if true, file is rendered as go text/template for each request
+	IsTemplate bool
 }
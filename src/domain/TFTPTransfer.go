@@ -0,0 +1,65 @@
+// Package domain stores the main structures of the program
+package domain
+
+import "time"
+
+//TFTPTransferState state of the TFTP transfer
+type TFTPTransferState uint
+
+const (
+	//TFTPTransferActive transfer is in progress
+	TFTPTransferActive = TFTPTransferState(iota)
+	//TFTPTransferSucceeded transfer is finished successfully
+	TFTPTransferSucceeded
+	//TFTPTransferFailed transfer is finished with error
+	TFTPTransferFailed
+)
+
+//String convert transfer state to string
+func (s TFTPTransferState) String() string {
+	switch s {
+	case TFTPTransferActive:
+		return "active"
+	case TFTPTransferSucceeded:
+		return "succeeded"
+	case TFTPTransferFailed:
+		return "failed"
+	}
+	return "unknown"
+}
+
+//TFTPTransfer single file transfer of the TFTP server
+type TFTPTransfer struct {
+	//ClientAddress client ip address and port
+	ClientAddress string
+	//IsUpload true if file is uploaded by the client
+	IsUpload bool
+	//VirtualPath file name requested by the client
+	VirtualPath string
+	//ActualPath actual file path, empty if path ratio is not found
+	ActualPath string
+	//Bytes count of the transferred bytes
+	Bytes int64
+	//StartedAt transfer start time
+	StartedAt time.Time
+	//Duration transfer duration, for the active transfer it's the duration up to now
+	Duration time.Duration
+	//State transfer state
+	State TFTPTransferState
+	//Error transfer error message, empty if transfer is not failed
+	Error string
+}
+
+//TFTPPathStats transfer counters of the single virtual path of the TFTP server
+type TFTPPathStats struct {
+	//VirtualPath file name requested by the clients
+	VirtualPath string
+	//Succeeded count of the succeeded transfers
+	Succeeded int64
+	//Failed count of the failed transfers
+	Failed int64
+	//Bytes total count of the transferred bytes
+	Bytes int64
+	//LastTransferAt time of the last finished transfer
+	LastTransferAt time.Time
+}
@@ -0,0 +1,18 @@
+package dtos
+
+//DHCP4BootRuleBaseDto base DTO for DHCP v4 boot rule entity
+type DHCP4BootRuleBaseDto struct {
+	//Priority rule priority, lower value is checked first
+	Priority int
+	//Architecture client system architecture code from option 93, like 0 for BIOS, 7 for UEFI x64, 11 for ARM64 UEFI,
+	//null matches any architecture
+	Architecture *int
+	//VendorClass prefix of the vendor class identifier from option 60, like PXEClient, empty matches any vendor class
+	VendorClass string
+	//UserClass user class from option 77, like iPXE, empty matches any user class
+	UserClass string
+	//NextServer address of the boot server in ipv4 format, optional, overrides server next server
+	NextServer string
+	//BootFileName boot file name, overrides server boot file name
+	BootFileName string
+}
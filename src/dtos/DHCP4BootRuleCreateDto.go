@@ -0,0 +1,7 @@
+package dtos
+
+//DHCP4BootRuleCreateDto DTO for creating DHCP v4 boot rule entity
+type DHCP4BootRuleCreateDto struct {
+	//	DHCP4BootRuleBaseDto - nested base DHCP v4 boot rule dto structure
+	DHCP4BootRuleBaseDto
+}
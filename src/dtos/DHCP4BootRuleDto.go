@@ -0,0 +1,11 @@
+package dtos
+
+import "github.com/google/uuid"
+
+//DHCP4BootRuleDto DTO for DHCP v4 boot rule entity
+type DHCP4BootRuleDto struct {
+	//	DHCP4BootRuleBaseDto - nested base DHCP v4 boot rule dto structure
+	DHCP4BootRuleBaseDto
+	//	BaseDto - nested base dto structure
+	BaseDto[uuid.UUID]
+}
@@ -0,0 +1,7 @@
+package dtos
+
+//DHCP4BootRuleUpdateDto DTO for updating DHCP v4 boot rule entity
+type DHCP4BootRuleUpdateDto struct {
+	//	DHCP4BootRuleBaseDto - nested base DHCP v4 boot rule dto structure
+	DHCP4BootRuleBaseDto
+}
@@ -0,0 +1,13 @@
+package dtos
+
+//DHCP4ReservationBaseDto base DTO for DHCP v4 reservation entity
+type DHCP4ReservationBaseDto struct {
+	//IP reserved address in ipv4 format
+	IP string
+	//MAC address in format like this 00:00:00:00:00:00
+	MAC string
+	//Hostname host name for the client, optional
+	Hostname string
+	//BootFileName per-host boot file name, optional, overrides server boot file name
+	BootFileName string
+}
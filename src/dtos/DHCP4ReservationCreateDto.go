@@ -0,0 +1,7 @@
+package dtos
+
+//DHCP4ReservationCreateDto DTO for creating DHCP v4 reservation entity
+type DHCP4ReservationCreateDto struct {
+	//	DHCP4ReservationBaseDto - nested base DHCP v4 reservation dto structure
+	DHCP4ReservationBaseDto
+}
@@ -0,0 +1,11 @@
+package dtos
+
+import "github.com/google/uuid"
+
+//DHCP4ReservationDto DTO for DHCP v4 reservation entity
+type DHCP4ReservationDto struct {
+	//	DHCP4ReservationBaseDto - nested base DHCP v4 reservation dto structure
+	DHCP4ReservationBaseDto
+	//	BaseDto - nested base dto structure
+	BaseDto[uuid.UUID]
+}
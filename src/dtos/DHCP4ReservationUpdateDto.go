@@ -0,0 +1,7 @@
+package dtos
+
+//DHCP4ReservationUpdateDto DTO for updating DHCP v4 reservation entity
+type DHCP4ReservationUpdateDto struct {
+	//	DHCP4ReservationBaseDto - nested base DHCP v4 reservation dto structure
+	DHCP4ReservationBaseDto
+}
@@ -16,6 +16,10 @@ type DHCP4ServerCreateDto struct {
 	DNS string
 	//NTP IP address or dns name of NTP server
 	NTP string
+	//NextServer IP address of the boot server (TFTP), sent as siaddr and option 66
+	NextServer string
+	//BootFileName boot file name, sent as option 67
+	BootFileName string
 	//Enabled server or no
 	Enabled bool
 	//Port of DHCP server
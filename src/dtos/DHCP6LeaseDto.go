@@ -0,0 +1,19 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+//DHCP6LeaseDto DTO for DHCP v6 lease entity
+type DHCP6LeaseDto struct {
+	BaseDto[uuid.UUID]
+	//IP address in ipv6 format
+	IP string
+	//DUID client DUID in hex format
+	DUID string
+	//MAC address in format like this 00:00:00:00:00:00
+	MAC string
+	//Expires datetime
+	Expires time.Time
+}
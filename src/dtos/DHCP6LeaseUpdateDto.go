@@ -0,0 +1,15 @@
+package dtos
+
+import "time"
+
+//DHCP6LeaseUpdateDto DTO for updating DHCP v6 lease entity
+type DHCP6LeaseUpdateDto struct {
+	//IP address in ipv6 format
+	IP string
+	//DUID client DUID in hex format
+	DUID string
+	//MAC address in format like this 00:00:00:00:00:00, optional
+	MAC string
+	//Expires datetime
+	Expires time.Time
+}
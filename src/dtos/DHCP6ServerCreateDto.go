@@ -0,0 +1,17 @@
+package dtos
+
+//DHCP6ServerCreateDto DTO for creating DHCP v6 server
+type DHCP6ServerCreateDto struct {
+	//Range of ipv6 addresses for this dhcp v6 server, separated by "-", for example: "fd00::100-fd00::1ff"
+	Range string
+	//Interface name
+	Interface string
+	//DNS servers in ipv6 format, separated by ";"
+	DNS string
+	//Enabled server or no
+	Enabled bool
+	//Port of DHCP server
+	Port int
+	//LeaseTime for dhcp v6 server leases
+	LeaseTime int
+}
@@ -0,0 +1,22 @@
+package dtos
+
+import "github.com/google/uuid"
+
+//DHCP6ServerDto DTO for DHCP v6 server entity
+type DHCP6ServerDto struct {
+	BaseDto[uuid.UUID]
+	//Range of ipv6 addresses for this dhcp v6 server, separated by "-", for example: "fd00::100-fd00::1ff"
+	Range string
+	//Interface name
+	Interface string
+	//DNS servers in ipv6 format, separated by ";"
+	DNS string
+	//Enabled server or no
+	Enabled bool
+	//Port of DHCP server
+	Port int
+	//LeaseTime for dhcp v6 server leases
+	LeaseTime int
+	//State current state of dhcp v6 server
+	State string
+}
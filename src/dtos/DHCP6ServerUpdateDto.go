@@ -0,0 +1,13 @@
+package dtos
+
+//DHCP6ServerUpdateDto DTO for updating DHCP v6 server
+type DHCP6ServerUpdateDto struct {
+	//DNS servers in ipv6 format, separated by ";"
+	DNS string
+	//Enabled server or no
+	Enabled bool
+	//Port of DHCP server
+	Port int
+	//LeaseTime for dhcp v6 server leases
+	LeaseTime int
+}
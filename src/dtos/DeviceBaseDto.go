@@ -0,0 +1,9 @@
+package dtos
+
+//DeviceBaseDto base dto for device
+type DeviceBaseDto struct {
+	//Name device name
+	Name string
+	//Serial device serial number
+	Serial string
+}
@@ -0,0 +1,32 @@
+// Package dtos stores all data transfer objects
+package dtos
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+//DeviceBootDto device boot dto
+type DeviceBootDto struct {
+	BaseDto[uuid.UUID]
+	//DeviceID id of the booting device
+	DeviceID uuid.UUID
+	//BootType boot stages list of the device template: net, disc or usb
+	BootType string
+	//TFTPServerID id of the TFTP server where the stages files are installed
+	TFTPServerID uuid.UUID
+	//StageTimeout max time of waiting for the stage events in seconds
+	StageTimeout int
+	//StageIndex index of the current stage
+	StageIndex int
+	//StageName name of the current stage
+	StageName string
+	//State boot state: running, succeeded, failed or cancelled
+	State string
+	//FinishedAt boot finish time, zero if boot is running
+	FinishedAt time.Time
+	//Error boot error message
+	Error string
+	//Stages history of the executed stages
+	Stages []DeviceBootStageDto
+}
@@ -0,0 +1,26 @@
+// Package dtos stores all data transfer objects
+package dtos
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+//DeviceBootStageDto executed stage of the device boot dto
+type DeviceBootStageDto struct {
+	BaseDto[uuid.UUID]
+	//Index index of the stage in the boot stages list
+	Index int
+	//Name stage name
+	Name string
+	//Action stage action
+	Action string
+	//State stage state: running, succeeded, failed or cancelled
+	State string
+	//StartedAt stage start time
+	StartedAt time.Time
+	//FinishedAt stage finish time, zero if stage is running
+	FinishedAt time.Time
+	//Message events observed on the stage or error message
+	Message string
+}
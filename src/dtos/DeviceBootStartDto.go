@@ -0,0 +1,14 @@
+// Package dtos stores all data transfer objects
+package dtos
+
+import "github.com/google/uuid"
+
+//DeviceBootStartDto dto for starting the device boot
+type DeviceBootStartDto struct {
+	//BootType boot stages list of the device template: net, disc or usb
+	BootType string
+	//TFTPServerID id of the TFTP server where the stages files are installed
+	TFTPServerID uuid.UUID
+	//StageTimeout max time of waiting for the stage events in seconds, 300 if not set
+	StageTimeout int
+}
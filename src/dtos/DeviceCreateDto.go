@@ -0,0 +1,12 @@
+package dtos
+
+//DeviceCreateDto device create dto
+type DeviceCreateDto struct {
+	//	DeviceBaseDto - nested base device dto structure
+	DeviceBaseDto
+	//DeviceTemplate name of the device template from which the device will be created
+	DeviceTemplate string
+	//NetworkInterfaces initial settings of the device network interfaces,
+	//interfaces that are not listed will be created from template with empty settings
+	NetworkInterfaces []DeviceNetworkInterfaceCreateDto
+}
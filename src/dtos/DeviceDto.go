@@ -0,0 +1,17 @@
+package dtos
+
+import "github.com/google/uuid"
+
+//DeviceDto device response dto
+type DeviceDto struct {
+	//	DeviceBaseDto - nested base device dto structure
+	DeviceBaseDto
+	//	BaseDto - nested base dto structure
+	BaseDto[uuid.UUID]
+	//DeviceTemplate name of the device template from which the device was created
+	DeviceTemplate string
+	//NetworkInterfaces device network interfaces
+	NetworkInterfaces []DeviceNetworkInterfaceDto
+	//PowerState last power state set by the system, can be: "on", "off", "unknown"
+	PowerState string
+}
@@ -0,0 +1,13 @@
+package dtos
+
+import "github.com/google/uuid"
+
+//DeviceNetworkInterfaceBaseDto base dto for device network interface
+type DeviceNetworkInterfaceBaseDto struct {
+	//MAC network interface mac address
+	MAC string
+	//EthernetSwitchID id of the ethernet switch to which the interface is cabled
+	EthernetSwitchID uuid.UUID
+	//EthernetSwitchPortID id of the ethernet switch port to which the interface is cabled
+	EthernetSwitchPortID uuid.UUID
+}
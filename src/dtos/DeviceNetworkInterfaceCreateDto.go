@@ -0,0 +1,9 @@
+package dtos
+
+//DeviceNetworkInterfaceCreateDto device network interface create dto
+type DeviceNetworkInterfaceCreateDto struct {
+	//	DeviceNetworkInterfaceBaseDto - nested base device network interface dto structure
+	DeviceNetworkInterfaceBaseDto
+	//Name of network interface from the device template
+	Name string
+}
@@ -0,0 +1,13 @@
+package dtos
+
+import "github.com/google/uuid"
+
+//DeviceNetworkInterfaceDto device network interface response dto
+type DeviceNetworkInterfaceDto struct {
+	//	DeviceNetworkInterfaceBaseDto - nested base device network interface dto structure
+	DeviceNetworkInterfaceBaseDto
+	//	BaseDto - nested base dto structure
+	BaseDto[uuid.UUID]
+	//Name of network interface from the device template
+	Name string
+}
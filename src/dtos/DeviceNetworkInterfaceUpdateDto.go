@@ -0,0 +1,7 @@
+package dtos
+
+//DeviceNetworkInterfaceUpdateDto device network interface update dto
+type DeviceNetworkInterfaceUpdateDto struct {
+	//	DeviceNetworkInterfaceBaseDto - nested base device network interface dto structure
+	DeviceNetworkInterfaceBaseDto
+}
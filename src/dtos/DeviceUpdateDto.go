@@ -0,0 +1,7 @@
+package dtos
+
+//DeviceUpdateDto device update dto
+type DeviceUpdateDto struct {
+	//	DeviceBaseDto - nested base device dto structure
+	DeviceBaseDto
+}
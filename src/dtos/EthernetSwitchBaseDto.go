@@ -12,4 +12,18 @@ type EthernetSwitchBaseDto struct {
 	Address string
 	//	Username - switch admin username
 	Username string
+	//	Transport - switch CLI management transport: "telnet" or "ssh", telnet is used if not set
+	Transport string
+	//	SSHHostKeyFingerprint - pinned SHA256 fingerprint of the switch ssh host key, required for ssh transport
+	SSHHostKeyFingerprint string
+	//	SNMPVersion - SNMP protocol version: "2c" or "3", empty if switch is not managed over SNMP
+	SNMPVersion string
+	//	SNMPPort - SNMP agent udp port, 161 is used if not set
+	SNMPPort int
+	//	SNMPUsername - SNMP v3 user name
+	SNMPUsername string
+	//	SNMPAuthProtocol - SNMP v3 authentication protocol: "md5", "sha" or empty for noAuth
+	SNMPAuthProtocol string
+	//	SNMPPrivProtocol - SNMP v3 privacy protocol: "des", "aes" or empty for noPriv
+	SNMPPrivProtocol string
 }
@@ -0,0 +1,22 @@
+package dtos
+
+import "github.com/google/uuid"
+
+//EthernetSwitchCablingSuggestionDto device that is probably plugged into the switch port,
+//it is found by the DHCP lease with the MAC address learned on the port
+type EthernetSwitchCablingSuggestionDto struct {
+	//	MAC - MAC address learned on the port
+	MAC string
+	//	IP - IP address of the last DHCP lease of the MAC address
+	IP string
+	//	DHCP4ServerID - ID of the DHCP server that issued the lease
+	DHCP4ServerID uuid.UUID
+	//	DeviceID - ID of the device with the network interface with this MAC address,
+	//	empty uuid if device is unknown
+	DeviceID uuid.UUID
+	//	DeviceNetworkInterfaceID - ID of the device network interface with this MAC address,
+	//	empty uuid if device is unknown
+	DeviceNetworkInterfaceID uuid.UUID
+	//	Cabled - device network interface is already cabled to this port
+	Cabled bool
+}
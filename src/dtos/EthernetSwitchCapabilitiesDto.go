@@ -0,0 +1,15 @@
+package dtos
+
+//EthernetSwitchCapabilitiesDto ethernet switch capabilities dto
+type EthernetSwitchCapabilitiesDto struct {
+	//	Ports - names of the switch physical ports
+	Ports []string
+	//	MaxVLANs - max number of VLANs on the switch, 0 if VLANs are not supported
+	MaxVLANs int
+	//	POEPorts - names of the ports that can supply PoE
+	POEPorts []string
+	//	POETypes - PoE types that can be enabled on POE ports
+	POETypes []string
+	//	POEBudget - total PoE power budget of the switch in watts, 0 if unknown
+	POEBudget int
+}
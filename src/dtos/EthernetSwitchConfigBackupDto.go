@@ -0,0 +1,16 @@
+package dtos
+
+import "github.com/google/uuid"
+
+//EthernetSwitchConfigBackupDto ethernet switch running config backup response dto
+type EthernetSwitchConfigBackupDto struct {
+	BaseDto[uuid.UUID]
+	//	EthernetSwitchID - ethernet switch ID
+	EthernetSwitchID uuid.UUID
+	//	Version - backup version, versions of the switch backups start from 1
+	Version int
+	//	Reason - reason of the backup: "scheduled", "change" or "restore"
+	Reason string
+	//	Config - switch running config
+	Config string
+}
@@ -0,0 +1,11 @@
+package dtos
+
+//EthernetSwitchConfigDiffDto unified diff between two ethernet switch running config backups
+type EthernetSwitchConfigDiffDto struct {
+	//	FromVersion - version of the original backup
+	FromVersion int
+	//	ToVersion - version of the changed backup
+	ToVersion int
+	//	Diff - unified diff, empty if the configs are equal
+	Diff string
+}
@@ -6,4 +6,12 @@ type EthernetSwitchCreateDto struct {
 	EthernetSwitchBaseDto
 	//	Password - ethernet switch management password
 	Password string
+	//	SNMPCommunity - SNMP v2c community
+	SNMPCommunity string
+	//	SNMPAuthPassword - SNMP v3 authentication passphrase
+	SNMPAuthPassword string
+	//	SNMPPrivPassword - SNMP v3 privacy passphrase
+	SNMPPrivPassword string
+	//	Discover - import ports and VLANs from the switch after creation
+	Discover bool
 }
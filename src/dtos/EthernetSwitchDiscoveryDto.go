@@ -0,0 +1,9 @@
+package dtos
+
+//EthernetSwitchDiscoveryDto ports and VLANs imported from the switch by discovery
+type EthernetSwitchDiscoveryDto struct {
+	//	Ports - created or updated switch ports
+	Ports []EthernetSwitchPortDto
+	//	VLANs - created or updated switch VLANs
+	VLANs []EthernetSwitchVLANDto
+}
@@ -0,0 +1,22 @@
+package dtos
+
+import (
+	"github.com/google/uuid"
+	"time"
+)
+
+//EthernetSwitchDriftDto differences between the switch configuration in the database and the actual switch state
+type EthernetSwitchDriftDto struct {
+	//	SwitchID - ethernet switch ID
+	SwitchID uuid.UUID
+	//	CheckedAt - time of the switch state reading
+	CheckedAt time.Time
+	//	InSync - switch state matches the database
+	InSync bool
+	//	Error - error of the switch state reading
+	Error string
+	//	VLANs - VLANs differences
+	VLANs []EthernetSwitchVLANDriftDto
+	//	Ports - ports differences
+	Ports []EthernetSwitchPortDriftDto
+}
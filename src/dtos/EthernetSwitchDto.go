@@ -8,4 +8,7 @@ type EthernetSwitchDto struct {
 	EthernetSwitchBaseDto
 	//	BaseDto - nested base dto structure
 	BaseDto[uuid.UUID]
+	//	Capabilities - switch capabilities last reported by its manager, they are read by the capabilities request,
+	//	discovery or ports changes, nil if they were not read yet or the switch has no manager
+	Capabilities *EthernetSwitchCapabilitiesDto
 }
@@ -0,0 +1,15 @@
+package dtos
+
+//EthernetSwitchLLDPNeighborDto LLDP neighbor of the switch port
+type EthernetSwitchLLDPNeighborDto struct {
+	//	ChassisID - neighbor chassis ID, MAC address in most cases
+	ChassisID string
+	//	PortID - ID of the neighbor port
+	PortID string
+	//	PortDescription - description of the neighbor port
+	PortDescription string
+	//	SystemName - neighbor system name
+	SystemName string
+	//	ManagementAddress - neighbor management IP address, empty if not advertised
+	ManagementAddress string
+}
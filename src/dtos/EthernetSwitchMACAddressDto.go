@@ -0,0 +1,9 @@
+package dtos
+
+//EthernetSwitchMACAddressDto MAC address learned on the switch port
+type EthernetSwitchMACAddressDto struct {
+	//	MAC - MAC address
+	MAC string
+	//	VLANID - ID of the VLAN where the address is learned
+	VLANID int
+}
@@ -8,4 +8,6 @@ type EthernetSwitchModelDto struct {
 	Manufacturer string
 	//Model - Switch model
 	Model string
+	//Capabilities - features the switch driver can manage: "poe", "vlan", "lldp"
+	Capabilities []string
 }
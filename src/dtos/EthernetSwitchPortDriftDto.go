@@ -0,0 +1,21 @@
+package dtos
+
+import "github.com/google/uuid"
+
+//EthernetSwitchPortDriftDto port difference between the database and the switch
+type EthernetSwitchPortDriftDto struct {
+	//	PortID - switch port ID
+	PortID uuid.UUID
+	//	PortName - switch port name
+	PortName string
+	//	State - difference kind: "missingOnSwitch", "poeMismatch" or "pvidMismatch"
+	State string
+	//	DatabasePOEEnabled - PoE status in the database
+	DatabasePOEEnabled bool
+	//	SwitchPOEEnabled - PoE status on the switch
+	SwitchPOEEnabled bool
+	//	DatabasePVID - port PVID in the database
+	DatabasePVID int
+	//	SwitchPVID - port PVID on the switch
+	SwitchPVID int
+}
@@ -0,0 +1,11 @@
+package dtos
+
+//EthernetSwitchPortNeighborsDto devices that are visible on the switch port
+type EthernetSwitchPortNeighborsDto struct {
+	//	MACAddresses - MAC addresses learned on the port
+	MACAddresses []EthernetSwitchMACAddressDto
+	//	LLDPNeighbors - LLDP neighbors of the port
+	LLDPNeighbors []EthernetSwitchLLDPNeighborDto
+	//	Suggestions - devices that are probably plugged into the port
+	Suggestions []EthernetSwitchCablingSuggestionDto
+}
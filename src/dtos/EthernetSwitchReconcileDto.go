@@ -0,0 +1,7 @@
+package dtos
+
+//EthernetSwitchReconcileDto dto for the switch configuration reconciliation
+type EthernetSwitchReconcileDto struct {
+	//	Mode - "import" writes the switch state to the database, "push" applies the database state to the switch
+	Mode string
+}
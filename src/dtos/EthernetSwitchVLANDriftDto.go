@@ -0,0 +1,17 @@
+package dtos
+
+//EthernetSwitchVLANDriftDto VLAN difference between the database and the switch
+type EthernetSwitchVLANDriftDto struct {
+	//	VlanID - VLAN ID
+	VlanID int
+	//	State - difference kind: "missingOnSwitch", "missingInDatabase" or "portsMismatch"
+	State string
+	//	DatabaseTaggedPorts - names of the tagged ports in the database
+	DatabaseTaggedPorts []string
+	//	DatabaseUntaggedPorts - names of the untagged ports in the database
+	DatabaseUntaggedPorts []string
+	//	SwitchTaggedPorts - names of the tagged ports on the switch
+	SwitchTaggedPorts []string
+	//	SwitchUntaggedPorts - names of the untagged ports on the switch
+	SwitchUntaggedPorts []string
+}
@@ -0,0 +1,12 @@
+// Package dtos stores all data transfer objects
+package dtos
+
+//HTTPBootPathBaseDto HTTP boot server path base dto
+type HTTPBootPathBaseDto struct {
+	//ActualPath actual file or directory path
+	ActualPath string
+	//VirtualPath virtual file or directory path, it's the url path of the file
+	VirtualPath string
+	//IsDirectory if true, all files under the VirtualPath directory are served from the ActualPath directory
+	IsDirectory bool
+}
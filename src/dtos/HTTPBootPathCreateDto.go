@@ -0,0 +1,7 @@
+// Package dtos stores all data transfer objects
+package dtos
+
+//HTTPBootPathCreateDto HTTP boot server path create dto
+type HTTPBootPathCreateDto struct {
+	HTTPBootPathBaseDto
+}
@@ -0,0 +1,10 @@
+// Package dtos stores all data transfer objects
+package dtos
+
+import "github.com/google/uuid"
+
+//HTTPBootPathDto HTTP boot server path dto
+type HTTPBootPathDto struct {
+	BaseDto[uuid.UUID]
+	HTTPBootPathBaseDto
+}
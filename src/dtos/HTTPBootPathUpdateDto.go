@@ -0,0 +1,7 @@
+// Package dtos stores all data transfer objects
+package dtos
+
+//HTTPBootPathUpdateDto HTTP boot server path update dto
+type HTTPBootPathUpdateDto struct {
+	HTTPBootPathBaseDto
+}
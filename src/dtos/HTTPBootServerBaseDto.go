@@ -0,0 +1,12 @@
+// Package dtos stores all data transfer objects
+package dtos
+
+//HTTPBootServerBaseDto HTTP boot server base dto
+type HTTPBootServerBaseDto struct {
+	//Address HTTP boot server IP address
+	Address string
+	//Port HTTP boot server port
+	Port string
+	//Enabled HTTP boot server startup status
+	Enabled bool
+}
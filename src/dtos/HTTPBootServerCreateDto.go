@@ -0,0 +1,7 @@
+// Package dtos stores all data transfer objects
+package dtos
+
+//HTTPBootServerCreateDto HTTP boot server create dto
+type HTTPBootServerCreateDto struct {
+	HTTPBootServerBaseDto
+}
@@ -0,0 +1,12 @@
+// Package dtos stores all data transfer objects
+package dtos
+
+import "github.com/google/uuid"
+
+//HTTPBootServerDto HTTP boot server dto
+type HTTPBootServerDto struct {
+	BaseDto[uuid.UUID]
+	HTTPBootServerBaseDto
+	//State of http boot server
+	State string
+}
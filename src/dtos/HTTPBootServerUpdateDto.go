@@ -0,0 +1,7 @@
+// Package dtos stores all data transfer objects
+package dtos
+
+//HTTPBootServerUpdateDto HTTP boot server update dto
+type HTTPBootServerUpdateDto struct {
+	HTTPBootServerBaseDto
+}
@@ -0,0 +1,18 @@
+// Package dtos stores all data transfer objects
+package dtos
+
+import "time"
+
+//TFTPPathStatsDto transfer counters of the TFTP server virtual path
+type TFTPPathStatsDto struct {
+	//VirtualPath file name requested by the clients
+	VirtualPath string
+	//Succeeded count of the succeeded transfers
+	Succeeded int64
+	//Failed count of the failed transfers
+	Failed int64
+	//Bytes total count of the transferred bytes
+	Bytes int64
+	//LastTransferAt time of the last finished transfer
+	LastTransferAt time.Time
+}
@@ -9,4 +9,10 @@ type TFTPServerBaseDto struct {
 	Port string
 	//Enabled TFTP server startup status
 	Enabled bool
+	//WriteEnabled allows clients to upload files to the UploadDir
+	WriteEnabled bool
+	//UploadDir root directory for uploaded files, required if WriteEnabled is true
+	UploadDir string
+	//MaxUploadSize max size of the uploaded file in bytes, if 0 the default limit is used
+	MaxUploadSize int64
 }
@@ -9,4 +9,6 @@ type TFTPServerDto struct {
 	TFTPServerBaseDto
 	//State of tftp server
 	State string
+	//LastError message of the last start error of tftp server, empty if server is started successfully
+	LastError string
 }
@@ -0,0 +1,26 @@
+// Package dtos stores all data transfer objects
+package dtos
+
+import "time"
+
+//TFTPTransferDto TFTP server file transfer dto
+type TFTPTransferDto struct {
+	//ClientAddress client ip address and port
+	ClientAddress string
+	//IsUpload true if file is uploaded by the client
+	IsUpload bool
+	//VirtualPath file name requested by the client
+	VirtualPath string
+	//ActualPath actual file path, empty if path ratio is not found
+	ActualPath string
+	//Bytes count of the transferred bytes
+	Bytes int64
+	//StartedAt transfer start time
+	StartedAt time.Time
+	//DurationMs transfer duration in milliseconds
+	DurationMs int64
+	//State transfer state: active, succeeded or failed
+	State string
+	//Error transfer error message
+	Error string
+}
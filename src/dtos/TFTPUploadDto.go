@@ -0,0 +1,14 @@
+// Package dtos stores all data transfer objects
+package dtos
+
+import "time"
+
+//TFTPUploadDto file uploaded to the TFTP server dto
+type TFTPUploadDto struct {
+	//Name path of the file relative to the server upload directory
+	Name string
+	//Size of the file in bytes
+	Size int64
+	//ModifiedAt last modification time of the file
+	ModifiedAt time.Time
+}
@@ -0,0 +1,58 @@
+// Package infrastructure stores all implementations of app interfaces
+package infrastructure
+
+import (
+	"rol/app/interfaces"
+	"rol/domain"
+	"sync"
+)
+
+//bootEventsQueueSize max count of the queued events, events are dropped if queue is full
+const bootEventsQueueSize = 256
+
+//ChannelBootEventBus IBootEventBus implementation, events are queued to the channel
+//and passed to the subscribers by the single dispatcher goroutine
+type ChannelBootEventBus struct {
+	events   chan domain.BootEvent
+	handlers []func(event domain.BootEvent)
+	//handlersMutex guards handlers slice
+	handlersMutex sync.RWMutex
+}
+
+//NewChannelBootEventBus creates new boot events bus and starts its dispatcher
+//
+//Return:
+//	interfaces.IBootEventBus - boot events bus
+func NewChannelBootEventBus() interfaces.IBootEventBus {
+	bus := &ChannelBootEventBus{
+		events: make(chan domain.BootEvent, bootEventsQueueSize),
+	}
+	go bus.dispatch()
+	return bus
+}
+
+func (b *ChannelBootEventBus) dispatch() {
+	for event := range b.events {
+		b.handlersMutex.RLock()
+		handlers := b.handlers
+		b.handlersMutex.RUnlock()
+		for _, handler := range handlers {
+			handler(event)
+		}
+	}
+}
+
+//Publish event to the subscribers, event is dropped if the queue is full
+func (b *ChannelBootEventBus) Publish(event domain.BootEvent) {
+	select {
+	case b.events <- event:
+	default:
+	}
+}
+
+//Subscribe handler to all published events
+func (b *ChannelBootEventBus) Subscribe(handler func(event domain.BootEvent)) {
+	b.handlersMutex.Lock()
+	defer b.handlersMutex.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
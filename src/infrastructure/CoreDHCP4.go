@@ -1,6 +1,7 @@
 package infrastructure
 
 import (
+	"fmt"
 	"github.com/coredhcp/coredhcp/plugins"
 	"github.com/google/uuid"
 	pluginDNS "github.com/insei/coredhcp/plugins/dns"
@@ -19,15 +20,33 @@ import (
 
 var pluginsInitialized = false
 
-func initializeCoreDHCPPlugins(leasesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease]) error {
+//defaultDHCP4LeaseTime lease time in seconds, used if lease time is not set in config
+const defaultDHCP4LeaseTime = 3600
+
+func initializeCoreDHCPPlugins(
+	leasesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease],
+	reservationsRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Reservation],
+	bootRulesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4BootRule],
+	events interfaces.IBootEventBus,
+) error {
 	pluginsSlice := []*plugins.Plugin{
 		&pluginDNS.Plugin,
 		&pluginNetmask.Plugin,
-		NewRangeRepositoryPlugin(leasesRepo),
+		NewRangeRepositoryPlugin(leasesRepo, reservationsRepo, events),
 		&pluginRouter.Plugin,
 		&pluginServerid.Plugin,
+		NewBootOptionsPlugin(bootRulesRepo),
 	}
+	return registerCoreDHCPPlugins(pluginsSlice)
+}
+
+//registerCoreDHCPPlugins registers plugins in the coredhcp plugins registry,
+//plugins that are shared by dhcp v4 and dhcp v6 servers are registered only once
+func registerCoreDHCPPlugins(pluginsSlice []*plugins.Plugin) error {
 	for _, plugin := range pluginsSlice {
+		if _, registered := plugins.RegisteredPlugins[plugin.Name]; registered {
+			continue
+		}
 		if err := plugins.RegisterPlugin(plugin); err != nil {
 			return errors.Internal.Wrapf(err, "failed to register plugin: %v", plugin.Name)
 		}
@@ -36,6 +55,7 @@ func initializeCoreDHCPPlugins(leasesRepo interfaces.IGenericRepository[uuid.UUI
 }
 
 type coreDHCP4Server struct {
+	id     uuid.UUID
 	config *config.Config
 	server *server.Servers
 	state  domain.DHCPServerState
@@ -45,9 +65,12 @@ type coreDHCP4Server struct {
 func NewCoreDHCP4Server(
 	dhcp4config domain.DHCP4Config,
 	leasesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease],
+	reservationsRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Reservation],
+	bootRulesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4BootRule],
+	events interfaces.IBootEventBus,
 ) (interfaces.IDHCP4Server, error) {
 	if !pluginsInitialized {
-		err := initializeCoreDHCPPlugins(leasesRepo)
+		err := initializeCoreDHCPPlugins(leasesRepo, reservationsRepo, bootRulesRepo, events)
 		if err != nil {
 			return nil, err
 		}
@@ -67,6 +90,11 @@ func (s *coreDHCP4Server) ReloadConfiguration(dhcp4config domain.DHCP4Config) er
 	if len(startEndIPs) < 2 {
 		return errors.Internal.Newf("incorrect ip range: %s", dhcp4config.Range)
 	}
+	leaseTime := dhcp4config.LeaseTime
+	if leaseTime <= 0 {
+		leaseTime = defaultDHCP4LeaseTime
+	}
+	s.id = dhcp4config.ID
 	s.config = &config.Config{
 		Server6: nil,
 		Server4: &config.ServerConfig{
@@ -86,7 +114,7 @@ func (s *coreDHCP4Server) ReloadConfiguration(dhcp4config domain.DHCP4Config) er
 						dhcp4config.ID.String(),
 						startEndIPs[0],
 						startEndIPs[1],
-						"3600s",
+						fmt.Sprintf("%ds", leaseTime),
 					},
 				},
 				{
@@ -101,6 +129,15 @@ func (s *coreDHCP4Server) ReloadConfiguration(dhcp4config domain.DHCP4Config) er
 					Name: "netmask",
 					Args: []string{dhcp4config.Mask},
 				},
+				{
+					Name: "boot_options",
+					Args: []string{
+						dhcp4config.ID.String(),
+						dhcp4config.NextServer,
+						dhcp4config.BootFileName,
+						dhcp4config.NTP,
+					},
+				},
 			},
 		},
 	}
@@ -132,3 +169,25 @@ func (s *coreDHCP4Server) Stop() {
 func (s *coreDHCP4Server) GetState() domain.DHCPServerState {
 	return s.state
 }
+
+//ReleaseLease return leased ip address to the pool of free addresses
+func (s *coreDHCP4Server) ReleaseLease(ip string) error {
+	leaseIP := net.ParseIP(ip)
+	if leaseIP == nil || leaseIP.To4() == nil {
+		return errors.Internal.Newf("incorrect lease ip address: %s", ip)
+	}
+	err := releaseRangeLeaseIP(s.id, leaseIP)
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to release lease ip address")
+	}
+	return nil
+}
+
+//ReleaseExpiredLease remove lease if it's still expired and return its ip address to the pool of free addresses
+func (s *coreDHCP4Server) ReleaseExpiredLease(leaseID uuid.UUID) (bool, error) {
+	released, err := releaseExpiredRangeLease(s.id, leaseID)
+	if err != nil {
+		return false, errors.Internal.Wrap(err, "failed to release expired lease")
+	}
+	return released, nil
+}
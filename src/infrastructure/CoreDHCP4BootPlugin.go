@@ -0,0 +1,155 @@
+package infrastructure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"net"
+	"rol/app/interfaces"
+	"rol/domain"
+	"strings"
+
+	"github.com/coredhcp/coredhcp/handler"
+	"github.com/coredhcp/coredhcp/logger"
+	"github.com/coredhcp/coredhcp/plugins"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+)
+
+var bootLog = logger.GetLogger("plugins/boot_options")
+var bootRulesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4BootRule]
+
+//NewBootOptionsPlugin constructor for plugin that sets network boot options:
+//siaddr and option 66 (next server), option 67 (boot file name) and option 42 (NTP servers).
+//Next server and boot file name can be overridden by the boot rules, matched by
+//client architecture (option 93), vendor class (option 60) and user class (option 77)
+func NewBootOptionsPlugin(rulesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4BootRule]) *plugins.Plugin {
+	bootRulesRepo = rulesRepo
+	return &plugins.Plugin{
+		Name:   "boot_options",
+		Setup4: setupBootOptions,
+	}
+}
+
+//BootOptionsPluginState is the data held by an instance of the boot options plugin
+type BootOptionsPluginState struct {
+	serverID     uuid.UUID
+	nextServer   net.IP
+	bootFileName string
+	ntpServers   []net.IP
+}
+
+func bootRuleMatches(rule domain.DHCP4BootRule, req *dhcpv4.DHCPv4) bool {
+	if rule.Architecture != nil {
+		archMatched := false
+		for _, arch := range req.ClientArch() {
+			if int(arch) == *rule.Architecture {
+				archMatched = true
+				break
+			}
+		}
+		if !archMatched {
+			return false
+		}
+	}
+	if rule.VendorClass != "" && !strings.HasPrefix(req.ClassIdentifier(), rule.VendorClass) {
+		return false
+	}
+	if rule.UserClass != "" {
+		userClassMatched := false
+		for _, userClass := range req.UserClass() {
+			if userClass == rule.UserClass {
+				userClassMatched = true
+				break
+			}
+		}
+		if !userClassMatched {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *BootOptionsPluginState) getMatchedBootRule(req *dhcpv4.DHCPv4) (*domain.DHCP4BootRule, error) {
+	if bootRulesRepo == nil {
+		return nil, nil
+	}
+	ctx := context.Background()
+	queryBuilder := bootRulesRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DHCP4ConfigID", "==", p.serverID)
+	rulesCount, err := bootRulesRepo.Count(ctx, queryBuilder)
+	if err != nil || rulesCount == 0 {
+		return nil, err
+	}
+	rules, err := bootRulesRepo.GetList(ctx, "Priority", "asc", 1, rulesCount, queryBuilder)
+	if err != nil {
+		return nil, err
+	}
+	for _, rule := range rules {
+		if bootRuleMatches(rule, req) {
+			return &rule, nil
+		}
+	}
+	return nil, nil
+}
+
+//Handler4 handles DHCPv4 packets for the boot options plugin
+func (p *BootOptionsPluginState) Handler4(req, resp *dhcpv4.DHCPv4) (*dhcpv4.DHCPv4, bool) {
+	nextServer := p.nextServer
+	bootFileName := p.bootFileName
+	rule, err := p.getMatchedBootRule(req)
+	if err != nil {
+		bootLog.Errorf("failed to get boot rules from repository: %v", err)
+	} else if rule != nil {
+		if ruleNextServer := net.ParseIP(rule.NextServer).To4(); ruleNextServer != nil {
+			nextServer = ruleNextServer
+		}
+		bootFileName = rule.BootFileName
+		bootLog.Debugf("boot rule %s matched for MAC %s", rule.ID.String(), req.ClientHWAddr.String())
+	}
+	if nextServer != nil {
+		resp.ServerIPAddr = nextServer
+		resp.Options.Update(dhcpv4.OptTFTPServerName(nextServer.String()))
+	}
+	//per-host boot file name from the reservation has priority over the rules and the server one
+	if bootFileName != "" && !resp.Options.Has(dhcpv4.OptionBootfileName) {
+		resp.BootFileName = bootFileName
+		resp.Options.Update(dhcpv4.OptBootFileName(bootFileName))
+	}
+	if len(p.ntpServers) > 0 {
+		resp.Options.Update(dhcpv4.OptNTPServers(p.ntpServers...))
+	}
+	bootLog.Debugf("added boot options for MAC %s", req.ClientHWAddr.String())
+	return resp, false
+}
+
+func setupBootOptions(args ...string) (handler.Handler4, error) {
+	p := &BootOptionsPluginState{}
+	if len(args) < 4 {
+		return nil, fmt.Errorf("invalid number of arguments, want: 4 (server id, next server, boot file name, ntp server), got: %d", len(args))
+	}
+	serverID, err := uuid.Parse(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid server ID: %v", args[0])
+	}
+	p.serverID = serverID
+	if args[1] != "" {
+		p.nextServer = net.ParseIP(args[1]).To4()
+		if p.nextServer == nil {
+			return nil, fmt.Errorf("invalid next server IPv4 address: %v", args[1])
+		}
+	}
+	if len(args[2]) > 128 {
+		return nil, errors.New("boot file name cannot be longer than 128 characters")
+	}
+	p.bootFileName = args[2]
+	if args[3] != "" {
+		ntpServer := net.ParseIP(args[3]).To4()
+		if ntpServer == nil {
+			return nil, fmt.Errorf("invalid NTP server IPv4 address: %v", args[3])
+		}
+		p.ntpServers = append(p.ntpServers, ntpServer)
+	}
+	bootLog.Printf("loaded boot options plugin for DHCPv4")
+	return p.Handler4, nil
+}
@@ -9,6 +9,7 @@ import (
 	"net"
 	"rol/app/interfaces"
 	"rol/domain"
+	"strings"
 	"sync"
 	"time"
 
@@ -22,10 +23,24 @@ import (
 
 var log = logger.GetLogger("plugins/range_repo")
 var leasesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease]
+var reservationsRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Reservation]
 
-//NewRangeRepositoryPlugin constructor for range plugin that integrated with leases repository
-func NewRangeRepositoryPlugin(repo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease]) *plugins.Plugin {
-	leasesRepo = repo
+//bootEvents bus for the dhcp requests events, can be nil
+var bootEvents interfaces.IBootEventBus
+
+//rangeStates plugin states of the running servers, key is a dhcp v4 server config id
+var rangeStates = map[uuid.UUID]*PluginState{}
+var rangeStatesMutex sync.Mutex
+
+//NewRangeRepositoryPlugin constructor for range plugin that integrated with leases and reservations repositories
+func NewRangeRepositoryPlugin(
+	leases interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease],
+	reservations interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Reservation],
+	events interfaces.IBootEventBus,
+) *plugins.Plugin {
+	leasesRepo = leases
+	reservationsRepo = reservations
+	bootEvents = events
 	return &plugins.Plugin{
 		Name:   "range_repo",
 		Setup4: setupRange,
@@ -70,6 +85,44 @@ func (p *PluginState) getLeaseFromRepo(mac string) (*Record, error) {
 	return nil, nil
 }
 
+func (p *PluginState) getReservationFromRepo(mac string) (*domain.DHCP4Reservation, error) {
+	if reservationsRepo == nil {
+		return nil, nil
+	}
+	ctx := context.Background()
+	queryBuilder := reservationsRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DHCP4ConfigID", "==", p.serverID)
+	queryBuilder.Where("MAC", "==", mac)
+	reservations, err := reservationsRepo.GetList(ctx, "", "", 1, 1, queryBuilder)
+	if err != nil {
+		return nil, err
+	}
+	if len(reservations) > 0 {
+		return &reservations[0], nil
+	}
+	return nil, nil
+}
+
+//handleReservation fills response with the reserved ip address, host name and per-host boot file name
+func (p *PluginState) handleReservation(reservation *domain.DHCP4Reservation, resp *dhcpv4.DHCPv4) (*dhcpv4.DHCPv4, bool) {
+	ip := net.ParseIP(reservation.IP).To4()
+	if ip == nil {
+		log.Errorf("invalid reserved IP address %s for MAC %s", reservation.IP, reservation.MAC)
+		return nil, true
+	}
+	resp.YourIPAddr = ip
+	resp.Options.Update(dhcpv4.OptIPAddressLeaseTime(p.LeaseTime.Round(time.Second)))
+	if reservation.Hostname != "" {
+		resp.Options.Update(dhcpv4.OptHostName(reservation.Hostname))
+	}
+	if reservation.BootFileName != "" {
+		resp.BootFileName = reservation.BootFileName
+		resp.Options.Update(dhcpv4.OptBootFileName(reservation.BootFileName))
+	}
+	log.Printf("found reserved IP address %s for MAC %s", ip, reservation.MAC)
+	return resp, false
+}
+
 func (p *PluginState) createLeaseInRepo(addr net.HardwareAddr, rec *Record) error {
 	newLease := domain.DHCP4Lease{
 		IP:            rec.IP.String(),
@@ -100,6 +153,16 @@ func (p *PluginState) updateLeaseExpiresTimeInRepo(rec *Record) error {
 func (p *PluginState) Handler4(req, resp *dhcpv4.DHCPv4) (*dhcpv4.DHCPv4, bool) {
 	p.Lock()
 	defer p.Unlock()
+	publishDHCPRequestEvent(req)
+
+	reservation, err := p.getReservationFromRepo(req.ClientHWAddr.String())
+	if err != nil {
+		log.Errorf("failed to get reservation for mac %v from repository: %v", req.ClientHWAddr.String(), err)
+		return nil, true
+	}
+	if reservation != nil {
+		return p.handleReservation(reservation, resp)
+	}
 
 	record, err := p.getLeaseFromRepo(req.ClientHWAddr.String())
 	if err != nil {
@@ -139,6 +202,98 @@ func (p *PluginState) Handler4(req, resp *dhcpv4.DHCPv4) (*dhcpv4.DHCPv4, bool)
 	return resp, false
 }
 
+//publishDHCPRequestEvent publishes boot event for the discover and request messages
+func publishDHCPRequestEvent(req *dhcpv4.DHCPv4) {
+	if bootEvents == nil {
+		return
+	}
+	messageType := req.MessageType()
+	if messageType != dhcpv4.MessageTypeDiscover && messageType != dhcpv4.MessageTypeRequest {
+		return
+	}
+	bootEvents.Publish(domain.BootEvent{
+		Type:      domain.BootEventDHCPRequest,
+		ClientMAC: strings.ToLower(req.ClientHWAddr.String()),
+		Time:      time.Now(),
+	})
+}
+
+//releaseRangeLeaseIP returns leased ip address back to the allocator of the server range
+//
+//Params:
+//	serverID - dhcp v4 server config id
+//	ip - leased ip address
+//Return:
+//	error - if an error occurred, otherwise nil
+func releaseRangeLeaseIP(serverID uuid.UUID, ip net.IP) error {
+	rangeStatesMutex.Lock()
+	p, ok := rangeStates[serverID]
+	rangeStatesMutex.Unlock()
+	if !ok {
+		return nil
+	}
+	p.Lock()
+	defer p.Unlock()
+	err := p.allocator.Free(net.IPNet{IP: ip.To4()})
+	if err != nil {
+		var doubleFreeErr *allocators.ErrDoubleFree
+		if errors.As(err, &doubleFreeErr) {
+			return nil
+		}
+		return err
+	}
+	return nil
+}
+
+//releaseExpiredRangeLease removes expired lease from the repository and returns its ip address back
+//to the allocator of the server range. Lease is re-read under the plugin lock, so lease that was renewed
+//after it was found as expired is kept
+//
+//Params:
+//	serverID - dhcp v4 server config id
+//	leaseID - dhcp v4 lease id
+//Return:
+//	bool - true if lease was removed
+//	error - if an error occurred, otherwise nil
+func releaseExpiredRangeLease(serverID, leaseID uuid.UUID) (bool, error) {
+	if leasesRepo == nil {
+		return false, errors.New("repository is not set")
+	}
+	rangeStatesMutex.Lock()
+	p, ok := rangeStates[serverID]
+	rangeStatesMutex.Unlock()
+	if ok {
+		p.Lock()
+		defer p.Unlock()
+	}
+	ctx := context.Background()
+	queryBuilder := leasesRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("ID", "==", leaseID).Where("Expires", "<", time.Now())
+	leases, err := leasesRepo.GetList(ctx, "", "", 1, 1, queryBuilder)
+	if err != nil {
+		return false, err
+	}
+	if len(leases) == 0 {
+		return false, nil
+	}
+	err = leasesRepo.Delete(ctx, leaseID)
+	if err != nil {
+		return false, err
+	}
+	if !ok {
+		return true, nil
+	}
+	err = p.allocator.Free(net.IPNet{IP: net.ParseIP(leases[0].IP).To4()})
+	if err != nil {
+		var doubleFreeErr *allocators.ErrDoubleFree
+		if errors.As(err, &doubleFreeErr) {
+			return true, nil
+		}
+		return true, err
+	}
+	return true, nil
+}
+
 func loadRecordsFromRepo(serverID uuid.UUID) (map[string]*Record, error) {
 	if leasesRepo == nil {
 		return nil, errors.New("repository is not set")
@@ -166,10 +321,8 @@ func loadRecordsFromRepo(serverID uuid.UUID) (map[string]*Record, error) {
 }
 
 func setupRange(args ...string) (handler.Handler4, error) {
-	var (
-		err error
-		p   PluginState
-	)
+	var err error
+	p := &PluginState{}
 
 	if len(args) < 4 {
 		return nil, fmt.Errorf("invalid number of arguments, want: 4 (file name, start IP, end IP, lease time), got: %d", len(args))
@@ -221,5 +374,8 @@ func setupRange(args ...string) (handler.Handler4, error) {
 			return nil, fmt.Errorf("allocator did not re-allocate requested leased ip %v: %v", v.IP.String(), ip.String())
 		}
 	}
+	rangeStatesMutex.Lock()
+	rangeStates[p.serverID] = p
+	rangeStatesMutex.Unlock()
 	return p.Handler4, nil
 }
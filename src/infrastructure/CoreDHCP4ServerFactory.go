@@ -9,15 +9,24 @@ import (
 
 //CoreDHCP4ServerFactory fabric for creating dhcp v4 servers
 type CoreDHCP4ServerFactory struct {
-	leasesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease]
+	leasesRepo       interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease]
+	reservationsRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Reservation]
+	bootRulesRepo    interfaces.IGenericRepository[uuid.UUID, domain.DHCP4BootRule]
+	events           interfaces.IBootEventBus
 }
 
 //NewCoreDHCP4ServerFactory constructor for CoreDHCP v4 servers manager
 func NewCoreDHCP4ServerFactory(
 	leasesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease],
+	reservationsRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Reservation],
+	bootRulesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4BootRule],
+	events interfaces.IBootEventBus,
 ) interfaces.IDHCP4ServerFactory {
 	return &CoreDHCP4ServerFactory{
-		leasesRepo: leasesRepo,
+		leasesRepo:       leasesRepo,
+		reservationsRepo: reservationsRepo,
+		bootRulesRepo:    bootRulesRepo,
+		events:           events,
 	}
 }
 
@@ -28,7 +37,7 @@ func NewCoreDHCP4ServerFactory(
 //Return:
 //	error - if an error occurred, otherwise nil
 func (m *CoreDHCP4ServerFactory) Create(config domain.DHCP4Config) (interfaces.IDHCP4Server, error) {
-	server, err := NewCoreDHCP4Server(config, m.leasesRepo)
+	server, err := NewCoreDHCP4Server(config, m.leasesRepo, m.reservationsRepo, m.bootRulesRepo, m.events)
 	if err != nil {
 		return nil, errors.Internal.Wrap(err, "failed to create dhcp v4 server")
 	}
@@ -0,0 +1,167 @@
+package infrastructure
+
+import (
+	"fmt"
+	"github.com/coredhcp/coredhcp/plugins"
+	"github.com/google/uuid"
+	pluginDNS "github.com/insei/coredhcp/plugins/dns"
+	pluginServerid "github.com/insei/coredhcp/plugins/serverid"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"net"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/domain"
+	"strings"
+
+	"github.com/coredhcp/coredhcp/config"
+	"github.com/coredhcp/coredhcp/server"
+)
+
+var plugins6Initialized = false
+
+//defaultDHCP6LeaseTime lease time in seconds, used if lease time is not set in config
+const defaultDHCP6LeaseTime = 3600
+
+func initializeCoreDHCP6Plugins(leasesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP6Lease]) error {
+	pluginsSlice := []*plugins.Plugin{
+		&pluginServerid.Plugin,
+		&pluginDNS.Plugin,
+		NewRange6RepositoryPlugin(leasesRepo),
+	}
+	return registerCoreDHCPPlugins(pluginsSlice)
+}
+
+type coreDHCP6Server struct {
+	dhcp6config domain.DHCP6Config
+	server      *server.Servers
+	state       domain.DHCPServerState
+}
+
+//NewCoreDHCP6Server constructor for core DHCP v6 server
+func NewCoreDHCP6Server(
+	dhcp6config domain.DHCP6Config,
+	leasesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP6Lease],
+) (interfaces.IDHCP6Server, error) {
+	if !plugins6Initialized {
+		err := initializeCoreDHCP6Plugins(leasesRepo)
+		if err != nil {
+			return nil, err
+		}
+		plugins6Initialized = true
+	}
+	serv := &coreDHCP6Server{state: domain.DHCPStateStopped}
+	err := serv.ReloadConfiguration(dhcp6config)
+	if err != nil {
+		return nil, err
+	}
+	return serv, nil
+}
+
+//ReloadConfiguration DHCP v6 server from config
+func (s *coreDHCP6Server) ReloadConfiguration(dhcp6config domain.DHCP6Config) error {
+	startEndIPs := strings.Split(dhcp6config.Range, "-")
+	if len(startEndIPs) < 2 {
+		return errors.Internal.Newf("incorrect ip range: %s", dhcp6config.Range)
+	}
+	s.dhcp6config = dhcp6config
+	return nil
+}
+
+//buildConfig creates coredhcp config, server id is a DUID-LL based on the interface mac address,
+//so config can be built only when interface exists
+func (s *coreDHCP6Server) buildConfig() (*config.Config, error) {
+	iface, err := net.InterfaceByName(s.dhcp6config.Interface)
+	if err != nil {
+		return nil, errors.Internal.Wrapf(err, "failed to get interface %s", s.dhcp6config.Interface)
+	}
+	if len(iface.HardwareAddr) == 0 {
+		return nil, errors.Internal.Newf("interface %s has no mac address for server DUID", iface.Name)
+	}
+	startEndIPs := strings.Split(s.dhcp6config.Range, "-")
+	leaseTime := s.dhcp6config.LeaseTime
+	if leaseTime <= 0 {
+		leaseTime = defaultDHCP6LeaseTime
+	}
+	pluginsConfig := []config.PluginConfig{
+		{
+			Name: "server_id",
+			Args: []string{"LL", iface.HardwareAddr.String()},
+		},
+	}
+	if s.dhcp6config.DNS != "" {
+		pluginsConfig = append(pluginsConfig, config.PluginConfig{
+			Name: "dns",
+			Args: strings.Split(s.dhcp6config.DNS, ";"),
+		})
+	}
+	pluginsConfig = append(pluginsConfig, config.PluginConfig{
+		Name: "range6_repo",
+		Args: []string{
+			s.dhcp6config.ID.String(),
+			startEndIPs[0],
+			startEndIPs[1],
+			fmt.Sprintf("%ds", leaseTime),
+		},
+	})
+	return &config.Config{
+		Server6: &config.ServerConfig{
+			Addresses: []net.UDPAddr{{
+				IP:   dhcpv6.AllDHCPRelayAgentsAndServers,
+				Port: s.dhcp6config.Port,
+				Zone: s.dhcp6config.Interface,
+			}},
+			Plugins: pluginsConfig,
+		},
+		Server4: nil,
+	}, nil
+}
+
+//Start DHCP v6 server
+func (s *coreDHCP6Server) Start() error {
+	serverConfig, err := s.buildConfig()
+	if err != nil {
+		s.state = domain.DHCPStateError
+		return errors.Internal.Wrap(err, "failed to build dhcp v6 server config")
+	}
+	coredhcp, err := server.Start(serverConfig)
+	if err != nil {
+		s.state = domain.DHCPStateError
+		return errors.Internal.Wrap(err, "failed to start dhcp v6 server")
+	}
+	s.state = domain.DHCPStateLaunched
+	s.server = coredhcp
+	return nil
+}
+
+//Stop DHCP v6 server
+func (s *coreDHCP6Server) Stop() {
+	if s.server != nil {
+		s.server.Close()
+	}
+	s.state = domain.DHCPStateStopped
+	s.server = nil
+}
+
+//GetState of DHCP v6 server
+func (s *coreDHCP6Server) GetState() domain.DHCPServerState {
+	return s.state
+}
+
+//ReleaseLease return leased ip address to the pool of free addresses
+func (s *coreDHCP6Server) ReleaseLease(ip string) error {
+	leaseIP := net.ParseIP(ip)
+	if leaseIP == nil || leaseIP.To4() != nil {
+		return errors.Internal.Newf("incorrect lease ip address: %s", ip)
+	}
+	releaseRange6LeaseIP(s.dhcp6config.ID, leaseIP)
+	return nil
+}
+
+//ReleaseExpiredLease remove lease if it's still expired and return its ip address to the pool of free addresses
+func (s *coreDHCP6Server) ReleaseExpiredLease(leaseID uuid.UUID) (bool, error) {
+	released, err := releaseExpiredRange6Lease(s.dhcp6config.ID, leaseID)
+	if err != nil {
+		return false, errors.Internal.Wrap(err, "failed to release expired lease")
+	}
+	return released, nil
+}
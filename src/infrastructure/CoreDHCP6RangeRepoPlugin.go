@@ -0,0 +1,384 @@
+package infrastructure
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"net"
+	"rol/app/interfaces"
+	"rol/domain"
+	"sync"
+	"time"
+
+	"github.com/coredhcp/coredhcp/handler"
+	"github.com/coredhcp/coredhcp/logger"
+	"github.com/coredhcp/coredhcp/plugins"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+)
+
+var log6 = logger.GetLogger("plugins/range6_repo")
+var leases6Repo interfaces.IGenericRepository[uuid.UUID, domain.DHCP6Lease]
+
+//range6States plugin states of the running servers, key is a dhcp v6 server config id
+var range6States = map[uuid.UUID]*Range6PluginState{}
+var range6StatesMutex sync.Mutex
+
+//maxDHCP6RangeSize max number of addresses in the dhcp v6 range, range can differ only in the last two bytes
+const maxDHCP6RangeSize = 1 << 16
+
+//NewRange6RepositoryPlugin constructor for dhcp v6 range plugin that integrated with leases repository
+func NewRange6RepositoryPlugin(repo interfaces.IGenericRepository[uuid.UUID, domain.DHCP6Lease]) *plugins.Plugin {
+	leases6Repo = repo
+	return &plugins.Plugin{
+		Name:   "range6_repo",
+		Setup6: setupRange6,
+	}
+}
+
+//ipv6RangeAllocator allocator of the single ipv6 addresses from the small range
+type ipv6RangeAllocator struct {
+	start net.IP
+	size  int
+	used  map[int]bool
+}
+
+func newIPv6RangeAllocator(start, end net.IP) (*ipv6RangeAllocator, error) {
+	for i := 0; i < net.IPv6len-2; i++ {
+		if start[i] != end[i] {
+			return nil, fmt.Errorf("range is too big, max size is %d addresses", maxDHCP6RangeSize)
+		}
+	}
+	size := int(end[14])<<8 | int(end[15]) - (int(start[14])<<8 | int(start[15])) + 1
+	if size < 1 {
+		return nil, errors.New("start of IP range can't be greater than the end of an IP range")
+	}
+	return &ipv6RangeAllocator{
+		start: start,
+		size:  size,
+		used:  map[int]bool{},
+	}, nil
+}
+
+func (a *ipv6RangeAllocator) toOffset(ip net.IP) (int, bool) {
+	ip = ip.To16()
+	if ip == nil {
+		return 0, false
+	}
+	for i := 0; i < net.IPv6len-2; i++ {
+		if ip[i] != a.start[i] {
+			return 0, false
+		}
+	}
+	offset := int(ip[14])<<8 | int(ip[15]) - (int(a.start[14])<<8 | int(a.start[15]))
+	return offset, offset >= 0 && offset < a.size
+}
+
+func (a *ipv6RangeAllocator) toIP(offset int) net.IP {
+	ip := make(net.IP, net.IPv6len)
+	copy(ip, a.start)
+	value := int(a.start[14])<<8 | int(a.start[15]) + offset
+	ip[14] = byte(value >> 8)
+	ip[15] = byte(value)
+	return ip
+}
+
+//Allocate reserves the hint address if it is free, otherwise first free address of the range
+func (a *ipv6RangeAllocator) Allocate(hint net.IP) (net.IP, error) {
+	if offset, ok := a.toOffset(hint); ok && !a.used[offset] {
+		a.used[offset] = true
+		return a.toIP(offset), nil
+	}
+	for offset := 0; offset < a.size; offset++ {
+		if !a.used[offset] {
+			a.used[offset] = true
+			return a.toIP(offset), nil
+		}
+	}
+	return nil, errors.New("no more free addresses in the range")
+}
+
+//Free returns address back to the range
+func (a *ipv6RangeAllocator) Free(ip net.IP) {
+	if offset, ok := a.toOffset(ip); ok {
+		delete(a.used, offset)
+	}
+}
+
+//Range6PluginState is the data held by an instance of the dhcp v6 range plugin
+type Range6PluginState struct {
+	sync.Mutex
+	LeaseTime time.Duration
+	allocator *ipv6RangeAllocator
+	serverID  uuid.UUID
+}
+
+func (p *Range6PluginState) getLeaseFromRepo(duid string) (*domain.DHCP6Lease, error) {
+	ctx := context.Background()
+	queryBuilder := leases6Repo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DHCP6ConfigID", "==", p.serverID)
+	queryBuilder.Where("DUID", "==", duid)
+	leases, err := leases6Repo.GetList(ctx, "", "", 1, 1, queryBuilder)
+	if err != nil {
+		return nil, err
+	}
+	if len(leases) > 0 {
+		return &leases[0], nil
+	}
+	return nil, nil
+}
+
+func (p *Range6PluginState) getLease(req dhcpv6.DHCPv6, duid string) (*domain.DHCP6Lease, error) {
+	lease, err := p.getLeaseFromRepo(duid)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	if lease != nil {
+		// Ensure we extend the existing lease at least past when the one we're giving expires
+		if lease.Expires.Before(time.Now().Add(p.LeaseTime)) {
+			lease.Expires = time.Now().Add(p.LeaseTime).Round(time.Second)
+			updatedLease, err := leases6Repo.Update(ctx, *lease)
+			if err != nil {
+				log6.Errorf("Could not persist lease for DUID %s: %v", duid, err)
+				return lease, nil
+			}
+			return &updatedLease, nil
+		}
+		return lease, nil
+	}
+	log6.Printf("DUID %s is new, leasing new IPv6 address", duid)
+	ip, err := p.allocator.Allocate(nil)
+	if err != nil {
+		return nil, err
+	}
+	newLease := domain.DHCP6Lease{
+		IP:            ip.String(),
+		DUID:          duid,
+		Expires:       time.Now().Add(p.LeaseTime).Round(time.Second),
+		DHCP6ConfigID: p.serverID,
+	}
+	if mac, err := dhcpv6.ExtractMAC(req); err == nil {
+		newLease.MAC = mac.String()
+	}
+	createdLease, err := leases6Repo.Insert(ctx, newLease)
+	if err != nil {
+		p.allocator.Free(ip)
+		return nil, err
+	}
+	return &createdLease, nil
+}
+
+//releaseLease removes released lease and returns its address to the range
+func (p *Range6PluginState) releaseLease(lease domain.DHCP6Lease) error {
+	err := leases6Repo.Delete(context.Background(), lease.ID)
+	if err != nil {
+		return err
+	}
+	p.allocator.Free(net.ParseIP(lease.IP))
+	return nil
+}
+
+//handleRelease handles Release message, client lease is released if its address is in the message IA_NA.
+//IA_NA without the client lease address is answered with NoBinding status
+func (p *Range6PluginState) handleRelease(msg *dhcpv6.Message, resp dhcpv6.DHCPv6, duid string) (dhcpv6.DHCPv6, bool) {
+	lease, err := p.getLeaseFromRepo(duid)
+	if err != nil {
+		log6.Errorf("Could not get lease for DUID %s: %v", duid, err)
+		return nil, true
+	}
+	for _, ia := range msg.Options.IANA() {
+		bound := false
+		for _, address := range ia.Options.Addresses() {
+			if lease != nil && address.IPv6Addr.Equal(net.ParseIP(lease.IP)) {
+				bound = true
+			}
+		}
+		if !bound {
+			resp.AddOption(&dhcpv6.OptIANA{
+				IaId: ia.IaId,
+				Options: dhcpv6.IdentityOptions{Options: dhcpv6.Options{
+					&dhcpv6.OptStatusCode{StatusCode: iana.StatusNoBinding},
+				}},
+			})
+			continue
+		}
+		err = p.releaseLease(*lease)
+		if err != nil {
+			log6.Errorf("Could not release IPv6 address %s for DUID %s: %v", lease.IP, duid, err)
+			return nil, true
+		}
+		log6.Printf("IPv6 address %s is released by DUID %s", lease.IP, duid)
+		lease = nil
+	}
+	resp.AddOption(&dhcpv6.OptStatusCode{StatusCode: iana.StatusSuccess})
+	return resp, false
+}
+
+//Handler6 handles DHCPv6 packets for the range6_repo plugin, it assigns addresses to the IA_NA requests
+//and releases addresses of the Release messages
+func (p *Range6PluginState) Handler6(req, resp dhcpv6.DHCPv6) (dhcpv6.DHCPv6, bool) {
+	msg, err := req.GetInnerMessage()
+	if err != nil {
+		log6.Errorf("Could not decapsulate relayed message, aborting: %v", err)
+		return nil, true
+	}
+	switch msg.MessageType {
+	case dhcpv6.MessageTypeSolicit, dhcpv6.MessageTypeRequest, dhcpv6.MessageTypeRenew, dhcpv6.MessageTypeRebind,
+		dhcpv6.MessageTypeRelease:
+	default:
+		return resp, false
+	}
+	clientID := msg.Options.ClientID()
+	if clientID == nil {
+		log6.Error("Invalid packet received, no client ID")
+		return nil, true
+	}
+	duid := hex.EncodeToString(clientID.ToBytes())
+	if msg.MessageType == dhcpv6.MessageTypeRelease {
+		p.Lock()
+		defer p.Unlock()
+		return p.handleRelease(msg, resp, duid)
+	}
+	ia := msg.Options.OneIANA()
+	if ia == nil {
+		return resp, false
+	}
+
+	p.Lock()
+	defer p.Unlock()
+	lease, err := p.getLease(req, duid)
+	if err != nil {
+		log6.Errorf("Could not get IPv6 address for DUID %s: %v", duid, err)
+		return nil, true
+	}
+	resp.AddOption(&dhcpv6.OptIANA{
+		IaId: ia.IaId,
+		T1:   p.LeaseTime / 2,
+		T2:   p.LeaseTime * 4 / 5,
+		Options: dhcpv6.IdentityOptions{Options: dhcpv6.Options{
+			&dhcpv6.OptIAAddress{
+				IPv6Addr:          net.ParseIP(lease.IP),
+				PreferredLifetime: p.LeaseTime,
+				ValidLifetime:     p.LeaseTime,
+			},
+		}},
+	})
+	log6.Printf("found IPv6 address %s for DUID %s", lease.IP, duid)
+	return resp, false
+}
+
+//releaseRange6LeaseIP returns leased ip address back to the allocator of the dhcp v6 server range
+//
+//Params:
+//	serverID - dhcp v6 server config id
+//	ip - leased ip address
+func releaseRange6LeaseIP(serverID uuid.UUID, ip net.IP) {
+	range6StatesMutex.Lock()
+	p, ok := range6States[serverID]
+	range6StatesMutex.Unlock()
+	if !ok {
+		return
+	}
+	p.Lock()
+	defer p.Unlock()
+	p.allocator.Free(ip)
+}
+
+//releaseExpiredRange6Lease removes expired lease from the repository and returns its ip address back
+//to the allocator of the server range. Lease is re-read under the plugin lock, so lease that was renewed
+//after it was found as expired is kept
+//
+//Params:
+//	serverID - dhcp v6 server config id
+//	leaseID - dhcp v6 lease id
+//Return:
+//	bool - true if lease was removed
+//	error - if an error occurred, otherwise nil
+func releaseExpiredRange6Lease(serverID, leaseID uuid.UUID) (bool, error) {
+	if leases6Repo == nil {
+		return false, errors.New("repository is not set")
+	}
+	range6StatesMutex.Lock()
+	p, ok := range6States[serverID]
+	range6StatesMutex.Unlock()
+	if ok {
+		p.Lock()
+		defer p.Unlock()
+	}
+	ctx := context.Background()
+	queryBuilder := leases6Repo.NewQueryBuilder(ctx)
+	queryBuilder.Where("ID", "==", leaseID).Where("Expires", "<", time.Now())
+	leases, err := leases6Repo.GetList(ctx, "", "", 1, 1, queryBuilder)
+	if err != nil {
+		return false, err
+	}
+	if len(leases) == 0 {
+		return false, nil
+	}
+	err = leases6Repo.Delete(ctx, leaseID)
+	if err != nil {
+		return false, err
+	}
+	if ok {
+		p.allocator.Free(net.ParseIP(leases[0].IP))
+	}
+	return true, nil
+}
+
+func setupRange6(args ...string) (handler.Handler6, error) {
+	var err error
+	p := &Range6PluginState{}
+
+	if len(args) < 4 {
+		return nil, fmt.Errorf("invalid number of arguments, want: 4 (server id, start IP, end IP, lease time), got: %d", len(args))
+	}
+	if leases6Repo == nil {
+		return nil, errors.New("repository is not set")
+	}
+	p.serverID, err = uuid.Parse(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid server ID: %v", args[0])
+	}
+	ipRangeStart := net.ParseIP(args[1])
+	if ipRangeStart == nil || ipRangeStart.To4() != nil {
+		return nil, fmt.Errorf("invalid IPv6 address: %v", args[1])
+	}
+	ipRangeEnd := net.ParseIP(args[2])
+	if ipRangeEnd == nil || ipRangeEnd.To4() != nil {
+		return nil, fmt.Errorf("invalid IPv6 address: %v", args[2])
+	}
+	p.allocator, err = newIPv6RangeAllocator(ipRangeStart, ipRangeEnd)
+	if err != nil {
+		return nil, fmt.Errorf("could not create an allocator: %w", err)
+	}
+	p.LeaseTime, err = time.ParseDuration(args[3])
+	if err != nil {
+		return nil, fmt.Errorf("invalid lease duration: %v", args[3])
+	}
+
+	ctx := context.Background()
+	queryBuilder := leases6Repo.NewQueryBuilder(ctx)
+	queryBuilder.Where("DHCP6ConfigID", "==", p.serverID)
+	leasesCount, err := leases6Repo.Count(ctx, queryBuilder)
+	if err != nil {
+		return nil, fmt.Errorf("could not count leases in repository: %v", err)
+	}
+	leases, err := leases6Repo.GetList(ctx, "", "", 1, leasesCount, queryBuilder)
+	if err != nil {
+		return nil, fmt.Errorf("could not load leases from repository: %v", err)
+	}
+	log6.Printf("Loaded %d DHCPv6 leases from repository", len(leases))
+	for _, lease := range leases {
+		ip, err := p.allocator.Allocate(net.ParseIP(lease.IP))
+		if err != nil || !ip.Equal(net.ParseIP(lease.IP)) {
+			return nil, fmt.Errorf("failed to re-allocate leased ip %v", lease.IP)
+		}
+	}
+	range6StatesMutex.Lock()
+	range6States[p.serverID] = p
+	range6StatesMutex.Unlock()
+	return p.Handler6, nil
+}
@@ -0,0 +1,36 @@
+package infrastructure
+
+import (
+	"github.com/google/uuid"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/domain"
+)
+
+//CoreDHCP6ServerFactory fabric for creating dhcp v6 servers
+type CoreDHCP6ServerFactory struct {
+	leasesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP6Lease]
+}
+
+//NewCoreDHCP6ServerFactory constructor for CoreDHCP v6 servers manager
+func NewCoreDHCP6ServerFactory(
+	leasesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP6Lease],
+) interfaces.IDHCP6ServerFactory {
+	return &CoreDHCP6ServerFactory{
+		leasesRepo: leasesRepo,
+	}
+}
+
+//Create new coreDHCP v6 server with config
+//
+//Params:
+//	config - dhcp v6 config
+//Return:
+//	error - if an error occurred, otherwise nil
+func (m *CoreDHCP6ServerFactory) Create(config domain.DHCP6Config) (interfaces.IDHCP6Server, error) {
+	server, err := NewCoreDHCP6Server(config, m.leasesRepo)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to create dhcp v6 server")
+	}
+	return server, nil
+}
@@ -0,0 +1,190 @@
+package infrastructure
+
+import (
+	"github.com/google/uuid"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/domain"
+	"sync"
+)
+
+//EthernetSwitchDriver ethernet switch driver description
+type EthernetSwitchDriver struct {
+	//Models - switch models that are managed by the driver
+	Models []domain.EthernetSwitchModel
+	//NewManager - switch manager constructor, nil if the driver stores switch configuration in database only
+	NewManager func(ethernetSwitch domain.EthernetSwitch) (interfaces.IEthernetSwitchManager, error)
+	//ReleaseSwitch - releases resources the driver holds for the switch, nil if the driver holds nothing
+	ReleaseSwitch func(switchID uuid.UUID) error
+	//RequiresSNMP - switch manager needs SNMP credentials to connect to the switch
+	RequiresSNMP bool
+}
+
+//EthernetSwitchDriverRegistry registry of the ethernet switch drivers by model code
+type EthernetSwitchDriverRegistry struct {
+	//codes model codes in registration order
+	codes   []string
+	models  map[string]domain.EthernetSwitchModel
+	drivers map[string]EthernetSwitchDriver
+	//releasers release hooks of the registered drivers
+	releasers []func(switchID uuid.UUID) error
+	mutex     sync.RWMutex
+}
+
+//ubiquityUnifiEthernetSwitchDriver Ubiquity UniFi switch has no manager, its configuration is stored in database only
+var ubiquityUnifiEthernetSwitchDriver = EthernetSwitchDriver{
+	Models: []domain.EthernetSwitchModel{{
+		Model:        "UniFi Switch US-24-250W",
+		Manufacturer: "Ubiquity",
+		Code:         "unifi_switch_us-24-250w",
+	}},
+}
+
+//NewEthernetSwitchDriverRegistry constructor for EthernetSwitchDriverRegistry with all built-in drivers,
+//CLI managed switches share one sessions pool
+//
+//Params:
+//	config - application config, simulated switches speak telnet if it is enabled in the config
+//Return:
+//	interfaces.IEthernetSwitchDriverRegistry - switch drivers registry
+//	error - if an error occurs, otherwise nil
+func NewEthernetSwitchDriverRegistry(config *domain.AppConfig) (interfaces.IEthernetSwitchDriverRegistry, error) {
+	registry := &EthernetSwitchDriverRegistry{
+		models:  map[string]domain.EthernetSwitchModel{},
+		drivers: map[string]EthernetSwitchDriver{},
+	}
+	sessions := NewSwitchCLISessionPool(switchCLISessionIdleTimeout, switchCLISessionKeepAliveInterval)
+	builtInDrivers := []EthernetSwitchDriver{
+		ubiquityUnifiEthernetSwitchDriver,
+		newTPLinkEthernetSwitchDriver(sessions),
+		snmpEthernetSwitchDriver,
+		newSimulatedEthernetSwitchDriver(sessions, config.EthernetSwitchSimulator.TelnetEnabled),
+	}
+	for _, driver := range builtInDrivers {
+		err := registry.Register(driver)
+		if err != nil {
+			return nil, errors.Internal.Wrap(err, "failed to register built-in switch driver")
+		}
+	}
+	return registry, nil
+}
+
+//Register registers driver for all its models
+//
+//Params:
+//	driver - switch driver
+//Return:
+//	error - if model code is empty or already registered
+func (r *EthernetSwitchDriverRegistry) Register(driver EthernetSwitchDriver) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	for _, model := range driver.Models {
+		if model.Code == "" {
+			return errors.Internal.Newf("switch model %s has empty code", model.Model)
+		}
+		if _, exist := r.models[model.Code]; exist {
+			return errors.Internal.Newf("switch model %s is already registered", model.Code)
+		}
+	}
+	for _, model := range driver.Models {
+		r.codes = append(r.codes, model.Code)
+		r.models[model.Code] = model
+		r.drivers[model.Code] = driver
+	}
+	if driver.ReleaseSwitch != nil {
+		r.releasers = append(r.releasers, driver.ReleaseSwitch)
+	}
+	return nil
+}
+
+//GetModels gets all switch models of the registered drivers
+//
+//Return:
+//	[]domain.EthernetSwitchModel - switch models in registration order
+func (r *EthernetSwitchDriverRegistry) GetModels() []domain.EthernetSwitchModel {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	models := make([]domain.EthernetSwitchModel, 0, len(r.codes))
+	for _, code := range r.codes {
+		models = append(models, r.models[code])
+	}
+	return models
+}
+
+//GetModel gets switch model by code
+//
+//Params:
+//	code - switch model code
+//Return:
+//	domain.EthernetSwitchModel - switch model
+//	error - NotFound error if model is not registered
+func (r *EthernetSwitchDriverRegistry) GetModel(code string) (domain.EthernetSwitchModel, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	model, ok := r.models[code]
+	if !ok {
+		return domain.EthernetSwitchModel{}, errors.NotFound.Newf("switch model %s is not supported", code)
+	}
+	return model, nil
+}
+
+//RequiresSNMP checks that the driver of the switch model needs SNMP credentials
+//
+//Params:
+//	code - switch model code
+//Return:
+//	bool - true if SNMP credentials are required
+//	error - NotFound error if model is not registered
+func (r *EthernetSwitchDriverRegistry) RequiresSNMP(code string) (bool, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	driver, ok := r.drivers[code]
+	if !ok {
+		return false, errors.NotFound.Newf("switch model %s is not supported", code)
+	}
+	return driver.RequiresSNMP, nil
+}
+
+//NewManager creates switch manager by the driver of the switch model
+//
+//Params:
+//	ethernetSwitch - switch entity
+//Return:
+//	interfaces.IEthernetSwitchManager - switch manager, nil if the driver stores switch configuration in database only
+//	error - NotFound error if switch model is not registered
+func (r *EthernetSwitchDriverRegistry) NewManager(ethernetSwitch domain.EthernetSwitch) (interfaces.IEthernetSwitchManager, error) {
+	r.mutex.RLock()
+	driver, ok := r.drivers[ethernetSwitch.SwitchModel]
+	r.mutex.RUnlock()
+	if !ok {
+		return nil, errors.NotFound.Newf("switch model %s is not supported", ethernetSwitch.SwitchModel)
+	}
+	if driver.NewManager == nil {
+		return nil, nil
+	}
+	manager, err := driver.NewManager(ethernetSwitch)
+	if err != nil {
+		return nil, errors.Internal.Wrapf(err, "failed to create manager of the switch model %s", ethernetSwitch.SwitchModel)
+	}
+	return manager, nil
+}
+
+//ReleaseSwitch releases resources that the drivers hold for the switch, all drivers are asked,
+//because the switch model could be changed after the resources were taken
+//
+//Params:
+//	switchID - ethernet switch ID
+//Return:
+//	error - if an error occurs, otherwise nil
+func (r *EthernetSwitchDriverRegistry) ReleaseSwitch(switchID uuid.UUID) error {
+	r.mutex.RLock()
+	releasers := append([]func(switchID uuid.UUID) error{}, r.releasers...)
+	r.mutex.RUnlock()
+	for _, release := range releasers {
+		err := release(switchID)
+		if err != nil {
+			return errors.Internal.Wrap(err, "failed to release switch driver resources")
+		}
+	}
+	return nil
+}
@@ -6,19 +6,33 @@ import (
 	"rol/app/errors"
 	"rol/app/interfaces"
 	"rol/domain"
+	"sync"
+	"time"
 )
 
+//ethernetSwitchManagerEntry cached switch manager
+type ethernetSwitchManagerEntry struct {
+	manager interfaces.IEthernetSwitchManager
+	//updatedAt switch entity update time, manager is recreated if the switch is updated
+	updatedAt time.Time
+}
+
 //EthernetSwitchManagerProvider struct for switch manager getter
 type EthernetSwitchManagerProvider struct {
 	switchRepo interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitch]
-	managers   map[uuid.UUID]interfaces.IEthernetSwitchManager
+	drivers    interfaces.IEthernetSwitchDriverRegistry
+	managers   map[uuid.UUID]ethernetSwitchManagerEntry
+	//managersMutex guards managers map
+	managersMutex sync.Mutex
 }
 
 //NewEthernetSwitchManagerProvider constructor for EthernetSwitchManagerProvider
-func NewEthernetSwitchManagerProvider(switchRepo interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitch]) interfaces.IEthernetSwitchManagerProvider {
+func NewEthernetSwitchManagerProvider(switchRepo interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitch],
+	drivers interfaces.IEthernetSwitchDriverRegistry) interfaces.IEthernetSwitchManagerProvider {
 	return &EthernetSwitchManagerProvider{
-		managers:   make(map[uuid.UUID]interfaces.IEthernetSwitchManager),
+		managers:   make(map[uuid.UUID]ethernetSwitchManagerEntry),
 		switchRepo: switchRepo,
+		drivers:    drivers,
 	}
 }
 
@@ -27,19 +41,45 @@ func NewEthernetSwitchManagerProvider(switchRepo interfaces.IGenericRepository[u
 //Params:
 //	ethernetSwitch - switch entity
 //Return:
-//	interfaces.IEthernetSwitchManager - switch manager interface
+//	interfaces.IEthernetSwitchManager - switch manager interface, nil if switch model driver has no manager
+//	error - NotFound error if switch model is not supported
 func (e *EthernetSwitchManagerProvider) Get(ctx context.Context, switchID uuid.UUID) (interfaces.IEthernetSwitchManager, error) {
-	if e.managers[switchID] == nil {
-		ethSwitch, err := e.switchRepo.GetByID(ctx, switchID)
-		if err != nil {
-			return nil, errors.Internal.Wrap(err, "failed to get ethernet switch configuration from repository")
-		}
-		switch ethSwitch.SwitchModel {
-		case "tl-sg2210mp":
-			e.managers[switchID] = NewTPLinkEthernetSwitchManager(ethSwitch.Address+":23", ethSwitch.Username, ethSwitch.Password)
-			return e.managers[switchID], nil
-		}
+	ethSwitch, err := e.switchRepo.GetByID(ctx, switchID)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to get ethernet switch configuration from repository")
+	}
+	updatedAt := time.Time{}
+	if ethSwitch.UpdatedAt != nil {
+		updatedAt = *ethSwitch.UpdatedAt
+	}
+	e.managersMutex.Lock()
+	defer e.managersMutex.Unlock()
+	entry, ok := e.managers[switchID]
+	if ok && entry.updatedAt.Equal(updatedAt) {
+		return entry.manager, nil
+	}
+	manager, err := e.drivers.NewManager(ethSwitch)
+	if err != nil {
+		delete(e.managers, switchID)
+		return nil, err
+	}
+	if manager == nil {
+		delete(e.managers, switchID)
 		return nil, nil
 	}
-	return e.managers[switchID], nil
+	e.managers[switchID] = ethernetSwitchManagerEntry{manager: manager, updatedAt: updatedAt}
+	return manager, nil
+}
+
+//Release ethernet switch manager and resources that the switch driver holds for the deleted switch
+//
+//Params:
+//	switchID - ethernet switch ID
+//Return:
+//	error - if an error occurs, otherwise nil
+func (e *EthernetSwitchManagerProvider) Release(switchID uuid.UUID) error {
+	e.managersMutex.Lock()
+	delete(e.managers, switchID)
+	e.managersMutex.Unlock()
+	return e.drivers.ReleaseSwitch(switchID)
 }
@@ -0,0 +1,124 @@
+package infrastructure
+
+import (
+	"net"
+	"rol/app/errors"
+	"rol/domain"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	//tpLinkMACTableEnd beginning of the last line of the TP-Link MAC address table output
+	tpLinkMACTableEnd = "Total MAC Addresses for this criterion:"
+	//tpLinkLLDPNeighborStart beginning of the neighbor section of the TP-Link LLDP neighbor information output
+	tpLinkLLDPNeighborStart = "Neighbor index"
+)
+
+//formatLLDPID formats LLDP chassis or port ID, MAC addresses are converted to 00:11:22:33:44:55 format
+func formatLLDPID(id string) string {
+	mac, err := net.ParseMAC(id)
+	if err == nil && len(mac) == 6 {
+		return mac.String()
+	}
+	return id
+}
+
+//sortMACAddressTable sorts MAC address table entries by port name, VLAN and MAC address
+func sortMACAddressTable(table []domain.EthernetSwitchMACAddress) {
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].PortName != table[j].PortName {
+			return table[i].PortName < table[j].PortName
+		}
+		if table[i].VLANID != table[j].VLANID {
+			return table[i].VLANID < table[j].VLANID
+		}
+		return table[i].MAC < table[j].MAC
+	})
+}
+
+//sortLLDPNeighbors sorts LLDP neighbors by port name and chassis ID
+func sortLLDPNeighbors(neighbors []domain.EthernetSwitchLLDPNeighbor) {
+	sort.Slice(neighbors, func(i, j int) bool {
+		if neighbors[i].PortName != neighbors[j].PortName {
+			return neighbors[i].PortName < neighbors[j].PortName
+		}
+		return neighbors[i].ChassisID < neighbors[j].ChassisID
+	})
+}
+
+//parseTPLinkMACAddressTable parses rows of the TP-Link MAC address table output, rows of the CPU
+//and LAG ports are skipped
+//
+//Params:
+//	output - show mac address-table output
+//Return:
+//	[]domain.EthernetSwitchMACAddress - MAC address table entries
+//	error - if an error occurs, otherwise nil
+func parseTPLinkMACAddressTable(output string) ([]domain.EthernetSwitchMACAddress, error) {
+	table := []domain.EthernetSwitchMACAddress{}
+	for _, line := range strings.Split(output, "\r\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 3 {
+			continue
+		}
+		mac, err := net.ParseMAC(fields[0])
+		if err != nil {
+			//table header and other lines
+			continue
+		}
+		if !strings.HasPrefix(strings.ToLower(fields[2]), "gi") {
+			continue
+		}
+		vlanID, err := strconv.Atoi(fields[1])
+		if err != nil {
+			return nil, errors.Internal.Wrapf(err, "wrong VLAN of the MAC address %s", fields[0])
+		}
+		table = append(table, domain.EthernetSwitchMACAddress{
+			MAC:      mac.String(),
+			VLANID:   vlanID,
+			PortName: "gi" + fields[2][2:],
+		})
+	}
+	sortMACAddressTable(table)
+	return table, nil
+}
+
+//parseTPLinkLLDPNeighbors parses TP-Link LLDP neighbor information output of the port
+//
+//Params:
+//	portName - port name
+//	output - show lldp neighbor-information interface output
+//Return:
+//	[]domain.EthernetSwitchLLDPNeighbor - port neighbors
+func parseTPLinkLLDPNeighbors(portName, output string) []domain.EthernetSwitchLLDPNeighbor {
+	neighbors := []domain.EthernetSwitchLLDPNeighbor{}
+	for _, line := range strings.Split(output, "\r\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, tpLinkLLDPNeighborStart) {
+			neighbors = append(neighbors, domain.EthernetSwitchLLDPNeighbor{PortName: portName})
+			continue
+		}
+		pair := strings.SplitN(line, ":", 2)
+		if len(neighbors) == 0 || len(pair) != 2 {
+			continue
+		}
+		neighbor := &neighbors[len(neighbors)-1]
+		//TP-Link pads the keys with spaces up to the colon column
+		value := strings.TrimSpace(pair[1])
+		switch strings.TrimSpace(pair[0]) {
+		case "Chassis ID":
+			neighbor.ChassisID = formatLLDPID(value)
+		case "Port ID":
+			neighbor.PortID = formatLLDPID(value)
+		case "Port description":
+			neighbor.PortDescription = value
+		case "System name":
+			neighbor.SystemName = value
+		case "Management address":
+			neighbor.ManagementAddress = value
+		}
+	}
+	return neighbors
+}
@@ -0,0 +1,51 @@
+package infrastructure
+
+import (
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/domain"
+)
+
+//applyEthernetSwitchOperations applies operations one by one with the manager methods,
+//it is used by the managers that have no sessions to batch the operations in
+//
+//Params:
+//	manager - switch manager
+//	operations - switch configuration changes
+//Return:
+//	error - if an error occurs, otherwise nil
+func applyEthernetSwitchOperations(manager interfaces.IEthernetSwitchManager, operations []domain.EthernetSwitchOperation) error {
+	for _, operation := range operations {
+		var err error
+		switch operation.Type {
+		case domain.EthernetSwitchOperationCreateVLAN:
+			err = manager.CreateVLAN(operation.VLANID)
+		case domain.EthernetSwitchOperationDeleteVLAN:
+			err = manager.DeleteVLAN(operation.VLANID)
+		case domain.EthernetSwitchOperationAddTaggedVLAN:
+			err = manager.AddTaggedVLANOnPort(operation.PortName, operation.VLANID)
+		case domain.EthernetSwitchOperationAddUntaggedVLAN:
+			err = manager.AddUntaggedVLANOnPort(operation.PortName, operation.VLANID)
+		case domain.EthernetSwitchOperationRemoveVLAN:
+			err = manager.RemoveVLANFromPort(operation.PortName, operation.VLANID)
+		case domain.EthernetSwitchOperationSetPVID:
+			err = manager.SetPortPVID(operation.PortName, operation.VLANID)
+		case domain.EthernetSwitchOperationEnablePOE, domain.EthernetSwitchOperationDisablePOE:
+			poeManager, ok := manager.(interfaces.IEthernetSwitchPOEManager)
+			if !ok {
+				return errors.Internal.New("switch does not support PoE")
+			}
+			if operation.Type == domain.EthernetSwitchOperationEnablePOE {
+				err = poeManager.EnablePOEPort(operation.PortName, operation.POEType)
+			} else {
+				err = poeManager.DisablePOEPort(operation.PortName)
+			}
+		default:
+			return errors.Internal.Newf("unknown switch operation %s", operation.Type)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
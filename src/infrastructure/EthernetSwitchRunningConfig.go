@@ -0,0 +1,305 @@
+package infrastructure
+
+import (
+	"fmt"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/utils"
+	"rol/domain"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	//tpLinkDefaultVLAN VLAN that ports are untagged members of unless the config removes them
+	tpLinkDefaultVLAN = 1
+	//tpLinkMinVLANID min VLAN ID of the VLANs list
+	tpLinkMinVLANID = 1
+	//tpLinkMaxVLANID max VLAN ID of the VLANs list
+	tpLinkMaxVLANID = 4094
+	//tpLinkConfigEnd last line of the running config
+	tpLinkConfigEnd = "end"
+)
+
+//tpLinkPortConfig port settings of the TP-Link running config
+type tpLinkPortConfig struct {
+	tagged   map[int]bool
+	untagged map[int]bool
+	pvid     int
+	//poeEnabled PoE state, nil if the config has no PoE settings for the port
+	poeEnabled *bool
+}
+
+//tpLinkRunningConfig VLANs and ports settings of the TP-Link running config
+type tpLinkRunningConfig struct {
+	vlans []int
+	ports map[string]*tpLinkPortConfig
+	//portsNames ports names in the config order
+	portsNames []string
+}
+
+//parseTPLinkVLANsList parses VLANs list like 10,20-22, VLAN IDs must be in range 1-4094
+func parseTPLinkVLANsList(list string) ([]int, error) {
+	vlans := []int{}
+	for _, item := range strings.Split(list, ",") {
+		bounds := strings.SplitN(item, "-", 2)
+		first, err := strconv.Atoi(bounds[0])
+		if err != nil {
+			return nil, errors.Internal.Wrapf(err, "wrong VLANs list %s", list)
+		}
+		last := first
+		if len(bounds) == 2 {
+			last, err = strconv.Atoi(bounds[1])
+			if err != nil {
+				return nil, errors.Internal.Wrapf(err, "wrong VLANs list %s", list)
+			}
+		}
+		if first < tpLinkMinVLANID || last > tpLinkMaxVLANID {
+			return nil, errors.Validation.Newf("VLAN ID is out of range %d-%d in VLANs list %s", tpLinkMinVLANID, tpLinkMaxVLANID, list)
+		}
+		if first > last {
+			return nil, errors.Validation.Newf("VLANs range %s has first VLAN ID greater than last in VLANs list %s", item, list)
+		}
+		for vlanID := first; vlanID <= last; vlanID++ {
+			vlans = append(vlans, vlanID)
+		}
+	}
+	return vlans, nil
+}
+
+//parseTPLinkRunningConfig parses VLANs, ports VLANs membership, PVID and PoE state from the TP-Link running config,
+//other settings are skipped
+//
+//Params:
+//	config - running config text
+//Return:
+//	tpLinkRunningConfig - parsed config
+//	error - if an error occurs, otherwise nil
+func parseTPLinkRunningConfig(config string) (tpLinkRunningConfig, error) {
+	parsed := tpLinkRunningConfig{
+		vlans: []int{tpLinkDefaultVLAN},
+		ports: map[string]*tpLinkPortConfig{},
+	}
+	var port *tpLinkPortConfig
+	for _, line := range strings.Split(strings.ReplaceAll(config, "\r\n", "\n"), "\n") {
+		fields := strings.Fields(line)
+		switch {
+		case len(fields) == 0:
+			continue
+		case fields[0] == "#" || fields[0] == "!" || fields[0] == tpLinkConfigEnd:
+			port = nil
+		case len(fields) == 2 && fields[0] == "vlan":
+			vlans, err := parseTPLinkVLANsList(fields[1])
+			if err != nil {
+				return parsed, err
+			}
+			for _, vlanID := range vlans {
+				if !utils.SliceContainsElement(parsed.vlans, vlanID) {
+					parsed.vlans = append(parsed.vlans, vlanID)
+				}
+			}
+		case len(fields) == 3 && fields[0] == "interface" && fields[1] == "gigabitEthernet":
+			portName := "gi" + fields[2]
+			port = &tpLinkPortConfig{
+				tagged:   map[int]bool{},
+				untagged: map[int]bool{tpLinkDefaultVLAN: true},
+				pvid:     tpLinkDefaultVLAN,
+			}
+			parsed.ports[portName] = port
+			parsed.portsNames = append(parsed.portsNames, portName)
+		case port == nil:
+			//global settings that are not restored
+			continue
+		case len(fields) == 6 && strings.Join(fields[:4], " ") == "switchport general allowed vlan":
+			vlans, err := parseTPLinkVLANsList(fields[4])
+			if err != nil {
+				return parsed, err
+			}
+			for _, vlanID := range vlans {
+				port.tagged[vlanID] = fields[5] == "tagged"
+				port.untagged[vlanID] = fields[5] == "untagged"
+			}
+		case len(fields) == 6 && strings.Join(fields[:5], " ") == "no switchport general allowed vlan":
+			vlans, err := parseTPLinkVLANsList(fields[5])
+			if err != nil {
+				return parsed, err
+			}
+			for _, vlanID := range vlans {
+				delete(port.tagged, vlanID)
+				delete(port.untagged, vlanID)
+			}
+		case len(fields) == 3 && fields[0] == "switchport" && fields[1] == "pvid":
+			pvid, err := strconv.Atoi(fields[2])
+			if err != nil {
+				return parsed, errors.Internal.Wrapf(err, "wrong PVID %s", fields[2])
+			}
+			port.pvid = pvid
+		case len(fields) == 4 && strings.Join(fields[:3], " ") == "power inline supply":
+			enabled := fields[3] == "enable"
+			port.poeEnabled = &enabled
+		}
+	}
+	sort.Ints(parsed.vlans)
+	return parsed, nil
+}
+
+//getVLANsIDs gets sorted IDs of the VLANs that are set in the membership map
+func getVLANsIDs(membership map[int]bool) []int {
+	vlans := []int{}
+	for vlanID, member := range membership {
+		if member {
+			vlans = append(vlans, vlanID)
+		}
+	}
+	sort.Ints(vlans)
+	return vlans
+}
+
+//renderTPLinkVLANsList renders VLANs list like 10,20,22
+func renderTPLinkVLANsList(vlans []int) string {
+	items := []string{}
+	for _, vlanID := range vlans {
+		items = append(items, strconv.Itoa(vlanID))
+	}
+	return strings.Join(items, ",")
+}
+
+//renderTPLinkPortConfig renders interface section of the TP-Link running config
+func renderTPLinkPortConfig(builder *strings.Builder, portName string, port tpLinkPortConfig) {
+	builder.WriteString("interface gigabitEthernet " + portName[2:] + "\n")
+	if !port.untagged[tpLinkDefaultVLAN] {
+		builder.WriteString(fmt.Sprintf(" no switchport general allowed vlan %d\n", tpLinkDefaultVLAN))
+	}
+	untagged := []int{}
+	for _, vlanID := range getVLANsIDs(port.untagged) {
+		if vlanID != tpLinkDefaultVLAN {
+			untagged = append(untagged, vlanID)
+		}
+	}
+	if len(untagged) > 0 {
+		builder.WriteString(" switchport general allowed vlan " + renderTPLinkVLANsList(untagged) + " untagged\n")
+	}
+	if tagged := getVLANsIDs(port.tagged); len(tagged) > 0 {
+		builder.WriteString(" switchport general allowed vlan " + renderTPLinkVLANsList(tagged) + " tagged\n")
+	}
+	if port.pvid != tpLinkDefaultVLAN {
+		builder.WriteString(fmt.Sprintf(" switchport pvid %d\n", port.pvid))
+	}
+	if port.poeEnabled != nil {
+		status := "disable"
+		if *port.poeEnabled {
+			status = "enable"
+		}
+		builder.WriteString(" power inline supply " + status + "\n")
+	}
+	builder.WriteString("#\n")
+}
+
+//getPortRestoreOperations gets operations that change port VLANs membership, PVID and PoE state to the config ones
+func getPortRestoreOperations(manager interfaces.IEthernetSwitchManager, capabilities domain.EthernetSwitchCapabilities,
+	portName string, port *tpLinkPortConfig) ([]domain.EthernetSwitchOperation, error) {
+	operations := []domain.EthernetSwitchOperation{}
+	untagged, tagged, err := manager.GetVLANsOnPort(portName)
+	if err != nil {
+		return nil, err
+	}
+	//port is added to the VLANs before it is removed from others, so it is never left without VLAN
+	for _, vlanID := range getVLANsIDs(port.untagged) {
+		if untagged != vlanID {
+			operations = append(operations, domain.EthernetSwitchOperation{
+				Type: domain.EthernetSwitchOperationAddUntaggedVLAN, PortName: portName, VLANID: vlanID})
+		}
+	}
+	for _, vlanID := range getVLANsIDs(port.tagged) {
+		if !utils.SliceContainsElement(tagged, vlanID) {
+			operations = append(operations, domain.EthernetSwitchOperation{
+				Type: domain.EthernetSwitchOperationAddTaggedVLAN, PortName: portName, VLANID: vlanID})
+		}
+	}
+	currentVLANs := append([]int{}, tagged...)
+	//manager reports only one untagged VLAN, so membership in the default VLAN is removed explicitly
+	currentVLANs = append(currentVLANs, untagged, tpLinkDefaultVLAN)
+	removed := map[int]bool{}
+	for _, vlanID := range currentVLANs {
+		if vlanID == 0 || removed[vlanID] || port.tagged[vlanID] || port.untagged[vlanID] {
+			continue
+		}
+		removed[vlanID] = true
+		operations = append(operations, domain.EthernetSwitchOperation{
+			Type: domain.EthernetSwitchOperationRemoveVLAN, PortName: portName, VLANID: vlanID})
+	}
+	pvid, err := manager.GetPortPVID(portName)
+	if err != nil {
+		return nil, err
+	}
+	if pvid != port.pvid {
+		operations = append(operations, domain.EthernetSwitchOperation{
+			Type: domain.EthernetSwitchOperationSetPVID, PortName: portName, VLANID: port.pvid})
+	}
+	poeManager, isPOEManager := manager.(interfaces.IEthernetSwitchPOEManager)
+	if port.poeEnabled == nil || !isPOEManager || !utils.SliceContainsElement(capabilities.POEPorts, portName) {
+		return operations, nil
+	}
+	status, err := poeManager.GetPOEPortStatus(portName)
+	if err != nil {
+		return nil, err
+	}
+	if *port.poeEnabled && status != "enable" {
+		poeType := ""
+		if len(capabilities.POETypes) > 0 {
+			poeType = capabilities.POETypes[0]
+		}
+		operations = append(operations, domain.EthernetSwitchOperation{
+			Type: domain.EthernetSwitchOperationEnablePOE, PortName: portName, POEType: poeType})
+	} else if !*port.poeEnabled && status == "enable" {
+		operations = append(operations, domain.EthernetSwitchOperation{
+			Type: domain.EthernetSwitchOperationDisablePOE, PortName: portName})
+	}
+	return operations, nil
+}
+
+//getRestoreOperations gets operations that change the switch state to the TP-Link running config state.
+//Ports that are not present in the config are not changed
+//
+//Params:
+//	manager - switch manager
+//	config - running config text
+//Return:
+//	[]domain.EthernetSwitchOperation - switch configuration changes
+//	error - if an error occurs, otherwise nil
+func getRestoreOperations(manager interfaces.IEthernetSwitchManager, config string) ([]domain.EthernetSwitchOperation, error) {
+	parsed, err := parseTPLinkRunningConfig(config)
+	if err != nil {
+		return nil, err
+	}
+	capabilities, err := manager.GetCapabilities()
+	if err != nil {
+		return nil, err
+	}
+	currentVLANs, err := manager.GetVLANs()
+	if err != nil {
+		return nil, err
+	}
+	operations := []domain.EthernetSwitchOperation{}
+	for _, vlanID := range parsed.vlans {
+		if !utils.SliceContainsElement(currentVLANs, vlanID) {
+			operations = append(operations, domain.EthernetSwitchOperation{
+				Type: domain.EthernetSwitchOperationCreateVLAN, VLANID: vlanID})
+		}
+	}
+	for _, portName := range parsed.portsNames {
+		portOperations, err := getPortRestoreOperations(manager, capabilities, portName, parsed.ports[portName])
+		if err != nil {
+			return nil, errors.Internal.Wrapf(err, "failed to read state of the port %s", portName)
+		}
+		operations = append(operations, portOperations...)
+	}
+	for _, vlanID := range currentVLANs {
+		if vlanID != tpLinkDefaultVLAN && !utils.SliceContainsElement(parsed.vlans, vlanID) {
+			operations = append(operations, domain.EthernetSwitchOperation{
+				Type: domain.EthernetSwitchOperationDeleteVLAN, VLANID: vlanID})
+		}
+	}
+	return operations, nil
+}
@@ -0,0 +1,27 @@
+package infrastructure
+
+import (
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"rol/app/interfaces"
+	"rol/domain"
+)
+
+//GormDHCP4BootRuleRepository repository for domain.DHCP4BootRule entity
+type GormDHCP4BootRuleRepository struct {
+	*GormGenericRepository[uuid.UUID, domain.DHCP4BootRule]
+}
+
+//NewGormDHCP4BootRuleRepository constructor for domain.DHCP4BootRule GORM generic repository
+//Params
+//	db - gorm database
+//	log - logrus logger
+//Return
+//	generic.IGenericRepository[domain.DHCP4BootRule] - new dhcp v4 boot rule repository
+func NewGormDHCP4BootRuleRepository(db *gorm.DB, log *logrus.Logger) interfaces.IGenericRepository[uuid.UUID, domain.DHCP4BootRule] {
+	genericRepository := NewGormGenericRepository[uuid.UUID, domain.DHCP4BootRule](db, log)
+	return &GormDHCP4BootRuleRepository{
+		genericRepository,
+	}
+}
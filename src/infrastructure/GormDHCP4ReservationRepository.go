@@ -0,0 +1,27 @@
+package infrastructure
+
+import (
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"rol/app/interfaces"
+	"rol/domain"
+)
+
+//GormDHCP4ReservationRepository repository for domain.DHCP4Reservation entity
+type GormDHCP4ReservationRepository struct {
+	*GormGenericRepository[uuid.UUID, domain.DHCP4Reservation]
+}
+
+//NewGormDHCP4ReservationRepository constructor for domain.DHCP4Reservation GORM generic repository
+//Params
+//	db - gorm database
+//	log - logrus logger
+//Return
+//	generic.IGenericRepository[domain.DHCP4Reservation] - new dhcp v4 reservation repository
+func NewGormDHCP4ReservationRepository(db *gorm.DB, log *logrus.Logger) interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Reservation] {
+	genericRepository := NewGormGenericRepository[uuid.UUID, domain.DHCP4Reservation](db, log)
+	return &GormDHCP4ReservationRepository{
+		genericRepository,
+	}
+}
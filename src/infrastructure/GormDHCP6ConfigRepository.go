@@ -0,0 +1,27 @@
+package infrastructure
+
+import (
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"rol/app/interfaces"
+	"rol/domain"
+)
+
+//GormDHCP6ConfigRepository repository for domain.DHCP6Config entity
+type GormDHCP6ConfigRepository struct {
+	*GormGenericRepository[uuid.UUID, domain.DHCP6Config]
+}
+
+//NewGormDHCP6ConfigRepository constructor for domain.DHCP6Config GORM generic repository
+//Params
+//	db - gorm database
+//	log - logrus logger
+//Return
+//	generic.IGenericRepository[domain.DHCP6Config] - new dhcp v6 config repository
+func NewGormDHCP6ConfigRepository(db *gorm.DB, log *logrus.Logger) interfaces.IGenericRepository[uuid.UUID, domain.DHCP6Config] {
+	genericRepository := NewGormGenericRepository[uuid.UUID, domain.DHCP6Config](db, log)
+	return &GormDHCP6ConfigRepository{
+		genericRepository,
+	}
+}
@@ -0,0 +1,27 @@
+package infrastructure
+
+import (
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"rol/app/interfaces"
+	"rol/domain"
+)
+
+//GormDHCP6LeaseRepository repository for domain.DHCP6Lease entity
+type GormDHCP6LeaseRepository struct {
+	*GormGenericRepository[uuid.UUID, domain.DHCP6Lease]
+}
+
+//NewGormDHCP6LeaseRepository constructor for domain.DHCP6Lease GORM generic repository
+//Params
+//	db - gorm database
+//	log - logrus logger
+//Return
+//	generic.IGenericRepository[domain.DHCP6Lease] - new dhcp v6 lease repository
+func NewGormDHCP6LeaseRepository(db *gorm.DB, log *logrus.Logger) interfaces.IGenericRepository[uuid.UUID, domain.DHCP6Lease] {
+	genericRepository := NewGormGenericRepository[uuid.UUID, domain.DHCP6Lease](db, log)
+	return &GormDHCP6LeaseRepository{
+		genericRepository,
+	}
+}
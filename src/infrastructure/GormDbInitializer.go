@@ -93,11 +93,22 @@ func NewGormEntityDb(cfg *domain.AppConfig) (*gorm.DB, error) {
 	err = db.AutoMigrate(
 		&domain.TFTPConfig{},
 		&domain.TFTPPathRatio{},
+		&domain.HTTPBootConfig{},
+		&domain.HTTPBootPathRatio{},
 		&domain.EthernetSwitch{},
 		&domain.EthernetSwitchPort{},
 		&domain.EthernetSwitchVLAN{},
+		&domain.EthernetSwitchConfigBackup{},
 		&domain.DHCP4Config{},
 		&domain.DHCP4Lease{},
+		&domain.DHCP4Reservation{},
+		&domain.DHCP4BootRule{},
+		&domain.DHCP6Config{},
+		&domain.DHCP6Lease{},
+		&domain.Device{},
+		&domain.DeviceNetworkInterface{},
+		&domain.DeviceBoot{},
+		&domain.DeviceBootStage{},
 	)
 	if err != nil {
 		return nil, errors.Internal.Wrap(err, "failed to apply db migrations")
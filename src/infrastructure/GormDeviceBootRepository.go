@@ -0,0 +1,28 @@
+package infrastructure
+
+import (
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"rol/app/interfaces"
+	"rol/domain"
+)
+
+//GormDeviceBootRepository repository for DeviceBoot entity
+type GormDeviceBootRepository struct {
+	*GormGenericRepository[uuid.UUID, domain.DeviceBoot]
+}
+
+//NewGormDeviceBootRepository constructor for domain.DeviceBoot GORM generic repository
+//
+//Params
+//	db - gorm database
+//	log - logrus logger
+//Return
+//	interfaces.IGenericRepository[uuid.UUID, domain.DeviceBoot] - new device boot repository
+func NewGormDeviceBootRepository(db *gorm.DB, log *logrus.Logger) interfaces.IGenericRepository[uuid.UUID, domain.DeviceBoot] {
+	genericRepository := NewGormGenericRepository[uuid.UUID, domain.DeviceBoot](db, log)
+	return GormDeviceBootRepository{
+		genericRepository,
+	}
+}
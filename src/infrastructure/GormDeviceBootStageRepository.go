@@ -0,0 +1,28 @@
+package infrastructure
+
+import (
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"rol/app/interfaces"
+	"rol/domain"
+)
+
+//GormDeviceBootStageRepository repository for DeviceBootStage entity
+type GormDeviceBootStageRepository struct {
+	*GormGenericRepository[uuid.UUID, domain.DeviceBootStage]
+}
+
+//NewGormDeviceBootStageRepository constructor for domain.DeviceBootStage GORM generic repository
+//
+//Params
+//	db - gorm database
+//	log - logrus logger
+//Return
+//	interfaces.IGenericRepository[uuid.UUID, domain.DeviceBootStage] - new device boot stage repository
+func NewGormDeviceBootStageRepository(db *gorm.DB, log *logrus.Logger) interfaces.IGenericRepository[uuid.UUID, domain.DeviceBootStage] {
+	genericRepository := NewGormGenericRepository[uuid.UUID, domain.DeviceBootStage](db, log)
+	return GormDeviceBootStageRepository{
+		genericRepository,
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"reflect"
 	"rol/app/errors"
 	"rol/app/interfaces"
+	"strings"
 
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
@@ -126,18 +127,12 @@ func (g *GormGenericRepository[EntityIDType, EntityType]) addQueryToGorm(gormQue
 	return nil
 }
 
-func generateOrderString(orderBy string, orderDirection string) string {
-	order := ""
-	if len(orderBy) > 0 {
-		order = orderBy
-		if len(orderDirection) > 0 {
-			order = order + " " + orderDirection
-		}
-	}
-	if len(order) < 1 {
-		order = "created_at desc"
+//generateOrderColumn builds order clause, column name is quoted, so columns named as sql keywords can be used
+func generateOrderColumn(orderBy string, orderDirection string) clause.OrderByColumn {
+	if len(orderBy) < 1 {
+		return clause.OrderByColumn{Column: clause.Column{Name: "created_at"}, Desc: true}
 	}
-	return order
+	return clause.OrderByColumn{Column: clause.Column{Name: orderBy}, Desc: strings.EqualFold(orderDirection, "desc")}
 }
 
 //GetList of elements with filtering and pagination
@@ -160,8 +155,7 @@ func (g *GormGenericRepository[EntityIDType, EntityType]) GetList(ctx context.Co
 	if len(orderBy) > 1 {
 		orderBy = ToSnakeCase(orderBy)
 	}
-	orderString := generateOrderString(orderBy, orderDirection)
-	gormQuery := g.Db.Model(model).Order(orderString)
+	gormQuery := g.Db.Model(model).Order(generateOrderColumn(orderBy, orderDirection))
 	err := g.addQueryToGorm(gormQuery, queryBuilder)
 	if err != nil {
 		return nil, errors.Internal.Wrap(err, "adding query to gorm failed")
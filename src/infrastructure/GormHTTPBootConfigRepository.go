@@ -0,0 +1,28 @@
+// Package infrastructure stores all implementations of app interfaces
+package infrastructure
+
+import (
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"rol/app/interfaces"
+	"rol/domain"
+)
+
+//GormHTTPBootConfigRepository repository for HTTPBootConfig entity
+type GormHTTPBootConfigRepository struct {
+	*GormGenericRepository[uuid.UUID, domain.HTTPBootConfig]
+}
+
+//NewGormHTTPBootConfigRepository constructor for domain.HTTPBootConfig GORM generic repository
+//Params
+//	db - gorm database
+//	log - logrus logger
+//Return
+//	generic.IGenericRepository[domain.HTTPBootConfig] - new http boot server repository
+func NewGormHTTPBootConfigRepository(db *gorm.DB, log *logrus.Logger) interfaces.IGenericRepository[uuid.UUID, domain.HTTPBootConfig] {
+	genericRepository := NewGormGenericRepository[uuid.UUID, domain.HTTPBootConfig](db, log)
+	return GormHTTPBootConfigRepository{
+		genericRepository,
+	}
+}
@@ -0,0 +1,28 @@
+// Package infrastructure stores all implementations of app interfaces
+package infrastructure
+
+import (
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/gorm"
+	"rol/app/interfaces"
+	"rol/domain"
+)
+
+//GormHTTPBootPathRatioRepository repository for HTTPBootPathRatio entity
+type GormHTTPBootPathRatioRepository struct {
+	*GormGenericRepository[uuid.UUID, domain.HTTPBootPathRatio]
+}
+
+//NewGormHTTPBootPathRatioRepository constructor for domain.HTTPBootPathRatio GORM generic repository
+//Params
+//	db - gorm database
+//	log - logrus logger
+//Return
+//	generic.IGenericRepository[domain.HTTPBootPathRatio] - new http boot path ratio repository
+func NewGormHTTPBootPathRatioRepository(db *gorm.DB, log *logrus.Logger) interfaces.IGenericRepository[uuid.UUID, domain.HTTPBootPathRatio] {
+	genericRepository := NewGormGenericRepository[uuid.UUID, domain.HTTPBootPathRatio](db, log)
+	return GormHTTPBootPathRatioRepository{
+		genericRepository,
+	}
+}
@@ -0,0 +1,206 @@
+// Package infrastructure stores all implementations of app interfaces
+package infrastructure
+
+import (
+	"net"
+	"net/http"
+	"os"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/domain"
+	"sort"
+	"sync"
+	"time"
+)
+
+//httpBootReadHeaderTimeout timeout for reading request headers, protects from the slow clients
+const httpBootReadHeaderTimeout = 10 * time.Second
+
+//NetHTTPBootServer HTTP boot file server implementation for IHTTPBootServer interface based on net/http.
+//Range requests are supported, so iPXE and UEFI HTTP boot clients can resume downloads
+type NetHTTPBootServer struct {
+	config  domain.HTTPBootConfig
+	runtime *http.Server
+	state   domain.HTTPBootServerState
+	//runtimeMutex guards runtime, config and state
+	runtimeMutex sync.Mutex
+	paths        *virtualPathTable
+	//pathsMutex guards paths table, that is replaced on the fly
+	pathsMutex sync.RWMutex
+	stats      map[string]*domain.HTTPBootFileStats
+	//statsMutex guards stats map, that is updated by the requests handlers
+	statsMutex sync.Mutex
+}
+
+//NewNetHTTPBootServer creates new net/http boot server
+//
+//Params:
+//	config - http boot server config
+//Return:
+//	interfaces.IHTTPBootServer - http boot server
+//	error - if an error occurred, otherwise nil
+func NewNetHTTPBootServer(config domain.HTTPBootConfig) (interfaces.IHTTPBootServer, error) {
+	return &NetHTTPBootServer{
+		config: config,
+		state:  domain.HTTPBootStateStopped,
+		paths:  newVirtualPathTable(),
+		stats:  map[string]*domain.HTTPBootFileStats{},
+	}, nil
+}
+
+//countingResponseWriter counts bytes written to the response
+type countingResponseWriter struct {
+	http.ResponseWriter
+	status  int
+	written int64
+}
+
+func (w *countingResponseWriter) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *countingResponseWriter) Write(p []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+func (s *NetHTTPBootServer) getPaths() *virtualPathTable {
+	s.pathsMutex.RLock()
+	defer s.pathsMutex.RUnlock()
+	return s.paths
+}
+
+func (s *NetHTTPBootServer) addStats(virtualPath string, written int64, isDownload bool) {
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+	stats, ok := s.stats[virtualPath]
+	if !ok {
+		stats = &domain.HTTPBootFileStats{VirtualPath: virtualPath}
+		s.stats[virtualPath] = stats
+	}
+	stats.Requests++
+	stats.BytesSent += written
+	if isDownload {
+		stats.Downloads++
+	}
+	stats.LastRequestAt = time.Now()
+}
+
+//serveFile serves the file found by the url path, range and conditional requests are handled by http.ServeContent
+func (s *NetHTTPBootServer) serveFile(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+		return
+	}
+	virtualPath := normalizeVirtualPath(r.URL.Path)
+	entry, ok := s.getPaths().lookup(virtualPath)
+	if virtualPath == "" || !ok {
+		http.NotFound(w, r)
+		return
+	}
+	file, err := os.Open(entry.actualPath)
+	if err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	defer file.Close()
+	info, err := file.Stat()
+	if err != nil || info.IsDir() {
+		http.NotFound(w, r)
+		return
+	}
+	counter := &countingResponseWriter{ResponseWriter: w}
+	http.ServeContent(counter, r, info.Name(), info.ModTime(), file)
+	isDownload := r.Method == http.MethodGet && counter.status == http.StatusOK && counter.written == info.Size()
+	s.addStats(virtualPath, counter.written, isDownload)
+}
+
+//ReloadConfig for HTTP boot server, new config is applied on the next start
+func (s *NetHTTPBootServer) ReloadConfig(config domain.HTTPBootConfig) error {
+	s.runtimeMutex.Lock()
+	defer s.runtimeMutex.Unlock()
+	s.config = config
+	return nil
+}
+
+//ReloadPaths for HTTP boot server
+func (s *NetHTTPBootServer) ReloadPaths(paths []domain.HTTPBootPathRatio) error {
+	table := newVirtualPathTable()
+	for _, ratio := range paths {
+		table.add(ratio.VirtualPath, virtualPathEntry{actualPath: ratio.ActualPath}, ratio.IsDirectory)
+	}
+	s.pathsMutex.Lock()
+	defer s.pathsMutex.Unlock()
+	s.paths = table
+	return nil
+}
+
+//Start HTTP boot server, listener is opened synchronously, so address errors are returned
+func (s *NetHTTPBootServer) Start() error {
+	s.runtimeMutex.Lock()
+	defer s.runtimeMutex.Unlock()
+	if s.runtime != nil {
+		return nil
+	}
+	listener, err := net.Listen("tcp", net.JoinHostPort(s.config.Address, s.config.Port))
+	if err != nil {
+		s.state = domain.HTTPBootStateError
+		return errors.Internal.Wrapf(err, "failed to listen on %s:%s", s.config.Address, s.config.Port)
+	}
+	runtime := &http.Server{
+		Handler:           http.HandlerFunc(s.serveFile),
+		ReadHeaderTimeout: httpBootReadHeaderTimeout,
+	}
+	s.runtime = runtime
+	s.state = domain.HTTPBootStateLaunched
+	go func() {
+		err := runtime.Serve(listener)
+		if err != nil && err != http.ErrServerClosed {
+			s.runtimeMutex.Lock()
+			defer s.runtimeMutex.Unlock()
+			if s.runtime == runtime {
+				s.state = domain.HTTPBootStateError
+				s.runtime = nil
+			}
+		}
+	}()
+	return nil
+}
+
+//Stop HTTP boot server
+func (s *NetHTTPBootServer) Stop() {
+	s.runtimeMutex.Lock()
+	defer s.runtimeMutex.Unlock()
+	if s.runtime != nil {
+		_ = s.runtime.Close()
+		s.runtime = nil
+	}
+	s.state = domain.HTTPBootStateStopped
+}
+
+//GetState from HTTP boot server
+func (s *NetHTTPBootServer) GetState() domain.HTTPBootServerState {
+	s.runtimeMutex.Lock()
+	defer s.runtimeMutex.Unlock()
+	return s.state
+}
+
+//GetFileStats get download statistics of the served files sorted by virtual path
+func (s *NetHTTPBootServer) GetFileStats() []domain.HTTPBootFileStats {
+	s.statsMutex.Lock()
+	defer s.statsMutex.Unlock()
+	stats := make([]domain.HTTPBootFileStats, 0, len(s.stats))
+	for _, fileStats := range s.stats {
+		stats = append(stats, *fileStats)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].VirtualPath < stats[j].VirtualPath
+	})
+	return stats
+}
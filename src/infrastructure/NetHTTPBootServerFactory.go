@@ -0,0 +1,30 @@
+// Package infrastructure stores all implementations of app interfaces
+package infrastructure
+
+import (
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/domain"
+)
+
+//NetHTTPBootServerFactory is implementation for IHTTPBootServerFactory interface
+type NetHTTPBootServerFactory struct {
+}
+
+//NewNetHTTPBootServerFactory creates new net/http boot server factory
+//
+//Return:
+//	interfaces.IHTTPBootServerFactory - http boot server factory
+//	error - if an error occurred, otherwise nil
+func NewNetHTTPBootServerFactory() (interfaces.IHTTPBootServerFactory, error) {
+	return &NetHTTPBootServerFactory{}, nil
+}
+
+//Create net/http boot server
+func (f *NetHTTPBootServerFactory) Create(config domain.HTTPBootConfig) (interfaces.IHTTPBootServer, error) {
+	server, err := NewNetHTTPBootServer(config)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to create new net/http boot server")
+	}
+	return server, nil
+}
@@ -2,92 +2,321 @@
 package infrastructure
 
 import (
-	"fmt"
+	"bytes"
 	"github.com/pin/tftp/v3"
 	"io"
+	"net"
 	"os"
+	"path/filepath"
 	"rol/app/errors"
 	"rol/app/interfaces"
+	"rol/app/utils"
 	"rol/domain"
+	"strings"
+	"sync"
+	"time"
 )
 
 //PinTFTPServer TFTP server implementation for ITFTPServer interface
 type PinTFTPServer struct {
-	runtime *tftp.Server
-	config  domain.TFTPConfig
-	paths   *[]domain.TFTPPathRatio
-	state   domain.TFTPServerState
+	runtime   *tftp.Server
+	conn      net.PacketConn
+	serveDone chan struct{}
+	config    domain.TFTPConfig
+	state     domain.TFTPServerState
+	lastError string
+	//runtimeMutex guards runtime and its connection, config, state and last error
+	runtimeMutex sync.Mutex
+	paths        *tftpPathIndex
+	//pathsMutex guards paths index, that is replaced on the fly
+	pathsMutex     sync.RWMutex
+	clientResolver *TFTPClientResolver
+	transfers      *tftpTransferRecorder
+	events         interfaces.IBootEventBus
 }
 
 //NewPinTFTPServer creates new pin tftp server
-func NewPinTFTPServer(config domain.TFTPConfig) (interfaces.ITFTPServer, error) {
+//
+//Params:
+//	config - tftp server config
+//	clientResolver - resolver of the client mac address and templates variables, can be nil
+//	events - bus for the file fetch events, can be nil
+//Return:
+//	interfaces.ITFTPServer - tftp server
+//	error - if an error occurred, otherwise nil
+func NewPinTFTPServer(config domain.TFTPConfig, clientResolver *TFTPClientResolver, events interfaces.IBootEventBus) (interfaces.ITFTPServer, error) {
 	server := &PinTFTPServer{
-		runtime: nil,
-		config:  config,
-		paths:   &[]domain.TFTPPathRatio{},
-		state:   domain.TFTPStateStopped,
-	}
-	server.runtime = tftp.NewServer(
-		func(filename string, rf io.ReaderFrom) error {
-			actualPath := ""
-			for _, ratio := range *server.paths {
-				if ratio.VirtualPath == filename {
-					actualPath = ratio.ActualPath
-				}
-			}
-			if actualPath == "" {
-				return errors.NotFound.New("actual file path is empty")
-			}
-			if _, err := os.Stat(actualPath); err != nil {
-				return errors.NotFound.Wrapf(err, "file %s not found", actualPath)
-			}
-			file, err := os.Open(actualPath)
-			if err != nil {
-				return errors.Internal.Wrapf(err, "filed to open file: %s", actualPath)
-			}
-			_, err = rf.ReadFrom(file)
-			if err != nil {
-				return errors.Internal.Wrapf(err, "failed to read file %s", actualPath)
-			}
-			return nil
-		}, nil,
-	)
+		runtime:        nil,
+		config:         config,
+		paths:          newTFTPPathIndex(nil),
+		state:          domain.TFTPStateStopped,
+		clientResolver: clientResolver,
+		events:         events,
+	}
+	server.transfers = newTFTPTransferRecorder(server.publishFetchEvent)
 	return server, nil
 }
 
-//ReloadConfig for TFTP server
+//publishFetchEvent publishes boot event for the file that is successfully downloaded by the client
+func (s *PinTFTPServer) publishFetchEvent(transfer domain.TFTPTransfer) {
+	if s.events == nil || transfer.IsUpload || transfer.State != domain.TFTPTransferSucceeded {
+		return
+	}
+	clientIP, _, err := net.SplitHostPort(transfer.ClientAddress)
+	if err != nil {
+		return
+	}
+	s.events.Publish(domain.BootEvent{
+		Type:        domain.BootEventTFTPFetch,
+		ClientMAC:   strings.ToLower(s.clientResolver.ResolveMAC(clientIP)),
+		ClientIP:    clientIP,
+		VirtualPath: normalizeVirtualPath(transfer.VirtualPath),
+		Time:        time.Now(),
+	})
+}
+
+//newRuntime creates pin tftp server, it can't be started again after shutdown, so it's created on each start
+func (s *PinTFTPServer) newRuntime() *tftp.Server {
+	runtime := tftp.NewServer(s.readHandler, s.writeHandler)
+	runtime.SetHook(s.transfers)
+	return runtime
+}
+
+func (s *PinTFTPServer) getConfig() domain.TFTPConfig {
+	s.runtimeMutex.Lock()
+	defer s.runtimeMutex.Unlock()
+	return s.config
+}
+
+//readHandler sends file found by the path ratios to the client
+func (s *PinTFTPServer) readHandler(filename string, rf io.ReaderFrom) error {
+	remoteAddr := net.UDPAddr{}
+	if transfer, ok := rf.(tftp.OutgoingTransfer); ok {
+		remoteAddr = transfer.RemoteAddr()
+	}
+	clientIP := ""
+	if remoteAddr.IP != nil {
+		clientIP = remoteAddr.IP.String()
+	}
+	entry, ok := s.lookupPath(filename, clientIP)
+	counter := s.transfers.begin(remoteAddr, filename, entry.actualPath, false)
+	if !ok {
+		return errors.NotFound.Newf("path ratio for file %s is not found", filename)
+	}
+	actualPath := entry.actualPath
+	if info, err := os.Stat(actualPath); err != nil || info.IsDir() {
+		return errors.NotFound.Newf("file %s not found", actualPath)
+	}
+	if entry.isTemplate {
+		return s.sendTemplate(filename, actualPath, clientIP, rf, counter)
+	}
+	file, err := os.Open(actualPath)
+	if err != nil {
+		return errors.Internal.Wrapf(err, "filed to open file: %s", actualPath)
+	}
+	defer file.Close()
+	_, err = rf.ReadFrom(&countingReader{reader: file, counter: counter})
+	if err != nil {
+		return errors.Internal.Wrapf(err, "failed to read file %s", actualPath)
+	}
+	return nil
+}
+
+//defaultTFTPMaxUploadSize max size of the uploaded file in bytes, used if limit is not set in config
+const defaultTFTPMaxUploadSize = 32 << 20
+
+//limitedFileWriter writes to the file until the limit is reached
+type limitedFileWriter struct {
+	file    *os.File
+	limit   int64
+	written int64
+}
+
+func (w *limitedFileWriter) Write(p []byte) (int, error) {
+	if w.written+int64(len(p)) > w.limit {
+		return 0, errors.Internal.Newf("file size exceeds the limit of %d bytes", w.limit)
+	}
+	n, err := w.file.Write(p)
+	w.written += int64(n)
+	return n, err
+}
+
+//writeHandler saves file uploaded by the client to the upload directory.
+//File is written to the temporary file first, so partially uploaded files are never visible
+func (s *PinTFTPServer) writeHandler(filename string, wt io.WriterTo) error {
+	config := s.getConfig()
+	relativePath, ok := utils.SanitizeRelativePath(filename)
+	actualPath := ""
+	if ok && config.UploadDir != "" {
+		actualPath = filepath.Join(config.UploadDir, filepath.FromSlash(relativePath))
+	}
+	remoteAddr := net.UDPAddr{}
+	if transfer, ok := wt.(tftp.IncomingTransfer); ok {
+		remoteAddr = transfer.RemoteAddr()
+	}
+	if !config.WriteEnabled || config.UploadDir == "" {
+		return errors.Internal.New("write is not allowed on this server")
+	}
+	if !ok {
+		return errors.Internal.Newf("incorrect file name: %s", filename)
+	}
+	counter := s.transfers.begin(remoteAddr, filename, actualPath, true)
+	limit := config.MaxUploadSize
+	if limit <= 0 {
+		limit = defaultTFTPMaxUploadSize
+	}
+	if transfer, ok := wt.(tftp.IncomingTransfer); ok {
+		if size, ok := transfer.Size(); ok && size > limit {
+			return errors.Internal.Newf("file size exceeds the limit of %d bytes", limit)
+		}
+	}
+	err := os.MkdirAll(filepath.Dir(actualPath), os.ModePerm)
+	if err != nil {
+		return errors.Internal.Wrapf(err, "failed to create directory for file: %s", actualPath)
+	}
+	tmpFile, err := os.CreateTemp(filepath.Dir(actualPath), ".upload-*")
+	if err != nil {
+		return errors.Internal.Wrapf(err, "failed to create temporary file for: %s", actualPath)
+	}
+	_, err = wt.WriteTo(&countingWriter{writer: &limitedFileWriter{file: tmpFile, limit: limit}, counter: counter})
+	closeErr := tmpFile.Close()
+	if err == nil && closeErr != nil {
+		err = errors.Internal.Wrapf(closeErr, "failed to close file: %s", tmpFile.Name())
+	}
+	if err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return errors.Internal.Wrapf(err, "failed to write file %s", actualPath)
+	}
+	err = os.Rename(tmpFile.Name(), actualPath)
+	if err != nil {
+		_ = os.Remove(tmpFile.Name())
+		return errors.Internal.Wrapf(err, "failed to save file %s", actualPath)
+	}
+	return nil
+}
+
+//ReloadConfig for TFTP server, new address and port are applied on the next start
 func (s *PinTFTPServer) ReloadConfig(config domain.TFTPConfig) error {
+	s.runtimeMutex.Lock()
+	defer s.runtimeMutex.Unlock()
 	s.config = config
 	return nil
 }
 
 //ReloadPaths for TFTP paths server
 func (s *PinTFTPServer) ReloadPaths(paths []domain.TFTPPathRatio) error {
-	s.paths = &paths
+	index := newTFTPPathIndex(paths)
+	s.pathsMutex.Lock()
+	defer s.pathsMutex.Unlock()
+	s.paths = index
 	return nil
 }
 
-//Start TFTP server
+func (s *PinTFTPServer) getPaths() *tftpPathIndex {
+	s.pathsMutex.RLock()
+	defer s.pathsMutex.RUnlock()
+	return s.paths
+}
+
+//lookupPath finds actual path of the requested file for the client
+func (s *PinTFTPServer) lookupPath(filename, clientIP string) (virtualPathEntry, bool) {
+	paths := s.getPaths()
+	clientMAC := ""
+	//mac address is resolved through the repositories, so it's done only if it's needed
+	if clientIP != "" && paths.HasMACScopedPaths() {
+		clientMAC = s.clientResolver.ResolveMAC(clientIP)
+	}
+	return paths.Lookup(filename, clientIP, clientMAC)
+}
+
+//sendTemplate renders templated file with the variables of the requesting client and sends it
+func (s *PinTFTPServer) sendTemplate(filename, actualPath, clientIP string, rf io.ReaderFrom, counter *tftpByteCounter) error {
+	data := s.clientResolver.ResolveTemplateData(filename, clientIP)
+	content, err := renderTFTPTemplate(actualPath, data)
+	if err != nil {
+		return err
+	}
+	if transfer, ok := rf.(tftp.OutgoingTransfer); ok {
+		transfer.SetSize(int64(len(content)))
+	}
+	_, err = rf.ReadFrom(&countingReader{reader: bytes.NewReader(content), counter: counter})
+	if err != nil {
+		return errors.Internal.Wrapf(err, "failed to send rendered file %s", actualPath)
+	}
+	return nil
+}
+
+//Start TFTP server, UDP socket is opened synchronously, so address errors are returned
 func (s *PinTFTPServer) Start() error {
+	s.runtimeMutex.Lock()
+	defer s.runtimeMutex.Unlock()
+	if s.runtime != nil {
+		return nil
+	}
+	conn, err := net.ListenPacket("udp", net.JoinHostPort(s.config.Address, s.config.Port))
+	if err != nil {
+		s.state = domain.TFTPStateError
+		s.lastError = err.Error()
+		return errors.Internal.Wrapf(err, "failed to listen on %s:%s", s.config.Address, s.config.Port)
+	}
+	runtime := s.newRuntime()
+	serveDone := make(chan struct{})
+	s.runtime = runtime
+	s.conn = conn
+	s.serveDone = serveDone
+	s.state = domain.TFTPStateLaunched
+	s.lastError = ""
 	go func() {
-		s.state = domain.TFTPStateLaunched
-		err := s.runtime.ListenAndServe(fmt.Sprintf("%s:%s", s.config.Address, s.config.Port))
+		err := runtime.Serve(conn)
+		close(serveDone)
 		if err != nil {
-			s.state = domain.TFTPStateError
+			s.runtimeMutex.Lock()
+			defer s.runtimeMutex.Unlock()
+			_ = conn.Close()
+			if s.runtime == runtime {
+				s.state = domain.TFTPStateError
+				s.lastError = err.Error()
+				s.runtime = nil
+			}
 		}
 	}()
 	return nil
 }
 
-//Stop TFTP server
+//Stop TFTP server, it waits until outstanding transfers are finished
 func (s *PinTFTPServer) Stop() {
+	s.runtimeMutex.Lock()
+	defer s.runtimeMutex.Unlock()
 	if s.runtime != nil {
+		//shutdown is safe before serving goroutine registers the connection, serving returns right after it
 		s.runtime.Shutdown()
+		_ = s.conn.Close()
+		<-s.serveDone
+		s.runtime = nil
 	}
 	s.state = domain.TFTPStateStopped
 }
 
+//GetTransfers get active and recent finished transfers of the TFTP server, the newest first
+func (s *PinTFTPServer) GetTransfers() []domain.TFTPTransfer {
+	return s.transfers.getTransfers()
+}
+
+//GetPathStats get transfer counters for each requested file of the TFTP server
+func (s *PinTFTPServer) GetPathStats() []domain.TFTPPathStats {
+	return s.transfers.getPathStats()
+}
+
 //GetState from TFTP server
 func (s *PinTFTPServer) GetState() domain.TFTPServerState {
+	s.runtimeMutex.Lock()
+	defer s.runtimeMutex.Unlock()
 	return s.state
 }
+
+//GetLastError get message of the last start or serve error, empty after successful start
+func (s *PinTFTPServer) GetLastError() string {
+	s.runtimeMutex.Lock()
+	defer s.runtimeMutex.Unlock()
+	return s.lastError
+}
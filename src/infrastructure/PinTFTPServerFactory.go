@@ -8,16 +8,29 @@ import (
 )
 
 //PinTFTPServerFactory is implementation for ITFTPServerFactory interface
-type PinTFTPServerFactory struct{}
+type PinTFTPServerFactory struct {
+	clientResolver *TFTPClientResolver
+	events         interfaces.IBootEventBus
+}
 
 //NewPinTFTPServerFactory creates new pin/tftp server factory
-func NewPinTFTPServerFactory() (interfaces.ITFTPServerFactory, error) {
-	return &PinTFTPServerFactory{}, nil
+//
+//Params:
+//	clientResolver - resolver of the tftp client mac address and templates variables, can be nil
+//	events - bus for the file fetch events, can be nil
+//Return:
+//	interfaces.ITFTPServerFactory - tftp server factory
+//	error - if an error occurred, otherwise nil
+func NewPinTFTPServerFactory(clientResolver *TFTPClientResolver, events interfaces.IBootEventBus) (interfaces.ITFTPServerFactory, error) {
+	return &PinTFTPServerFactory{
+		clientResolver: clientResolver,
+		events:         events,
+	}, nil
 }
 
 //Create pin tftp server
 func (f *PinTFTPServerFactory) Create(config domain.TFTPConfig) (interfaces.ITFTPServer, error) {
-	server, err := NewPinTFTPServer(config)
+	server, err := NewPinTFTPServer(config, f.clientResolver, f.events)
 	if err != nil {
 		return nil, errors.Internal.Wrap(err, "failed to create new pin/TFTP server")
 	}
@@ -0,0 +1,871 @@
+package infrastructure
+
+import (
+	"fmt"
+	"github.com/gosnmp/gosnmp"
+	"net"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/domain"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	//snmpDefaultPort default SNMP agent udp port
+	snmpDefaultPort = 161
+	//snmpTimeout timeout of the single SNMP request
+	snmpTimeout = 2 * time.Second
+	//snmpRetries count of the SNMP request retries
+	snmpRetries = 1
+
+	//oidIfName IF-MIB::ifName, interface names indexed by ifIndex
+	oidIfName = ".1.3.6.1.2.1.31.1.1.1.1"
+	//oidDot1dBasePortIfIndex BRIDGE-MIB::dot1dBasePortIfIndex, ifIndex of the bridge port
+	oidDot1dBasePortIfIndex = ".1.3.6.1.2.1.17.1.4.1.2"
+	//oidDot1qVlanStaticEgressPorts Q-BRIDGE-MIB::dot1qVlanStaticEgressPorts, port list of the vlan members
+	oidDot1qVlanStaticEgressPorts = ".1.3.6.1.2.1.17.7.1.4.3.1.2"
+	//oidDot1qVlanStaticUntaggedPorts Q-BRIDGE-MIB::dot1qVlanStaticUntaggedPorts, port list of the untagged vlan members
+	oidDot1qVlanStaticUntaggedPorts = ".1.3.6.1.2.1.17.7.1.4.3.1.4"
+	//oidDot1qVlanStaticRowStatus Q-BRIDGE-MIB::dot1qVlanStaticRowStatus, static vlan row status
+	oidDot1qVlanStaticRowStatus = ".1.3.6.1.2.1.17.7.1.4.3.1.5"
+	//oidDot1qPvid Q-BRIDGE-MIB::dot1qPvid, port vlan id indexed by bridge port
+	oidDot1qPvid = ".1.3.6.1.2.1.17.7.1.4.5.1.1"
+	//oidDot1qMaxSupportedVlans Q-BRIDGE-MIB::dot1qMaxSupportedVlans, max number of vlans on the switch
+	oidDot1qMaxSupportedVlans = ".1.3.6.1.2.1.17.7.1.1.3.0"
+	//oidPethPsePortAdminEnable POWER-ETHERNET-MIB::pethPsePortAdminEnable indexed by group and port
+	oidPethPsePortAdminEnable = ".1.3.6.1.2.1.105.1.1.1.3"
+	//oidPethMainPsePower POWER-ETHERNET-MIB::pethMainPsePower, PSE group power budget in watts
+	oidPethMainPsePower = ".1.3.6.1.2.1.105.1.3.1.1.2"
+	//oidDot1qTpFdbPort Q-BRIDGE-MIB::dot1qTpFdbPort, bridge port of the learned MAC address indexed by fdb id and MAC
+	oidDot1qTpFdbPort = ".1.3.6.1.2.1.17.7.1.2.2.1.2"
+	//oidDot1qVlanFdbID Q-BRIDGE-MIB::dot1qVlanFdbId, fdb id of the vlan indexed by time mark and vlan
+	oidDot1qVlanFdbID = ".1.3.6.1.2.1.17.7.1.4.2.1.3"
+	//oidLldpRemChassisIDSubtype LLDP-MIB::lldpRemChassisIdSubtype indexed by time mark, local port and neighbor index
+	oidLldpRemChassisIDSubtype = ".1.0.8802.1.1.2.1.4.1.1.4"
+	//oidLldpRemChassisID LLDP-MIB::lldpRemChassisId, neighbor chassis ID
+	oidLldpRemChassisID = ".1.0.8802.1.1.2.1.4.1.1.5"
+	//oidLldpRemPortIDSubtype LLDP-MIB::lldpRemPortIdSubtype, neighbor port ID subtype
+	oidLldpRemPortIDSubtype = ".1.0.8802.1.1.2.1.4.1.1.6"
+	//oidLldpRemPortID LLDP-MIB::lldpRemPortId, neighbor port ID
+	oidLldpRemPortID = ".1.0.8802.1.1.2.1.4.1.1.7"
+	//oidLldpRemPortDesc LLDP-MIB::lldpRemPortDesc, neighbor port description
+	oidLldpRemPortDesc = ".1.0.8802.1.1.2.1.4.1.1.8"
+	//oidLldpRemSysName LLDP-MIB::lldpRemSysName, neighbor system name
+	oidLldpRemSysName = ".1.0.8802.1.1.2.1.4.1.1.9"
+	//oidLldpRemManAddrIfSubtype LLDP-MIB::lldpRemManAddrIfSubtype, neighbor management address is the part of the index
+	oidLldpRemManAddrIfSubtype = ".1.0.8802.1.1.2.1.4.2.1.3"
+
+	//snmpPethGroupIndex PSE group index, single group is used for the whole switch
+	snmpPethGroupIndex = 1
+	//snmpRowStatusCreateAndGo RowStatus createAndGo value
+	snmpRowStatusCreateAndGo = 4
+	//snmpRowStatusDestroy RowStatus destroy value
+	snmpRowStatusDestroy = 6
+	//snmpTruthValueTrue TruthValue true value
+	snmpTruthValueTrue = 1
+	//snmpTruthValueFalse TruthValue false value
+	snmpTruthValueFalse = 2
+	//snmpLldpChassisIDSubtypeMAC LldpChassisIdSubtype macAddress value
+	snmpLldpChassisIDSubtypeMAC = 4
+	//snmpLldpPortIDSubtypeMAC LldpPortIdSubtype macAddress value
+	snmpLldpPortIDSubtypeMAC = 3
+	//snmpAddressFamilyIPv4 AddressFamilyNumbers ipV4 value
+	snmpAddressFamilyIPv4 = 1
+)
+
+//SNMPEthernetSwitchManager is a struct for generic ethernet switch management over SNMP,
+//vlans are managed through Q-BRIDGE-MIB and poe through POWER-ETHERNET-MIB
+type SNMPEthernetSwitchManager struct {
+	address      string
+	port         uint16
+	version      string
+	community    string
+	username     string
+	authProtocol gosnmp.SnmpV3AuthProtocol
+	authPassword string
+	privProtocol gosnmp.SnmpV3PrivProtocol
+	privPassword string
+	msgFlags     gosnmp.SnmpV3MsgFlags
+	//capabilities cached switch capabilities, nil until the first successful discovery
+	capabilities      *domain.EthernetSwitchCapabilities
+	capabilitiesMutex sync.Mutex
+}
+
+//snmpEthernetSwitchDriver driver of any switch with Q-BRIDGE-MIB and POWER-ETHERNET-MIB support
+var snmpEthernetSwitchDriver = EthernetSwitchDriver{
+	Models: []domain.EthernetSwitchModel{{
+		Model:        "Generic SNMP switch",
+		Manufacturer: "Generic",
+		Code:         "snmp_generic",
+		Capabilities: []string{domain.EthernetSwitchCapabilityVLAN, domain.EthernetSwitchCapabilityPOE, domain.EthernetSwitchCapabilityLLDP},
+	}},
+	NewManager: func(ethernetSwitch domain.EthernetSwitch) (interfaces.IEthernetSwitchManager, error) {
+		return NewSNMPEthernetSwitchManager(ethernetSwitch), nil
+	},
+	RequiresSNMP: true,
+}
+
+//NewSNMPEthernetSwitchManager constructor for SNMPEthernetSwitchManager
+//
+//Params:
+//	ethernetSwitch - switch entity with the SNMP credentials
+//Return:
+//	interfaces.IEthernetSwitchManager - switch manager
+func NewSNMPEthernetSwitchManager(ethernetSwitch domain.EthernetSwitch) interfaces.IEthernetSwitchManager {
+	port := uint16(snmpDefaultPort)
+	if ethernetSwitch.SNMPPort > 0 {
+		port = uint16(ethernetSwitch.SNMPPort)
+	}
+	manager := &SNMPEthernetSwitchManager{
+		address:      ethernetSwitch.Address,
+		port:         port,
+		version:      ethernetSwitch.SNMPVersion,
+		community:    ethernetSwitch.SNMPCommunity,
+		username:     ethernetSwitch.SNMPUsername,
+		msgFlags:     gosnmp.NoAuthNoPriv,
+		authProtocol: gosnmp.NoAuth,
+		privProtocol: gosnmp.NoPriv,
+	}
+	switch ethernetSwitch.SNMPAuthProtocol {
+	case "md5":
+		manager.authProtocol = gosnmp.MD5
+	case "sha":
+		manager.authProtocol = gosnmp.SHA
+	}
+	if manager.authProtocol != gosnmp.NoAuth {
+		manager.msgFlags = gosnmp.AuthNoPriv
+		//  pragma: allowlist nextline secret
+		manager.authPassword = ethernetSwitch.SNMPAuthPassword
+	}
+	switch ethernetSwitch.SNMPPrivProtocol {
+	case "des":
+		manager.privProtocol = gosnmp.DES
+	case "aes":
+		manager.privProtocol = gosnmp.AES
+	}
+	if manager.privProtocol != gosnmp.NoPriv {
+		manager.msgFlags = gosnmp.AuthPriv
+		//  pragma: allowlist nextline secret
+		manager.privPassword = ethernetSwitch.SNMPPrivPassword
+	}
+	return manager
+}
+
+//connect creates SNMP client connected to the switch agent
+func (s *SNMPEthernetSwitchManager) connect() (*gosnmp.GoSNMP, error) {
+	client := &gosnmp.GoSNMP{
+		Target:             s.address,
+		Port:               s.port,
+		Transport:          "udp",
+		Timeout:            snmpTimeout,
+		Retries:            snmpRetries,
+		MaxOids:            gosnmp.MaxOids,
+		ExponentialTimeout: true,
+	}
+	if s.version == "3" {
+		client.Version = gosnmp.Version3
+		client.SecurityModel = gosnmp.UserSecurityModel
+		client.MsgFlags = s.msgFlags
+		client.SecurityParameters = &gosnmp.UsmSecurityParameters{
+			UserName:                 s.username,
+			AuthenticationProtocol:   s.authProtocol,
+			AuthenticationPassphrase: s.authPassword,
+			PrivacyProtocol:          s.privProtocol,
+			PrivacyPassphrase:        s.privPassword,
+		}
+	} else {
+		client.Version = gosnmp.Version2c
+		client.Community = s.community
+	}
+	err := client.Connect()
+	if err != nil {
+		return nil, errors.Internal.Wrapf(err, "error creating snmp connection to %s", s.getSNMPAddress())
+	}
+	return client, nil
+}
+
+func closeSNMPConnection(client *gosnmp.GoSNMP) {
+	if client.Conn != nil {
+		_ = client.Conn.Close()
+	}
+}
+
+//set sends SNMP set request and checks the response error status
+func (s *SNMPEthernetSwitchManager) set(pdus ...gosnmp.SnmpPDU) error {
+	client, err := s.connect()
+	if err != nil {
+		return err
+	}
+	defer closeSNMPConnection(client)
+	result, err := client.Set(pdus)
+	if err != nil {
+		return errors.Internal.Wrap(err, "snmp set request failed")
+	}
+	if result.Error != gosnmp.NoError {
+		return errors.Internal.Newf("snmp set request failed with status %s", result.Error.String())
+	}
+	return nil
+}
+
+//walk gets all values of the table column, result key is the oid suffix after the column oid
+func (s *SNMPEthernetSwitchManager) walk(client *gosnmp.GoSNMP, columnOID string) (map[string]gosnmp.SnmpPDU, error) {
+	out := map[string]gosnmp.SnmpPDU{}
+	pdus, err := client.WalkAll(columnOID)
+	if err != nil {
+		return nil, errors.Internal.Wrapf(err, "snmp walk of %s failed", columnOID)
+	}
+	for _, pdu := range pdus {
+		name := "." + strings.TrimPrefix(pdu.Name, ".")
+		if !strings.HasPrefix(name, columnOID+".") {
+			continue
+		}
+		out[strings.TrimPrefix(name, columnOID+".")] = pdu
+	}
+	return out, nil
+}
+
+//getBridgePort resolves bridge port number of the port by its interface name
+func (s *SNMPEthernetSwitchManager) getBridgePort(client *gosnmp.GoSNMP, portName string) (int, error) {
+	names, err := s.walk(client, oidIfName)
+	if err != nil {
+		return 0, err
+	}
+	ifIndex := ""
+	for index, pdu := range names {
+		if snmpPDUToString(pdu) == portName {
+			ifIndex = index
+			break
+		}
+	}
+	if ifIndex == "" {
+		return 0, errors.Internal.Newf("port %s is not found on switch", portName)
+	}
+	bridgePorts, err := s.walk(client, oidDot1dBasePortIfIndex)
+	if err != nil {
+		return 0, err
+	}
+	for bridgePort, pdu := range bridgePorts {
+		if strconv.FormatInt(gosnmp.ToBigInt(pdu.Value).Int64(), 10) != ifIndex {
+			continue
+		}
+		number, err := strconv.Atoi(bridgePort)
+		if err != nil {
+			return 0, errors.Internal.Wrap(err, "error convert string to int")
+		}
+		return number, nil
+	}
+	return 0, errors.Internal.Newf("port %s is not a bridge port", portName)
+}
+
+//getBridgePortsNames gets interface names of the bridge ports by bridge port number
+func (s *SNMPEthernetSwitchManager) getBridgePortsNames(client *gosnmp.GoSNMP) (map[int]string, error) {
+	names, err := s.walk(client, oidIfName)
+	if err != nil {
+		return nil, err
+	}
+	bridgePorts, err := s.walk(client, oidDot1dBasePortIfIndex)
+	if err != nil {
+		return nil, err
+	}
+	out := map[int]string{}
+	for bridgePort, pdu := range bridgePorts {
+		number, err := strconv.Atoi(bridgePort)
+		if err != nil {
+			return nil, errors.Internal.Wrap(err, "error convert string to int")
+		}
+		name, ok := names[gosnmp.ToBigInt(pdu.Value).String()]
+		if !ok {
+			continue
+		}
+		out[number] = snmpPDUToString(name)
+	}
+	return out, nil
+}
+
+//getVLANPorts gets port list of the vlan from the given Q-BRIDGE-MIB column
+func (s *SNMPEthernetSwitchManager) getVLANPorts(client *gosnmp.GoSNMP, columnOID string, vlanID int) ([]byte, error) {
+	result, err := client.Get([]string{fmt.Sprintf("%s.%d", columnOID, vlanID)})
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "snmp get request failed")
+	}
+	if len(result.Variables) == 0 || result.Variables[0].Type != gosnmp.OctetString {
+		return nil, errors.Internal.Newf("vlan %d is not found on switch", vlanID)
+	}
+	ports, _ := result.Variables[0].Value.([]byte)
+	return ports, nil
+}
+
+//snmpPDUToString converts octet string value to string, empty string if the value is missing
+func snmpPDUToString(pdu gosnmp.SnmpPDU) string {
+	if value, ok := pdu.Value.([]byte); ok {
+		return string(value)
+	}
+	if pdu.Value == nil {
+		return ""
+	}
+	return fmt.Sprint(pdu.Value)
+}
+
+//portListContains checks that Q-BRIDGE-MIB PortList contains the bridge port, the first octet bit 7 is the port 1
+func portListContains(ports []byte, bridgePort int) bool {
+	octet := (bridgePort - 1) / 8
+	if bridgePort < 1 || octet >= len(ports) {
+		return false
+	}
+	return ports[octet]&(0x80>>uint((bridgePort-1)%8)) != 0
+}
+
+//setPortListMember adds the bridge port to the Q-BRIDGE-MIB PortList or removes it, list is expanded if needed
+func setPortListMember(ports []byte, bridgePort int, member bool) []byte {
+	octet := (bridgePort - 1) / 8
+	out := make([]byte, len(ports))
+	copy(out, ports)
+	for len(out) <= octet {
+		out = append(out, 0)
+	}
+	mask := byte(0x80 >> uint((bridgePort-1)%8))
+	if member {
+		out[octet] |= mask
+	} else {
+		out[octet] &^= mask
+	}
+	return out
+}
+
+//GetVLANs gets all VLANs on switch
+//
+//Return:
+//	[]int - slice of VLANs
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) GetVLANs() ([]int, error) {
+	client, err := s.connect()
+	if err != nil {
+		return []int{}, err
+	}
+	defer closeSNMPConnection(client)
+	rows, err := s.walk(client, oidDot1qVlanStaticRowStatus)
+	if err != nil {
+		return []int{}, err
+	}
+	out := []int{}
+	for index := range rows {
+		id, err := strconv.Atoi(index)
+		if err != nil {
+			return nil, errors.Internal.Wrap(err, "error convert string to int")
+		}
+		out = append(out, id)
+	}
+	sort.Ints(out)
+	return out, nil
+}
+
+//GetVLANsOnPort gets all VLANs on given port
+//
+//Params:
+//	portName - port name
+//Return:
+//	int - untagged VLAN ID
+//	[]int - slice of tagged VLANs IDs
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) GetVLANsOnPort(portName string) (int, []int, error) {
+	client, err := s.connect()
+	if err != nil {
+		return 0, []int{}, err
+	}
+	defer closeSNMPConnection(client)
+	bridgePort, err := s.getBridgePort(client, portName)
+	if err != nil {
+		return 0, []int{}, err
+	}
+	egress, err := s.walk(client, oidDot1qVlanStaticEgressPorts)
+	if err != nil {
+		return 0, []int{}, err
+	}
+	untagged, err := s.walk(client, oidDot1qVlanStaticUntaggedPorts)
+	if err != nil {
+		return 0, []int{}, err
+	}
+	untaggedVLAN := 0
+	taggedVLANs := []int{}
+	for index, pdu := range egress {
+		ports, _ := pdu.Value.([]byte)
+		if !portListContains(ports, bridgePort) {
+			continue
+		}
+		id, err := strconv.Atoi(index)
+		if err != nil {
+			return 0, nil, errors.Internal.Wrap(err, "error convert string to int")
+		}
+		untaggedPorts, _ := untagged[index].Value.([]byte)
+		if portListContains(untaggedPorts, bridgePort) {
+			untaggedVLAN = id
+			continue
+		}
+		taggedVLANs = append(taggedVLANs, id)
+	}
+	sort.Ints(taggedVLANs)
+	return untaggedVLAN, taggedVLANs, nil
+}
+
+//GetPortPVID gets port PVID
+//
+//Params:
+//	portName - port name
+//Return:
+//	int - PVID
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) GetPortPVID(portName string) (int, error) {
+	client, err := s.connect()
+	if err != nil {
+		return 0, err
+	}
+	defer closeSNMPConnection(client)
+	bridgePort, err := s.getBridgePort(client, portName)
+	if err != nil {
+		return 0, err
+	}
+	return s.getScalar(client, fmt.Sprintf("%s.%d", oidDot1qPvid, bridgePort))
+}
+
+//setVLANPortMembership sets membership of the port in the vlan egress and untagged port lists
+func (s *SNMPEthernetSwitchManager) setVLANPortMembership(portName string, vlanID int, egress, untagged bool) error {
+	client, err := s.connect()
+	if err != nil {
+		return err
+	}
+	defer closeSNMPConnection(client)
+	bridgePort, err := s.getBridgePort(client, portName)
+	if err != nil {
+		return err
+	}
+	egressPorts, err := s.getVLANPorts(client, oidDot1qVlanStaticEgressPorts, vlanID)
+	if err != nil {
+		return err
+	}
+	untaggedPorts, err := s.getVLANPorts(client, oidDot1qVlanStaticUntaggedPorts, vlanID)
+	if err != nil {
+		return err
+	}
+	return s.set(
+		gosnmp.SnmpPDU{
+			Name:  fmt.Sprintf("%s.%d", oidDot1qVlanStaticEgressPorts, vlanID),
+			Type:  gosnmp.OctetString,
+			Value: setPortListMember(egressPorts, bridgePort, egress),
+		},
+		gosnmp.SnmpPDU{
+			Name:  fmt.Sprintf("%s.%d", oidDot1qVlanStaticUntaggedPorts, vlanID),
+			Type:  gosnmp.OctetString,
+			Value: setPortListMember(untaggedPorts, bridgePort, untagged),
+		},
+	)
+}
+
+//AddTaggedVLANOnPort add tagged VLAN on given port
+//
+//Params:
+//	portName - port name
+//	vlanID - vlan ID
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) AddTaggedVLANOnPort(portName string, vlanID int) error {
+	return s.setVLANPortMembership(portName, vlanID, true, false)
+}
+
+//AddUntaggedVLANOnPort add untagged VLAN on given port
+//
+//Params:
+//	portName - port name
+//	vlanID - vlan ID
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) AddUntaggedVLANOnPort(portName string, vlanID int) error {
+	return s.setVLANPortMembership(portName, vlanID, true, true)
+}
+
+//RemoveVLANFromPort remove VLAN from given port
+//
+//Params:
+//	portName - name of port
+//	vlanID	- vlan ID
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) RemoveVLANFromPort(portName string, vlanID int) error {
+	return s.setVLANPortMembership(portName, vlanID, false, false)
+}
+
+//SetPortPVID sets port PVID
+//
+//Params:
+//	portName - port name
+//	vlanID - vlan ID
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) SetPortPVID(portName string, vlanID int) error {
+	client, err := s.connect()
+	if err != nil {
+		return err
+	}
+	bridgePort, err := s.getBridgePort(client, portName)
+	closeSNMPConnection(client)
+	if err != nil {
+		return err
+	}
+	return s.set(gosnmp.SnmpPDU{
+		Name:  fmt.Sprintf("%s.%d", oidDot1qPvid, bridgePort),
+		Type:  gosnmp.Gauge32,
+		Value: uint(vlanID),
+	})
+}
+
+//DeleteVLAN delete VLAN by id
+//
+//Params:
+//	vlanID - vlan ID
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) DeleteVLAN(vlanID int) error {
+	return s.set(gosnmp.SnmpPDU{
+		Name:  fmt.Sprintf("%s.%d", oidDot1qVlanStaticRowStatus, vlanID),
+		Type:  gosnmp.Integer,
+		Value: snmpRowStatusDestroy,
+	})
+}
+
+//CreateVLAN create vlan on switch
+//
+//Params:
+//	vlanID	- vlan ID
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) CreateVLAN(vlanID int) error {
+	return s.set(gosnmp.SnmpPDU{
+		Name:  fmt.Sprintf("%s.%d", oidDot1qVlanStaticRowStatus, vlanID),
+		Type:  gosnmp.Integer,
+		Value: snmpRowStatusCreateAndGo,
+	})
+}
+
+//ApplyOperations applies operations one by one, every operation is a separate snmp request
+//
+//Params:
+//	operations - switch configuration changes
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) ApplyOperations(operations []domain.EthernetSwitchOperation) error {
+	return applyEthernetSwitchOperations(s, operations)
+}
+
+//getPOEPortOID gets pethPsePortAdminEnable oid of the port, pse port index is the bridge port number
+func (s *SNMPEthernetSwitchManager) getPOEPortOID(client *gosnmp.GoSNMP, portName string) (string, error) {
+	bridgePort, err := s.getBridgePort(client, portName)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s.%d.%d", oidPethPsePortAdminEnable, snmpPethGroupIndex, bridgePort), nil
+}
+
+//GetPOEPortStatus gets poe status on given port
+//
+//Params:
+//	portName - port name
+//Return:
+//	string - poe port status "enable" or "disable"
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) GetPOEPortStatus(portName string) (string, error) {
+	client, err := s.connect()
+	if err != nil {
+		return "", err
+	}
+	defer closeSNMPConnection(client)
+	oid, err := s.getPOEPortOID(client, portName)
+	if err != nil {
+		return "", err
+	}
+	result, err := client.Get([]string{oid})
+	if err != nil {
+		return "", errors.Internal.Wrap(err, "snmp get request failed")
+	}
+	if len(result.Variables) == 0 || result.Variables[0].Type != gosnmp.Integer {
+		return "", errors.Internal.Newf("port %s does not support poe", portName)
+	}
+	if gosnmp.ToBigInt(result.Variables[0].Value).Int64() == snmpTruthValueTrue {
+		return "enable", nil
+	}
+	return "disable", nil
+}
+
+//EnablePOEPort enable poe on give port
+//
+//Params:
+//	portName - port name
+//	poeType - poe type: "poe", "poe+" etc, power class is negotiated by the switch
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) EnablePOEPort(portName, poeType string) error {
+	return s.setPOEPortAdminEnable(portName, snmpTruthValueTrue)
+}
+
+//DisablePOEPort disable poe on given port
+//
+//Params:
+//	portName - port name
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) DisablePOEPort(portName string) error {
+	return s.setPOEPortAdminEnable(portName, snmpTruthValueFalse)
+}
+
+func (s *SNMPEthernetSwitchManager) setPOEPortAdminEnable(portName string, value int) error {
+	client, err := s.connect()
+	if err != nil {
+		return err
+	}
+	oid, err := s.getPOEPortOID(client, portName)
+	closeSNMPConnection(client)
+	if err != nil {
+		return err
+	}
+	return s.set(gosnmp.SnmpPDU{Name: oid, Type: gosnmp.Integer, Value: value})
+}
+
+//getScalar gets integer value of the scalar object, 0 if the agent does not implement it
+func (s *SNMPEthernetSwitchManager) getScalar(client *gosnmp.GoSNMP, oid string) (int, error) {
+	result, err := client.Get([]string{oid})
+	if err != nil {
+		return 0, errors.Internal.Wrap(err, "snmp get request failed")
+	}
+	if len(result.Variables) == 0 {
+		return 0, nil
+	}
+	switch result.Variables[0].Type {
+	case gosnmp.Integer, gosnmp.Gauge32, gosnmp.Counter32, gosnmp.Uinteger32:
+		return int(gosnmp.ToBigInt(result.Variables[0].Value).Int64()), nil
+	}
+	return 0, nil
+}
+
+//GetCapabilities gets features of the switch: bridge ports from BRIDGE-MIB, max vlans from Q-BRIDGE-MIB
+//and PoE ports with the power budget from POWER-ETHERNET-MIB. Result is cached for the manager lifetime
+//
+//Return:
+//	domain.EthernetSwitchCapabilities - switch capabilities
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) GetCapabilities() (domain.EthernetSwitchCapabilities, error) {
+	s.capabilitiesMutex.Lock()
+	defer s.capabilitiesMutex.Unlock()
+	if s.capabilities != nil {
+		return *s.capabilities, nil
+	}
+	capabilities := domain.EthernetSwitchCapabilities{}
+	client, err := s.connect()
+	if err != nil {
+		return capabilities, err
+	}
+	defer closeSNMPConnection(client)
+	portsNames, err := s.getBridgePortsNames(client)
+	if err != nil {
+		return capabilities, err
+	}
+	poePorts, err := s.walk(client, fmt.Sprintf("%s.%d", oidPethPsePortAdminEnable, snmpPethGroupIndex))
+	if err != nil {
+		return capabilities, err
+	}
+	numbers := []int{}
+	for number := range portsNames {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	for _, number := range numbers {
+		portName := portsNames[number]
+		capabilities.Ports = append(capabilities.Ports, portName)
+		if _, ok := poePorts[strconv.Itoa(number)]; ok {
+			capabilities.POEPorts = append(capabilities.POEPorts, portName)
+		}
+	}
+	if len(capabilities.POEPorts) > 0 {
+		capabilities.POETypes = []string{"poe", "poe+"}
+		capabilities.POEBudget, err = s.getScalar(client, fmt.Sprintf("%s.%d", oidPethMainPsePower, snmpPethGroupIndex))
+		if err != nil {
+			return capabilities, err
+		}
+	}
+	capabilities.MaxVLANs, err = s.getScalar(client, oidDot1qMaxSupportedVlans)
+	if err != nil {
+		return capabilities, err
+	}
+	s.capabilities = &capabilities
+	return capabilities, nil
+}
+
+//getFdbVLANs gets VLAN IDs by filtering database ID, fdb ID equals VLAN ID on the most switches
+func (s *SNMPEthernetSwitchManager) getFdbVLANs(client *gosnmp.GoSNMP) (map[string]int, error) {
+	fdbIDs, err := s.walk(client, oidDot1qVlanFdbID)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]int{}
+	for index, pdu := range fdbIDs {
+		//index is time mark and vlan ID
+		parts := strings.Split(index, ".")
+		vlanID, err := strconv.Atoi(parts[len(parts)-1])
+		if err != nil {
+			return nil, errors.Internal.Wrap(err, "error convert string to int")
+		}
+		out[gosnmp.ToBigInt(pdu.Value).String()] = vlanID
+	}
+	return out, nil
+}
+
+//GetMACAddressTable gets MAC addresses learned on the switch ports from Q-BRIDGE-MIB forwarding database,
+//addresses that are not learned on the bridge ports are skipped
+//
+//Return:
+//	[]domain.EthernetSwitchMACAddress - MAC address forwarding table entries
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) GetMACAddressTable() ([]domain.EthernetSwitchMACAddress, error) {
+	client, err := s.connect()
+	if err != nil {
+		return []domain.EthernetSwitchMACAddress{}, err
+	}
+	defer closeSNMPConnection(client)
+	portsNames, err := s.getBridgePortsNames(client)
+	if err != nil {
+		return []domain.EthernetSwitchMACAddress{}, err
+	}
+	fdbVLANs, err := s.getFdbVLANs(client)
+	if err != nil {
+		return []domain.EthernetSwitchMACAddress{}, err
+	}
+	entries, err := s.walk(client, oidDot1qTpFdbPort)
+	if err != nil {
+		return []domain.EthernetSwitchMACAddress{}, err
+	}
+	table := []domain.EthernetSwitchMACAddress{}
+	for index, pdu := range entries {
+		//index is fdb ID and 6 octets of the MAC address
+		parts := strings.Split(index, ".")
+		if len(parts) != 7 {
+			continue
+		}
+		portName, ok := portsNames[int(gosnmp.ToBigInt(pdu.Value).Int64())]
+		if !ok {
+			continue
+		}
+		mac := make(net.HardwareAddr, 6)
+		for i, part := range parts[1:] {
+			octet, err := strconv.Atoi(part)
+			if err != nil {
+				return nil, errors.Internal.Wrap(err, "error convert string to int")
+			}
+			mac[i] = byte(octet)
+		}
+		vlanID, ok := fdbVLANs[parts[0]]
+		if !ok {
+			vlanID, _ = strconv.Atoi(parts[0])
+		}
+		table = append(table, domain.EthernetSwitchMACAddress{MAC: mac.String(), VLANID: vlanID, PortName: portName})
+	}
+	sortMACAddressTable(table)
+	return table, nil
+}
+
+//formatSNMPLLDPID formats LLDP chassis or port ID, ID with MAC address subtype is converted to 00:11:22:33:44:55 format
+func formatSNMPLLDPID(id, subtype gosnmp.SnmpPDU, macSubtype int) string {
+	value, _ := id.Value.([]byte)
+	if gosnmp.ToBigInt(subtype.Value).Int64() == int64(macSubtype) && len(value) == 6 {
+		return net.HardwareAddr(value).String()
+	}
+	return snmpPDUToString(id)
+}
+
+//getLLDPManagementAddresses gets IPv4 management addresses of the LLDP neighbors by neighbor index
+func (s *SNMPEthernetSwitchManager) getLLDPManagementAddresses(client *gosnmp.GoSNMP) (map[string]string, error) {
+	rows, err := s.walk(client, oidLldpRemManAddrIfSubtype)
+	if err != nil {
+		return nil, err
+	}
+	out := map[string]string{}
+	for index := range rows {
+		//index is time mark, local port, neighbor index, address subtype, address length and address octets
+		parts := strings.Split(index, ".")
+		if len(parts) != 9 || parts[3] != strconv.Itoa(snmpAddressFamilyIPv4) || parts[4] != "4" {
+			continue
+		}
+		out[strings.Join(parts[:3], ".")] = strings.Join(parts[5:], ".")
+	}
+	return out, nil
+}
+
+//GetLLDPNeighbors gets LLDP neighbors of the switch ports from LLDP-MIB, LLDP local port number
+//is the bridge port number
+//
+//Return:
+//	[]domain.EthernetSwitchLLDPNeighbor - neighbors of all ports
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) GetLLDPNeighbors() ([]domain.EthernetSwitchLLDPNeighbor, error) {
+	client, err := s.connect()
+	if err != nil {
+		return []domain.EthernetSwitchLLDPNeighbor{}, err
+	}
+	defer closeSNMPConnection(client)
+	portsNames, err := s.getBridgePortsNames(client)
+	if err != nil {
+		return []domain.EthernetSwitchLLDPNeighbor{}, err
+	}
+	columns := map[string]map[string]gosnmp.SnmpPDU{}
+	for _, columnOID := range []string{oidLldpRemChassisIDSubtype, oidLldpRemChassisID, oidLldpRemPortIDSubtype,
+		oidLldpRemPortID, oidLldpRemPortDesc, oidLldpRemSysName} {
+		columns[columnOID], err = s.walk(client, columnOID)
+		if err != nil {
+			return []domain.EthernetSwitchLLDPNeighbor{}, err
+		}
+	}
+	addresses, err := s.getLLDPManagementAddresses(client)
+	if err != nil {
+		return []domain.EthernetSwitchLLDPNeighbor{}, err
+	}
+	neighbors := []domain.EthernetSwitchLLDPNeighbor{}
+	for index, chassisID := range columns[oidLldpRemChassisID] {
+		//index is time mark, local port and neighbor index
+		parts := strings.Split(index, ".")
+		if len(parts) != 3 {
+			continue
+		}
+		localPort, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, errors.Internal.Wrap(err, "error convert string to int")
+		}
+		portName, ok := portsNames[localPort]
+		if !ok {
+			continue
+		}
+		neighbors = append(neighbors, domain.EthernetSwitchLLDPNeighbor{
+			PortName:          portName,
+			ChassisID:         formatSNMPLLDPID(chassisID, columns[oidLldpRemChassisIDSubtype][index], snmpLldpChassisIDSubtypeMAC),
+			PortID:            formatSNMPLLDPID(columns[oidLldpRemPortID][index], columns[oidLldpRemPortIDSubtype][index], snmpLldpPortIDSubtypeMAC),
+			PortDescription:   snmpPDUToString(columns[oidLldpRemPortDesc][index]),
+			SystemName:        snmpPDUToString(columns[oidLldpRemSysName][index]),
+			ManagementAddress: addresses[index],
+		})
+	}
+	sortLLDPNeighbors(neighbors)
+	return neighbors, nil
+}
+
+//SaveConfig save current settings on switch. There is no standard MIB for it,
+//so it's expected that the switch agent persists the set requests by itself
+//
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SNMPEthernetSwitchManager) SaveConfig() error {
+	return nil
+}
+
+//getSNMPAddress formats agent address for logs and errors
+func (s *SNMPEthernetSwitchManager) getSNMPAddress() string {
+	return net.JoinHostPort(s.address, strconv.Itoa(int(s.port)))
+}
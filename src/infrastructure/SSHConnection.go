@@ -0,0 +1,171 @@
+package infrastructure
+
+import (
+	"golang.org/x/crypto/ssh"
+	"io"
+	"net"
+	"rol/app/errors"
+	"time"
+)
+
+//sshConnectTimeout timeout of the tcp connection and ssh handshake
+const sshConnectTimeout = 10 * time.Second
+
+//SSHConnection structure for ssh connection to the switch CLI, the remote shell is used like a telnet session
+type SSHConnection struct {
+	client  *ssh.Client
+	session *ssh.Session
+	stdin   io.Writer
+	output  *cliOutputReader
+	//username ssh user name
+	username string
+	//password ssh user password, used for password and keyboard-interactive authentication
+	password string
+	//hostKeyFingerprint pinned SHA256 fingerprint of the server host key
+	hostKeyFingerprint string
+}
+
+//NewSSHConnection constructor for SSHConnection
+//
+//Params:
+//	username - ssh user name
+//	password - ssh user password
+//	hostKeyFingerprint - pinned SHA256 fingerprint of the server host key, for example SHA256:Ajq1...
+//Return:
+//	*SSHConnection - ssh connection
+func NewSSHConnection(username, password, hostKeyFingerprint string) *SSHConnection {
+	return &SSHConnection{
+		username: username,
+		//  pragma: allowlist nextline secret
+		password:           password,
+		hostKeyFingerprint: hostKeyFingerprint,
+	}
+}
+
+//checkHostKey rejects the server if its host key does not match the pinned fingerprint
+func (s *SSHConnection) checkHostKey(hostname string, remote net.Addr, key ssh.PublicKey) error {
+	if s.hostKeyFingerprint == "" {
+		return errors.Internal.New("ssh host key fingerprint is not pinned")
+	}
+	fingerprint := ssh.FingerprintSHA256(key)
+	if fingerprint != s.hostKeyFingerprint {
+		return errors.Internal.Newf("ssh host key fingerprint mismatch: expected %s, got %s", s.hostKeyFingerprint, fingerprint)
+	}
+	return nil
+}
+
+//keyboardInteractive answers all keyboard-interactive questions with the password
+func (s *SSHConnection) keyboardInteractive(user, instruction string, questions []string, echos []bool) ([]string, error) {
+	answers := make([]string, len(questions))
+	for i := range answers {
+		answers[i] = s.password
+	}
+	return answers, nil
+}
+
+//Close closes ssh session and connection if they are opened
+//
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SSHConnection) Close() error {
+	if s.client == nil {
+		return nil
+	}
+	err := s.client.Close()
+	s.client = nil
+	s.session = nil
+	if err != nil {
+		return errors.Internal.Wrap(err, "error closing ssh connection")
+	}
+	return nil
+}
+
+//Connect makes a connection with ssh server and starts interactive shell, previous connection is closed
+//
+//Params:
+//	address - ssh server address
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SSHConnection) Connect(address string) error {
+	_ = s.Close()
+	config := &ssh.ClientConfig{
+		User: s.username,
+		Auth: []ssh.AuthMethod{
+			ssh.Password(s.password),
+			ssh.KeyboardInteractive(s.keyboardInteractive),
+		},
+		HostKeyCallback: s.checkHostKey,
+		Timeout:         sshConnectTimeout,
+	}
+	client, err := ssh.Dial("tcp", address, config)
+	if err != nil {
+		return errors.Internal.Wrap(err, "error connecting to ssh server")
+	}
+	session, err := client.NewSession()
+	if err != nil {
+		_ = client.Close()
+		return errors.Internal.Wrap(err, "error opening ssh session")
+	}
+	s.client = client
+	s.session = session
+	s.stdin, err = session.StdinPipe()
+	if err != nil {
+		_ = s.Close()
+		return errors.Internal.Wrap(err, "error opening ssh session stdin")
+	}
+	stdout, err := session.StdoutPipe()
+	if err != nil {
+		_ = s.Close()
+		return errors.Internal.Wrap(err, "error opening ssh session stdout")
+	}
+	s.output = newCLIOutputReader(stdout)
+	modes := ssh.TerminalModes{
+		ssh.ECHO: 0,
+	}
+	err = session.RequestPty("vt100", 0, 200, modes)
+	if err != nil {
+		_ = s.Close()
+		return errors.Internal.Wrap(err, "error requesting ssh pty")
+	}
+	err = session.Shell()
+	if err != nil {
+		_ = s.Close()
+		return errors.Internal.Wrap(err, "error starting ssh shell")
+	}
+	return nil
+}
+
+//Read reads all output lines before expect word
+//
+//Params:
+//	expect - the word to which you want to read lines
+//Return:
+//	string - shell output
+//	error - if an error occurs, otherwise nil
+func (s *SSHConnection) Read(expect string) (string, error) {
+	if s.session == nil {
+		return "", errors.Internal.New("ssh connection is not established")
+	}
+	out, err := s.output.readUntil(expect)
+	if err != nil {
+		return "", errors.Internal.Wrap(err, "error reading from ssh server")
+	}
+	return out, nil
+}
+
+//Send sends command to ssh shell, the command is terminated by CRLF like in telnet session
+//
+//Params:
+//	command - command to send
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SSHConnection) Send(command string) error {
+	if s.session == nil {
+		return errors.Internal.New("ssh connection is not established")
+	}
+	_, err := s.stdin.Write([]byte(command + "\r\n"))
+	if err != nil {
+		return errors.Internal.Wrap(err, "error sending command to ssh server")
+	}
+	return nil
+}
@@ -0,0 +1,843 @@
+package infrastructure
+
+import (
+	"fmt"
+	"github.com/google/uuid"
+	"net"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/utils"
+	"rol/domain"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	//simulatedEthernetSwitchPortsCount number of the simulated switch ports, all of them supply PoE
+	simulatedEthernetSwitchPortsCount = 24
+	//simulatedEthernetSwitchPOEBudget simulated switch PoE power budget in watts
+	simulatedEthernetSwitchPOEBudget = 370
+	//simulatedEthernetSwitchMaxVLANs max number of VLANs on the simulated switch
+	simulatedEthernetSwitchMaxVLANs = 4094
+	//simulatedEthernetSwitchDefaultVLAN default VLAN, it can't be deleted
+	simulatedEthernetSwitchDefaultVLAN = 1
+)
+
+//simulatedVLAN members of the simulated switch VLAN
+type simulatedVLAN struct {
+	tagged   map[string]bool
+	untagged map[string]bool
+}
+
+//SimulatedEthernetSwitch in-memory state of the simulated switch: VLANs, ports PVID and PoE, learned MAC addresses
+//and LLDP neighbors. Ports are named like TP-Link ports: gi1/0/1, gi1/0/2 and so on, all ports are untagged members of VLAN 1 initially
+type SimulatedEthernetSwitch struct {
+	mutex      sync.RWMutex
+	ports      []string
+	poePorts   []string
+	vlans      map[int]*simulatedVLAN
+	pvids      map[string]int
+	poeEnabled map[string]bool
+	//savesCount count of the saved configurations
+	savesCount int
+	//macAddresses MAC address forwarding table
+	macAddresses []domain.EthernetSwitchMACAddress
+	//lldpNeighbors LLDP neighbors of the ports
+	lldpNeighbors []domain.EthernetSwitchLLDPNeighbor
+}
+
+//NewSimulatedEthernetSwitch constructor for SimulatedEthernetSwitch
+//
+//Params:
+//	portsCount - number of the switch ports
+//	poePortsCount - number of the first switch ports with PoE
+//Return:
+//	*SimulatedEthernetSwitch - simulated switch
+func NewSimulatedEthernetSwitch(portsCount, poePortsCount int) *SimulatedEthernetSwitch {
+	simulator := &SimulatedEthernetSwitch{
+		vlans:      map[int]*simulatedVLAN{},
+		pvids:      map[string]int{},
+		poeEnabled: map[string]bool{},
+	}
+	defaultVLAN := &simulatedVLAN{tagged: map[string]bool{}, untagged: map[string]bool{}}
+	for port := 1; port <= portsCount; port++ {
+		portName := fmt.Sprintf("gi1/0/%d", port)
+		simulator.ports = append(simulator.ports, portName)
+		if port <= poePortsCount {
+			simulator.poePorts = append(simulator.poePorts, portName)
+		}
+		simulator.pvids[portName] = simulatedEthernetSwitchDefaultVLAN
+		defaultVLAN.untagged[portName] = true
+	}
+	simulator.vlans[simulatedEthernetSwitchDefaultVLAN] = defaultVLAN
+	return simulator
+}
+
+func (s *SimulatedEthernetSwitch) checkPort(portName string) error {
+	if !utils.SliceContainsElement(s.ports, portName) {
+		return errors.NotFound.Newf("port %s is not found", portName)
+	}
+	return nil
+}
+
+func (s *SimulatedEthernetSwitch) getVLAN(vlanID int) (*simulatedVLAN, error) {
+	vlan, ok := s.vlans[vlanID]
+	if !ok {
+		return nil, errors.NotFound.Newf("vlan %d is not found", vlanID)
+	}
+	return vlan, nil
+}
+
+//GetPorts gets names of the switch ports
+//
+//Return:
+//	[]string - ports names
+func (s *SimulatedEthernetSwitch) GetPorts() []string {
+	return append([]string{}, s.ports...)
+}
+
+//GetVLANs gets sorted IDs of the switch VLANs
+//
+//Return:
+//	[]int - VLANs IDs
+func (s *SimulatedEthernetSwitch) GetVLANs() []int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	vlans := make([]int, 0, len(s.vlans))
+	for vlanID := range s.vlans {
+		vlans = append(vlans, vlanID)
+	}
+	sort.Ints(vlans)
+	return vlans
+}
+
+//GetVLANPorts gets members of the VLAN
+//
+//Params:
+//	vlanID - VLAN ID
+//Return:
+//	[]string - tagged ports names
+//	[]string - untagged ports names
+//	error - NotFound error if VLAN is not found
+func (s *SimulatedEthernetSwitch) GetVLANPorts(vlanID int) ([]string, []string, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	vlan, err := s.getVLAN(vlanID)
+	if err != nil {
+		return nil, nil, err
+	}
+	tagged := []string{}
+	untagged := []string{}
+	for _, portName := range s.ports {
+		if vlan.tagged[portName] {
+			tagged = append(tagged, portName)
+		}
+		if vlan.untagged[portName] {
+			untagged = append(untagged, portName)
+		}
+	}
+	return tagged, untagged, nil
+}
+
+//GetVLANsOnPort gets VLANs of the port
+//
+//Params:
+//	portName - port name
+//Return:
+//	[]int - sorted IDs of the VLANs where port is tagged member
+//	[]int - sorted IDs of the VLANs where port is untagged member
+//	error - NotFound error if port is not found
+func (s *SimulatedEthernetSwitch) GetVLANsOnPort(portName string) ([]int, []int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if err := s.checkPort(portName); err != nil {
+		return nil, nil, err
+	}
+	tagged := []int{}
+	untagged := []int{}
+	for vlanID, vlan := range s.vlans {
+		if vlan.tagged[portName] {
+			tagged = append(tagged, vlanID)
+		}
+		if vlan.untagged[portName] {
+			untagged = append(untagged, vlanID)
+		}
+	}
+	sort.Ints(tagged)
+	sort.Ints(untagged)
+	return tagged, untagged, nil
+}
+
+//CreateVLAN creates VLAN without members, existing VLAN is not changed
+//
+//Params:
+//	vlanID - VLAN ID
+//Return:
+//	error - if VLAN ID is out of range, otherwise nil
+func (s *SimulatedEthernetSwitch) CreateVLAN(vlanID int) error {
+	if vlanID < 1 || vlanID > simulatedEthernetSwitchMaxVLANs {
+		return errors.Internal.Newf("vlan id %d is out of range", vlanID)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, ok := s.vlans[vlanID]; !ok {
+		s.vlans[vlanID] = &simulatedVLAN{tagged: map[string]bool{}, untagged: map[string]bool{}}
+	}
+	return nil
+}
+
+//DeleteVLAN deletes VLAN, PVID of the ports with this VLAN is reset to default VLAN
+//
+//Params:
+//	vlanID - VLAN ID
+//Return:
+//	error - NotFound error if VLAN is not found, Internal error for default VLAN
+func (s *SimulatedEthernetSwitch) DeleteVLAN(vlanID int) error {
+	if vlanID == simulatedEthernetSwitchDefaultVLAN {
+		return errors.Internal.New("default vlan can't be deleted")
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if _, err := s.getVLAN(vlanID); err != nil {
+		return err
+	}
+	delete(s.vlans, vlanID)
+	s.forgetMACAddresses(func(entry domain.EthernetSwitchMACAddress) bool {
+		return entry.VLANID == vlanID
+	})
+	for portName, pvid := range s.pvids {
+		if pvid == vlanID {
+			s.pvids[portName] = simulatedEthernetSwitchDefaultVLAN
+		}
+	}
+	return nil
+}
+
+//AddVLANOnPort adds port to the VLAN members
+//
+//Params:
+//	portName - port name
+//	vlanID - VLAN ID
+//	untagged - port is untagged member
+//Return:
+//	error - NotFound error if port or VLAN is not found
+func (s *SimulatedEthernetSwitch) AddVLANOnPort(portName string, vlanID int, untagged bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.checkPort(portName); err != nil {
+		return err
+	}
+	vlan, err := s.getVLAN(vlanID)
+	if err != nil {
+		return err
+	}
+	vlan.tagged[portName] = !untagged
+	vlan.untagged[portName] = untagged
+	return nil
+}
+
+//RemoveVLANFromPort removes port from the VLAN members
+//
+//Params:
+//	portName - port name
+//	vlanID - VLAN ID
+//Return:
+//	error - NotFound error if port or VLAN is not found
+func (s *SimulatedEthernetSwitch) RemoveVLANFromPort(portName string, vlanID int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.checkPort(portName); err != nil {
+		return err
+	}
+	vlan, err := s.getVLAN(vlanID)
+	if err != nil {
+		return err
+	}
+	delete(vlan.tagged, portName)
+	delete(vlan.untagged, portName)
+	s.forgetMACAddresses(func(entry domain.EthernetSwitchMACAddress) bool {
+		return entry.PortName == portName && entry.VLANID == vlanID
+	})
+	return nil
+}
+
+//GetPortPVID gets port PVID
+//
+//Params:
+//	portName - port name
+//Return:
+//	int - PVID
+//	error - NotFound error if port is not found
+func (s *SimulatedEthernetSwitch) GetPortPVID(portName string) (int, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if err := s.checkPort(portName); err != nil {
+		return 0, err
+	}
+	return s.pvids[portName], nil
+}
+
+//SetPortPVID sets port PVID
+//
+//Params:
+//	portName - port name
+//	vlanID - PVID
+//Return:
+//	error - NotFound error if port or VLAN is not found
+func (s *SimulatedEthernetSwitch) SetPortPVID(portName string, vlanID int) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.checkPort(portName); err != nil {
+		return err
+	}
+	if _, err := s.getVLAN(vlanID); err != nil {
+		return err
+	}
+	s.pvids[portName] = vlanID
+	return nil
+}
+
+//GetPOEPortStatus gets PoE status of the port
+//
+//Params:
+//	portName - port name
+//Return:
+//	bool - PoE is enabled
+//	error - NotFound error if port is not found, Internal error if port has no PoE
+func (s *SimulatedEthernetSwitch) GetPOEPortStatus(portName string) (bool, error) {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	if err := s.checkPOEPort(portName); err != nil {
+		return false, err
+	}
+	return s.poeEnabled[portName], nil
+}
+
+//SetPOEPortStatus enables or disables PoE on the port
+//
+//Params:
+//	portName - port name
+//	enabled - PoE is enabled
+//Return:
+//	error - NotFound error if port is not found, Internal error if port has no PoE
+func (s *SimulatedEthernetSwitch) SetPOEPortStatus(portName string, enabled bool) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.checkPOEPort(portName); err != nil {
+		return err
+	}
+	s.poeEnabled[portName] = enabled
+	return nil
+}
+
+func (s *SimulatedEthernetSwitch) checkPOEPort(portName string) error {
+	if err := s.checkPort(portName); err != nil {
+		return err
+	}
+	if !utils.SliceContainsElement(s.poePorts, portName) {
+		return errors.Internal.Newf("port %s does not support poe", portName)
+	}
+	return nil
+}
+
+//GetCapabilities gets features of the simulated switch
+//
+//Return:
+//	domain.EthernetSwitchCapabilities - switch capabilities
+func (s *SimulatedEthernetSwitch) GetCapabilities() domain.EthernetSwitchCapabilities {
+	return domain.EthernetSwitchCapabilities{
+		Ports:     append([]string{}, s.ports...),
+		MaxVLANs:  simulatedEthernetSwitchMaxVLANs,
+		POEPorts:  append([]string{}, s.poePorts...),
+		POETypes:  []string{"poe", "poe+"},
+		POEBudget: simulatedEthernetSwitchPOEBudget,
+	}
+}
+
+//SaveConfig saves current configuration, only the count of the saves is stored
+func (s *SimulatedEthernetSwitch) SaveConfig() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.savesCount++
+}
+
+//GetSavesCount gets count of the saved configurations
+//
+//Return:
+//	int - saves count
+func (s *SimulatedEthernetSwitch) GetSavesCount() int {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	return s.savesCount
+}
+
+//forgetMACAddresses removes MAC address table entries that match the condition
+func (s *SimulatedEthernetSwitch) forgetMACAddresses(match func(entry domain.EthernetSwitchMACAddress) bool) {
+	kept := []domain.EthernetSwitchMACAddress{}
+	for _, entry := range s.macAddresses {
+		if !match(entry) {
+			kept = append(kept, entry)
+		}
+	}
+	s.macAddresses = kept
+}
+
+//LearnMACAddress adds MAC address to the forwarding table as if the frame from it was received on the port,
+//address that was learned in the same VLAN on another port is moved to this port
+//
+//Params:
+//	portName - port name
+//	mac - MAC address
+//	vlanID - VLAN ID
+//Return:
+//	error - NotFound error if port or VLAN is not found, Internal error if MAC address is wrong
+func (s *SimulatedEthernetSwitch) LearnMACAddress(portName, mac string, vlanID int) error {
+	hardwareAddr, err := net.ParseMAC(mac)
+	if err != nil {
+		return errors.Internal.Wrapf(err, "wrong mac address %s", mac)
+	}
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err = s.checkPort(portName); err != nil {
+		return err
+	}
+	if _, err = s.getVLAN(vlanID); err != nil {
+		return err
+	}
+	entry := domain.EthernetSwitchMACAddress{MAC: hardwareAddr.String(), VLANID: vlanID, PortName: portName}
+	s.forgetMACAddresses(func(learned domain.EthernetSwitchMACAddress) bool {
+		return learned.MAC == entry.MAC && learned.VLANID == entry.VLANID
+	})
+	s.macAddresses = append(s.macAddresses, entry)
+	return nil
+}
+
+//GetMACAddressTable gets MAC address forwarding table
+//
+//Return:
+//	[]domain.EthernetSwitchMACAddress - entries sorted by port name, VLAN and MAC address
+func (s *SimulatedEthernetSwitch) GetMACAddressTable() []domain.EthernetSwitchMACAddress {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	table := append([]domain.EthernetSwitchMACAddress{}, s.macAddresses...)
+	sortMACAddressTable(table)
+	return table
+}
+
+//AddLLDPNeighbor adds LLDP neighbor of the port as if its advertisement was received
+//
+//Params:
+//	neighbor - LLDP neighbor
+//Return:
+//	error - NotFound error if port is not found
+func (s *SimulatedEthernetSwitch) AddLLDPNeighbor(neighbor domain.EthernetSwitchLLDPNeighbor) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if err := s.checkPort(neighbor.PortName); err != nil {
+		return err
+	}
+	s.lldpNeighbors = append(s.lldpNeighbors, neighbor)
+	return nil
+}
+
+//GetLLDPNeighbors gets LLDP neighbors of the ports
+//
+//Return:
+//	[]domain.EthernetSwitchLLDPNeighbor - neighbors sorted by port name and chassis ID
+func (s *SimulatedEthernetSwitch) GetLLDPNeighbors() []domain.EthernetSwitchLLDPNeighbor {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	neighbors := append([]domain.EthernetSwitchLLDPNeighbor{}, s.lldpNeighbors...)
+	sortLLDPNeighbors(neighbors)
+	return neighbors
+}
+
+//GetRunningConfig renders the switch state as TL-SG2210MP running config
+//
+//Return:
+//	string - running config text
+func (s *SimulatedEthernetSwitch) GetRunningConfig() string {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	builder := &strings.Builder{}
+	builder.WriteString("#\n")
+	vlans := make([]int, 0, len(s.vlans))
+	for vlanID := range s.vlans {
+		vlans = append(vlans, vlanID)
+	}
+	sort.Ints(vlans)
+	for _, vlanID := range vlans {
+		if vlanID != simulatedEthernetSwitchDefaultVLAN {
+			builder.WriteString(fmt.Sprintf("vlan %d\n#\n", vlanID))
+		}
+	}
+	for _, portName := range s.ports {
+		port := tpLinkPortConfig{tagged: map[int]bool{}, untagged: map[int]bool{}, pvid: s.pvids[portName]}
+		for vlanID, vlan := range s.vlans {
+			port.tagged[vlanID] = vlan.tagged[portName]
+			port.untagged[vlanID] = vlan.untagged[portName]
+		}
+		if utils.SliceContainsElement(s.poePorts, portName) {
+			poeEnabled := s.poeEnabled[portName]
+			port.poeEnabled = &poeEnabled
+		}
+		renderTPLinkPortConfig(builder, portName, port)
+	}
+	builder.WriteString(tpLinkConfigEnd + "\n")
+	return builder.String()
+}
+
+//SimulatedEthernetSwitchManager is a struct for the simulated switch management without any network transport
+type SimulatedEthernetSwitchManager struct {
+	simulator *SimulatedEthernetSwitch
+}
+
+//NewSimulatedEthernetSwitchManager constructor for SimulatedEthernetSwitchManager
+//
+//Params:
+//	simulator - simulated switch
+//Return:
+//	interfaces.IEthernetSwitchManager - simulated switch manager
+func NewSimulatedEthernetSwitchManager(simulator *SimulatedEthernetSwitch) interfaces.IEthernetSwitchManager {
+	return &SimulatedEthernetSwitchManager{simulator: simulator}
+}
+
+//GetSimulator gets simulated switch of the manager, it is used to emulate traffic and neighbors of the switch
+//
+//Return:
+//	*SimulatedEthernetSwitch - simulated switch
+func (s *SimulatedEthernetSwitchManager) GetSimulator() *SimulatedEthernetSwitch {
+	return s.simulator
+}
+
+//GetVLANs gets all VLANs on switch
+//
+//Return:
+//	[]int - slice of VLANs
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) GetVLANs() ([]int, error) {
+	return s.simulator.GetVLANs(), nil
+}
+
+//GetVLANsOnPort gets all ethernet switch VLANs on given port
+//
+//Params:
+//	portName - port name
+//Return:
+//	int - untagged VLAN ID, the last one if port is untagged member of several VLANs
+//	[]int - slice of tagged VLANs IDs
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) GetVLANsOnPort(portName string) (int, []int, error) {
+	tagged, untagged, err := s.simulator.GetVLANsOnPort(portName)
+	if err != nil {
+		return 0, []int{}, err
+	}
+	untaggedVLAN := 0
+	if len(untagged) > 0 {
+		untaggedVLAN = untagged[len(untagged)-1]
+	}
+	return untaggedVLAN, tagged, nil
+}
+
+//GetPortPVID gets port PVID
+//
+//Params:
+//	portName - port name
+//Return:
+//	int - PVID
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) GetPortPVID(portName string) (int, error) {
+	return s.simulator.GetPortPVID(portName)
+}
+
+//AddTaggedVLANOnPort add tagged VLAN on given port
+//
+//Params:
+//	portName - port name
+//	vlanID - vlan ID
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) AddTaggedVLANOnPort(portName string, vlanID int) error {
+	return s.simulator.AddVLANOnPort(portName, vlanID, false)
+}
+
+//AddUntaggedVLANOnPort add untagged VLAN on given port
+//
+//Params:
+//	portName - port name
+//	vlanID - vlan ID
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) AddUntaggedVLANOnPort(portName string, vlanID int) error {
+	return s.simulator.AddVLANOnPort(portName, vlanID, true)
+}
+
+//RemoveVLANFromPort remove VLAN from given port
+//
+//Params:
+//	portName - name of port
+//	vlanID	- vlan ID
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) RemoveVLANFromPort(portName string, vlanID int) error {
+	return s.simulator.RemoveVLANFromPort(portName, vlanID)
+}
+
+//SetPortPVID set PVID on given port
+//
+//Params:
+//	portName - port name
+//	vlanID - PVID
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) SetPortPVID(portName string, vlanID int) error {
+	return s.simulator.SetPortPVID(portName, vlanID)
+}
+
+//DeleteVLAN delete VLAN by id
+//
+//Params:
+//	vlanID - vlan ID
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) DeleteVLAN(vlanID int) error {
+	return s.simulator.DeleteVLAN(vlanID)
+}
+
+//CreateVLAN create vlan on switch
+//
+//Params:
+//	vlanID	- vlan ID
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) CreateVLAN(vlanID int) error {
+	return s.simulator.CreateVLAN(vlanID)
+}
+
+//GetPOEPortStatus gets poe status on given port
+//
+//Params:
+//	portName - port name
+//Return:
+//	string - poe port status "enable" or "disable"
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) GetPOEPortStatus(portName string) (string, error) {
+	enabled, err := s.simulator.GetPOEPortStatus(portName)
+	if err != nil {
+		return "", err
+	}
+	if enabled {
+		return "enable", nil
+	}
+	return "disable", nil
+}
+
+//EnablePOEPort enable poe on given port
+//
+//Params:
+//	portName - port name
+//	poeType - poe type: "poe" or "poe+"
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) EnablePOEPort(portName, poeType string) error {
+	if !utils.SliceContainsElement(s.simulator.GetCapabilities().POETypes, poeType) {
+		return errors.Internal.Newf("this switch does not support %s poe", poeType)
+	}
+	return s.simulator.SetPOEPortStatus(portName, true)
+}
+
+//DisablePOEPort disable poe on given port
+//
+//Params:
+//	portName - port name
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) DisablePOEPort(portName string) error {
+	return s.simulator.SetPOEPortStatus(portName, false)
+}
+
+//GetCapabilities gets features of the simulated switch
+//
+//Return:
+//	domain.EthernetSwitchCapabilities - switch capabilities
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) GetCapabilities() (domain.EthernetSwitchCapabilities, error) {
+	return s.simulator.GetCapabilities(), nil
+}
+
+//ApplyOperations applies operations one by one to the simulated switch
+//
+//Params:
+//	operations - switch configuration changes
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) ApplyOperations(operations []domain.EthernetSwitchOperation) error {
+	return applyEthernetSwitchOperations(s, operations)
+}
+
+//SaveConfig save current settings on switch
+//
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) SaveConfig() error {
+	s.simulator.SaveConfig()
+	return nil
+}
+
+//GetRunningConfig gets full running configuration of the switch
+//
+//Return:
+//	string - running configuration text
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) GetRunningConfig() (string, error) {
+	return s.simulator.GetRunningConfig(), nil
+}
+
+//RestoreConfig applies VLANs, ports VLANs membership, PVID and PoE state from the running configuration
+//
+//Params:
+//	config - running configuration text
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) RestoreConfig(config string) error {
+	operations, err := getRestoreOperations(s, config)
+	if err != nil {
+		return err
+	}
+	return s.ApplyOperations(operations)
+}
+
+//GetMACAddressTable gets MAC addresses learned on the switch ports
+//
+//Return:
+//	[]domain.EthernetSwitchMACAddress - MAC address forwarding table entries
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) GetMACAddressTable() ([]domain.EthernetSwitchMACAddress, error) {
+	return s.simulator.GetMACAddressTable(), nil
+}
+
+//GetLLDPNeighbors gets LLDP neighbors of the switch ports
+//
+//Return:
+//	[]domain.EthernetSwitchLLDPNeighbor - neighbors of all ports
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchManager) GetLLDPNeighbors() ([]domain.EthernetSwitchLLDPNeighbor, error) {
+	return s.simulator.GetLLDPNeighbors(), nil
+}
+
+//simulatedTelnetEthernetSwitchManager TP-Link manager of the simulated switch that speaks TL-SG2210MP CLI,
+//capabilities are reported by the simulator because the manager knows only the real TP-Link hardware
+type simulatedTelnetEthernetSwitchManager struct {
+	*TPLinkEthernetSwitchManager
+	simulator *SimulatedEthernetSwitch
+}
+
+//GetCapabilities gets features of the simulated switch
+func (s *simulatedTelnetEthernetSwitchManager) GetCapabilities() (domain.EthernetSwitchCapabilities, error) {
+	return s.simulator.GetCapabilities(), nil
+}
+
+//RestoreConfig applies running configuration, ports of the simulated switch are reported by the simulator
+func (s *simulatedTelnetEthernetSwitchManager) RestoreConfig(config string) error {
+	operations, err := getRestoreOperations(s, config)
+	if err != nil {
+		return err
+	}
+	return s.ApplyOperations(operations)
+}
+
+//GetLLDPNeighbors gets LLDP neighbors of the simulated switch ports
+func (s *simulatedTelnetEthernetSwitchManager) GetLLDPNeighbors() ([]domain.EthernetSwitchLLDPNeighbor, error) {
+	return s.getLLDPNeighbors(s.simulator.GetPorts())
+}
+
+//simulatedEthernetSwitchInstance simulated switch with its optional telnet CLI server
+type simulatedEthernetSwitchInstance struct {
+	simulator *SimulatedEthernetSwitch
+	server    *SimulatedEthernetSwitchTelnetServer
+}
+
+//simulatedEthernetSwitches simulated switches by switch ID, the state lives as long as the application
+type simulatedEthernetSwitches struct {
+	instances map[uuid.UUID]*simulatedEthernetSwitchInstance
+	mutex     sync.Mutex
+	sessions  *SwitchCLISessionPool
+	//telnetEnabled simulated switches are managed over telnet with TL-SG2210MP CLI
+	telnetEnabled bool
+}
+
+//newSimulatedEthernetSwitchDriver creates driver of the simulated switches
+//
+//Params:
+//	sessions - CLI sessions pool for the telnet managers
+//	telnetEnabled - simulated switches serve TL-SG2210MP telnet CLI on a local port and are managed over it
+//Return:
+//	EthernetSwitchDriver - switch driver
+func newSimulatedEthernetSwitchDriver(sessions *SwitchCLISessionPool, telnetEnabled bool) EthernetSwitchDriver {
+	switches := &simulatedEthernetSwitches{
+		instances:     map[uuid.UUID]*simulatedEthernetSwitchInstance{},
+		sessions:      sessions,
+		telnetEnabled: telnetEnabled,
+	}
+	return EthernetSwitchDriver{
+		Models: []domain.EthernetSwitchModel{{
+			Model:        "Simulated 24-port PoE switch",
+			Manufacturer: "ROL",
+			Code:         "sim-24p",
+			Capabilities: []string{domain.EthernetSwitchCapabilityVLAN, domain.EthernetSwitchCapabilityPOE, domain.EthernetSwitchCapabilityLLDP},
+		}},
+		NewManager:    switches.newManager,
+		ReleaseSwitch: switches.release,
+	}
+}
+
+//getInstance gets simulated switch by switch ID, it is created on first request
+func (s *simulatedEthernetSwitches) getInstance(switchID uuid.UUID) (*simulatedEthernetSwitchInstance, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	instance, ok := s.instances[switchID]
+	if ok {
+		return instance, nil
+	}
+	instance = &simulatedEthernetSwitchInstance{
+		simulator: NewSimulatedEthernetSwitch(simulatedEthernetSwitchPortsCount, simulatedEthernetSwitchPortsCount),
+	}
+	if s.telnetEnabled {
+		server, err := NewSimulatedEthernetSwitchTelnetServer(instance.simulator, "127.0.0.1:0")
+		if err != nil {
+			return nil, errors.Internal.Wrap(err, "failed to start simulated switch telnet server")
+		}
+		instance.server = server
+	}
+	s.instances[switchID] = instance
+	return instance, nil
+}
+
+//release removes simulated switch state and stops its telnet server
+func (s *simulatedEthernetSwitches) release(switchID uuid.UUID) error {
+	s.mutex.Lock()
+	instance, ok := s.instances[switchID]
+	delete(s.instances, switchID)
+	s.mutex.Unlock()
+	if !ok || instance.server == nil {
+		return nil
+	}
+	return instance.server.Close()
+}
+
+func (s *simulatedEthernetSwitches) newManager(ethernetSwitch domain.EthernetSwitch) (interfaces.IEthernetSwitchManager, error) {
+	instance, err := s.getInstance(ethernetSwitch.ID)
+	if err != nil {
+		return nil, err
+	}
+	if instance.server == nil {
+		return NewSimulatedEthernetSwitchManager(instance.simulator), nil
+	}
+	instance.server.SetCredentials(ethernetSwitch.Username, ethernetSwitch.Password)
+	return &simulatedTelnetEthernetSwitchManager{
+		TPLinkEthernetSwitchManager: newTPLinkEthernetSwitchManager(s.sessions, NewTelnetConnection(),
+			instance.server.Address(), ethernetSwitch.Username, ethernetSwitch.Password),
+		simulator: instance.simulator,
+	}, nil
+}
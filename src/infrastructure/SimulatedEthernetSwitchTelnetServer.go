@@ -0,0 +1,483 @@
+package infrastructure
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"rol/app/errors"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	//simulatedCLIHostname hostname of the simulated switch in the CLI prompt
+	simulatedCLIHostname = "TL-SG2210MP"
+	//simulatedCLIBadCommand CLI answer on unknown or invalid command
+	simulatedCLIBadCommand = "Error: Bad command"
+)
+
+const (
+	simulatedCLIModeUser = iota
+	simulatedCLIModePrivileged
+	simulatedCLIModeConfig
+	simulatedCLIModeConfigVLAN
+	simulatedCLIModeConfigInterface
+)
+
+//SimulatedEthernetSwitchTelnetServer telnet server with TL-SG2210MP CLI dialect over the simulated switch,
+//it prints only the commands output that is parsed by TPLinkEthernetSwitchManager, commands are not echoed
+type SimulatedEthernetSwitchTelnetServer struct {
+	simulator *SimulatedEthernetSwitch
+	listener  net.Listener
+	//mutex guards credentials, failed commands and connections
+	mutex    sync.Mutex
+	login    string
+	password string
+	//failedCommands commands that CLI rejected
+	failedCommands []string
+	//conns opened CLI connections, they are closed with the server
+	conns map[net.Conn]struct{}
+}
+
+//simulatedCLISession state of the single CLI connection
+type simulatedCLISession struct {
+	server *SimulatedEthernetSwitchTelnetServer
+	conn   net.Conn
+	mode   int
+	//portName port name of the interface configuration mode
+	portName string
+}
+
+//NewSimulatedEthernetSwitchTelnetServer constructor for SimulatedEthernetSwitchTelnetServer, server starts listening immediately
+//
+//Params:
+//	simulator - simulated switch
+//	address - tcp address to listen, for example 127.0.0.1:0
+//Return:
+//	*SimulatedEthernetSwitchTelnetServer - simulated switch telnet server
+//	error - if an error occurs, otherwise nil
+func NewSimulatedEthernetSwitchTelnetServer(simulator *SimulatedEthernetSwitch, address string) (*SimulatedEthernetSwitchTelnetServer, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, "failed to listen simulated switch telnet address")
+	}
+	server := &SimulatedEthernetSwitchTelnetServer{
+		simulator: simulator,
+		listener:  listener,
+		conns:     map[net.Conn]struct{}{},
+	}
+	go server.serve()
+	return server, nil
+}
+
+//Address gets server listening address
+//
+//Return:
+//	string - address with port
+func (s *SimulatedEthernetSwitchTelnetServer) Address() string {
+	return s.listener.Addr().String()
+}
+
+//SetCredentials sets credentials of the CLI user
+//
+//Params:
+//	login - user name
+//	password - user password
+func (s *SimulatedEthernetSwitchTelnetServer) SetCredentials(login, password string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.login = login
+	//  pragma: allowlist nextline secret
+	s.password = password
+}
+
+//GetFailedCommands gets commands that were rejected by CLI
+//
+//Return:
+//	[]string - rejected commands
+func (s *SimulatedEthernetSwitchTelnetServer) GetFailedCommands() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]string{}, s.failedCommands...)
+}
+
+//Close stops listening and closes opened connections
+//
+//Return:
+//	error - if an error occurs, otherwise nil
+func (s *SimulatedEthernetSwitchTelnetServer) Close() error {
+	err := s.listener.Close()
+	s.mutex.Lock()
+	for conn := range s.conns {
+		_ = conn.Close()
+	}
+	s.mutex.Unlock()
+	if err != nil {
+		return errors.Internal.Wrap(err, "failed to close simulated switch telnet listener")
+	}
+	return nil
+}
+
+func (s *SimulatedEthernetSwitchTelnetServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		s.mutex.Lock()
+		s.conns[conn] = struct{}{}
+		s.mutex.Unlock()
+		session := &simulatedCLISession{server: s, conn: conn}
+		go session.serve()
+	}
+}
+
+func (s *SimulatedEthernetSwitchTelnetServer) removeConn(conn net.Conn) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	delete(s.conns, conn)
+}
+
+func (s *SimulatedEthernetSwitchTelnetServer) checkCredentials(login, password string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return login == s.login && password == s.password
+}
+
+func (s *SimulatedEthernetSwitchTelnetServer) addFailedCommand(command string) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.failedCommands = append(s.failedCommands, command)
+}
+
+func (c *simulatedCLISession) write(format string, args ...interface{}) {
+	_, _ = fmt.Fprintf(c.conn, format, args...)
+}
+
+func (c *simulatedCLISession) prompt() string {
+	switch c.mode {
+	case simulatedCLIModeUser:
+		return simulatedCLIHostname + ">"
+	case simulatedCLIModeConfig:
+		return simulatedCLIHostname + "(config)#"
+	case simulatedCLIModeConfigVLAN:
+		return simulatedCLIHostname + "(config-vlan)#"
+	case simulatedCLIModeConfigInterface:
+		return simulatedCLIHostname + "(config-if)#"
+	}
+	return simulatedCLIHostname + "#"
+}
+
+func (c *simulatedCLISession) serve() {
+	defer c.server.removeConn(c.conn)
+	defer c.conn.Close()
+	reader := bufio.NewReader(c.conn)
+	readLine := func() (string, error) {
+		line, err := reader.ReadString('\n')
+		return strings.TrimSpace(line), err
+	}
+	c.write("\r\nUser Access Verification\r\n\r\nLogin: ")
+	login, err := readLine()
+	if err != nil {
+		return
+	}
+	c.write("Password: ")
+	password, err := readLine()
+	if err != nil {
+		return
+	}
+	if !c.server.checkCredentials(login, password) {
+		c.write("\r\nLogin invalid.\r\n")
+		return
+	}
+	c.mode = simulatedCLIModeUser
+	c.write("\r\n%s", c.prompt())
+	for {
+		command, err := readLine()
+		if err != nil {
+			return
+		}
+		if command != "" && !c.execute(command) {
+			c.server.addFailedCommand(command)
+			c.write("\r\n%s\r\n", simulatedCLIBadCommand)
+		}
+		if c.mode < simulatedCLIModeUser {
+			return
+		}
+		c.write("\r\n%s", c.prompt())
+	}
+}
+
+//execute executes CLI command, returns false if the command is rejected
+func (c *simulatedCLISession) execute(command string) bool {
+	switch command {
+	case "exit":
+		c.exit()
+		return true
+	case "end":
+		if c.mode < simulatedCLIModeConfig {
+			return false
+		}
+		c.mode = simulatedCLIModePrivileged
+		return true
+	case "enable":
+		if c.mode > simulatedCLIModePrivileged {
+			return false
+		}
+		c.mode = simulatedCLIModePrivileged
+		return true
+	}
+	if c.mode == simulatedCLIModeUser {
+		return false
+	}
+	if strings.HasPrefix(command, "show ") {
+		return c.show(strings.TrimPrefix(command, "show "))
+	}
+	switch c.mode {
+	case simulatedCLIModePrivileged:
+		return c.executePrivileged(command)
+	case simulatedCLIModeConfig:
+		return c.executeConfig(command)
+	case simulatedCLIModeConfigInterface:
+		return c.executeConfigInterface(command)
+	}
+	return false
+}
+
+//exit returns to the previous mode, user mode exit closes the session
+func (c *simulatedCLISession) exit() {
+	switch c.mode {
+	case simulatedCLIModeConfigVLAN, simulatedCLIModeConfigInterface:
+		c.mode = simulatedCLIModeConfig
+	case simulatedCLIModeConfig:
+		c.mode = simulatedCLIModePrivileged
+	case simulatedCLIModePrivileged:
+		c.mode = simulatedCLIModeUser
+	default:
+		c.mode = -1
+	}
+}
+
+func (c *simulatedCLISession) executePrivileged(command string) bool {
+	switch command {
+	case "config", "configure":
+		c.mode = simulatedCLIModeConfig
+		return true
+	case "copy running-config startup-config":
+		c.server.simulator.SaveConfig()
+		c.write("\r\n Start to save user config......\r\n Saving user config OK!")
+		return true
+	}
+	return false
+}
+
+func (c *simulatedCLISession) executeConfig(command string) bool {
+	fields := strings.Fields(command)
+	switch {
+	case len(fields) == 2 && fields[0] == "vlan":
+		vlanID, err := strconv.Atoi(fields[1])
+		if err != nil || c.server.simulator.CreateVLAN(vlanID) != nil {
+			return false
+		}
+		c.mode = simulatedCLIModeConfigVLAN
+		return true
+	case len(fields) == 3 && fields[0] == "no" && fields[1] == "vlan":
+		vlanID, err := strconv.Atoi(fields[2])
+		return err == nil && c.server.simulator.DeleteVLAN(vlanID) == nil
+	case len(fields) == 3 && fields[0] == "interface" && fields[1] == "gigabitEthernet":
+		portName := "gi" + fields[2]
+		if _, err := c.server.simulator.GetPortPVID(portName); err != nil {
+			return false
+		}
+		c.portName = portName
+		c.mode = simulatedCLIModeConfigInterface
+		return true
+	}
+	return false
+}
+
+func (c *simulatedCLISession) executeConfigInterface(command string) bool {
+	simulator := c.server.simulator
+	fields := strings.Fields(command)
+	switch {
+	case len(fields) == 6 && strings.Join(fields[:4], " ") == "switchport general allowed vlan":
+		if fields[5] != "tagged" && fields[5] != "untagged" {
+			return false
+		}
+		return c.forEachVLAN(fields[4], func(vlanID int) error {
+			return simulator.AddVLANOnPort(c.portName, vlanID, fields[5] == "untagged")
+		})
+	case len(fields) == 6 && strings.Join(fields[:5], " ") == "no switchport general allowed vlan":
+		return c.forEachVLAN(fields[5], func(vlanID int) error {
+			return simulator.RemoveVLANFromPort(c.portName, vlanID)
+		})
+	case len(fields) == 3 && fields[0] == "switchport" && fields[1] == "pvid":
+		vlanID, err := strconv.Atoi(fields[2])
+		return err == nil && simulator.SetPortPVID(c.portName, vlanID) == nil
+	case command == "power inline consumption auto":
+		_, err := simulator.GetPOEPortStatus(c.portName)
+		return err == nil
+	case command == "power inline supply enable":
+		return simulator.SetPOEPortStatus(c.portName, true) == nil
+	case command == "power inline supply disable":
+		return simulator.SetPOEPortStatus(c.portName, false) == nil
+	}
+	return false
+}
+
+//forEachVLAN runs action for each VLAN of comma separated VLANs list
+func (c *simulatedCLISession) forEachVLAN(vlansList string, action func(vlanID int) error) bool {
+	for _, vlan := range strings.Split(vlansList, ",") {
+		vlanID, err := strconv.Atoi(vlan)
+		if err != nil || action(vlanID) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+//cliPortName converts port name to the name that is printed by CLI: gi1/0/1 to Gi1/0/1
+func cliPortName(portName string) string {
+	return strings.ToUpper(portName[:1]) + portName[1:]
+}
+
+//cliVLANName VLAN name that is printed by CLI
+func cliVLANName(vlanID int) string {
+	if vlanID == simulatedEthernetSwitchDefaultVLAN {
+		return "System-VLAN"
+	}
+	return fmt.Sprintf("VLAN%04d", vlanID)
+}
+
+//show prints output of the show command. Empty tables are printed with one blank row,
+//because manager drops the first byte after the table header
+func (c *simulatedCLISession) show(arguments string) bool {
+	fields := strings.Fields(arguments)
+	switch {
+	case arguments == "running-config":
+		config := c.server.simulator.GetRunningConfig()
+		c.write("\r\n!%s\r\n%s", simulatedCLIHostname, strings.ReplaceAll(config, "\n", "\r\n"))
+		return true
+	case arguments == "vlan":
+		c.showVLANs(c.server.simulator.GetVLANs())
+		return true
+	case len(fields) == 3 && fields[0] == "vlan" && fields[1] == "id":
+		vlanID, err := strconv.Atoi(fields[2])
+		if err != nil {
+			return false
+		}
+		if _, _, err = c.server.simulator.GetVLANPorts(vlanID); err != nil {
+			c.showVLANs([]int{})
+			return true
+		}
+		c.showVLANs([]int{vlanID})
+		return true
+	case len(fields) == 4 && strings.Join(fields[:3], " ") == "interface switchport gigabitEthernet":
+		return c.showInterfaceSwitchport("gi" + fields[3])
+	case len(fields) == 6 && strings.Join(fields[:5], " ") == "power inline configuration interface gigabitEthernet":
+		return c.showPowerInlineConfiguration("gi" + fields[5])
+	case arguments == "mac address-table all":
+		c.showMACAddressTable()
+		return true
+	case len(fields) == 5 && strings.Join(fields[:4], " ") == "lldp neighbor-information interface gigabitEthernet":
+		return c.showLLDPNeighbors("gi" + fields[4])
+	}
+	return false
+}
+
+func (c *simulatedCLISession) showVLANs(vlans []int) {
+	c.write("\r\nVLAN  Name                 Status    Ports\r\n" +
+		"----- -------------------- --------- ----------------------------------------\r\n")
+	if len(vlans) == 0 {
+		c.write(" \r\n")
+	}
+	for _, vlanID := range vlans {
+		tagged, untagged, err := c.server.simulator.GetVLANPorts(vlanID)
+		if err != nil {
+			continue
+		}
+		ports := []string{}
+		for _, portName := range append(untagged, tagged...) {
+			ports = append(ports, cliPortName(portName))
+		}
+		c.write("%-5d %-20s %-9s %s\r\n", vlanID, cliVLANName(vlanID), "active", strings.Join(ports, ", "))
+	}
+	c.write("\r\n")
+}
+
+func (c *simulatedCLISession) showInterfaceSwitchport(portName string) bool {
+	simulator := c.server.simulator
+	tagged, untagged, err := simulator.GetVLANsOnPort(portName)
+	if err != nil {
+		return false
+	}
+	pvid, _ := simulator.GetPortPVID(portName)
+	c.write("\r\nPort %s:\r\n  Type: General\r\n  PVID: %d\r\n  Acceptable frame type: All\r\n  Ingress Checking: Enable\r\n\r\n"+
+		"  Vlan      Name                  Egress-rule\r\n"+
+		"  --------  --------------------  -----------\r\n", cliPortName(portName), pvid)
+	if len(tagged)+len(untagged) == 0 {
+		c.write("  \r\n")
+	}
+	for _, vlanID := range untagged {
+		c.write("  %-8d  %-20s  %s\r\n", vlanID, cliVLANName(vlanID), "Untagged")
+	}
+	for _, vlanID := range tagged {
+		c.write("  %-8d  %-20s  %s\r\n", vlanID, cliVLANName(vlanID), "Tagged")
+	}
+	c.write("\n\r")
+	return true
+}
+
+func (c *simulatedCLISession) showPowerInlineConfiguration(portName string) bool {
+	enabled, err := c.server.simulator.GetPOEPortStatus(portName)
+	if err != nil {
+		return false
+	}
+	status := "Disable"
+	if enabled {
+		status = "Enable"
+	}
+	c.write("\r\nInterface  Status   Po-Limit(w)   Time-Range  Po-Priority  Power-Mode\r\n"+
+		"---------  -------  ------------  ----------  -----------  -----------\r\n"+
+		"%-9s  %-7s  %-12s  %-10s  %-11s  %s\r\n\n\r", cliPortName(portName), status, "30.0(Class4)", "No Limit", "Low", "802.3at")
+	return true
+}
+
+func (c *simulatedCLISession) showMACAddressTable() {
+	table := c.server.simulator.GetMACAddressTable()
+	c.write("\r\nMAC Address Table\r\n" +
+		"------------------------------------------------------------\r\n" +
+		"MAC Address        VLAN    Port        Type        Aging\r\n" +
+		"-----------------  ------  ----------  ----------  ---------\r\n")
+	for _, entry := range table {
+		c.write("%-17s  %-6d  %-10s  %-10s  %s\r\n", strings.ToUpper(entry.MAC), entry.VLANID, cliPortName(entry.PortName),
+			"dynamic", "Aging")
+	}
+	c.write("\r\nTotal MAC Addresses for this criterion: %d\r\n", len(table))
+}
+
+func (c *simulatedCLISession) showLLDPNeighbors(portName string) bool {
+	if _, err := c.server.simulator.GetPortPVID(portName); err != nil {
+		return false
+	}
+	c.write("\r\nLLDP Neighbor Information\r\n\r\n%s\r\n", cliPortName(portName))
+	index := 0
+	for _, neighbor := range c.server.simulator.GetLLDPNeighbors() {
+		if neighbor.PortName != portName {
+			continue
+		}
+		index++
+		c.write("  Neighbor index %d:\r\n"+
+			"    Chassis type                  :  MAC address\r\n"+
+			"    Chassis ID                    :  %s\r\n"+
+			"    Port ID                       :  %s\r\n"+
+			"    Port description              :  %s\r\n"+
+			"    System name                   :  %s\r\n"+
+			"    Management address type       :  ipv4\r\n"+
+			"    Management address            :  %s\r\n", index, neighbor.ChassisID, neighbor.PortID,
+			neighbor.PortDescription, neighbor.SystemName, neighbor.ManagementAddress)
+	}
+	c.write("\n\r")
+	return true
+}
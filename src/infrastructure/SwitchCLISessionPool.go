@@ -0,0 +1,167 @@
+package infrastructure
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"sync"
+	"time"
+)
+
+const (
+	//switchCLISessionIdleTimeout session is closed if it is not used for this time
+	switchCLISessionIdleTimeout = 5 * time.Minute
+	//switchCLISessionKeepAliveInterval interval of the keep-alive commands for the idle session
+	switchCLISessionKeepAliveInterval = 30 * time.Second
+)
+
+//switchCLISession opened switch CLI session
+type switchCLISession struct {
+	//mutex serializes session usage, switch CLI can execute only one command sequence at a time
+	mutex     sync.Mutex
+	conn      interfaces.ISwitchCLIConnection
+	keepAlive func(conn interfaces.ISwitchCLIConnection) error
+	lastUsed  time.Time
+	//done is closed when the session is closed
+	done chan struct{}
+}
+
+//SwitchCLISessionPool keeps switch CLI sessions opened between manager calls, one session per switch.
+//Idle sessions receive keep-alive commands and are closed after idle timeout
+type SwitchCLISessionPool struct {
+	sessions map[string]*switchCLISession
+	//sessionsMutex guards sessions map
+	sessionsMutex     sync.Mutex
+	idleTimeout       time.Duration
+	keepAliveInterval time.Duration
+}
+
+//NewSwitchCLISessionPool constructor for SwitchCLISessionPool
+//
+//Params:
+//	idleTimeout - session is closed if it is not used for this time
+//	keepAliveInterval - interval of the keep-alive commands for the idle session
+//Return:
+//	*SwitchCLISessionPool - switch CLI sessions pool
+func NewSwitchCLISessionPool(idleTimeout, keepAliveInterval time.Duration) *SwitchCLISessionPool {
+	return &SwitchCLISessionPool{
+		sessions:          map[string]*switchCLISession{},
+		idleTimeout:       idleTimeout,
+		keepAliveInterval: keepAliveInterval,
+	}
+}
+
+//SwitchCLISessionKey builds pool key of the switch session. Key includes hash of the values the session
+//is opened with, so the session that was opened with old credentials is not reused after they are changed
+//
+//Params:
+//	login - switch user name
+//	address - switch CLI address with port
+//	secrets - password, transport and other values the session is opened with
+//Return:
+//	string - switch session key
+func SwitchCLISessionKey(login, address string, secrets ...string) string {
+	hash := sha256.New()
+	for _, secret := range secrets {
+		hash.Write([]byte(secret))
+		hash.Write([]byte{0})
+	}
+	return fmt.Sprintf("%s@%s#%x", login, address, hash.Sum(nil)[:8])
+}
+
+func (p *SwitchCLISessionPool) getSession(key string) *switchCLISession {
+	p.sessionsMutex.Lock()
+	defer p.sessionsMutex.Unlock()
+	session, ok := p.sessions[key]
+	if !ok {
+		session = &switchCLISession{}
+		p.sessions[key] = session
+	}
+	return session
+}
+
+//Do runs action in the opened session of the switch, the session is opened if needed.
+//Session is closed if the action fails, because its CLI state is unknown
+//
+//Params:
+//	key - switch session key
+//	open - opens new session, it is called only if there is no opened session
+//	keepAlive - sends keep-alive command to the idle session
+//	action - action to run in the session
+//Return:
+//	error - if an error occurs, otherwise nil
+func (p *SwitchCLISessionPool) Do(key string, open func() (interfaces.ISwitchCLIConnection, error),
+	keepAlive func(conn interfaces.ISwitchCLIConnection) error, action func(conn interfaces.ISwitchCLIConnection) error) error {
+	session := p.getSession(key)
+	session.mutex.Lock()
+	defer session.mutex.Unlock()
+	if session.conn == nil {
+		conn, err := open()
+		if err != nil {
+			return errors.Internal.Wrap(err, "failed to open switch CLI session")
+		}
+		session.conn = conn
+		session.keepAlive = keepAlive
+		session.done = make(chan struct{})
+		go p.keepAliveLoop(session, session.done)
+	}
+	session.lastUsed = time.Now()
+	err := action(session.conn)
+	if err != nil {
+		session.close()
+		return err
+	}
+	session.lastUsed = time.Now()
+	return nil
+}
+
+//Close closes all opened sessions
+func (p *SwitchCLISessionPool) Close() {
+	p.sessionsMutex.Lock()
+	sessions := make([]*switchCLISession, 0, len(p.sessions))
+	for _, session := range p.sessions {
+		sessions = append(sessions, session)
+	}
+	p.sessionsMutex.Unlock()
+	for _, session := range sessions {
+		session.mutex.Lock()
+		session.close()
+		session.mutex.Unlock()
+	}
+}
+
+//keepAliveLoop sends keep-alive commands to the idle session and closes it after idle timeout
+func (p *SwitchCLISessionPool) keepAliveLoop(session *switchCLISession, done chan struct{}) {
+	ticker := time.NewTicker(p.keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			session.mutex.Lock()
+			if session.conn == nil || session.done != done {
+				session.mutex.Unlock()
+				return
+			}
+			idle := time.Since(session.lastUsed)
+			if idle >= p.idleTimeout {
+				session.close()
+			} else if idle >= p.keepAliveInterval && session.keepAlive(session.conn) != nil {
+				session.close()
+			}
+			session.mutex.Unlock()
+		}
+	}
+}
+
+//close closes session connection, session mutex must be locked
+func (s *switchCLISession) close() {
+	if s.conn == nil {
+		return
+	}
+	_ = s.conn.Close()
+	s.conn = nil
+	close(s.done)
+}
@@ -0,0 +1,145 @@
+package infrastructure
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"rol/app/interfaces"
+	"rol/domain"
+	"strings"
+	"time"
+)
+
+//TFTPClientResolver resolves tftp client mac address, DHCP v4 lease and device by the client ip address
+type TFTPClientResolver struct {
+	leasesRepo       interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease]
+	reservationsRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Reservation]
+	devicesRepo      interfaces.IGenericRepository[uuid.UUID, domain.Device]
+	interfacesRepo   interfaces.IGenericRepository[uuid.UUID, domain.DeviceNetworkInterface]
+	templatesStorage interfaces.IGenericTemplateStorage[domain.DeviceTemplate]
+}
+
+//NewTFTPClientResolver constructor for TFTPClientResolver
+//
+//Params:
+//	leasesRepo - DHCP v4 leases repository
+//	reservationsRepo - DHCP v4 reservations repository
+//	devicesRepo - devices repository
+//	interfacesRepo - device network interfaces repository
+//	templatesStorage - device templates storage
+//Return:
+//	*TFTPClientResolver - tftp client resolver
+func NewTFTPClientResolver(
+	leasesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease],
+	reservationsRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Reservation],
+	devicesRepo interfaces.IGenericRepository[uuid.UUID, domain.Device],
+	interfacesRepo interfaces.IGenericRepository[uuid.UUID, domain.DeviceNetworkInterface],
+	templatesStorage interfaces.IGenericTemplateStorage[domain.DeviceTemplate],
+) *TFTPClientResolver {
+	return &TFTPClientResolver{
+		leasesRepo:       leasesRepo,
+		reservationsRepo: reservationsRepo,
+		devicesRepo:      devicesRepo,
+		interfacesRepo:   interfacesRepo,
+		templatesStorage: templatesStorage,
+	}
+}
+
+func (r *TFTPClientResolver) getActiveLease(ctx context.Context, ip string) *domain.DHCP4Lease {
+	if r.leasesRepo == nil {
+		return nil
+	}
+	queryBuilder := r.leasesRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("IP", "==", ip)
+	queryBuilder.Where("Expires", ">", time.Now())
+	leases, err := r.leasesRepo.GetList(ctx, "UpdatedAt", "desc", 1, 1, queryBuilder)
+	if err != nil || len(leases) == 0 {
+		return nil
+	}
+	return &leases[0]
+}
+
+func (r *TFTPClientResolver) getReservationMAC(ctx context.Context, ip string) string {
+	if r.reservationsRepo == nil {
+		return ""
+	}
+	queryBuilder := r.reservationsRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("IP", "==", ip)
+	reservations, err := r.reservationsRepo.GetList(ctx, "", "", 1, 1, queryBuilder)
+	if err != nil || len(reservations) == 0 {
+		return ""
+	}
+	return strings.ToLower(reservations[0].MAC)
+}
+
+//getNetworkInterface finds device network interface by the lower case mac address
+func (r *TFTPClientResolver) getNetworkInterface(ctx context.Context, mac string) *domain.DeviceNetworkInterface {
+	queryBuilder := r.interfacesRepo.NewQueryBuilder(ctx)
+	queryBuilder.Where("MAC", "==", mac)
+	networkInterfaces, err := r.interfacesRepo.GetList(ctx, "", "", 1, 1, queryBuilder)
+	if err != nil || len(networkInterfaces) == 0 {
+		return nil
+	}
+	return &networkInterfaces[0]
+}
+
+//ResolveMAC resolves client mac address in lower case, active leases have priority over reservations
+//
+//Params:
+//	ip - client ip address
+//Return:
+//	string - client mac address, empty if it's not found
+func (r *TFTPClientResolver) ResolveMAC(ip string) string {
+	if r == nil {
+		return ""
+	}
+	ctx := context.Background()
+	if lease := r.getActiveLease(ctx, ip); lease != nil {
+		return strings.ToLower(lease.MAC)
+	}
+	return r.getReservationMAC(ctx, ip)
+}
+
+//ResolveTemplateData collects variables for the templated file requested by the client.
+//Device is matched by the mac address of its network interface, absent values are left nil
+//
+//Params:
+//	filename - file name requested by tftp client
+//	ip - client ip address
+//Return:
+//	TFTPTemplateData - template variables
+func (r *TFTPClientResolver) ResolveTemplateData(filename, ip string) TFTPTemplateData {
+	data := TFTPTemplateData{
+		Filename: filename,
+		ClientIP: ip,
+	}
+	if r == nil || ip == "" {
+		return data
+	}
+	ctx := context.Background()
+	data.Lease = r.getActiveLease(ctx, ip)
+	if data.Lease != nil {
+		data.ClientMAC = strings.ToLower(data.Lease.MAC)
+	} else {
+		data.ClientMAC = r.getReservationMAC(ctx, ip)
+	}
+	if data.ClientMAC == "" || r.interfacesRepo == nil || r.devicesRepo == nil {
+		return data
+	}
+	data.NetworkInterface = r.getNetworkInterface(ctx, data.ClientMAC)
+	if data.NetworkInterface == nil {
+		return data
+	}
+	device, err := r.devicesRepo.GetByID(ctx, data.NetworkInterface.DeviceID)
+	if err != nil {
+		return data
+	}
+	data.Device = &device
+	if r.templatesStorage == nil || device.DeviceTemplate == "" {
+		return data
+	}
+	deviceTemplate, err := r.templatesStorage.GetByName(ctx, device.DeviceTemplate)
+	if err == nil {
+		data.DeviceTemplate = &deviceTemplate
+	}
+	return data
+}
@@ -0,0 +1,80 @@
+package infrastructure
+
+import (
+	"rol/domain"
+	"strings"
+)
+
+//tftpPathIndex index of the TFTP path ratios, ratios can be global or scoped to the client ip or mac address
+type tftpPathIndex struct {
+	global *virtualPathTable
+	byIP   map[string]*virtualPathTable
+	byMAC  map[string]*virtualPathTable
+}
+
+//addTFTPPathRatio adds tftp path ratio to the table
+func addTFTPPathRatio(table *virtualPathTable, ratio domain.TFTPPathRatio) {
+	entry := virtualPathEntry{actualPath: ratio.ActualPath, isTemplate: ratio.IsTemplate}
+	table.add(ratio.VirtualPath, entry, ratio.IsDirectory)
+}
+
+func newTFTPPathIndex(paths []domain.TFTPPathRatio) *tftpPathIndex {
+	index := &tftpPathIndex{
+		global: newVirtualPathTable(),
+		byIP:   map[string]*virtualPathTable{},
+		byMAC:  map[string]*virtualPathTable{},
+	}
+	for _, ratio := range paths {
+		table := index.global
+		if ratio.ClientIP != "" {
+			table = index.byIP[ratio.ClientIP]
+			if table == nil {
+				table = newVirtualPathTable()
+				index.byIP[ratio.ClientIP] = table
+			}
+		} else if ratio.ClientMAC != "" {
+			mac := strings.ToLower(ratio.ClientMAC)
+			table = index.byMAC[mac]
+			if table == nil {
+				table = newVirtualPathTable()
+				index.byMAC[mac] = table
+			}
+		}
+		addTFTPPathRatio(table, ratio)
+	}
+	return index
+}
+
+//HasMACScopedPaths returns true if at least one ratio is scoped to the client mac address
+func (i *tftpPathIndex) HasMACScopedPaths() bool {
+	return len(i.byMAC) > 0
+}
+
+//Lookup finds actual path for the requested file name.
+//Ratios scoped to the client ip address have priority over ratios scoped to the client mac address,
+//which have priority over global ratios.
+//
+//Params:
+//	filename - file name requested by tftp client
+//	clientIP - client ip address, can be empty
+//	clientMAC - client mac address in lower case, can be empty
+//Return:
+//	virtualPathEntry - actual file path and its flags
+//	bool - false if ratio is not found
+func (i *tftpPathIndex) Lookup(filename, clientIP, clientMAC string) (virtualPathEntry, bool) {
+	virtualPath := normalizeVirtualPath(filename)
+	if virtualPath == "" {
+		return virtualPathEntry{}, false
+	}
+	if table, ok := i.byIP[clientIP]; ok && clientIP != "" {
+		if entry, ok := table.lookup(virtualPath); ok {
+			return entry, true
+		}
+	}
+	if table, ok := i.byMAC[clientMAC]; ok && clientMAC != "" {
+		if entry, ok := table.lookup(virtualPath); ok {
+			return entry, true
+		}
+	}
+	return i.global.lookup(virtualPath)
+}
@@ -0,0 +1,61 @@
+package infrastructure
+
+import (
+	"bytes"
+	"os"
+	"rol/app/errors"
+	"rol/domain"
+	"strings"
+	"text/template"
+)
+
+//TFTPTemplateData variables available in the templated tftp files
+type TFTPTemplateData struct {
+	//Filename - file name requested by tftp client
+	Filename string
+	//ClientIP - client ip address
+	ClientIP string
+	//ClientMAC - client mac address in lower case, empty if it's not resolved
+	ClientMAC string
+	//Lease - active DHCP v4 lease of the client, nil if it's not found
+	Lease *domain.DHCP4Lease
+	//NetworkInterface - device network interface with the client mac address, nil if it's not found
+	NetworkInterface *domain.DeviceNetworkInterface
+	//Device - device of the network interface, nil if it's not found
+	Device *domain.Device
+	//DeviceTemplate - template of the device, nil if it's not found
+	DeviceTemplate *domain.DeviceTemplate
+}
+
+//tftpTemplateFuncs helper functions available in the templated tftp files,
+//for example {{ replace .ClientMAC ":" "-" }} for pxelinux.cfg file names
+var tftpTemplateFuncs = template.FuncMap{
+	"replace": strings.ReplaceAll,
+	"lower":   strings.ToLower,
+	"upper":   strings.ToUpper,
+}
+
+//renderTFTPTemplate renders file as go text/template
+//
+//Params:
+//	actualPath - template file path
+//	data - template variables
+//Return:
+//	[]byte - rendered file content
+//	error - if an error occurred, otherwise nil
+func renderTFTPTemplate(actualPath string, data TFTPTemplateData) ([]byte, error) {
+	content, err := os.ReadFile(actualPath)
+	if err != nil {
+		return nil, errors.Internal.Wrapf(err, "failed to read template file: %s", actualPath)
+	}
+	tmpl, err := template.New(actualPath).Funcs(tftpTemplateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, errors.Internal.Wrapf(err, "failed to parse template file: %s", actualPath)
+	}
+	buffer := &bytes.Buffer{}
+	err = tmpl.Execute(buffer, data)
+	if err != nil {
+		return nil, errors.Internal.Wrapf(err, "failed to render template file: %s", actualPath)
+	}
+	return buffer.Bytes(), nil
+}
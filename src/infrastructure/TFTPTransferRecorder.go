@@ -0,0 +1,227 @@
+package infrastructure
+
+import (
+	"github.com/pin/tftp/v3"
+	"io"
+	"net"
+	"rol/domain"
+	"sort"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+//tftpTransfersHistorySize max count of the finished transfers kept by the recorder
+const tftpTransfersHistorySize = 1000
+
+//tftpByteCounter counts bytes of the transfer, it's read by the live view while transfer is in progress
+type tftpByteCounter struct {
+	count int64
+}
+
+func (c *tftpByteCounter) add(n int) {
+	atomic.AddInt64(&c.count, int64(n))
+}
+
+func (c *tftpByteCounter) get() int64 {
+	return atomic.LoadInt64(&c.count)
+}
+
+//countingReader reader that counts read bytes
+type countingReader struct {
+	reader  io.Reader
+	counter *tftpByteCounter
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.reader.Read(p)
+	r.counter.add(n)
+	return n, err
+}
+
+//countingWriter writer that counts written bytes
+type countingWriter struct {
+	writer  io.Writer
+	counter *tftpByteCounter
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.writer.Write(p)
+	w.counter.add(n)
+	return n, err
+}
+
+type tftpActiveTransfer struct {
+	transfer domain.TFTPTransfer
+	counter  *tftpByteCounter
+}
+
+//tftpTransferRecorder records transfers of the TFTP server, it's used as pin/tftp server hook.
+//Handlers begin the transfer with its actual path and bytes counter, the hook finishes it
+type tftpTransferRecorder struct {
+	mutex  sync.Mutex
+	active map[string]*tftpActiveTransfer
+	//history finished transfers ring buffer, next is the index for the next record
+	history []domain.TFTPTransfer
+	next    int
+	paths   map[string]*domain.TFTPPathStats
+	//onFinished is called for each finished transfer outside the recorder lock, can be nil
+	onFinished func(transfer domain.TFTPTransfer)
+}
+
+func newTFTPTransferRecorder(onFinished func(transfer domain.TFTPTransfer)) *tftpTransferRecorder {
+	return &tftpTransferRecorder{
+		active:     map[string]*tftpActiveTransfer{},
+		history:    make([]domain.TFTPTransfer, 0, tftpTransfersHistorySize),
+		paths:      map[string]*domain.TFTPPathStats{},
+		onFinished: onFinished,
+	}
+}
+
+//tftpTransferKey unique key of the transfer, pin/tftp uses client port as transfer id
+func tftpTransferKey(ip net.IP, port int) string {
+	return net.JoinHostPort(ip.String(), strconv.Itoa(port))
+}
+
+//begin registers new active transfer
+//
+//Params:
+//	remoteAddr - client address
+//	filename - file name requested by the client
+//	actualPath - actual file path, can be empty
+//	isUpload - true for write requests
+//Return:
+//	*tftpByteCounter - counter of the transfer bytes
+func (r *tftpTransferRecorder) begin(remoteAddr net.UDPAddr, filename, actualPath string, isUpload bool) *tftpByteCounter {
+	key := tftpTransferKey(remoteAddr.IP, remoteAddr.Port)
+	counter := &tftpByteCounter{}
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.active[key] = &tftpActiveTransfer{
+		transfer: domain.TFTPTransfer{
+			ClientAddress: key,
+			IsUpload:      isUpload,
+			VirtualPath:   filename,
+			ActualPath:    actualPath,
+			StartedAt:     time.Now(),
+			State:         domain.TFTPTransferActive,
+		},
+		counter: counter,
+	}
+	return counter
+}
+
+func (r *tftpTransferRecorder) finish(stats tftp.TransferStats, err error) {
+	transfer := r.record(stats, err)
+	if r.onFinished != nil {
+		r.onFinished(transfer)
+	}
+}
+
+//findActive finds active transfer of the finished transfer stats.
+//Upload gets its transfer id with the first data packet, so upload that failed before it is reported
+//with zero transfer id and it's found by client IP and file name
+func (r *tftpTransferRecorder) findActive(stats tftp.TransferStats) (string, *tftpActiveTransfer) {
+	key := tftpTransferKey(stats.RemoteAddr, stats.Tid)
+	if active, ok := r.active[key]; ok || stats.Tid != 0 {
+		return key, active
+	}
+	for activeKey, active := range r.active {
+		host, _, _ := net.SplitHostPort(activeKey)
+		if active.transfer.IsUpload && active.transfer.VirtualPath == stats.Filename && host == stats.RemoteAddr.String() {
+			return activeKey, active
+		}
+	}
+	return key, nil
+}
+
+func (r *tftpTransferRecorder) record(stats tftp.TransferStats, err error) domain.TFTPTransfer {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	transfer := domain.TFTPTransfer{
+		VirtualPath: stats.Filename,
+		StartedAt:   time.Now().Add(-stats.Duration),
+	}
+	if stats.RemoteAddr != nil {
+		key, active := r.findActive(stats)
+		transfer.ClientAddress = key
+		if active != nil {
+			transfer = active.transfer
+			transfer.Bytes = active.counter.get()
+			delete(r.active, key)
+		}
+	}
+	transfer.Duration = stats.Duration
+	transfer.State = domain.TFTPTransferSucceeded
+	if err != nil {
+		transfer.State = domain.TFTPTransferFailed
+		transfer.Error = err.Error()
+	}
+	if len(r.history) < tftpTransfersHistorySize {
+		r.history = append(r.history, transfer)
+	} else {
+		r.history[r.next] = transfer
+	}
+	r.next = (r.next + 1) % tftpTransfersHistorySize
+	//requests rejected before parsing have no file name
+	if transfer.VirtualPath == "" {
+		return transfer
+	}
+	pathStats, ok := r.paths[transfer.VirtualPath]
+	if !ok {
+		pathStats = &domain.TFTPPathStats{VirtualPath: transfer.VirtualPath}
+		r.paths[transfer.VirtualPath] = pathStats
+	}
+	if err != nil {
+		pathStats.Failed++
+	} else {
+		pathStats.Succeeded++
+	}
+	pathStats.Bytes += transfer.Bytes
+	pathStats.LastTransferAt = time.Now()
+	return transfer
+}
+
+//OnSuccess implements tftp.Hook interface
+func (r *tftpTransferRecorder) OnSuccess(stats tftp.TransferStats) {
+	r.finish(stats, nil)
+}
+
+//OnFailure implements tftp.Hook interface
+func (r *tftpTransferRecorder) OnFailure(stats tftp.TransferStats, err error) {
+	r.finish(stats, err)
+}
+
+//getTransfers returns active transfers and finished transfers, the newest first
+func (r *tftpTransferRecorder) getTransfers() []domain.TFTPTransfer {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	transfers := make([]domain.TFTPTransfer, 0, len(r.active)+len(r.history))
+	now := time.Now()
+	for _, active := range r.active {
+		transfer := active.transfer
+		transfer.Bytes = active.counter.get()
+		transfer.Duration = now.Sub(transfer.StartedAt)
+		transfers = append(transfers, transfer)
+	}
+	transfers = append(transfers, r.history...)
+	sort.SliceStable(transfers, func(i, j int) bool {
+		return transfers[i].StartedAt.After(transfers[j].StartedAt)
+	})
+	return transfers
+}
+
+//getPathStats returns transfer counters sorted by virtual path
+func (r *tftpTransferRecorder) getPathStats() []domain.TFTPPathStats {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	stats := make([]domain.TFTPPathStats, 0, len(r.paths))
+	for _, pathStats := range r.paths {
+		stats = append(stats, *pathStats)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].VirtualPath < stats[j].VirtualPath
+	})
+	return stats
+}
@@ -2,9 +2,11 @@ package infrastructure
 
 import (
 	"fmt"
+	"net"
 	"regexp"
 	"rol/app/errors"
 	"rol/app/interfaces"
+	"rol/domain"
 	"strconv"
 	"strings"
 )
@@ -24,24 +26,129 @@ const (
 	ErrorExecuteTelnet = "error executing telnet commands"
 )
 
+const (
+	//tpLinkPortsCount number of the switch ports
+	tpLinkPortsCount = 10
+	//tpLinkPOEPortsCount number of the first switch ports with PoE+
+	tpLinkPOEPortsCount = 8
+	//tpLinkPOEBudget switch PoE power budget in watts
+	tpLinkPOEBudget = 150
+	//tpLinkMaxVLANs max number of VLANs on the switch
+	tpLinkMaxVLANs = 4094
+)
+
 //TPLinkEthernetSwitchManager is a struct for tp link ethernet switch management
 type TPLinkEthernetSwitchManager struct {
-	telnetConn *TelnetConnection
-	address    string
-	login      string
-	password   string
+	cliConn  interfaces.ISwitchCLIConnection
+	address  string
+	login    string
+	password string
+	//cliLogin credentials are requested by the CLI after connect, false for ssh where they are checked by transport
+	cliLogin bool
+	//sessions opened CLI sessions, the session is in privileged mode between manager calls
+	sessions *SwitchCLISessionPool
+	//sessionKey key of the manager session in the sessions pool
+	sessionKey string
+}
+
+//newTPLinkEthernetSwitchDriver creates driver of the TP-Link switches managed over telnet or ssh CLI
+//
+//Params:
+//	sessions - CLI sessions pool shared by all managers of the driver
+//Return:
+//	EthernetSwitchDriver - switch driver
+func newTPLinkEthernetSwitchDriver(sessions *SwitchCLISessionPool) EthernetSwitchDriver {
+	return EthernetSwitchDriver{
+		Models: []domain.EthernetSwitchModel{{
+			Model:        "TL-SG2210MP",
+			Manufacturer: "TP-Link",
+			Code:         "tl-sg2210mp",
+			Capabilities: []string{domain.EthernetSwitchCapabilityVLAN, domain.EthernetSwitchCapabilityPOE, domain.EthernetSwitchCapabilityLLDP},
+		}},
+		NewManager: func(ethernetSwitch domain.EthernetSwitch) (interfaces.IEthernetSwitchManager, error) {
+			return NewTPLinkEthernetSwitchManager(sessions, ethernetSwitch), nil
+		},
+	}
 }
 
-//NewTPLinkEthernetSwitchManager constructor for TPLinkEthernetSwitchManager
-func NewTPLinkEthernetSwitchManager(address, login, password string) interfaces.IEthernetSwitchManager {
-	tNet := NewTelnetConnection()
+//NewTPLinkEthernetSwitchManager constructor for TPLinkEthernetSwitchManager, CLI transport is selected by switch transport
+//
+//Params:
+//	sessions - CLI sessions pool
+//	ethernetSwitch - switch entity
+//Return:
+//	interfaces.IEthernetSwitchManager - tp link switch manager
+func NewTPLinkEthernetSwitchManager(sessions *SwitchCLISessionPool, ethernetSwitch domain.EthernetSwitch) interfaces.IEthernetSwitchManager {
+	if ethernetSwitch.Transport == domain.EthernetSwitchTransportSSH {
+		sshConn := NewSSHConnection(ethernetSwitch.Username, ethernetSwitch.Password, ethernetSwitch.SSHHostKeyFingerprint)
+		return newTPLinkEthernetSwitchManager(sessions, sshConn, net.JoinHostPort(ethernetSwitch.Address, "22"),
+			ethernetSwitch.Username, ethernetSwitch.Password)
+	}
+	return newTPLinkEthernetSwitchManager(sessions, NewTelnetConnection(), net.JoinHostPort(ethernetSwitch.Address, "23"),
+		ethernetSwitch.Username, ethernetSwitch.Password)
+}
+
+//NewTPLinkEthernetSwitchManagerWithConnection constructor for TPLinkEthernetSwitchManager with the given CLI connection,
+//the manager keeps its session in own sessions pool
+//
+//Params:
+//	cliConn - switch CLI connection
+//	address - switch CLI address with port
+//	login - switch user name
+//	password - switch user password
+//Return:
+//	interfaces.IEthernetSwitchManager - tp link switch manager
+func NewTPLinkEthernetSwitchManagerWithConnection(cliConn interfaces.ISwitchCLIConnection, address, login, password string) interfaces.IEthernetSwitchManager {
+	sessions := NewSwitchCLISessionPool(switchCLISessionIdleTimeout, switchCLISessionKeepAliveInterval)
+	return newTPLinkEthernetSwitchManager(sessions, cliConn, address, login, password)
+}
+
+func newTPLinkEthernetSwitchManager(sessions *SwitchCLISessionPool, cliConn interfaces.ISwitchCLIConnection,
+	address, login, password string) *TPLinkEthernetSwitchManager {
+	sshConn, isSSH := cliConn.(*SSHConnection)
+	sessionKey := SwitchCLISessionKey(login, address, domain.EthernetSwitchTransportTelnet, password)
+	if isSSH {
+		sessionKey = SwitchCLISessionKey(login, address, domain.EthernetSwitchTransportSSH, password, sshConn.hostKeyFingerprint)
+	}
 	return &TPLinkEthernetSwitchManager{
-		telnetConn: tNet,
-		address:    address,
-		login:      login,
+		cliConn: cliConn,
+		address: address,
+		login:   login,
 		//  pragma: allowlist nextline secret
-		password: password,
+		password:   password,
+		cliLogin:   !isSSH,
+		sessions:   sessions,
+		sessionKey: sessionKey,
+	}
+}
+
+//session runs action in the switch CLI session, the session is in privileged mode before and after the action
+func (t *TPLinkEthernetSwitchManager) session(action func(conn interfaces.ISwitchCLIConnection) error) error {
+	return t.sessions.Do(t.sessionKey, t.openSession, t.keepAlive, action)
+}
+
+//openSession connects to the switch CLI, logs in and enters privileged mode
+func (t *TPLinkEthernetSwitchManager) openSession() (interfaces.ISwitchCLIConnection, error) {
+	err := t.cliConn.Connect(t.address)
+	if err != nil {
+		return nil, errors.Internal.Wrap(err, ErrorCreatingConnection)
+	}
+	err = t.logIn()
+	if err != nil {
+		_ = t.cliConn.Close()
+		return nil, errors.Internal.Wrap(err, ErrorLoginIn)
 	}
+	err = t.cliConn.Send("enable")
+	if err != nil {
+		_ = t.cliConn.Close()
+		return nil, errors.Internal.Wrap(err, ErrorEnablingTelnet)
+	}
+	return t.cliConn, nil
+}
+
+//keepAlive sends empty command, the switch answers with the prompt that is skipped by next reads
+func (t *TPLinkEthernetSwitchManager) keepAlive(conn interfaces.ISwitchCLIConnection) error {
+	return conn.Send("")
 }
 
 //GetVLANs gets all VLANs on switch
@@ -50,31 +157,32 @@ func NewTPLinkEthernetSwitchManager(address, login, password string) interfaces.
 //	[]int - slice of VLANs
 //	error - if an error occurs, otherwise nil
 func (t *TPLinkEthernetSwitchManager) GetVLANs() ([]int, error) {
-	err := t.telnetConn.Connect(t.address)
-	if err != nil {
-		return []int{}, errors.Internal.Wrap(err, ErrorCreatingConnection)
-	}
-	err = t.logIn()
-	if err != nil {
-		return []int{}, errors.Internal.Wrap(err, ErrorLoginIn)
-	}
-	err = t.telnetConn.Send("enable")
+	out := []int{}
+	err := t.session(func(conn interfaces.ISwitchCLIConnection) error {
+		var err error
+		out, err = t.readVLANs(conn)
+		return err
+	})
 	if err != nil {
-		return []int{}, errors.Internal.Wrap(err, ErrorEnablingTelnet)
+		return []int{}, err
 	}
-	err = t.telnetConn.Send("show vlan")
+	return out, nil
+}
+
+func (t *TPLinkEthernetSwitchManager) readVLANs(conn interfaces.ISwitchCLIConnection) ([]int, error) {
+	err := conn.Send("show vlan")
 	if err != nil {
-		return []int{}, errors.Internal.Wrap(err, "showing vlan error")
+		return nil, errors.Internal.Wrap(err, "showing vlan error")
 	}
-	_, err = t.telnetConn.Read("-----------\r\n")
+	_, err = conn.Read("-----------\r\n")
 	if err != nil {
-		return []int{}, errors.Internal.Wrap(err, ErrorReadingTelnet)
+		return nil, errors.Internal.Wrap(err, ErrorReadingTelnet)
 	}
 	out := []int{}
 	for {
-		msg, err := t.telnetConn.Read("\r")
+		msg, err := conn.Read("\r")
 		if err != nil {
-			return []int{}, errors.Internal.Wrap(err, ErrorReadingTelnet)
+			return nil, errors.Internal.Wrap(err, ErrorReadingTelnet)
 		}
 		fields := strings.Fields(msg)
 		if len(fields) == 0 {
@@ -112,52 +220,87 @@ func (t *TPLinkEthernetSwitchManager) GetVLANs() ([]int, error) {
 //	[]int - slice of tagged VLANs IDs
 //	error - if an error occurs, otherwise nil
 func (t *TPLinkEthernetSwitchManager) GetVLANsOnPort(portName string) (int, []int, error) {
-	err := t.telnetConn.Connect(t.address)
-	if err != nil {
-		return 0, []int{}, errors.Internal.Wrap(err, ErrorCreatingConnection)
-	}
-	err = t.logIn()
-	if err != nil {
-		return 0, []int{}, errors.Internal.Wrap(err, ErrorLoginIn)
-	}
-	portNumber := portName[2:]
-	err = t.telnetConn.Send("enable")
-	if err != nil {
-		return 0, []int{}, errors.Internal.Wrap(err, ErrorEnablingTelnet)
-	}
-	err = t.telnetConn.Send("show interface switchport gigabitEthernet " + portNumber)
-	if err != nil {
-		return 0, []int{}, errors.Internal.Wrap(err, ErrorShowInterface)
-	}
-
-	msg, err := t.telnetConn.Read("-----------\r\n")
-	if err != nil {
-		return 0, []int{}, errors.Internal.Wrap(err, ErrorReadingTelnet)
-	}
-
-	msg, err = t.telnetConn.Read("\r\n\n\r")
-	if err != nil {
-		return 0, []int{}, errors.Internal.Wrap(err, ErrorReadingTelnet)
-	}
 	untaggedVLAN := 0
 	taggedVLANs := []int{}
-	str := msg[:len(msg)-4]
-	vlansInfo := strings.Split(str, "\r\n")
-	for _, vlan := range vlansInfo {
-		fields := strings.Fields(vlan)
-		id, err := strconv.Atoi(fields[0])
+	err := t.session(func(conn interfaces.ISwitchCLIConnection) error {
+		portNumber := portName[2:]
+		err := conn.Send("show interface switchport gigabitEthernet " + portNumber)
 		if err != nil {
-			return 0, nil, errors.Internal.Wrap(err, "convert string to int failed")
+			return errors.Internal.Wrap(err, ErrorShowInterface)
 		}
-		if fields[2] == "Untagged" {
-			untaggedVLAN = id
-		} else {
-			taggedVLANs = append(taggedVLANs, id)
+		_, err = conn.Read("-----------\r\n")
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorReadingTelnet)
+		}
+		msg, err := conn.Read("\r\n\n\r")
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorReadingTelnet)
+		}
+		str := msg[:len(msg)-4]
+		vlansInfo := strings.Split(str, "\r\n")
+		for _, vlan := range vlansInfo {
+			fields := strings.Fields(vlan)
+			if len(fields) < 3 {
+				continue
+			}
+			id, err := strconv.Atoi(fields[0])
+			if err != nil {
+				return errors.Internal.Wrap(err, "convert string to int failed")
+			}
+			if fields[2] == "Untagged" {
+				untaggedVLAN = id
+			} else {
+				taggedVLANs = append(taggedVLANs, id)
+			}
 		}
+		return nil
+	})
+	if err != nil {
+		return 0, []int{}, err
 	}
 	return untaggedVLAN, taggedVLANs, nil
 }
 
+//GetPortPVID gets port PVID
+//
+//Params:
+//	portName - port name
+//Return:
+//	int - PVID
+//	error - if an error occurs, otherwise nil
+func (t *TPLinkEthernetSwitchManager) GetPortPVID(portName string) (int, error) {
+	pvid := 0
+	err := t.session(func(conn interfaces.ISwitchCLIConnection) error {
+		portNumber := portName[2:]
+		err := conn.Send("show interface switchport gigabitEthernet " + portNumber)
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorShowInterface)
+		}
+		_, err = conn.Read("PVID:")
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorReadingTelnet)
+		}
+		msg, err := conn.Read("\r\n")
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorReadingTelnet)
+		}
+		pvid, err = strconv.Atoi(strings.TrimSpace(msg))
+		if err != nil {
+			return errors.Internal.Wrap(err, "convert string to int failed")
+		}
+		//VLANs table is read to the end, so the next command output is not mixed with it
+		_, err = conn.Read("\r\n\n\r")
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorReadingTelnet)
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return pvid, nil
+}
+
 //AddTaggedVLANOnPort add tagged VLAN on given port
 //
 //Params:
@@ -166,7 +309,11 @@ func (t *TPLinkEthernetSwitchManager) GetVLANsOnPort(portName string) (int, []in
 //Return:
 //	error - if an error occurs, otherwise nil
 func (t *TPLinkEthernetSwitchManager) AddTaggedVLANOnPort(portName string, vlanID int) error {
-	return t.addVLANOnPort(portName, "tagged", vlanID)
+	return t.ApplyOperations([]domain.EthernetSwitchOperation{{
+		Type:     domain.EthernetSwitchOperationAddTaggedVLAN,
+		PortName: portName,
+		VLANID:   vlanID,
+	}})
 }
 
 //RemoveVLANFromPort remove tagged VLAN from given port
@@ -177,22 +324,11 @@ func (t *TPLinkEthernetSwitchManager) AddTaggedVLANOnPort(portName string, vlanI
 //Return:
 //	error - if an error occurs, otherwise nil
 func (t *TPLinkEthernetSwitchManager) RemoveVLANFromPort(portName string, vlanID int) error {
-	err := t.telnetConn.Connect(t.address)
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorCreatingConnection)
-	}
-	err = t.logIn()
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorLoginIn)
-	}
-	portNumber := portName[2:]
-	exec := fmt.Sprintf("enable;config;interface gigabitEthernet %s;no switchport general allowed vlan %d;exit;exit;exit", portNumber, vlanID)
-	err = t.executeTelnetCommands(exec)
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorExecuteTelnet)
-	}
-
-	return nil
+	return t.ApplyOperations([]domain.EthernetSwitchOperation{{
+		Type:     domain.EthernetSwitchOperationRemoveVLAN,
+		PortName: portName,
+		VLANID:   vlanID,
+	}})
 }
 
 //AddUntaggedVLANOnPort sets tagged VLAN on given port
@@ -203,7 +339,11 @@ func (t *TPLinkEthernetSwitchManager) RemoveVLANFromPort(portName string, vlanID
 //Return:
 //	error - if an error occurs, otherwise nil
 func (t *TPLinkEthernetSwitchManager) AddUntaggedVLANOnPort(portName string, vlanID int) error {
-	return t.addVLANOnPort(portName, "untagged", vlanID)
+	return t.ApplyOperations([]domain.EthernetSwitchOperation{{
+		Type:     domain.EthernetSwitchOperationAddUntaggedVLAN,
+		PortName: portName,
+		VLANID:   vlanID,
+	}})
 }
 
 //SetPortPVID set PVID on given port
@@ -214,21 +354,11 @@ func (t *TPLinkEthernetSwitchManager) AddUntaggedVLANOnPort(portName string, vla
 //Return:
 //	error - if an error occurs, otherwise nil
 func (t *TPLinkEthernetSwitchManager) SetPortPVID(portName string, vlanID int) error {
-	err := t.telnetConn.Connect(t.address)
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorCreatingConnection)
-	}
-	err = t.logIn()
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorLoginIn)
-	}
-	portNumber := portName[2:]
-	exec := fmt.Sprintf("enable;config;interface gigabitEthernet %s;switchport pvid %d;end;exit;exit", portNumber, vlanID)
-	err = t.executeTelnetCommands(exec)
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorExecuteTelnet)
-	}
-	return nil
+	return t.ApplyOperations([]domain.EthernetSwitchOperation{{
+		Type:     domain.EthernetSwitchOperationSetPVID,
+		PortName: portName,
+		VLANID:   vlanID,
+	}})
 }
 
 //DeleteVLAN delete VLAN by id
@@ -238,20 +368,10 @@ func (t *TPLinkEthernetSwitchManager) SetPortPVID(portName string, vlanID int) e
 //Return:
 //	error - if an error occurs, otherwise nil
 func (t *TPLinkEthernetSwitchManager) DeleteVLAN(vlanID int) error {
-	err := t.telnetConn.Connect(t.address)
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorCreatingConnection)
-	}
-	err = t.logIn()
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorLoginIn)
-	}
-	exec := fmt.Sprintf("enable;config;no vlan %d;exit;exit;exit", vlanID)
-	err = t.executeTelnetCommands(exec)
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorExecuteTelnet)
-	}
-	return nil
+	return t.ApplyOperations([]domain.EthernetSwitchOperation{{
+		Type:   domain.EthernetSwitchOperationDeleteVLAN,
+		VLANID: vlanID,
+	}})
 }
 
 //CreateVLAN create vlan on switch
@@ -261,20 +381,10 @@ func (t *TPLinkEthernetSwitchManager) DeleteVLAN(vlanID int) error {
 //Return:
 //	error - if an error occurs, otherwise nil
 func (t *TPLinkEthernetSwitchManager) CreateVLAN(vlanID int) error {
-	err := t.telnetConn.Connect(t.address)
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorCreatingConnection)
-	}
-	err = t.logIn()
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorLoginIn)
-	}
-	exec := fmt.Sprintf("enable;config;vlan %d;exit;exit", vlanID)
-	err = t.executeTelnetCommands(exec)
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorExecuteTelnet)
-	}
-	return nil
+	return t.ApplyOperations([]domain.EthernetSwitchOperation{{
+		Type:   domain.EthernetSwitchOperationCreateVLAN,
+		VLANID: vlanID,
+	}})
 }
 
 //GetPOEPortStatus gets poe status on give port
@@ -285,33 +395,32 @@ func (t *TPLinkEthernetSwitchManager) CreateVLAN(vlanID int) error {
 //	string - poe port status "enable" or "disable"
 //	error - if an error occurs, otherwise nil
 func (t *TPLinkEthernetSwitchManager) GetPOEPortStatus(portName string) (string, error) {
-	err := t.telnetConn.Connect(t.address)
-	if err != nil {
-		return "", errors.Internal.Wrap(err, ErrorCreatingConnection)
-	}
-	err = t.logIn()
-	if err != nil {
-		return "", errors.Internal.Wrap(err, ErrorLoginIn)
-	}
-	portNumber := portName[2:]
-	err = t.telnetConn.Send("enable")
-	if err != nil {
-		return "", errors.Internal.Wrap(err, ErrorEnablingTelnet)
-	}
-	err = t.telnetConn.Send("show power inline configuration interface gigabitEthernet " + portNumber)
-	if err != nil {
-		return "", errors.Internal.Wrap(err, ErrorShowInterface)
-	}
-	_, err = t.telnetConn.Read("-----------\r\n")
-	if err != nil {
-		return "", errors.Internal.Wrap(err, ErrorReadingTelnet)
-	}
-	msg, err := t.telnetConn.Read("\r\n\n\r")
+	status := ""
+	err := t.session(func(conn interfaces.ISwitchCLIConnection) error {
+		portNumber := portName[2:]
+		err := conn.Send("show power inline configuration interface gigabitEthernet " + portNumber)
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorShowInterface)
+		}
+		_, err = conn.Read("-----------\r\n")
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorReadingTelnet)
+		}
+		msg, err := conn.Read("\r\n\n\r")
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorReadingTelnet)
+		}
+		portConfig := strings.Fields(msg)
+		if len(portConfig) < 2 {
+			return errors.Internal.Newf("unexpected poe configuration of the port %s", portName)
+		}
+		status = strings.ToLower(portConfig[1])
+		return nil
+	})
 	if err != nil {
-		return "", errors.Internal.Wrap(err, ErrorReadingTelnet)
+		return "", err
 	}
-	portConfig := strings.Fields(msg)
-	return portConfig[1], nil
+	return status, nil
 }
 
 //EnablePOEPort enable poe on given port
@@ -322,29 +431,11 @@ func (t *TPLinkEthernetSwitchManager) GetPOEPortStatus(portName string) (string,
 //Return:
 //	error - if an error occurs, otherwise nil
 func (t *TPLinkEthernetSwitchManager) EnablePOEPort(portName, poeType string) error {
-	err := t.telnetConn.Connect(t.address)
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorCreatingConnection)
-	}
-	err = t.logIn()
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorLoginIn)
-	}
-	consumption := ""
-	switch poeType {
-	case "passive24":
-		return errors.Internal.Wrap(err, "this switch does not support passive24 poe")
-	default:
-		consumption = "auto"
-	}
-
-	portNumber := portName[2:]
-	exec := fmt.Sprintf("enable;config;interface gigabitEthernet %s;power inline consumption %s;power inline supply enable;exit;exit;exit;exit", portNumber, consumption)
-	err = t.executeTelnetCommands(exec)
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorExecuteTelnet)
-	}
-	return nil
+	return t.ApplyOperations([]domain.EthernetSwitchOperation{{
+		Type:     domain.EthernetSwitchOperationEnablePOE,
+		PortName: portName,
+		POEType:  poeType,
+	}})
 }
 
 //DisablePOEPort disable poe on given port
@@ -354,114 +445,269 @@ func (t *TPLinkEthernetSwitchManager) EnablePOEPort(portName, poeType string) er
 //Return:
 //	error - if an error occurs, otherwise nil
 func (t *TPLinkEthernetSwitchManager) DisablePOEPort(portName string) error {
-	err := t.telnetConn.Connect(t.address)
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorCreatingConnection)
-	}
-	err = t.logIn()
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorLoginIn)
-	}
-	portNumber := portName[2:]
-	exec := fmt.Sprintf("enable;config;interface gigabitEthernet %s;power inline supply disable;exit;exit;exit;exit", portNumber)
-	err = t.executeTelnetCommands(exec)
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorExecuteTelnet)
-	}
-	return nil
+	return t.ApplyOperations([]domain.EthernetSwitchOperation{{
+		Type:     domain.EthernetSwitchOperationDisablePOE,
+		PortName: portName,
+	}})
 }
 
-//SaveConfig Save current settings on switch
+//GetCapabilities gets features of the switch, TL-SG2210MP has 8 gigabit PoE+ ports and 2 SFP ports
 //
 //Return:
+//	domain.EthernetSwitchCapabilities - switch capabilities
 //	error - if an error occurs, otherwise nil
-func (t *TPLinkEthernetSwitchManager) SaveConfig() error {
-	err := t.telnetConn.Connect(t.address)
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorCreatingConnection)
-	}
-	err = t.logIn()
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorLoginIn)
-	}
-	exec := fmt.Sprintf("enable;copy running-config startup-config;exit;exit")
-	err = t.executeTelnetCommands(exec)
-	if err != nil {
-		return errors.Internal.Wrap(err, ErrorExecuteTelnet)
+func (t *TPLinkEthernetSwitchManager) GetCapabilities() (domain.EthernetSwitchCapabilities, error) {
+	capabilities := domain.EthernetSwitchCapabilities{
+		MaxVLANs:  tpLinkMaxVLANs,
+		POETypes:  []string{"poe", "poe+"},
+		POEBudget: tpLinkPOEBudget,
+	}
+	for port := 1; port <= tpLinkPortsCount; port++ {
+		portName := fmt.Sprintf("gi1/0/%d", port)
+		capabilities.Ports = append(capabilities.Ports, portName)
+		if port <= tpLinkPOEPortsCount {
+			capabilities.POEPorts = append(capabilities.POEPorts, portName)
+		}
 	}
-	return nil
-
+	return capabilities, nil
 }
 
-func (t *TPLinkEthernetSwitchManager) logIn() (err error) {
-	_, err = t.telnetConn.Read("Login")
-	if err != nil {
-		return errors.Internal.Wrap(err, "error waiting for login string")
+//ApplyOperations applies operations within one CLI session, the VLANs existence is checked before
+//entering the configuration mode, then all configuration commands are sent at once
+//
+//Params:
+//	operations - switch configuration changes
+//Return:
+//	error - if an error occurs, otherwise nil
+func (t *TPLinkEthernetSwitchManager) ApplyOperations(operations []domain.EthernetSwitchOperation) error {
+	if len(operations) == 0 {
+		return nil
 	}
-	err = t.telnetConn.Send(t.login)
-	if err != nil {
-		return errors.Internal.Wrap(err, "login send error")
+	return t.session(func(conn interfaces.ISwitchCLIConnection) error {
+		//vlansExistence VLANs existence on the switch after already processed operations
+		vlansExistence := map[int]bool{}
+		commands := []string{"config"}
+		for _, operation := range operations {
+			operationCommands, err := t.getOperationCommands(conn, operation, vlansExistence)
+			if err != nil {
+				return err
+			}
+			commands = append(commands, operationCommands...)
+		}
+		commands = append(commands, "end")
+		err := t.executeCLICommands(conn, commands)
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorExecuteTelnet)
+		}
+		return nil
+	})
+}
+
+//getOperationCommands gets configuration mode commands of the operation
+func (t *TPLinkEthernetSwitchManager) getOperationCommands(conn interfaces.ISwitchCLIConnection, operation domain.EthernetSwitchOperation,
+	vlansExistence map[int]bool) ([]string, error) {
+	portInterface := ""
+	if operation.PortName != "" {
+		portInterface = "interface gigabitEthernet " + operation.PortName[2:]
+	}
+	switch operation.Type {
+	case domain.EthernetSwitchOperationCreateVLAN:
+		vlansExistence[operation.VLANID] = true
+		return []string{fmt.Sprintf("vlan %d", operation.VLANID), "exit"}, nil
+	case domain.EthernetSwitchOperationDeleteVLAN:
+		vlansExistence[operation.VLANID] = false
+		return []string{fmt.Sprintf("no vlan %d", operation.VLANID)}, nil
+	case domain.EthernetSwitchOperationAddTaggedVLAN, domain.EthernetSwitchOperationAddUntaggedVLAN:
+		vlanExist, known := vlansExistence[operation.VLANID]
+		if !known {
+			var err error
+			vlanExist, err = t.isVLANExists(conn, operation.VLANID)
+			if err != nil {
+				return nil, errors.Internal.Wrap(err, "failed check vlan existence")
+			}
+			vlansExistence[operation.VLANID] = vlanExist
+		}
+		if !vlanExist {
+			return nil, errors.NotFound.New("vlan not found")
+		}
+		vlanType := "tagged"
+		if operation.Type == domain.EthernetSwitchOperationAddUntaggedVLAN {
+			vlanType = "untagged"
+		}
+		return []string{portInterface, fmt.Sprintf("switchport general allowed vlan %d %s", operation.VLANID, vlanType), "exit"}, nil
+	case domain.EthernetSwitchOperationRemoveVLAN:
+		return []string{portInterface, fmt.Sprintf("no switchport general allowed vlan %d", operation.VLANID), "exit"}, nil
+	case domain.EthernetSwitchOperationSetPVID:
+		return []string{portInterface, fmt.Sprintf("switchport pvid %d", operation.VLANID), "exit"}, nil
+	case domain.EthernetSwitchOperationEnablePOE:
+		if operation.POEType == "passive24" {
+			return nil, errors.Internal.New("this switch does not support passive24 poe")
+		}
+		return []string{portInterface, "power inline consumption auto", "power inline supply enable", "exit"}, nil
+	case domain.EthernetSwitchOperationDisablePOE:
+		return []string{portInterface, "power inline supply disable", "exit"}, nil
 	}
-	_, err = t.telnetConn.Read("Password")
+	return nil, errors.Internal.Newf("unknown switch operation %s", operation.Type)
+}
+
+//GetRunningConfig gets full running configuration of the switch, header line with the switch model is skipped
+//
+//Return:
+//	string - running configuration text
+//	error - if an error occurs, otherwise nil
+func (t *TPLinkEthernetSwitchManager) GetRunningConfig() (string, error) {
+	config := ""
+	err := t.session(func(conn interfaces.ISwitchCLIConnection) error {
+		err := conn.Send("show running-config")
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorExecuteTelnet)
+		}
+		_, err = conn.Read("\r\n!")
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorReadingTelnet)
+		}
+		msg, err := conn.Read("\r\n" + tpLinkConfigEnd + "\r\n")
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorReadingTelnet)
+		}
+		headerEnd := strings.Index(msg, "\r\n")
+		config = strings.ReplaceAll(msg[headerEnd+2:], "\r\n", "\n")
+		return nil
+	})
 	if err != nil {
-		return errors.Internal.Wrap(err, "error waiting for password string")
+		return "", err
 	}
-	err = t.telnetConn.Send(t.password)
+	return config, nil
+}
+
+//RestoreConfig applies VLANs, ports VLANs membership, PVID and PoE state from the running configuration
+//
+//Params:
+//	config - running configuration text
+//Return:
+//	error - if an error occurs, otherwise nil
+func (t *TPLinkEthernetSwitchManager) RestoreConfig(config string) error {
+	operations, err := getRestoreOperations(t, config)
 	if err != nil {
-		return errors.Internal.Wrap(err, "password send error")
+		return err
 	}
-	return nil
+	return t.ApplyOperations(operations)
 }
 
-func (t *TPLinkEthernetSwitchManager) addVLANOnPort(portName, vlanType string, vlanID int) error {
-	err := t.telnetConn.Connect(t.address)
+//GetMACAddressTable gets MAC addresses learned on the switch ports
+//
+//Return:
+//	[]domain.EthernetSwitchMACAddress - MAC address forwarding table entries
+//	error - if an error occurs, otherwise nil
+func (t *TPLinkEthernetSwitchManager) GetMACAddressTable() ([]domain.EthernetSwitchMACAddress, error) {
+	table := []domain.EthernetSwitchMACAddress{}
+	err := t.session(func(conn interfaces.ISwitchCLIConnection) error {
+		err := conn.Send("show mac address-table all")
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorExecuteTelnet)
+		}
+		msg, err := conn.Read(tpLinkMACTableEnd)
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorReadingTelnet)
+		}
+		//addresses count is read to the end of line, so the next command output is not mixed with it
+		_, err = conn.Read("\r\n")
+		if err != nil {
+			return errors.Internal.Wrap(err, ErrorReadingTelnet)
+		}
+		table, err = parseTPLinkMACAddressTable(msg)
+		return err
+	})
 	if err != nil {
-		return errors.Internal.Wrap(err, ErrorCreatingConnection)
+		return []domain.EthernetSwitchMACAddress{}, err
 	}
-	err = t.logIn()
+	return table, nil
+}
+
+//GetLLDPNeighbors gets LLDP neighbors of the switch ports
+//
+//Return:
+//	[]domain.EthernetSwitchLLDPNeighbor - neighbors of all ports
+//	error - if an error occurs, otherwise nil
+func (t *TPLinkEthernetSwitchManager) GetLLDPNeighbors() ([]domain.EthernetSwitchLLDPNeighbor, error) {
+	capabilities, err := t.GetCapabilities()
 	if err != nil {
-		return errors.Internal.Wrap(err, ErrorLoginIn)
+		return []domain.EthernetSwitchLLDPNeighbor{}, err
 	}
+	return t.getLLDPNeighbors(capabilities.Ports)
+}
 
-	vlanExist, err := t.isVLANExists(vlanID)
+//getLLDPNeighbors reads LLDP neighbors of the given ports within one CLI session
+func (t *TPLinkEthernetSwitchManager) getLLDPNeighbors(ports []string) ([]domain.EthernetSwitchLLDPNeighbor, error) {
+	neighbors := []domain.EthernetSwitchLLDPNeighbor{}
+	err := t.session(func(conn interfaces.ISwitchCLIConnection) error {
+		for _, portName := range ports {
+			err := conn.Send("show lldp neighbor-information interface gigabitEthernet " + portName[2:])
+			if err != nil {
+				return errors.Internal.Wrap(err, ErrorShowInterface)
+			}
+			msg, err := conn.Read("\r\n\n\r")
+			if err != nil {
+				return errors.Internal.Wrap(err, ErrorReadingTelnet)
+			}
+			neighbors = append(neighbors, parseTPLinkLLDPNeighbors(portName, msg)...)
+		}
+		return nil
+	})
 	if err != nil {
-		return errors.Internal.Wrap(err, "failed check vlan existence")
+		return []domain.EthernetSwitchLLDPNeighbor{}, err
 	}
-	if vlanExist {
-		portNumber := portName[2:]
-		exec := fmt.Sprintf("enable;config;interface gigabitEthernet %s;switchport general allowed vlan %d %s;exit;exit;exit;", portNumber, vlanID, vlanType)
-		err = t.executeTelnetCommands(exec)
+	sortLLDPNeighbors(neighbors)
+	return neighbors, nil
+}
+
+//SaveConfig Save current settings on switch
+//
+//Return:
+//	error - if an error occurs, otherwise nil
+func (t *TPLinkEthernetSwitchManager) SaveConfig() error {
+	return t.session(func(conn interfaces.ISwitchCLIConnection) error {
+		err := t.executeCLICommands(conn, []string{"copy running-config startup-config"})
 		if err != nil {
 			return errors.Internal.Wrap(err, ErrorExecuteTelnet)
 		}
 		return nil
-	}
-	return errors.NotFound.New("vlan not found")
+	})
 }
 
-func (t *TPLinkEthernetSwitchManager) isVLANExists(vlanID int) (bool, error) {
-	err := t.telnetConn.Connect(t.address)
+func (t *TPLinkEthernetSwitchManager) logIn() (err error) {
+	if !t.cliLogin {
+		return nil
+	}
+	_, err = t.cliConn.Read("Login")
 	if err != nil {
-		return false, errors.Internal.Wrap(err, ErrorCreatingConnection)
+		return errors.Internal.Wrap(err, "error waiting for login string")
 	}
-	err = t.logIn()
+	err = t.cliConn.Send(t.login)
 	if err != nil {
-		return false, errors.Internal.Wrap(err, ErrorLoginIn)
+		return errors.Internal.Wrap(err, "login send error")
+	}
+	_, err = t.cliConn.Read("Password")
+	if err != nil {
+		return errors.Internal.Wrap(err, "error waiting for password string")
 	}
-	err = t.telnetConn.Send("enable")
+	err = t.cliConn.Send(t.password)
 	if err != nil {
-		return false, errors.Internal.Wrap(err, ErrorEnablingTelnet)
+		return errors.Internal.Wrap(err, "password send error")
 	}
-	exec := fmt.Sprintf("config;show vlan id %d", vlanID)
-	err = t.executeTelnetCommands(exec)
+	return nil
+}
+
+func (t *TPLinkEthernetSwitchManager) isVLANExists(conn interfaces.ISwitchCLIConnection, vlanID int) (bool, error) {
+	err := conn.Send(fmt.Sprintf("show vlan id %d", vlanID))
 	if err != nil {
 		return false, errors.Internal.Wrap(err, ErrorExecuteTelnet)
 	}
-	_, err = t.telnetConn.Read("---\r\n")
+	_, err = conn.Read("---\r\n")
 	if err != nil {
 		return false, errors.Internal.Wrap(err, ErrorReadingTelnet)
 	}
-	msg, err := t.telnetConn.Read("\r")
+	msg, err := conn.Read("\r")
 	if err != nil {
 		return false, errors.Internal.Wrap(err, ErrorReadingTelnet)
 	}
@@ -472,13 +718,12 @@ func (t *TPLinkEthernetSwitchManager) isVLANExists(vlanID int) (bool, error) {
 	return false, nil
 }
 
-func (t *TPLinkEthernetSwitchManager) executeTelnetCommands(exec string) error {
-	commands := strings.Split(exec, ";")
+func (t *TPLinkEthernetSwitchManager) executeCLICommands(conn interfaces.ISwitchCLIConnection, commands []string) error {
 	var err error
 	for _, command := range commands {
-		err = t.telnetConn.Send(command)
+		err = conn.Send(command)
 		if err != nil {
-			return errors.Internal.Wrap(err, "send command to telnet server failed")
+			return errors.Internal.Wrap(err, "send command to switch CLI failed")
 		}
 	}
 	return nil
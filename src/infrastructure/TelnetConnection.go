@@ -1,14 +1,32 @@
 package infrastructure
 
 import (
-	"github.com/reiver/go-telnet"
+	"bufio"
+	"bytes"
+	"io"
+	"net"
 	"rol/app/errors"
-	"strings"
+)
+
+const (
+	//cliOutputChunkSize size of the switch CLI output chunk that is read at once
+	cliOutputChunkSize = 4096
+	//telnetIAC telnet "interpret as command" byte
+	telnetIAC = 255
+	//telnetSB telnet subnegotiation start command
+	telnetSB = 250
+	//telnetSE telnet subnegotiation end command
+	telnetSE = 240
+	//telnetWILL telnet option negotiation command, WILL, WONT, DO and DONT commands follow one after another
+	telnetWILL = 251
+	//telnetDONT last telnet option negotiation command
+	telnetDONT = 254
 )
 
 //TelnetConnection structure for telnet connection
 type TelnetConnection struct {
-	bond *telnet.Conn
+	conn   net.Conn
+	output *cliOutputReader
 }
 
 //NewTelnetConnection constructor for TelnetConnection
@@ -16,20 +34,130 @@ func NewTelnetConnection() *TelnetConnection {
 	return &TelnetConnection{}
 }
 
-//Connect makes a connection with telnet server
+//Connect makes a connection with telnet server, previous connection is closed
 //
 //Params:
 //	address - telnet server address
 //Return:
 //	error - if an error occurs, otherwise nil
-func (t *TelnetConnection) Connect(address string) (err error) {
-	t.bond, err = telnet.DialTo(address)
-	if nil != err {
+func (t *TelnetConnection) Connect(address string) error {
+	_ = t.Close()
+	conn, err := net.Dial("tcp", address)
+	if err != nil {
+		return errors.Internal.Wrap(err, "error connecting to telnet server")
+	}
+	t.conn = conn
+	t.output = newCLIOutputReader(&telnetDataReader{buffered: bufio.NewReader(conn)})
+	return nil
+}
+
+//Close closes telnet connection if it is opened
+//
+//Return:
+//	error - if an error occurs, otherwise nil
+func (t *TelnetConnection) Close() error {
+	if t.conn == nil {
+		return nil
+	}
+	err := t.conn.Close()
+	t.conn = nil
+	t.output = nil
+	if err != nil {
+		return errors.Internal.Wrap(err, "error closing telnet connection")
+	}
+	return nil
+}
+
+//telnetDataReader reads data bytes of the telnet stream, telnet commands are skipped.
+//Unlike io.ReadFull it returns already received bytes without waiting for the whole buffer
+type telnetDataReader struct {
+	buffered *bufio.Reader
+}
+
+//Read reads at least one data byte and then all data bytes that are already received
+func (r *telnetDataReader) Read(data []byte) (int, error) {
+	n := 0
+	for n < len(data) && (n == 0 || r.buffered.Buffered() > 0) {
+		b, err := r.buffered.ReadByte()
+		if err != nil {
+			return n, err
+		}
+		if b != telnetIAC {
+			data[n] = b
+			n++
+			continue
+		}
+		command, err := r.buffered.ReadByte()
+		if err != nil {
+			return n, err
+		}
+		switch {
+		case command == telnetIAC:
+			data[n] = telnetIAC
+			n++
+		case command >= telnetWILL && command <= telnetDONT:
+			_, err = r.buffered.ReadByte()
+		case command == telnetSB:
+			err = r.skipSubnegotiation()
+		}
 		if err != nil {
-			return errors.Internal.Wrap(err, "error connecting to telnet server")
+			return n, err
+		}
+	}
+	return n, nil
+}
+
+//skipSubnegotiation skips subnegotiation bytes up to IAC SE
+func (r *telnetDataReader) skipSubnegotiation() error {
+	previous := byte(0)
+	for {
+		b, err := r.buffered.ReadByte()
+		if err != nil {
+			return err
+		}
+		if previous == telnetIAC && b == telnetSE {
+			return nil
+		}
+		if previous == telnetIAC && b == telnetIAC {
+			b = 0
+		}
+		previous = b
+	}
+}
+
+//cliOutputReader reads switch CLI output up to the expect word. Output is read in chunks,
+//the part of the chunk after the expect word is kept for the next read
+type cliOutputReader struct {
+	reader  io.Reader
+	pending []byte
+}
+
+func newCLIOutputReader(reader io.Reader) *cliOutputReader {
+	return &cliOutputReader{reader: reader}
+}
+
+//readUntil reads output up to and including the expect word
+func (r *cliOutputReader) readUntil(expect string) (string, error) {
+	out := r.pending
+	r.pending = nil
+	chunk := make([]byte, cliOutputChunkSize)
+	searchFrom := 0
+	for {
+		if index := bytes.Index(out[searchFrom:], []byte(expect)); index >= 0 {
+			end := searchFrom + index + len(expect)
+			r.pending = append([]byte{}, out[end:]...)
+			return string(out[:end]), nil
+		}
+		if len(out) >= len(expect) {
+			searchFrom = len(out) - len(expect) + 1
+		}
+		n, err := r.reader.Read(chunk)
+		out = append(out, chunk[:n]...)
+		if err != nil && n == 0 {
+			r.pending = out
+			return "", err
 		}
 	}
-	return nil
 }
 
 //Read reads all output lines before expect word
@@ -40,22 +168,12 @@ func (t *TelnetConnection) Connect(address string) (err error) {
 //	string - telnet output
 //	error - if an error occurs, otherwise nil
 func (t TelnetConnection) Read(expect string) (string, error) {
-	var buffer [1]byte
-	recvData := buffer[:]
-	var (
-		n   int
-		err error
-		out string
-	)
-	for {
-		n, err = t.bond.Read(recvData)
-		if n <= 0 || err != nil || strings.Contains(out, expect) {
-			if err != nil {
-				return "", errors.Internal.Wrap(err, "error reading from telnet server")
-			}
-			break
-		}
-		out += string(recvData)
+	if t.conn == nil {
+		return "", errors.Internal.New("telnet connection is not established")
+	}
+	out, err := t.output.readUntil(expect)
+	if err != nil {
+		return "", errors.Internal.Wrap(err, "error reading from telnet server")
 	}
 	return out, nil
 }
@@ -67,19 +185,16 @@ func (t TelnetConnection) Read(expect string) (string, error) {
 //Return:
 //	error - if an error occurs, otherwise nil
 func (t TelnetConnection) Send(command string) error {
-	var commandBuffer []byte
-	for _, char := range command {
-		commandBuffer = append(commandBuffer, byte(char))
+	if t.conn == nil {
+		return errors.Internal.New("telnet connection is not established")
 	}
-
-	crlfBuffer := [2]byte{'\r', '\n'}
-	crlf := crlfBuffer[:]
-
-	_, err := t.bond.Write(commandBuffer)
+	//IAC byte of the data is escaped by doubling
+	commandBuffer := bytes.ReplaceAll([]byte(command), []byte{telnetIAC}, []byte{telnetIAC, telnetIAC})
+	_, err := t.conn.Write(commandBuffer)
 	if err != nil {
 		return errors.Internal.Wrap(err, "error sending command to telnet server")
 	}
-	_, err = t.bond.Write(crlf)
+	_, err = t.conn.Write([]byte{'\r', '\n'})
 	if err != nil {
 		return errors.Internal.Wrap(err, "error sending line break to telnet server")
 	}
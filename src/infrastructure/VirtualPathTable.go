@@ -0,0 +1,82 @@
+package infrastructure
+
+import (
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+//virtualPathEntry actual path of the path ratio
+type virtualPathEntry struct {
+	actualPath string
+	//isTemplate file must be rendered as text/template before sending, used only by tftp server
+	isTemplate bool
+}
+
+//virtualPathTable path ratios of the single scope for the fast lookup of the actual file path
+type virtualPathTable struct {
+	//files virtual file path to actual file path
+	files map[string]virtualPathEntry
+	//directories virtual directory path to actual directory path, root virtual directory is ""
+	directories map[string]virtualPathEntry
+}
+
+//normalizeVirtualPath converts virtual path to the form without leading and trailing slashes,
+//".." elements can't leave the root
+func normalizeVirtualPath(virtualPath string) string {
+	cleaned := path.Clean("/" + strings.ReplaceAll(virtualPath, "\\", "/"))
+	return strings.TrimPrefix(cleaned, "/")
+}
+
+func newVirtualPathTable() *virtualPathTable {
+	return &virtualPathTable{
+		files:       map[string]virtualPathEntry{},
+		directories: map[string]virtualPathEntry{},
+	}
+}
+
+//add adds file or directory ratio to the table
+func (t *virtualPathTable) add(virtualPath string, entry virtualPathEntry, isDirectory bool) {
+	virtualPath = normalizeVirtualPath(virtualPath)
+	if isDirectory {
+		t.directories[virtualPath] = entry
+	} else if virtualPath != "" {
+		t.files[virtualPath] = entry
+	}
+}
+
+//lookup finds actual path for the normalized virtual path.
+//File ratios have priority over directory ratios, directory ratios are matched by the longest prefix
+func (t *virtualPathTable) lookup(virtualPath string) (virtualPathEntry, bool) {
+	if entry, ok := t.files[virtualPath]; ok {
+		return entry, true
+	}
+	if len(t.directories) == 0 {
+		return virtualPathEntry{}, false
+	}
+	dir := virtualPath
+	for {
+		dir = path.Dir(dir)
+		if dir == "." || dir == "/" {
+			dir = ""
+		}
+		if dirEntry, ok := t.directories[dir]; ok {
+			relativePath := strings.TrimPrefix(virtualPath, dir)
+			actualPath, ok := joinInsideDirectory(dirEntry.actualPath, relativePath)
+			return virtualPathEntry{actualPath: actualPath, isTemplate: dirEntry.isTemplate}, ok
+		}
+		if dir == "" {
+			return virtualPathEntry{}, false
+		}
+	}
+}
+
+//joinInsideDirectory joins relative path to the directory and checks that result is inside this directory
+func joinInsideDirectory(dir, relativePath string) (string, bool) {
+	root := filepath.Clean(dir)
+	actualPath := filepath.Join(root, filepath.FromSlash(relativePath))
+	if actualPath == root || !strings.HasPrefix(actualPath, strings.TrimSuffix(root, string(filepath.Separator))+string(filepath.Separator)) {
+		return "", false
+	}
+	return actualPath, true
+}
@@ -51,17 +51,33 @@ func main() {
 			infrastructure.NewGormAppLogRepository,
 			infrastructure.NewGormTFTPConfigRepository,
 			infrastructure.NewGormTFTPPathRatioRepository,
+			infrastructure.NewChannelBootEventBus,
+			infrastructure.NewTFTPClientResolver,
 			infrastructure.NewPinTFTPServerFactory,
+			infrastructure.NewGormHTTPBootConfigRepository,
+			infrastructure.NewGormHTTPBootPathRatioRepository,
+			infrastructure.NewNetHTTPBootServerFactory,
 			infrastructure.NewLogrusLogger,
 			infrastructure.NewGormEthernetSwitchPortRepository,
 			infrastructure.NewDeviceTemplateStorage,
 			infrastructure.NewYamlHostNetworkConfigStorage,
 			infrastructure.NewHostNetworkManager,
 			infrastructure.NewGormEthernetSwitchVLANRepository,
+			infrastructure.NewGormEthernetSwitchConfigBackupRepository,
+			infrastructure.NewEthernetSwitchDriverRegistry,
 			infrastructure.NewEthernetSwitchManagerProvider,
 			infrastructure.NewGormDHCP4LeaseRepository,
+			infrastructure.NewGormDHCP4ReservationRepository,
+			infrastructure.NewGormDHCP4BootRuleRepository,
 			infrastructure.NewGormDHCP4ConfigRepository,
 			infrastructure.NewCoreDHCP4ServerFactory,
+			infrastructure.NewGormDHCP6LeaseRepository,
+			infrastructure.NewGormDHCP6ConfigRepository,
+			infrastructure.NewCoreDHCP6ServerFactory,
+			infrastructure.NewGormDeviceRepository,
+			infrastructure.NewGormDeviceNetworkInterfaceRepository,
+			infrastructure.NewGormDeviceBootRepository,
+			infrastructure.NewGormDeviceBootStageRepository,
 			// Application logic
 			services.NewEthernetSwitchService,
 			services.NewHTTPLogService,
@@ -69,7 +85,11 @@ func main() {
 			services.NewDeviceTemplateService,
 			services.NewHostNetworkService,
 			services.NewDHCP4ServerService,
+			services.NewDHCP6ServerService,
 			services.NewTFTPServerService,
+			services.NewHTTPBootServerService,
+			services.NewDeviceService,
+			services.NewDeviceBootService,
 			// WEB API -> GIN Server
 			webapi.NewGinHTTPServer,
 			// WEB API -> GIN Controllers
@@ -82,16 +102,25 @@ func main() {
 			controllers.NewHostNetworkBridgeController,
 			controllers.NewHostNetworkController,
 			controllers.NewEthernetSwitchVLANGinController,
+			controllers.NewEthernetSwitchConfigBackupGinController,
 			controllers.NewDHCP4ServerGinController,
+			controllers.NewDHCP6ServerGinController,
 			controllers.NewTFTPServerGinController,
+			controllers.NewHTTPBootServerGinController,
+			controllers.NewDeviceGinController,
+			controllers.NewDeviceNetworkInterfaceGinController,
+			controllers.NewDeviceBootGinController,
 		),
 		fx.Invoke(
 			//Register logrus hooks
 			infrastructure.RegisterLogHooks,
 			//Services initialization
-			services.EthernetSwitchServiceInit,
 			services.DHCP4ServerServiceInit,
+			services.DHCP6ServerServiceInit,
 			services.TFTPServerServiceInit,
+			services.HTTPBootServerServiceInit,
+			services.DeviceBootServiceInit,
+			services.EthernetSwitchServiceInit,
 			//GIN Controllers registration
 			controllers.RegisterEthernetSwitchController,
 			controllers.RegisterHTTPLogController,
@@ -102,8 +131,14 @@ func main() {
 			controllers.RegisterHostNetworkBridgeController,
 			controllers.RegisterHostNetworkController,
 			controllers.RegisterEthernetSwitchVLANGinController,
+			controllers.RegisterEthernetSwitchConfigBackupGinController,
 			controllers.RegisterDHCP4ServerGinController,
+			controllers.RegisterDHCP6ServerGinController,
 			controllers.RegisterTFTPServerGinController,
+			controllers.RegisterHTTPBootServerGinController,
+			controllers.RegisterDeviceGinController,
+			controllers.RegisterDeviceNetworkInterfaceGinController,
+			controllers.RegisterDeviceBootGinController,
 			//Start GIN http server
 			webapi.StartHTTPServer,
 		),
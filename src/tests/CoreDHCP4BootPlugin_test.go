@@ -0,0 +1,136 @@
+package tests
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"net"
+	"os"
+	"rol/domain"
+	"rol/infrastructure"
+	"testing"
+)
+
+func Test_CoreDHCP4BootPlugin_Handler4(t *testing.T) {
+	plugin := infrastructure.NewBootOptionsPlugin(nil)
+	handler, err := plugin.Setup4(uuid.New().String(), "10.10.10.1", "pxelinux.0", "10.10.10.2")
+	if err != nil {
+		t.Errorf("plugin setup failed: %v", err)
+		return
+	}
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	req, err := dhcpv4.NewDiscovery(mac)
+	if err != nil {
+		t.Errorf("failed to create discovery: %v", err)
+		return
+	}
+	resp, err := dhcpv4.NewReplyFromRequest(req)
+	if err != nil {
+		t.Errorf("failed to create reply: %v", err)
+		return
+	}
+	resp, _ = handler(req, resp)
+	if !resp.ServerIPAddr.Equal(net.ParseIP("10.10.10.1")) {
+		t.Errorf("unexpected siaddr: %s, expect 10.10.10.1", resp.ServerIPAddr)
+	}
+	if resp.TFTPServerName() != "10.10.10.1" {
+		t.Errorf("unexpected option 66: %s, expect 10.10.10.1", resp.TFTPServerName())
+	}
+	if resp.BootFileNameOption() != "pxelinux.0" {
+		t.Errorf("unexpected option 67: %s, expect pxelinux.0", resp.BootFileNameOption())
+	}
+	ntpServers := resp.NTPServers()
+	if len(ntpServers) != 1 || !ntpServers[0].Equal(net.ParseIP("10.10.10.2")) {
+		t.Errorf("unexpected option 42: %v, expect [10.10.10.2]", ntpServers)
+	}
+}
+
+func Test_CoreDHCP4BootPlugin_SetupFailByWrongNextServer(t *testing.T) {
+	plugin := infrastructure.NewBootOptionsPlugin(nil)
+	_, err := plugin.Setup4(uuid.New().String(), "not-ip", "", "")
+	if err == nil {
+		t.Error("expect setup error for wrong next server address")
+	}
+}
+
+func Test_CoreDHCP4BootPlugin_BootRules(t *testing.T) {
+	dbPath := "coreDHCP4BootPlugin_test.db"
+	_ = os.Remove(dbPath)
+	testGenDb, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		t.Errorf("creating db failed: %v", err)
+		return
+	}
+	if err = testGenDb.AutoMigrate(new(domain.DHCP4BootRule)); err != nil {
+		t.Errorf("migration failed: %v", err)
+		return
+	}
+	rulesRepo := infrastructure.NewGormDHCP4BootRuleRepository(testGenDb, logrus.New())
+	defer func() {
+		_ = rulesRepo.Dispose()
+		_ = os.Remove(dbPath)
+	}()
+	serverID := uuid.New()
+	uefiArch := int(iana.EFI_X86_64)
+	rules := []domain.DHCP4BootRule{{
+		Priority:      0,
+		UserClass:     "iPXE",
+		NextServer:    "10.10.10.3",
+		BootFileName:  "http://10.10.10.3/boot.ipxe",
+		DHCP4ConfigID: serverID,
+	}, {
+		Priority:      1,
+		Architecture:  &uefiArch,
+		BootFileName:  "ipxe.efi",
+		DHCP4ConfigID: serverID,
+	}}
+	for _, rule := range rules {
+		if _, err = rulesRepo.Insert(context.TODO(), rule); err != nil {
+			t.Errorf("insert boot rule failed: %v", err)
+			return
+		}
+	}
+	handler, err := infrastructure.NewBootOptionsPlugin(rulesRepo).Setup4(serverID.String(), "10.10.10.1", "undionly.kpxe", "")
+	if err != nil {
+		t.Errorf("plugin setup failed: %v", err)
+		return
+	}
+	mac, _ := net.ParseMAC("00:11:22:33:44:55")
+	testCases := map[string]struct {
+		modifiers      []dhcpv4.Modifier
+		bootFileName   string
+		nextServerAddr string
+	}{
+		"bios": {nil, "undionly.kpxe", "10.10.10.1"},
+		"uefi": {[]dhcpv4.Modifier{
+			dhcpv4.WithOption(dhcpv4.OptClientArch(iana.EFI_X86_64)),
+		}, "ipxe.efi", "10.10.10.1"},
+		"ipxe": {[]dhcpv4.Modifier{
+			dhcpv4.WithOption(dhcpv4.OptClientArch(iana.EFI_X86_64)),
+			dhcpv4.WithOption(dhcpv4.OptUserClass("iPXE")),
+		}, "http://10.10.10.3/boot.ipxe", "10.10.10.3"},
+	}
+	for name, testCase := range testCases {
+		req, err := dhcpv4.NewDiscovery(mac, testCase.modifiers...)
+		if err != nil {
+			t.Errorf("failed to create discovery: %v", err)
+			return
+		}
+		resp, err := dhcpv4.NewReplyFromRequest(req)
+		if err != nil {
+			t.Errorf("failed to create reply: %v", err)
+			return
+		}
+		resp, _ = handler(req, resp)
+		if resp.BootFileNameOption() != testCase.bootFileName {
+			t.Errorf("%s: unexpected boot file name: %s, expect %s", name, resp.BootFileNameOption(), testCase.bootFileName)
+		}
+		if !resp.ServerIPAddr.Equal(net.ParseIP(testCase.nextServerAddr)) {
+			t.Errorf("%s: unexpected siaddr: %s, expect %s", name, resp.ServerIPAddr, testCase.nextServerAddr)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/insomniacslk/dhcp/dhcpv4"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"net"
+	"os"
+	"rol/domain"
+	"rol/infrastructure"
+	"testing"
+	"time"
+)
+
+func Test_CoreDHCP4RangeRepoPlugin_ReleaseExpiredLease(t *testing.T) {
+	dbPath := "coreDHCP4RangeRepoPlugin_test.db"
+	_ = os.Remove(dbPath)
+	testGenDb, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		t.Errorf("creating db failed: %v", err)
+		return
+	}
+	if err = testGenDb.AutoMigrate(new(domain.DHCP4Lease), new(domain.DHCP4Reservation)); err != nil {
+		t.Errorf("migration failed: %v", err)
+		return
+	}
+	logger := logrus.New()
+	leasesRepo := infrastructure.NewGormDHCP4LeaseRepository(testGenDb, logger)
+	reservationsRepo := infrastructure.NewGormDHCP4ReservationRepository(testGenDb, logger)
+	defer func() {
+		_ = leasesRepo.Dispose()
+		_ = os.Remove(dbPath)
+	}()
+	config := domain.DHCP4Config{
+		Range:     "10.10.20.2-10.10.20.3",
+		Mask:      "255.255.255.0",
+		ServerID:  "10.10.20.1",
+		Gateway:   "10.10.20.1",
+		LeaseTime: 600,
+	}
+	config.ID = uuid.New()
+	server, err := infrastructure.NewCoreDHCP4Server(config, leasesRepo, reservationsRepo, nil, nil)
+	if err != nil {
+		t.Errorf("create server failed: %v", err)
+		return
+	}
+	plugin := infrastructure.NewRangeRepositoryPlugin(leasesRepo, reservationsRepo, nil)
+	handler, err := plugin.Setup4(config.ID.String(), "10.10.20.2", "10.10.20.3", "600s")
+	if err != nil {
+		t.Errorf("plugin setup failed: %v", err)
+		return
+	}
+	request := func(mac string) net.IP {
+		hwAddr, _ := net.ParseMAC(mac)
+		req, _ := dhcpv4.NewDiscovery(hwAddr)
+		resp, _ := dhcpv4.NewReplyFromRequest(req)
+		resp, _ = handler(req, resp)
+		if resp == nil {
+			return nil
+		}
+		return resp.YourIPAddr
+	}
+	expire := func() domain.DHCP4Lease {
+		ctx := context.TODO()
+		leases, _ := leasesRepo.GetList(ctx, "", "", 1, 1, nil)
+		if len(leases) == 0 {
+			return domain.DHCP4Lease{}
+		}
+		leases[0].Expires = time.Now().Add(-time.Minute)
+		lease, _ := leasesRepo.Update(ctx, leases[0])
+		return lease
+	}
+
+	leasedIP := request("00:11:22:33:44:55")
+	if leasedIP == nil {
+		t.Error("ip address was not leased")
+		return
+	}
+	expiredLease := expire()
+	//client renews lease after the reaper has found it as expired
+	request("00:11:22:33:44:55")
+	released, err := server.ReleaseExpiredLease(expiredLease.ID)
+	if err != nil || released {
+		t.Errorf("renewed lease was released: %v", err)
+	}
+	if _, err = leasesRepo.GetByID(context.TODO(), expiredLease.ID); err != nil {
+		t.Errorf("renewed lease was removed: %v", err)
+	}
+	if ip := request("00:11:22:33:44:56"); ip == nil || ip.Equal(leasedIP) {
+		t.Errorf("unexpected ip address for another client: %v", ip)
+	}
+
+	leases, _ := leasesRepo.GetList(context.TODO(), "", "", 1, 10, nil)
+	for range leases {
+		expiredLease = expire()
+		released, err = server.ReleaseExpiredLease(expiredLease.ID)
+		if err != nil || !released {
+			t.Errorf("expired lease was not released: %v", err)
+		}
+	}
+	if ip := request("00:11:22:33:44:57"); ip == nil {
+		t.Error("released ip address was not returned to the pool")
+	}
+}
@@ -0,0 +1,198 @@
+package tests
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/insomniacslk/dhcp/dhcpv6"
+	"github.com/insomniacslk/dhcp/iana"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"net"
+	"os"
+	"rol/domain"
+	"rol/infrastructure"
+	"testing"
+	"time"
+)
+
+//tDHCP6RangeClient dhcp v6 client that sends messages directly to the range plugin handler
+type tDHCP6RangeClient struct {
+	handler func(req, resp dhcpv6.DHCPv6) (dhcpv6.DHCPv6, bool)
+	duid    dhcpv6.Duid
+}
+
+func newDHCP6RangeClient(handler func(req, resp dhcpv6.DHCPv6) (dhcpv6.DHCPv6, bool), mac string) *tDHCP6RangeClient {
+	hwAddr, _ := net.ParseMAC(mac)
+	return &tDHCP6RangeClient{
+		handler: handler,
+		duid:    dhcpv6.Duid{Type: dhcpv6.DUID_LL, HwType: iana.HWTypeEthernet, LinkLayerAddr: hwAddr},
+	}
+}
+
+//send sends message with IA_NA and returns IA_NA of the reply, reply status is nil if message was dropped
+func (c *tDHCP6RangeClient) send(messageType dhcpv6.MessageType, ip net.IP) (*dhcpv6.OptIANA, *dhcpv6.OptStatusCode) {
+	ia := &dhcpv6.OptIANA{IaId: [4]byte{0, 0, 0, 1}}
+	if ip != nil {
+		ia.Options.Add(&dhcpv6.OptIAAddress{IPv6Addr: ip})
+	}
+	req := &dhcpv6.Message{MessageType: messageType, TransactionID: dhcpv6.TransactionID{1, 2, 3}}
+	req.AddOption(dhcpv6.OptClientID(c.duid))
+	req.AddOption(ia)
+	resp := &dhcpv6.Message{MessageType: dhcpv6.MessageTypeReply, TransactionID: req.TransactionID}
+	result, _ := c.handler(req, resp)
+	if result == nil {
+		return nil, nil
+	}
+	msg := result.(*dhcpv6.Message)
+	status, _ := msg.GetOneOption(dhcpv6.OptionStatusCode).(*dhcpv6.OptStatusCode)
+	if status == nil {
+		status = &dhcpv6.OptStatusCode{StatusCode: iana.StatusSuccess}
+	}
+	return msg.Options.OneIANA(), status
+}
+
+//request requests an address, nil if address was not leased
+func (c *tDHCP6RangeClient) request() net.IP {
+	ia, _ := c.send(dhcpv6.MessageTypeRequest, nil)
+	if ia == nil || ia.Options.OneAddress() == nil {
+		return nil
+	}
+	return ia.Options.OneAddress().IPv6Addr
+}
+
+func Test_CoreDHCP6RangeRepoPlugin_Release(t *testing.T) {
+	dbPath := "coreDHCP6RangeRepoPlugin_test.db"
+	_ = os.Remove(dbPath)
+	testGenDb, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		t.Errorf("creating db failed: %v", err)
+		return
+	}
+	if err = testGenDb.AutoMigrate(new(domain.DHCP6Lease)); err != nil {
+		t.Errorf("migration failed: %v", err)
+		return
+	}
+	leasesRepo := infrastructure.NewGormDHCP6LeaseRepository(testGenDb, logrus.New())
+	defer func() {
+		_ = leasesRepo.Dispose()
+		_ = os.Remove(dbPath)
+	}()
+	plugin := infrastructure.NewRange6RepositoryPlugin(leasesRepo)
+	handler, err := plugin.Setup6(uuid.New().String(), "fd00::2", "fd00::3", "600s")
+	if err != nil {
+		t.Errorf("plugin setup failed: %v", err)
+		return
+	}
+	first := newDHCP6RangeClient(handler, "00:11:22:33:44:55")
+	second := newDHCP6RangeClient(handler, "00:11:22:33:44:56")
+	ctx := context.TODO()
+
+	//released address is leased to another client
+	leasedIP := first.request()
+	if leasedIP == nil {
+		t.Error("ip address was not leased")
+		return
+	}
+	if ia, status := first.send(dhcpv6.MessageTypeRelease, net.ParseIP("fd00::3")); status == nil ||
+		ia == nil || ia.Options.Status() == nil || ia.Options.Status().StatusCode != iana.StatusNoBinding {
+		t.Errorf("expect NoBinding status for the address that is not leased to the client, got %v", ia)
+	}
+	if _, status := first.send(dhcpv6.MessageTypeRelease, leasedIP); status == nil || status.StatusCode != iana.StatusSuccess {
+		t.Errorf("unexpected release status: %v", status)
+	}
+	if count, _ := leasesRepo.Count(ctx, nil); count != 0 {
+		t.Errorf("released lease was not removed, leases count: %d", count)
+	}
+	if ip := second.request(); !ip.Equal(leasedIP) {
+		t.Errorf("released ip address was not returned to the range, got %v", ip)
+	}
+}
+
+func Test_CoreDHCP6RangeRepoPlugin_ReleaseExpiredLease(t *testing.T) {
+	dbPath := "coreDHCP6RangeRepoPluginExpired_test.db"
+	_ = os.Remove(dbPath)
+	testGenDb, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		t.Errorf("creating db failed: %v", err)
+		return
+	}
+	if err = testGenDb.AutoMigrate(new(domain.DHCP6Lease)); err != nil {
+		t.Errorf("migration failed: %v", err)
+		return
+	}
+	leasesRepo := infrastructure.NewGormDHCP6LeaseRepository(testGenDb, logrus.New())
+	defer func() {
+		_ = leasesRepo.Dispose()
+		_ = os.Remove(dbPath)
+	}()
+	config := domain.DHCP6Config{Range: "fd00::2-fd00::3", LeaseTime: 600}
+	config.ID = uuid.New()
+	server, err := infrastructure.NewCoreDHCP6Server(config, leasesRepo)
+	if err != nil {
+		t.Errorf("create server failed: %v", err)
+		return
+	}
+	plugin := infrastructure.NewRange6RepositoryPlugin(leasesRepo)
+	handler, err := plugin.Setup6(config.ID.String(), "fd00::2", "fd00::3", "600s")
+	if err != nil {
+		t.Errorf("plugin setup failed: %v", err)
+		return
+	}
+	client := newDHCP6RangeClient(handler, "00:11:22:33:44:55")
+	ctx := context.TODO()
+	if client.request() == nil {
+		t.Error("ip address was not leased")
+		return
+	}
+	leases, _ := leasesRepo.GetList(ctx, "", "", 1, 1, nil)
+	if len(leases) != 1 {
+		t.Errorf("unexpected leases: %+v", leases)
+		return
+	}
+	leases[0].Expires = time.Now().Add(-time.Minute)
+	expiredLease, _ := leasesRepo.Update(ctx, leases[0])
+	//client renews lease after the reaper has found it as expired
+	client.send(dhcpv6.MessageTypeRenew, nil)
+	released, err := server.ReleaseExpiredLease(expiredLease.ID)
+	if err != nil || released {
+		t.Errorf("renewed lease was released: %v", err)
+	}
+	if _, err = leasesRepo.GetByID(ctx, expiredLease.ID); err != nil {
+		t.Errorf("renewed lease was removed: %v", err)
+	}
+}
+
+func Test_CoreDHCP6RangeRepoPlugin_SingleAddressRange(t *testing.T) {
+	dbPath := "coreDHCP6RangeRepoPluginSingle_test.db"
+	_ = os.Remove(dbPath)
+	testGenDb, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		t.Errorf("creating db failed: %v", err)
+		return
+	}
+	if err = testGenDb.AutoMigrate(new(domain.DHCP6Lease)); err != nil {
+		t.Errorf("migration failed: %v", err)
+		return
+	}
+	leasesRepo := infrastructure.NewGormDHCP6LeaseRepository(testGenDb, logrus.New())
+	defer func() {
+		_ = leasesRepo.Dispose()
+		_ = os.Remove(dbPath)
+	}()
+	plugin := infrastructure.NewRange6RepositoryPlugin(leasesRepo)
+	if _, err = plugin.Setup6(uuid.New().String(), "fd00::3", "fd00::2", "600s"); err == nil {
+		t.Error("expect error for the range with start greater than end")
+	}
+	handler, err := plugin.Setup6(uuid.New().String(), "fd00::2", "fd00::2", "600s")
+	if err != nil {
+		t.Errorf("plugin setup failed: %v", err)
+		return
+	}
+	if ip := newDHCP6RangeClient(handler, "00:11:22:33:44:55").request(); !ip.Equal(net.ParseIP("fd00::2")) {
+		t.Errorf("unexpected ip address of the single address range: %v", ip)
+	}
+	if ip := newDHCP6RangeClient(handler, "00:11:22:33:44:56").request(); ip != nil {
+		t.Errorf("expect no free addresses in the single address range, got %v", ip)
+	}
+}
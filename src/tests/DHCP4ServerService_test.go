@@ -0,0 +1,244 @@
+package tests
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"os"
+	"rol/app/interfaces"
+	"rol/app/services"
+	"rol/domain"
+	"rol/dtos"
+	"rol/infrastructure"
+	"testing"
+	"time"
+)
+
+type tDHCP4FakeServer struct {
+	state       domain.DHCPServerState
+	releasedIPs []string
+}
+
+func (f *tDHCP4FakeServer) ReloadConfiguration(dhcp4config domain.DHCP4Config) error {
+	return nil
+}
+
+func (f *tDHCP4FakeServer) Start() error {
+	f.state = domain.DHCPStateLaunched
+	return nil
+}
+
+func (f *tDHCP4FakeServer) Stop() {
+	f.state = domain.DHCPStateStopped
+}
+
+func (f *tDHCP4FakeServer) GetState() domain.DHCPServerState {
+	return f.state
+}
+
+func (f *tDHCP4FakeServer) ReleaseLease(ip string) error {
+	f.releasedIPs = append(f.releasedIPs, ip)
+	return nil
+}
+
+func (f *tDHCP4FakeServer) ReleaseExpiredLease(leaseID uuid.UUID) (bool, error) {
+	ctx := context.TODO()
+	lease, err := dhcp4ServiceTester.leasesRepo.GetByID(ctx, leaseID)
+	if err != nil || !lease.Expires.Before(time.Now()) {
+		return false, nil
+	}
+	if err = dhcp4ServiceTester.leasesRepo.Delete(ctx, leaseID); err != nil {
+		return false, err
+	}
+	return true, f.ReleaseLease(lease.IP)
+}
+
+type tDHCP4FakeServerFactory struct {
+	servers map[uuid.UUID]*tDHCP4FakeServer
+}
+
+func (f *tDHCP4FakeServerFactory) Create(config domain.DHCP4Config) (interfaces.IDHCP4Server, error) {
+	server := &tDHCP4FakeServer{state: domain.DHCPStateStopped}
+	f.servers[config.ID] = server
+	return server, nil
+}
+
+type tDHCP4ServerService struct {
+	service    *services.DHCP4ServerService
+	factory    *tDHCP4FakeServerFactory
+	leasesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease]
+	dbPath     string
+	serverID   uuid.UUID
+}
+
+var dhcp4ServiceTester *tDHCP4ServerService
+
+func Test_DHCP4ServerService_Prepare(t *testing.T) {
+	dhcp4ServiceTester = &tDHCP4ServerService{}
+	dhcp4ServiceTester.dbPath = "dhcp4ServerService_test.db"
+	_ = os.Remove(dhcp4ServiceTester.dbPath)
+	dbConnection := sqlite.Open(dhcp4ServiceTester.dbPath)
+	testGenDb, err := gorm.Open(dbConnection, &gorm.Config{})
+	if err != nil {
+		t.Errorf("creating db failed: %v", err)
+	}
+	err = testGenDb.AutoMigrate(
+		new(domain.DHCP4Config),
+		new(domain.DHCP4Lease),
+		new(domain.DHCP4Reservation),
+		new(domain.DHCP4BootRule),
+	)
+	if err != nil {
+		t.Errorf("migration failed: %v", err)
+	}
+	logger := logrus.New()
+	configsRepo := infrastructure.NewGormDHCP4ConfigRepository(testGenDb, logger)
+	dhcp4ServiceTester.leasesRepo = infrastructure.NewGormDHCP4LeaseRepository(testGenDb, logger)
+	dhcp4ServiceTester.factory = &tDHCP4FakeServerFactory{servers: map[uuid.UUID]*tDHCP4FakeServer{}}
+	reservationsRepo := infrastructure.NewGormDHCP4ReservationRepository(testGenDb, logger)
+	bootRulesRepo := infrastructure.NewGormDHCP4BootRuleRepository(testGenDb, logger)
+	dhcp4ServiceTester.service = services.NewDHCP4ServerService(configsRepo, dhcp4ServiceTester.leasesRepo,
+		reservationsRepo, bootRulesRepo, dhcp4ServiceTester.factory, logger)
+}
+
+func Test_DHCP4ServerService_CreateServer(t *testing.T) {
+	createDto := dtos.DHCP4ServerCreateDto{
+		Range:        "10.10.10.2-10.10.10.22",
+		Mask:         "255.255.255.0",
+		ServerID:     "10.10.10.1",
+		Interface:    "eth0",
+		Gateway:      "10.10.10.1",
+		DNS:          "10.10.10.1",
+		NTP:          "10.10.10.1",
+		NextServer:   "10.10.10.1",
+		BootFileName: "pxelinux.0",
+		Enabled:      true,
+		Port:         67,
+		LeaseTime:    600,
+	}
+	server, err := dhcp4ServiceTester.service.CreateServer(context.TODO(), createDto)
+	if err != nil {
+		t.Errorf("create server failed: %v", err)
+		return
+	}
+	dhcp4ServiceTester.serverID = server.ID
+	if server.State != domain.DHCPStateLaunched.String() {
+		t.Errorf("unexpected server state: %s, expect %s", server.State, domain.DHCPStateLaunched.String())
+	}
+	if server.NextServer != createDto.NextServer || server.BootFileName != createDto.BootFileName {
+		t.Error("next server or boot file name was not saved")
+	}
+}
+
+func Test_DHCP4ServerService_RemoveExpiredLeases(t *testing.T) {
+	ctx := context.TODO()
+	expiredLease := dtos.DHCP4LeaseCreateDto{
+		IP:      "10.10.10.2",
+		MAC:     "00:11:22:33:44:55",
+		Expires: time.Now().Add(-time.Hour),
+	}
+	activeLease := dtos.DHCP4LeaseCreateDto{
+		IP:      "10.10.10.3",
+		MAC:     "00:11:22:33:44:56",
+		Expires: time.Now().Add(time.Hour),
+	}
+	for _, createDto := range []dtos.DHCP4LeaseCreateDto{expiredLease, activeLease} {
+		_, err := dhcp4ServiceTester.service.CreateLease(ctx, dhcp4ServiceTester.serverID, createDto)
+		if err != nil {
+			t.Errorf("create lease failed: %v", err)
+			return
+		}
+	}
+	err := dhcp4ServiceTester.service.RemoveExpiredLeases(ctx)
+	if err != nil {
+		t.Errorf("remove expired leases failed: %v", err)
+		return
+	}
+	leases, err := dhcp4ServiceTester.service.GetLeaseList(ctx, dhcp4ServiceTester.serverID, "", "", "", 1, 10)
+	if err != nil {
+		t.Errorf("get leases failed: %v", err)
+		return
+	}
+	if len(leases.Items) != 1 || leases.Items[0].IP != activeLease.IP {
+		t.Errorf("unexpected leases after cleanup: %+v", leases.Items)
+	}
+	releasedIPs := dhcp4ServiceTester.factory.servers[dhcp4ServiceTester.serverID].releasedIPs
+	if len(releasedIPs) != 1 || releasedIPs[0] != expiredLease.IP {
+		t.Errorf("unexpected released ips: %v, expect [%s]", releasedIPs, expiredLease.IP)
+	}
+}
+
+func Test_DHCP4ServerService_CreateReservation(t *testing.T) {
+	ctx := context.TODO()
+	createDto := dtos.DHCP4ReservationCreateDto{DHCP4ReservationBaseDto: dtos.DHCP4ReservationBaseDto{
+		IP:           "10.10.10.100",
+		MAC:          "AA:BB:CC:DD:EE:01",
+		Hostname:     "node-01",
+		BootFileName: "node-01/pxelinux.0",
+	}}
+	reservation, err := dhcp4ServiceTester.service.CreateReservation(ctx, dhcp4ServiceTester.serverID, createDto)
+	if err != nil {
+		t.Errorf("create reservation failed: %v", err)
+		return
+	}
+	if reservation.MAC != "aa:bb:cc:dd:ee:01" {
+		t.Errorf("unexpected reservation mac: %s", reservation.MAC)
+	}
+	invalidIPs := map[string]string{
+		"10.10.10.5":   "inside dynamic range",
+		"10.10.11.5":   "outside subnet",
+		"10.10.10.1":   "server address",
+		"10.10.10.255": "broadcast address",
+		"10.10.10.100": "already reserved",
+	}
+	for ip, reason := range invalidIPs {
+		createDto.IP = ip
+		createDto.MAC = "aa:bb:cc:dd:ee:02"
+		_, err = dhcp4ServiceTester.service.CreateReservation(ctx, dhcp4ServiceTester.serverID, createDto)
+		if err == nil {
+			t.Errorf("reservation with ip %s (%s) was created", ip, reason)
+		}
+	}
+	createDto.IP = "10.10.10.101"
+	createDto.MAC = "aa:bb:cc:dd:ee:01"
+	_, err = dhcp4ServiceTester.service.CreateReservation(ctx, dhcp4ServiceTester.serverID, createDto)
+	if err == nil {
+		t.Error("reservation with duplicate mac was created")
+	}
+}
+
+func Test_DHCP4ServerService_CreateBootRule(t *testing.T) {
+	ctx := context.TODO()
+	createDto := dtos.DHCP4BootRuleCreateDto{DHCP4BootRuleBaseDto: dtos.DHCP4BootRuleBaseDto{
+		BootFileName: "ipxe.efi",
+	}}
+	_, err := dhcp4ServiceTester.service.CreateBootRule(ctx, dhcp4ServiceTester.serverID, createDto)
+	if err == nil {
+		t.Error("boot rule without match criteria was created")
+	}
+	uefiArch := 7
+	createDto.Architecture = &uefiArch
+	rule, err := dhcp4ServiceTester.service.CreateBootRule(ctx, dhcp4ServiceTester.serverID, createDto)
+	if err != nil {
+		t.Errorf("create boot rule failed: %v", err)
+		return
+	}
+	if rule.Architecture == nil || *rule.Architecture != uefiArch {
+		t.Errorf("unexpected boot rule architecture: %v", rule.Architecture)
+	}
+}
+
+func Test_DHCP4ServerService_RemoveDb(t *testing.T) {
+	err := dhcp4ServiceTester.service.DeleteServer(context.TODO(), dhcp4ServiceTester.serverID)
+	if err != nil {
+		t.Errorf("delete server failed: %v", err)
+	}
+	if err := dhcp4ServiceTester.leasesRepo.Dispose(); err != nil {
+		t.Errorf("close db failed:  %s", err)
+	}
+	if err := os.Remove(dhcp4ServiceTester.dbPath); err != nil {
+		t.Errorf("remove db failed:  %s", err)
+	}
+}
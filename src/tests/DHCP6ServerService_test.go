@@ -0,0 +1,186 @@
+package tests
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"os"
+	"rol/app/interfaces"
+	"rol/app/services"
+	"rol/app/validators"
+	"rol/domain"
+	"rol/dtos"
+	"rol/infrastructure"
+	"testing"
+	"time"
+)
+
+type tDHCP6FakeServer struct {
+	state       domain.DHCPServerState
+	releasedIPs []string
+}
+
+func (f *tDHCP6FakeServer) ReloadConfiguration(dhcp6config domain.DHCP6Config) error {
+	return nil
+}
+
+func (f *tDHCP6FakeServer) Start() error {
+	f.state = domain.DHCPStateLaunched
+	return nil
+}
+
+func (f *tDHCP6FakeServer) Stop() {
+	f.state = domain.DHCPStateStopped
+}
+
+func (f *tDHCP6FakeServer) GetState() domain.DHCPServerState {
+	return f.state
+}
+
+func (f *tDHCP6FakeServer) ReleaseLease(ip string) error {
+	f.releasedIPs = append(f.releasedIPs, ip)
+	return nil
+}
+
+func (f *tDHCP6FakeServer) ReleaseExpiredLease(leaseID uuid.UUID) (bool, error) {
+	ctx := context.TODO()
+	lease, err := dhcp6ServiceTester.leasesRepo.GetByID(ctx, leaseID)
+	if err != nil || !lease.Expires.Before(time.Now()) {
+		return false, nil
+	}
+	if err = dhcp6ServiceTester.leasesRepo.Delete(ctx, leaseID); err != nil {
+		return false, err
+	}
+	return true, f.ReleaseLease(lease.IP)
+}
+
+type tDHCP6FakeServerFactory struct {
+	servers map[uuid.UUID]*tDHCP6FakeServer
+}
+
+func (f *tDHCP6FakeServerFactory) Create(config domain.DHCP6Config) (interfaces.IDHCP6Server, error) {
+	server := &tDHCP6FakeServer{state: domain.DHCPStateStopped}
+	f.servers[config.ID] = server
+	return server, nil
+}
+
+type tDHCP6ServerService struct {
+	service    *services.DHCP6ServerService
+	factory    *tDHCP6FakeServerFactory
+	leasesRepo interfaces.IGenericRepository[uuid.UUID, domain.DHCP6Lease]
+	dbPath     string
+	serverID   uuid.UUID
+}
+
+var dhcp6ServiceTester *tDHCP6ServerService
+
+func Test_DHCP6ServerService_Prepare(t *testing.T) {
+	dhcp6ServiceTester = &tDHCP6ServerService{}
+	dhcp6ServiceTester.dbPath = "dhcp6ServerService_test.db"
+	_ = os.Remove(dhcp6ServiceTester.dbPath)
+	dbConnection := sqlite.Open(dhcp6ServiceTester.dbPath)
+	testGenDb, err := gorm.Open(dbConnection, &gorm.Config{})
+	if err != nil {
+		t.Errorf("creating db failed: %v", err)
+	}
+	err = testGenDb.AutoMigrate(
+		new(domain.DHCP6Config),
+		new(domain.DHCP6Lease),
+	)
+	if err != nil {
+		t.Errorf("migration failed: %v", err)
+	}
+	logger := logrus.New()
+	configsRepo := infrastructure.NewGormDHCP6ConfigRepository(testGenDb, logger)
+	dhcp6ServiceTester.leasesRepo = infrastructure.NewGormDHCP6LeaseRepository(testGenDb, logger)
+	dhcp6ServiceTester.factory = &tDHCP6FakeServerFactory{servers: map[uuid.UUID]*tDHCP6FakeServer{}}
+	dhcp6ServiceTester.service = services.NewDHCP6ServerService(configsRepo, dhcp6ServiceTester.leasesRepo,
+		dhcp6ServiceTester.factory, logger)
+}
+
+func Test_DHCP6ServerService_CreateServer(t *testing.T) {
+	createDto := dtos.DHCP6ServerCreateDto{
+		Range:     "fd00::100-fd00::1ff",
+		Interface: "eth0",
+		DNS:       "fd00::1",
+		Enabled:   true,
+		Port:      547,
+		LeaseTime: 600,
+	}
+	invalidRanges := []string{"fd00::1ff-fd00::100", "fd00::100-fd00:1::100", "10.10.10.2-10.10.10.22"}
+	for _, invalidRange := range invalidRanges {
+		invalidDto := createDto
+		invalidDto.Range = invalidRange
+		_, err := dhcp6ServiceTester.service.CreateServer(context.TODO(), invalidDto)
+		if err == nil {
+			t.Errorf("server with invalid range %s was created", invalidRange)
+		}
+	}
+	singleAddressDto := createDto
+	singleAddressDto.Range = "fd00::100-fd00::100"
+	if err := validators.ValidateDHCP6ServerCreateDto(singleAddressDto); err != nil {
+		t.Errorf("single address range is not valid: %v", err)
+	}
+	server, err := dhcp6ServiceTester.service.CreateServer(context.TODO(), createDto)
+	if err != nil {
+		t.Errorf("create server failed: %v", err)
+		return
+	}
+	dhcp6ServiceTester.serverID = server.ID
+	if server.State != domain.DHCPStateLaunched.String() {
+		t.Errorf("unexpected server state: %s, expect %s", server.State, domain.DHCPStateLaunched.String())
+	}
+}
+
+func Test_DHCP6ServerService_RemoveExpiredLeases(t *testing.T) {
+	ctx := context.TODO()
+	expiredLease := dtos.DHCP6LeaseCreateDto{
+		IP:      "fd00::100",
+		DUID:    "000300010011223344aa",
+		Expires: time.Now().Add(-time.Hour),
+	}
+	activeLease := dtos.DHCP6LeaseCreateDto{
+		IP:      "fd00::101",
+		DUID:    "000300010011223344ab",
+		Expires: time.Now().Add(time.Hour),
+	}
+	for _, createDto := range []dtos.DHCP6LeaseCreateDto{expiredLease, activeLease} {
+		_, err := dhcp6ServiceTester.service.CreateLease(ctx, dhcp6ServiceTester.serverID, createDto)
+		if err != nil {
+			t.Errorf("create lease failed: %v", err)
+			return
+		}
+	}
+	err := dhcp6ServiceTester.service.RemoveExpiredLeases(ctx)
+	if err != nil {
+		t.Errorf("remove expired leases failed: %v", err)
+		return
+	}
+	leases, err := dhcp6ServiceTester.service.GetLeaseList(ctx, dhcp6ServiceTester.serverID, "", "", "", 1, 10)
+	if err != nil {
+		t.Errorf("get leases failed: %v", err)
+		return
+	}
+	if len(leases.Items) != 1 || leases.Items[0].IP != activeLease.IP {
+		t.Errorf("unexpected leases after cleanup: %+v", leases.Items)
+	}
+	releasedIPs := dhcp6ServiceTester.factory.servers[dhcp6ServiceTester.serverID].releasedIPs
+	if len(releasedIPs) != 1 || releasedIPs[0] != expiredLease.IP {
+		t.Errorf("unexpected released ips: %v, expect [%s]", releasedIPs, expiredLease.IP)
+	}
+}
+
+func Test_DHCP6ServerService_RemoveDb(t *testing.T) {
+	err := dhcp6ServiceTester.service.DeleteServer(context.TODO(), dhcp6ServiceTester.serverID)
+	if err != nil {
+		t.Errorf("delete server failed: %v", err)
+	}
+	if err := dhcp6ServiceTester.leasesRepo.Dispose(); err != nil {
+		t.Errorf("close db failed:  %s", err)
+	}
+	if err := os.Remove(dhcp6ServiceTester.dbPath); err != nil {
+		t.Errorf("remove db failed:  %s", err)
+	}
+}
@@ -0,0 +1,382 @@
+package tests
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"io/ioutil"
+	"os"
+	"path"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/services"
+	"rol/domain"
+	"rol/dtos"
+	"rol/infrastructure"
+	"testing"
+	"time"
+)
+
+const (
+	deviceBootServiceTemplateName = "AutoTestingDeviceBootService"
+	deviceBootServiceMAC          = "00:11:22:33:44:66"
+)
+
+type tDeviceBootService struct {
+	service      *services.DeviceBootService
+	tftpService  *services.TFTPServerService
+	bootsRepo    interfaces.IGenericRepository[uuid.UUID, domain.DeviceBoot]
+	events       interfaces.IBootEventBus
+	dbPath       string
+	templatePath string
+	deviceID     uuid.UUID
+	tftpServerID uuid.UUID
+}
+
+var deviceBootServiceTester *tDeviceBootService
+
+func Test_DeviceBootService_Prepare(t *testing.T) {
+	deviceBootServiceTester = &tDeviceBootService{}
+	deviceBootServiceTester.dbPath = "deviceBootService_test.db"
+	_ = os.Remove(deviceBootServiceTester.dbPath)
+	testGenDb, err := gorm.Open(sqlite.Open(deviceBootServiceTester.dbPath), &gorm.Config{})
+	if err != nil {
+		t.Errorf("creating db failed: %v", err)
+		return
+	}
+	err = testGenDb.AutoMigrate(
+		new(domain.EthernetSwitch),
+		new(domain.EthernetSwitchPort),
+		new(domain.Device),
+		new(domain.DeviceNetworkInterface),
+		new(domain.DeviceBoot),
+		new(domain.DeviceBootStage),
+		new(domain.TFTPConfig),
+		new(domain.TFTPPathRatio),
+	)
+	if err != nil {
+		t.Errorf("migration failed: %v", err)
+		return
+	}
+	executedFilePath, _ := os.Executable()
+	templatesDir := path.Join(path.Dir(executedFilePath), "templates", "devices")
+	if err = os.MkdirAll(templatesDir, 0777); err != nil {
+		t.Errorf("creating templates dir failed: %v", err)
+		return
+	}
+	template := domain.DeviceTemplate{
+		Name:    deviceBootServiceTemplateName,
+		Control: domain.DeviceTemplateControlDesc{Power: "POE"},
+		NetworkInterfaces: []domain.DeviceTemplateNetworkInterface{
+			{Name: "eth0", NetBoot: true, POEIn: true, Management: true},
+		},
+		NetBootStages: []domain.BootStageTemplate{
+			{Name: "bootloader", Action: "File", Files: []domain.BootStageTemplateFile{
+				{ExistingFileName: "boot/start.elf", VirtualFileName: "/start.elf"},
+				{ExistingFileName: "boot/config.txt", VirtualFileName: "config.txt"},
+			}},
+			{Name: "management", Action: "CheckManagement"},
+			{Name: "power off", Action: "PowerOff"},
+		},
+		USBBootStages: []domain.BootStageTemplate{
+			{Name: "kernel", Action: "File", Files: []domain.BootStageTemplateFile{
+				{ExistingFileName: "boot/kernel.img", VirtualFileName: "kernel.img"},
+			}},
+		},
+	}
+	yamlData, err := yaml.Marshal(&template)
+	if err != nil {
+		t.Errorf("yaml marshal failed: %v", err)
+		return
+	}
+	deviceBootServiceTester.templatePath = path.Join(templatesDir, deviceBootServiceTemplateName+".yml")
+	if err = ioutil.WriteFile(deviceBootServiceTester.templatePath, yamlData, 0777); err != nil {
+		t.Errorf("create yaml file failed: %v", err)
+		return
+	}
+
+	logger := logrus.New()
+	deviceRepo := infrastructure.NewGormDeviceRepository(testGenDb, logger)
+	interfaceRepo := infrastructure.NewGormDeviceNetworkInterfaceRepository(testGenDb, logger)
+	switchRepo := infrastructure.NewGormEthernetSwitchRepository(testGenDb, logger)
+	portRepo := infrastructure.NewGormEthernetSwitchPortRepository(testGenDb, logger)
+	deviceBootServiceTester.bootsRepo = infrastructure.NewGormDeviceBootRepository(testGenDb, logger)
+	stagesRepo := infrastructure.NewGormDeviceBootStageRepository(testGenDb, logger)
+	templateStorage, err := infrastructure.NewDeviceTemplateStorage(logger)
+	if err != nil {
+		t.Errorf("creating templates storage failed: %v", err)
+		return
+	}
+	drivers, err := infrastructure.NewEthernetSwitchDriverRegistry(&domain.AppConfig{})
+	if err != nil {
+		t.Errorf("create switch drivers registry failed: %v", err)
+		return
+	}
+	deviceService, err := services.NewDeviceService(deviceRepo, interfaceRepo, switchRepo, portRepo, templateStorage,
+		infrastructure.NewEthernetSwitchManagerProvider(switchRepo, drivers), logger)
+	if err != nil {
+		t.Errorf("create device service failed: %v", err)
+		return
+	}
+	factory, _ := infrastructure.NewPinTFTPServerFactory(nil, nil)
+	deviceBootServiceTester.tftpService = services.NewTFTPServerService(
+		infrastructure.NewGormGenericRepository[uuid.UUID, domain.TFTPConfig](testGenDb, logger),
+		infrastructure.NewGormGenericRepository[uuid.UUID, domain.TFTPPathRatio](testGenDb, logger),
+		factory, logger)
+	deviceBootServiceTester.events = infrastructure.NewChannelBootEventBus()
+	deviceBootServiceTester.service = services.NewDeviceBootService(deviceBootServiceTester.bootsRepo, stagesRepo,
+		deviceRepo, interfaceRepo, templateStorage, deviceService, deviceBootServiceTester.tftpService,
+		deviceBootServiceTester.events, logger)
+	if err = services.DeviceBootServiceInit(deviceBootServiceTester.service); err != nil {
+		t.Errorf("device boot service init failed: %v", err)
+		return
+	}
+
+	ctx := context.TODO()
+	tftpServer, err := deviceBootServiceTester.tftpService.CreateServer(ctx, dtos.TFTPServerCreateDto{
+		TFTPServerBaseDto: dtos.TFTPServerBaseDto{Address: "127.0.0.1", Port: "16975"},
+	})
+	if err != nil {
+		t.Errorf("create tftp server failed: %v", err)
+		return
+	}
+	deviceBootServiceTester.tftpServerID = tftpServer.ID
+	ethSwitch, err := switchRepo.Insert(ctx, domain.EthernetSwitch{
+		Name:        "AutoTesting",
+		Serial:      "device_boot_test_serial",
+		SwitchModel: "sim-24p",
+		Address:     "123.123.123.124",
+	})
+	if err != nil {
+		t.Errorf("create switch failed: %v", err)
+		return
+	}
+	port, err := portRepo.Insert(ctx, domain.EthernetSwitchPort{
+		Name:             "gi1/0/1",
+		EthernetSwitchID: ethSwitch.ID,
+		POEType:          "poe",
+	})
+	if err != nil {
+		t.Errorf("create switch port failed: %v", err)
+		return
+	}
+	device, err := deviceService.Create(ctx, dtos.DeviceCreateDto{
+		DeviceBaseDto:  dtos.DeviceBaseDto{Name: "AutoTesting", Serial: "boot_serial_1"},
+		DeviceTemplate: deviceBootServiceTemplateName,
+		NetworkInterfaces: []dtos.DeviceNetworkInterfaceCreateDto{{
+			Name: "eth0",
+			DeviceNetworkInterfaceBaseDto: dtos.DeviceNetworkInterfaceBaseDto{
+				MAC:                  deviceBootServiceMAC,
+				EthernetSwitchID:     ethSwitch.ID,
+				EthernetSwitchPortID: port.ID,
+			},
+		}},
+	})
+	if err != nil {
+		t.Errorf("create device failed: %v", err)
+		return
+	}
+	deviceBootServiceTester.deviceID = device.ID
+}
+
+//waitDeviceBoot polls the current device boot until condition is true or timeout is expired
+func waitDeviceBoot(condition func(boot dtos.DeviceBootDto) bool, timeout time.Duration) (dtos.DeviceBootDto, bool) {
+	deadline := time.Now().Add(timeout)
+	boot := dtos.DeviceBootDto{}
+	for time.Now().Before(deadline) {
+		current, err := deviceBootServiceTester.service.GetCurrentBoot(context.TODO(), deviceBootServiceTester.deviceID)
+		if err == nil {
+			boot = current
+			if condition(boot) {
+				return boot, true
+			}
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	return boot, false
+}
+
+//cancelDeviceBoot cancels the device boot if it is still running, so the next test can start a new boot
+func cancelDeviceBoot() {
+	_, _ = deviceBootServiceTester.service.CancelBoot(context.TODO(), deviceBootServiceTester.deviceID)
+}
+
+func Test_DeviceBootService_GetCurrentNotFound(t *testing.T) {
+	_, err := deviceBootServiceTester.service.GetCurrentBoot(context.TODO(), deviceBootServiceTester.deviceID)
+	if err == nil || !errors.As(err, errors.NotFound) {
+		t.Error("expect not found error")
+	}
+}
+
+func Test_DeviceBootService_StartFailByBootType(t *testing.T) {
+	_, err := deviceBootServiceTester.service.StartBoot(context.TODO(), deviceBootServiceTester.deviceID, dtos.DeviceBootStartDto{
+		BootType:     "disc",
+		TFTPServerID: deviceBootServiceTester.tftpServerID,
+	})
+	if err == nil || !errors.As(err, errors.Validation) {
+		t.Error("expect validation error")
+		return
+	}
+	if _, ok := errors.GetErrorContext(err)["BootType"]; !ok {
+		t.Error("expect boot type validation error")
+	}
+}
+
+func Test_DeviceBootService_StartFailByTFTPServer(t *testing.T) {
+	_, err := deviceBootServiceTester.service.StartBoot(context.TODO(), deviceBootServiceTester.deviceID, dtos.DeviceBootStartDto{
+		BootType:     "net",
+		TFTPServerID: uuid.New(),
+	})
+	if err == nil || !errors.As(err, errors.Validation) {
+		t.Error("expect validation error")
+		return
+	}
+	if _, ok := errors.GetErrorContext(err)["TFTPServerID"]; !ok {
+		t.Error("expect tftp server validation error")
+	}
+}
+
+func Test_DeviceBootService_NetBootOK(t *testing.T) {
+	ctx := context.TODO()
+	_, err := deviceBootServiceTester.service.StartBoot(ctx, deviceBootServiceTester.deviceID, dtos.DeviceBootStartDto{
+		BootType:     "net",
+		TFTPServerID: deviceBootServiceTester.tftpServerID,
+	})
+	if err != nil {
+		t.Errorf("start boot failed: %v", err)
+		return
+	}
+	defer cancelDeviceBoot()
+	deadline := time.Now().Add(5 * time.Second)
+	paths := dtos.PaginatedItemsDto[dtos.TFTPPathDto]{}
+	for time.Now().Before(deadline) && paths.Pagination.TotalCount != 2 {
+		time.Sleep(50 * time.Millisecond)
+		paths, err = deviceBootServiceTester.tftpService.GetPathsList(ctx, deviceBootServiceTester.tftpServerID, "", "", 1, 10)
+		if err != nil {
+			t.Errorf("get tftp paths failed: %v", err)
+			return
+		}
+	}
+	if paths.Pagination.TotalCount != 2 {
+		t.Errorf("unexpected tftp paths count: %d, expect 2", paths.Pagination.TotalCount)
+		return
+	}
+	for _, tftpPath := range paths.Items {
+		if tftpPath.ClientMAC != deviceBootServiceMAC {
+			t.Errorf("tftp path %s is not scoped to the device mac: %q", tftpPath.VirtualPath, tftpPath.ClientMAC)
+		}
+	}
+	for _, virtualPath := range []string{"start.elf", "config.txt"} {
+		deviceBootServiceTester.events.Publish(domain.BootEvent{
+			Type:        domain.BootEventTFTPFetch,
+			ClientMAC:   deviceBootServiceMAC,
+			VirtualPath: virtualPath,
+			Time:        time.Now(),
+		})
+	}
+	_, ok := waitDeviceBoot(func(boot dtos.DeviceBootDto) bool { return boot.StageIndex == 1 }, 10*time.Second)
+	if !ok {
+		t.Error("boot is not advanced to the management stage")
+		return
+	}
+	deviceBootServiceTester.events.Publish(domain.BootEvent{
+		Type:      domain.BootEventDHCPRequest,
+		ClientMAC: deviceBootServiceMAC,
+		Time:      time.Now(),
+	})
+	boot, err := deviceBootServiceTester.service.WaitBoot(ctx, deviceBootServiceTester.deviceID)
+	if err != nil || boot.State != domain.DeviceBootStateSucceeded.String() {
+		t.Errorf("unexpected boot state: %s, error: %q, wait error: %v", boot.State, boot.Error, err)
+		return
+	}
+	if len(boot.Stages) != 3 {
+		t.Errorf("unexpected stages count: %d, expect 3", len(boot.Stages))
+		return
+	}
+	for _, stage := range boot.Stages {
+		if stage.State != domain.DeviceBootStateSucceeded.String() {
+			t.Errorf("unexpected stage %q state: %s", stage.Name, stage.State)
+		}
+	}
+	paths, err = deviceBootServiceTester.tftpService.GetPathsList(ctx, deviceBootServiceTester.tftpServerID, "", "", 1, 10)
+	if err != nil {
+		t.Errorf("get tftp paths failed: %v", err)
+		return
+	}
+	if paths.Pagination.TotalCount != 0 {
+		t.Errorf("stage tftp paths are not removed, count: %d", paths.Pagination.TotalCount)
+	}
+}
+
+func Test_DeviceBootService_StageTimeout(t *testing.T) {
+	ctx := context.TODO()
+	_, err := deviceBootServiceTester.service.StartBoot(ctx, deviceBootServiceTester.deviceID, dtos.DeviceBootStartDto{
+		BootType:     "usb",
+		TFTPServerID: deviceBootServiceTester.tftpServerID,
+		StageTimeout: 1,
+	})
+	if err != nil {
+		t.Errorf("start boot failed: %v", err)
+		return
+	}
+	boot, err := deviceBootServiceTester.service.WaitBoot(ctx, deviceBootServiceTester.deviceID)
+	if err != nil || boot.State != domain.DeviceBootStateFailed.String() || boot.Error == "" {
+		t.Errorf("unexpected boot state: %s, error: %q, wait error: %v", boot.State, boot.Error, err)
+	}
+}
+
+func Test_DeviceBootService_Cancel(t *testing.T) {
+	ctx := context.TODO()
+	startDto := dtos.DeviceBootStartDto{
+		BootType:     "usb",
+		TFTPServerID: deviceBootServiceTester.tftpServerID,
+	}
+	_, err := deviceBootServiceTester.service.StartBoot(ctx, deviceBootServiceTester.deviceID, startDto)
+	if err != nil {
+		t.Errorf("start boot failed: %v", err)
+		return
+	}
+	defer cancelDeviceBoot()
+	_, err = deviceBootServiceTester.service.StartBoot(ctx, deviceBootServiceTester.deviceID, startDto)
+	if err == nil || !errors.As(err, errors.Validation) {
+		t.Error("expect validation error on the second boot start")
+	}
+	boot, err := deviceBootServiceTester.service.CancelBoot(ctx, deviceBootServiceTester.deviceID)
+	if err != nil {
+		t.Errorf("cancel boot failed: %v", err)
+		return
+	}
+	if boot.State != domain.DeviceBootStateCancelled.String() {
+		t.Errorf("unexpected boot state: %s, expect %s", boot.State, domain.DeviceBootStateCancelled.String())
+	}
+	_, err = deviceBootServiceTester.service.CancelBoot(ctx, deviceBootServiceTester.deviceID)
+	if err == nil || !errors.As(err, errors.NotFound) {
+		t.Error("expect not found error on the finished boot cancel")
+	}
+}
+
+func Test_DeviceBootService_GetList(t *testing.T) {
+	boots, err := deviceBootServiceTester.service.GetBootList(context.TODO(), deviceBootServiceTester.deviceID, "", "", 1, 10)
+	if err != nil {
+		t.Errorf("get boots list failed: %v", err)
+		return
+	}
+	if boots.Pagination.TotalCount != 3 {
+		t.Errorf("unexpected boots count: %d, expect 3", boots.Pagination.TotalCount)
+	}
+}
+
+func Test_DeviceBootService_RemoveDb(t *testing.T) {
+	if err := deviceBootServiceTester.bootsRepo.Dispose(); err != nil {
+		t.Errorf("close db failed:  %s", err)
+	}
+	if err := os.Remove(deviceBootServiceTester.dbPath); err != nil {
+		t.Errorf("remove db failed:  %s", err)
+	}
+	if err := os.Remove(deviceBootServiceTester.templatePath); err != nil {
+		t.Errorf("remove template failed:  %s", err)
+	}
+}
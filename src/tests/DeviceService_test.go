@@ -0,0 +1,482 @@
+package tests
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"io/ioutil"
+	"os"
+	"path"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/services"
+	"rol/domain"
+	"rol/dtos"
+	"rol/infrastructure"
+	"testing"
+	"time"
+)
+
+const deviceServiceTemplateName = "AutoTestingDeviceService"
+
+type tDeviceService struct {
+	service       *services.DeviceService
+	deviceRepo    interfaces.IGenericRepository[uuid.UUID, domain.Device]
+	interfaceRepo interfaces.IGenericRepository[uuid.UUID, domain.DeviceNetworkInterface]
+	switchRepo    interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitch]
+	portRepo      interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitchPort]
+	dbPath        string
+	templatePath  string
+	switchID      uuid.UUID
+	portID        uuid.UUID
+	deviceID      uuid.UUID
+}
+
+var deviceServiceTester *tDeviceService
+
+func Test_DeviceService_Prepare(t *testing.T) {
+	deviceServiceTester = &tDeviceService{}
+	deviceServiceTester.dbPath = "deviceService_test.db"
+	_ = os.Remove(deviceServiceTester.dbPath)
+	dbConnection := sqlite.Open(deviceServiceTester.dbPath)
+	testGenDb, err := gorm.Open(dbConnection, &gorm.Config{})
+	if err != nil {
+		t.Errorf("creating db failed: %v", err)
+	}
+	err = testGenDb.AutoMigrate(
+		new(domain.EthernetSwitch),
+		new(domain.EthernetSwitchPort),
+		new(domain.Device),
+		new(domain.DeviceNetworkInterface),
+	)
+	if err != nil {
+		t.Errorf("migration failed: %v", err)
+	}
+	executedFilePath, _ := os.Executable()
+	templatesDir := path.Join(path.Dir(executedFilePath), "templates", "devices")
+	err = os.MkdirAll(templatesDir, 0777)
+	if err != nil {
+		t.Errorf("creating templates dir failed: %v", err)
+	}
+	template := domain.DeviceTemplate{
+		Name: deviceServiceTemplateName,
+		Control: domain.DeviceTemplateControlDesc{
+			Power: "POE",
+		},
+		NetworkInterfaces: []domain.DeviceTemplateNetworkInterface{
+			{Name: "eth0", NetBoot: true, POEIn: true, Management: true},
+			{Name: "eth1"},
+		},
+	}
+	yamlData, err := yaml.Marshal(&template)
+	if err != nil {
+		t.Errorf("yaml marshal failed: %v", err)
+	}
+	deviceServiceTester.templatePath = path.Join(templatesDir, deviceServiceTemplateName+".yml")
+	err = ioutil.WriteFile(deviceServiceTester.templatePath, yamlData, 0777)
+	if err != nil {
+		t.Errorf("create yaml file failed: %v", err)
+	}
+
+	logger := logrus.New()
+	deviceServiceTester.deviceRepo = infrastructure.NewGormDeviceRepository(testGenDb, logger)
+	deviceServiceTester.interfaceRepo = infrastructure.NewGormDeviceNetworkInterfaceRepository(testGenDb, logger)
+	deviceServiceTester.switchRepo = infrastructure.NewGormEthernetSwitchRepository(testGenDb, logger)
+	deviceServiceTester.portRepo = infrastructure.NewGormEthernetSwitchPortRepository(testGenDb, logger)
+	templateStorage, err := infrastructure.NewDeviceTemplateStorage(logger)
+	if err != nil {
+		t.Errorf("creating templates storage failed: %v", err)
+	}
+	drivers, err := infrastructure.NewEthernetSwitchDriverRegistry(&domain.AppConfig{})
+	if err != nil {
+		t.Errorf("create switch drivers registry failed: %v", err)
+	}
+	managersProvider := infrastructure.NewEthernetSwitchManagerProvider(deviceServiceTester.switchRepo, drivers)
+	deviceServiceTester.service, err = services.NewDeviceService(deviceServiceTester.deviceRepo,
+		deviceServiceTester.interfaceRepo, deviceServiceTester.switchRepo, deviceServiceTester.portRepo, templateStorage,
+		managersProvider, logger)
+	if err != nil {
+		t.Errorf("create new service failed: %v", err)
+	}
+
+	ctx := context.TODO()
+	ethSwitch, err := deviceServiceTester.switchRepo.Insert(ctx, domain.EthernetSwitch{
+		Name:        "AutoTesting",
+		Serial:      "device_test_serial",
+		SwitchModel: "sim-24p",
+		Address:     "123.123.123.123",
+	})
+	if err != nil {
+		t.Errorf("create switch failed: %v", err)
+	}
+	deviceServiceTester.switchID = ethSwitch.ID
+	port, err := deviceServiceTester.portRepo.Insert(ctx, domain.EthernetSwitchPort{
+		Name:             "gi1/0/1",
+		EthernetSwitchID: ethSwitch.ID,
+		POEType:          "poe",
+	})
+	if err != nil {
+		t.Errorf("create switch port failed: %v", err)
+	}
+	deviceServiceTester.portID = port.ID
+}
+
+func Test_DeviceService_CreateFailByUnknownTemplate(t *testing.T) {
+	createDto := dtos.DeviceCreateDto{
+		DeviceBaseDto:  dtos.DeviceBaseDto{Name: "AutoTesting", Serial: "serial_1"},
+		DeviceTemplate: "NotExistedTemplate",
+	}
+	_, err := deviceServiceTester.service.Create(context.TODO(), createDto)
+	if err == nil || !errors.As(err, errors.Validation) {
+		t.Error("expect validation error")
+		return
+	}
+	if _, ok := errors.GetErrorContext(err)["DeviceTemplate"]; !ok {
+		t.Error("expect device template validation error")
+	}
+}
+
+func Test_DeviceService_CreateFailByUnknownInterface(t *testing.T) {
+	createDto := dtos.DeviceCreateDto{
+		DeviceBaseDto:  dtos.DeviceBaseDto{Name: "AutoTesting", Serial: "serial_1"},
+		DeviceTemplate: deviceServiceTemplateName,
+		NetworkInterfaces: []dtos.DeviceNetworkInterfaceCreateDto{{
+			Name: "wlan0",
+		}},
+	}
+	_, err := deviceServiceTester.service.Create(context.TODO(), createDto)
+	if err == nil || !errors.As(err, errors.Validation) {
+		t.Error("expect validation error")
+		return
+	}
+	if _, ok := errors.GetErrorContext(err)["NetworkInterfaces[0].Name"]; !ok {
+		t.Error("expect network interface name validation error")
+	}
+}
+
+func Test_DeviceService_CreateFailByNotExistedPort(t *testing.T) {
+	createDto := dtos.DeviceCreateDto{
+		DeviceBaseDto:  dtos.DeviceBaseDto{Name: "AutoTesting", Serial: "serial_1"},
+		DeviceTemplate: deviceServiceTemplateName,
+		NetworkInterfaces: []dtos.DeviceNetworkInterfaceCreateDto{{
+			Name: "eth0",
+			DeviceNetworkInterfaceBaseDto: dtos.DeviceNetworkInterfaceBaseDto{
+				EthernetSwitchID:     deviceServiceTester.switchID,
+				EthernetSwitchPortID: uuid.New(),
+			},
+		}},
+	}
+	_, err := deviceServiceTester.service.Create(context.TODO(), createDto)
+	if err == nil || !errors.As(err, errors.Validation) {
+		t.Error("expect validation error")
+		return
+	}
+	if _, ok := errors.GetErrorContext(err)["NetworkInterfaces[0].EthernetSwitchPortID"]; !ok {
+		t.Error("expect ethernet switch port validation error")
+	}
+}
+
+func Test_DeviceService_CreateOK(t *testing.T) {
+	createDto := dtos.DeviceCreateDto{
+		DeviceBaseDto:  dtos.DeviceBaseDto{Name: "AutoTesting", Serial: "serial_1"},
+		DeviceTemplate: deviceServiceTemplateName,
+		NetworkInterfaces: []dtos.DeviceNetworkInterfaceCreateDto{{
+			Name: "eth0",
+			DeviceNetworkInterfaceBaseDto: dtos.DeviceNetworkInterfaceBaseDto{
+				MAC:                  "00:11:22:33:44:55",
+				EthernetSwitchID:     deviceServiceTester.switchID,
+				EthernetSwitchPortID: deviceServiceTester.portID,
+			},
+		}},
+	}
+	device, err := deviceServiceTester.service.Create(context.TODO(), createDto)
+	if err != nil {
+		t.Errorf("create device failed: %v", err)
+		return
+	}
+	deviceServiceTester.deviceID = device.ID
+	if len(device.NetworkInterfaces) != 2 {
+		t.Errorf("unexpected network interfaces count: %d, expect 2", len(device.NetworkInterfaces))
+		return
+	}
+	for _, networkInterface := range device.NetworkInterfaces {
+		if networkInterface.Name == "eth0" && networkInterface.EthernetSwitchPortID != deviceServiceTester.portID {
+			t.Error("eth0 network interface is not cabled to the port")
+		}
+	}
+}
+
+func Test_DeviceService_CreateFailByNotUniqueSerial(t *testing.T) {
+	createDto := dtos.DeviceCreateDto{
+		DeviceBaseDto:  dtos.DeviceBaseDto{Name: "AutoTesting2", Serial: "serial_1"},
+		DeviceTemplate: deviceServiceTemplateName,
+	}
+	_, err := deviceServiceTester.service.Create(context.TODO(), createDto)
+	if err == nil || !errors.As(err, errors.Validation) {
+		t.Error("expect validation error")
+	}
+}
+
+func Test_DeviceService_NetworkInterfaceMACNormalization(t *testing.T) {
+	ctx := context.TODO()
+	createDto := dtos.DeviceCreateDto{
+		DeviceBaseDto:  dtos.DeviceBaseDto{Name: "AutoTesting4", Serial: "serial_4"},
+		DeviceTemplate: deviceServiceTemplateName,
+		NetworkInterfaces: []dtos.DeviceNetworkInterfaceCreateDto{{
+			Name:                          "eth0",
+			DeviceNetworkInterfaceBaseDto: dtos.DeviceNetworkInterfaceBaseDto{MAC: "AA:BB:CC:00:00:01"},
+		}},
+	}
+	device, err := deviceServiceTester.service.Create(ctx, createDto)
+	if err != nil {
+		t.Errorf("create device failed: %v", err)
+		return
+	}
+	defer func() {
+		_ = deviceServiceTester.service.Delete(ctx, device.ID)
+	}()
+	if device.NetworkInterfaces[0].MAC != "aa:bb:cc:00:00:01" {
+		t.Errorf("unexpected stored mac: %s, expect aa:bb:cc:00:00:01", device.NetworkInterfaces[0].MAC)
+	}
+	updateDto := dtos.DeviceNetworkInterfaceUpdateDto{
+		DeviceNetworkInterfaceBaseDto: dtos.DeviceNetworkInterfaceBaseDto{MAC: "aa:bb:cc:00:00:01"},
+	}
+	_, err = deviceServiceTester.service.UpdateNetworkInterface(ctx, device.ID, device.NetworkInterfaces[1].ID, updateDto)
+	if err == nil || !errors.As(err, errors.Validation) {
+		t.Error("expect validation error for the same mac address in another case")
+	}
+}
+
+type tDeviceFailingInterfaceRepo struct {
+	interfaces.IGenericRepository[uuid.UUID, domain.DeviceNetworkInterface]
+	inserted int
+}
+
+func (r *tDeviceFailingInterfaceRepo) Insert(ctx context.Context, entity domain.DeviceNetworkInterface) (domain.DeviceNetworkInterface, error) {
+	if r.inserted > 0 {
+		return entity, errors.Internal.New("insert failed")
+	}
+	r.inserted++
+	return r.IGenericRepository.Insert(ctx, entity)
+}
+
+func Test_DeviceService_CreateRemovesDeviceOnInterfaceInsertFail(t *testing.T) {
+	ctx := context.TODO()
+	templateStorage, _ := infrastructure.NewDeviceTemplateStorage(logrus.New())
+	service, err := services.NewDeviceService(deviceServiceTester.deviceRepo,
+		&tDeviceFailingInterfaceRepo{IGenericRepository: deviceServiceTester.interfaceRepo},
+		deviceServiceTester.switchRepo, deviceServiceTester.portRepo, templateStorage, nil, logrus.New())
+	if err != nil {
+		t.Errorf("create new service failed: %v", err)
+		return
+	}
+	createDto := dtos.DeviceCreateDto{
+		DeviceBaseDto:  dtos.DeviceBaseDto{Name: "AutoTesting3", Serial: "serial_3"},
+		DeviceTemplate: deviceServiceTemplateName,
+	}
+	if _, err = service.Create(ctx, createDto); err == nil {
+		t.Error("device was created with failed network interface insert")
+	}
+	device, err := deviceServiceTester.service.Create(ctx, createDto)
+	if err != nil {
+		t.Errorf("create device with the same serial after failure failed: %v", err)
+		return
+	}
+	if len(device.NetworkInterfaces) != 2 {
+		t.Errorf("unexpected network interfaces count: %d, expect 2", len(device.NetworkInterfaces))
+	}
+	err = deviceServiceTester.service.Delete(ctx, device.ID)
+	if err != nil {
+		t.Errorf("delete device failed: %v", err)
+	}
+}
+
+func Test_DeviceService_UpdateNetworkInterfaceFailByBusyPort(t *testing.T) {
+	ctx := context.TODO()
+	createDto := dtos.DeviceCreateDto{
+		DeviceBaseDto:  dtos.DeviceBaseDto{Name: "AutoTesting2", Serial: "serial_2"},
+		DeviceTemplate: deviceServiceTemplateName,
+	}
+	device, err := deviceServiceTester.service.Create(ctx, createDto)
+	if err != nil {
+		t.Errorf("create device failed: %v", err)
+		return
+	}
+	updateDto := dtos.DeviceNetworkInterfaceUpdateDto{
+		DeviceNetworkInterfaceBaseDto: dtos.DeviceNetworkInterfaceBaseDto{
+			EthernetSwitchID:     deviceServiceTester.switchID,
+			EthernetSwitchPortID: deviceServiceTester.portID,
+		},
+	}
+	_, err = deviceServiceTester.service.UpdateNetworkInterface(ctx, device.ID, device.NetworkInterfaces[0].ID, updateDto)
+	if err == nil || !errors.As(err, errors.Validation) {
+		t.Error("expect validation error")
+	}
+	_, err = deviceServiceTester.service.PowerOn(ctx, device.ID)
+	if err == nil || !errors.As(err, errors.Validation) {
+		t.Error("expect validation error for power on not cabled device")
+	}
+	err = deviceServiceTester.service.Delete(ctx, device.ID)
+	if err != nil {
+		t.Errorf("delete device failed: %v", err)
+	}
+}
+
+func Test_DeviceService_PowerOn(t *testing.T) {
+	ctx := context.TODO()
+	device, err := deviceServiceTester.service.PowerOn(ctx, deviceServiceTester.deviceID)
+	if err != nil {
+		t.Errorf("power on device failed: %v", err)
+		return
+	}
+	if device.PowerState != domain.DevicePowerStateOn.String() {
+		t.Errorf("unexpected power state: %s, expect %s", device.PowerState, domain.DevicePowerStateOn.String())
+	}
+	port, err := deviceServiceTester.portRepo.GetByID(ctx, deviceServiceTester.portID)
+	if err != nil {
+		t.Errorf("get switch port failed: %v", err)
+		return
+	}
+	if !port.POEEnabled {
+		t.Error("poe is not enabled on the cabled port")
+	}
+}
+
+func Test_DeviceService_PowerOff(t *testing.T) {
+	ctx := context.TODO()
+	_, err := deviceServiceTester.service.PowerOff(ctx, deviceServiceTester.deviceID)
+	if err != nil {
+		t.Errorf("power off device failed: %v", err)
+		return
+	}
+	device, err := deviceServiceTester.service.GetByID(ctx, deviceServiceTester.deviceID)
+	if err != nil {
+		t.Errorf("get device failed: %v", err)
+		return
+	}
+	if device.PowerState != domain.DevicePowerStateOff.String() {
+		t.Errorf("unexpected power state: %s, expect %s", device.PowerState, domain.DevicePowerStateOff.String())
+	}
+}
+
+func Test_DeviceService_PowerCycle(t *testing.T) {
+	ctx := context.TODO()
+	started := time.Now()
+	device, err := deviceServiceTester.service.PowerCycle(ctx, deviceServiceTester.deviceID)
+	if err != nil {
+		t.Errorf("power cycle device failed: %v", err)
+		return
+	}
+	//device is powered on in background after the power cycle delay
+	if time.Since(started) > time.Second || device.PowerState != domain.DevicePowerStateOff.String() {
+		t.Errorf("power cycle is not finished in background: power state %s, duration %v", device.PowerState, time.Since(started))
+	}
+	if _, err = deviceServiceTester.service.PowerCycle(ctx, deviceServiceTester.deviceID); !errors.As(err, errors.Validation) {
+		t.Errorf("expect validation error for running power cycle, got %v", err)
+	}
+	if _, err = deviceServiceTester.service.PowerOff(ctx, deviceServiceTester.deviceID); !errors.As(err, errors.Validation) {
+		t.Errorf("expect validation error for power off during power cycle, got %v", err)
+	}
+	for i := 0; i < 50 && device.PowerState != domain.DevicePowerStateOn.String(); i++ {
+		time.Sleep(200 * time.Millisecond)
+		device, err = deviceServiceTester.service.GetByID(ctx, deviceServiceTester.deviceID)
+		if err != nil {
+			t.Errorf("get device failed: %v", err)
+			return
+		}
+	}
+	if device.PowerState != domain.DevicePowerStateOn.String() {
+		t.Errorf("device is not powered on after power cycle: %s", device.PowerState)
+	}
+}
+
+func Test_DeviceService_PowerFailBySwitchWithoutManager(t *testing.T) {
+	ctx := context.TODO()
+	ethSwitch, err := deviceServiceTester.switchRepo.Insert(ctx, domain.EthernetSwitch{
+		Name:        "AutoTestingUnmanaged",
+		Serial:      "device_test_unmanaged_serial",
+		SwitchModel: "unifi_switch_us-24-250w",
+		Address:     "123.123.123.125",
+	})
+	if err != nil {
+		t.Errorf("create switch failed: %v", err)
+		return
+	}
+	port, err := deviceServiceTester.portRepo.Insert(ctx, domain.EthernetSwitchPort{
+		Name:             "gi1",
+		EthernetSwitchID: ethSwitch.ID,
+		POEType:          "poe",
+	})
+	if err != nil {
+		t.Errorf("create switch port failed: %v", err)
+		return
+	}
+	device, err := deviceServiceTester.service.Create(ctx, dtos.DeviceCreateDto{
+		DeviceBaseDto:  dtos.DeviceBaseDto{Name: "AutoTestingUnmanaged", Serial: "serial_unmanaged"},
+		DeviceTemplate: deviceServiceTemplateName,
+		NetworkInterfaces: []dtos.DeviceNetworkInterfaceCreateDto{{
+			Name: "eth0",
+			DeviceNetworkInterfaceBaseDto: dtos.DeviceNetworkInterfaceBaseDto{
+				MAC:                  "00:11:22:33:44:66",
+				EthernetSwitchID:     ethSwitch.ID,
+				EthernetSwitchPortID: port.ID,
+			},
+		}},
+	})
+	if err != nil {
+		t.Errorf("create device failed: %v", err)
+		return
+	}
+	defer func() {
+		_ = deviceServiceTester.service.Delete(ctx, device.ID)
+	}()
+	if err = deviceServiceTester.service.CheckPowerControl(ctx, device.ID); !errors.As(err, errors.Validation) {
+		t.Errorf("expect validation error for the switch without manager, got %v", err)
+	}
+	if _, err = deviceServiceTester.service.PowerOn(ctx, device.ID); !errors.As(err, errors.Validation) {
+		t.Errorf("expect validation error for power on through the switch without manager, got %v", err)
+	}
+	port, err = deviceServiceTester.portRepo.GetByID(ctx, port.ID)
+	if err != nil || port.POEEnabled {
+		t.Errorf("poe is saved as enabled on the port of the switch without manager, error: %v", err)
+	}
+	device, err = deviceServiceTester.service.GetByID(ctx, device.ID)
+	if err != nil || device.PowerState != domain.DevicePowerStateUnknown.String() {
+		t.Errorf("unexpected power state of the device: %s, error: %v", device.PowerState, err)
+	}
+}
+
+func Test_DeviceService_Delete(t *testing.T) {
+	ctx := context.TODO()
+	err := deviceServiceTester.service.Delete(ctx, deviceServiceTester.deviceID)
+	if err != nil {
+		t.Errorf("delete device failed: %v", err)
+		return
+	}
+	_, err = deviceServiceTester.service.GetByID(ctx, deviceServiceTester.deviceID)
+	if err == nil || !errors.As(err, errors.NotFound) {
+		t.Error("expect not found error")
+	}
+	count, err := deviceServiceTester.interfaceRepo.Count(ctx, nil)
+	if err != nil {
+		t.Errorf("count network interfaces failed: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("unexpected network interfaces count: %d, expect 0", count)
+	}
+}
+
+func Test_DeviceService_RemoveDb(t *testing.T) {
+	if err := deviceServiceTester.deviceRepo.Dispose(); err != nil {
+		t.Errorf("close db failed:  %s", err)
+	}
+	if err := os.Remove(deviceServiceTester.dbPath); err != nil {
+		t.Errorf("remove db failed:  %s", err)
+	}
+	if err := os.Remove(deviceServiceTester.templatePath); err != nil {
+		t.Errorf("remove template failed:  %s", err)
+	}
+}
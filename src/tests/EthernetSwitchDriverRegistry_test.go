@@ -0,0 +1,107 @@
+package tests
+
+import (
+	"reflect"
+	"rol/app/errors"
+	"rol/domain"
+	"rol/infrastructure"
+	"testing"
+)
+
+func Test_EthernetSwitchDriverRegistry_GetModels(t *testing.T) {
+	registry, err := infrastructure.NewEthernetSwitchDriverRegistry(&domain.AppConfig{})
+	if err != nil {
+		t.Errorf("create registry failed: %v", err)
+		return
+	}
+	codes := map[string]domain.EthernetSwitchModel{}
+	for _, model := range registry.GetModels() {
+		codes[model.Code] = model
+	}
+	for _, code := range []string{"unifi_switch_us-24-250w", "tl-sg2210mp", "snmp_generic"} {
+		if _, ok := codes[code]; !ok {
+			t.Errorf("model %s is not registered", code)
+		}
+	}
+	model, err := registry.GetModel("tl-sg2210mp")
+	if err != nil {
+		t.Errorf("get model failed: %v", err)
+		return
+	}
+	if model.Manufacturer != "TP-Link" || len(model.Capabilities) == 0 {
+		t.Errorf("unexpected model: %+v", model)
+	}
+	//all managed models report LLDP neighbors
+	for _, code := range []string{"tl-sg2210mp", "snmp_generic", "sim-24p"} {
+		model, err = registry.GetModel(code)
+		if err != nil || !reflect.DeepEqual(model.Capabilities, []string{domain.EthernetSwitchCapabilityVLAN,
+			domain.EthernetSwitchCapabilityPOE, domain.EthernetSwitchCapabilityLLDP}) {
+			t.Errorf("unexpected capabilities of the model %s: %v, error: %v", code, model.Capabilities, err)
+		}
+	}
+}
+
+func Test_EthernetSwitchDriverRegistry_UnknownModel(t *testing.T) {
+	registry, _ := infrastructure.NewEthernetSwitchDriverRegistry(&domain.AppConfig{})
+	_, err := registry.GetModel("bad_model")
+	if !errors.As(err, errors.NotFound) {
+		t.Errorf("expect not found error, got %v", err)
+	}
+	manager, err := registry.NewManager(domain.EthernetSwitch{SwitchModel: "bad_model"})
+	if manager != nil || !errors.As(err, errors.NotFound) {
+		t.Errorf("expect not found error, got %v", err)
+	}
+}
+
+func Test_EthernetSwitchDriverRegistry_NewManager(t *testing.T) {
+	registry, _ := infrastructure.NewEthernetSwitchDriverRegistry(&domain.AppConfig{})
+	manager, err := registry.NewManager(domain.EthernetSwitch{SwitchModel: "unifi_switch_us-24-250w"})
+	if err != nil || manager != nil {
+		t.Errorf("expect database only driver without manager, got %v, %v", manager, err)
+	}
+	manager, err = registry.NewManager(domain.EthernetSwitch{SwitchModel: "snmp_generic", SNMPVersion: "2c"})
+	if err != nil || manager == nil {
+		t.Errorf("expect snmp manager, got error %v", err)
+	}
+}
+
+func Test_EthernetSwitchDriverRegistry_Register(t *testing.T) {
+	created, _ := infrastructure.NewEthernetSwitchDriverRegistry(&domain.AppConfig{})
+	registry := created.(*infrastructure.EthernetSwitchDriverRegistry)
+	driver := infrastructure.EthernetSwitchDriver{
+		Models: []domain.EthernetSwitchModel{{Model: "Custom", Manufacturer: "Custom", Code: "custom"}},
+	}
+	if err := registry.Register(driver); err != nil {
+		t.Errorf("register driver failed: %v", err)
+		return
+	}
+	if _, err := registry.GetModel("custom"); err != nil {
+		t.Errorf("registered model is not found: %v", err)
+	}
+	if err := registry.Register(driver); err == nil {
+		t.Error("expect error on duplicate model code")
+	}
+}
+
+func Test_EthernetSwitchDriverRegistry_RequiresSNMP(t *testing.T) {
+	created, _ := infrastructure.NewEthernetSwitchDriverRegistry(&domain.AppConfig{})
+	registry := created.(*infrastructure.EthernetSwitchDriverRegistry)
+	err := registry.Register(infrastructure.EthernetSwitchDriver{
+		Models:       []domain.EthernetSwitchModel{{Model: "Custom SNMP", Manufacturer: "Custom", Code: "custom_snmp"}},
+		RequiresSNMP: true,
+	})
+	if err != nil {
+		t.Errorf("register driver failed: %v", err)
+		return
+	}
+	expected := map[string]bool{"snmp_generic": true, "custom_snmp": true, "tl-sg2210mp": false, "unifi_switch_us-24-250w": false}
+	for code, expectRequired := range expected {
+		required, err := registry.RequiresSNMP(code)
+		if err != nil || required != expectRequired {
+			t.Errorf("unexpected snmp requirement of %s: %v, expect %v, err: %v", code, required, expectRequired, err)
+		}
+	}
+	if _, err = registry.RequiresSNMP("bad_model"); !errors.As(err, errors.NotFound) {
+		t.Errorf("expect not found error, got %v", err)
+	}
+}
@@ -0,0 +1,49 @@
+package tests
+
+import (
+	"reflect"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/infrastructure"
+	"testing"
+)
+
+func Test_EthernetSwitchRunningConfig_WrongVLANsList(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"zero vlan", "#\nvlan 0\n#\nend\n"},
+		{"vlan above max", "#\nvlan 10,4095\n#\nend\n"},
+		{"range above max", "#\nvlan 4090-4100\n#\nend\n"},
+		{"inverted range", "#\nvlan 30-20\n#\nend\n"},
+		{"port zero vlan", "#\ninterface gigabitEthernet 1/0/1\n switchport general allowed vlan 0 tagged\n#\nend\n"},
+		{"port inverted range", "#\ninterface gigabitEthernet 1/0/1\n switchport general allowed vlan 12-10 tagged\n#\nend\n"},
+		{"port removed vlan above max", "#\ninterface gigabitEthernet 1/0/1\n no switchport general allowed vlan 5000\n#\nend\n"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			simulator := infrastructure.NewSimulatedEthernetSwitch(8, 8)
+			manager := infrastructure.NewSimulatedEthernetSwitchManager(simulator).(interfaces.IEthernetSwitchConfigManager)
+			err := manager.RestoreConfig(test.config)
+			if !errors.As(err, errors.Validation) {
+				t.Errorf("expect validation error, got %v", err)
+			}
+			if vlans := simulator.GetVLANs(); !reflect.DeepEqual(vlans, []int{1}) {
+				t.Errorf("expect config is not applied, got vlans %v", vlans)
+			}
+		})
+	}
+}
+
+func Test_EthernetSwitchRunningConfig_VLANsListBounds(t *testing.T) {
+	simulator := infrastructure.NewSimulatedEthernetSwitch(8, 8)
+	manager := infrastructure.NewSimulatedEthernetSwitchManager(simulator).(interfaces.IEthernetSwitchConfigManager)
+	err := manager.RestoreConfig("#\nvlan 2,4093-4094\n#\nend\n")
+	if err != nil {
+		t.Fatalf("restore config failed: %v", err)
+	}
+	if vlans := simulator.GetVLANs(); !reflect.DeepEqual(vlans, []int{1, 2, 4093, 4094}) {
+		t.Errorf("unexpected vlans after restore: %v", vlans)
+	}
+}
@@ -0,0 +1,122 @@
+package tests
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"rol/app/errors"
+	"rol/domain"
+	"rol/dtos"
+	"strings"
+	"testing"
+)
+
+func Test_EthernetSwitchService_ConfigBackup(t *testing.T) {
+	env := newSimulatedSwitchServiceEnv(t, &domain.AppConfig{}, "ethernetSwitchConfigBackup_test.db")
+	defer env.close()
+	ctx := context.Background()
+	switchID, portsIDs := env.createSwitchWithPorts(t, "gi1/0/1", "gi1/0/2")
+	backups, err := env.service.GetConfigBackups(ctx, switchID, "Version", "desc", 1, 100)
+	if err != nil || len(backups.Items) == 0 {
+		t.Fatalf("expect backups after ports creation, got %+v, error: %v", backups.Items, err)
+	}
+	before := backups.Items[0]
+	if before.Reason != domain.EthernetSwitchConfigBackupChange || before.Version != len(backups.Items) {
+		t.Errorf("unexpected last backup %+v", before)
+	}
+
+	_, err = env.service.CreateVLAN(ctx, switchID, dtos.EthernetSwitchVLANCreateDto{
+		EthernetSwitchVLANBaseDto: dtos.EthernetSwitchVLANBaseDto{
+			TaggedPorts:   []uuid.UUID{portsIDs[0]},
+			UntaggedPorts: []uuid.UUID{},
+		},
+		VlanID: 100,
+	})
+	if err != nil {
+		t.Fatalf("create vlan failed: %v", err)
+	}
+	backups, _ = env.service.GetConfigBackups(ctx, switchID, "Version", "desc", 1, 100)
+	after := backups.Items[0]
+	if after.Version != before.Version+1 || !strings.Contains(after.Config, "vlan 100\n") {
+		t.Fatalf("expect new backup with VLAN 100, got %+v", after)
+	}
+	//unchanged config doesn't create new version
+	_, err = env.service.UpdatePort(ctx, switchID, portsIDs[1], dtos.EthernetSwitchPortUpdateDto{
+		EthernetSwitchPortBaseDto: dtos.EthernetSwitchPortBaseDto{Name: "gi1/0/2", POEType: "poe", POEEnabled: true, PVID: 1},
+	})
+	if err != nil {
+		t.Fatalf("update port failed: %v", err)
+	}
+	if backups, _ = env.service.GetConfigBackups(ctx, switchID, "Version", "desc", 1, 100); backups.Items[0].Version != after.Version {
+		t.Errorf("expect no new backup for unchanged config, got version %d", backups.Items[0].Version)
+	}
+
+	diff, err := env.service.GetConfigBackupsDiff(ctx, switchID, before.Version, after.Version)
+	if err != nil || !strings.Contains(diff.Diff, "\n+vlan 100\n") ||
+		!strings.Contains(diff.Diff, "+ switchport general allowed vlan 100 tagged\n") {
+		t.Errorf("unexpected diff:\n%s\nerror: %v", diff.Diff, err)
+	}
+	if diff, _ = env.service.GetConfigBackupsDiff(ctx, switchID, after.Version, after.Version); diff.Diff != "" {
+		t.Errorf("expect empty diff for the same version, got:\n%s", diff.Diff)
+	}
+
+	restored, err := env.service.RestoreConfigBackup(ctx, switchID, before.ID)
+	if err != nil {
+		t.Fatalf("restore failed: %v", err)
+	}
+	if restored.Version != after.Version+1 || restored.Reason != domain.EthernetSwitchConfigBackupRestore ||
+		restored.Config != before.Config {
+		t.Errorf("unexpected restored backup %+v", restored)
+	}
+	manager, _ := env.managers.Get(ctx, switchID)
+	if vlans, _ := manager.GetVLANs(); len(vlans) != 1 {
+		t.Errorf("expect only default VLAN after restore, got %v", vlans)
+	}
+	vlans, _ := env.service.GetVLANs(ctx, switchID, "", "VlanID", "asc", 1, 10)
+	if len(vlans.Items) != 0 {
+		t.Errorf("expect VLAN records are imported after restore, got %+v", vlans.Items)
+	}
+}
+
+func Test_EthernetSwitchService_ConfigBackupErrors(t *testing.T) {
+	env := newSimulatedSwitchServiceEnv(t, &domain.AppConfig{}, "ethernetSwitchConfigBackupErrors_test.db")
+	defer env.close()
+	ctx := context.Background()
+	switchID, _ := env.createSwitchWithPorts(t, "gi1/0/1")
+	_, err := env.service.GetConfigBackups(ctx, uuid.New(), "", "", 1, 10)
+	if !errors.As(err, errors.NotFound) {
+		t.Errorf("expect not found error for nonexistent switch, got %v", err)
+	}
+	_, err = env.service.GetConfigBackupsDiff(ctx, switchID, 1, 100)
+	if !errors.As(err, errors.NotFound) {
+		t.Errorf("expect not found error for nonexistent version, got %v", err)
+	}
+	_, err = env.service.RestoreConfigBackup(ctx, switchID, uuid.New())
+	if !errors.As(err, errors.NotFound) {
+		t.Errorf("expect not found error for nonexistent backup, got %v", err)
+	}
+	unmanaged, err := env.service.Create(ctx, dtos.EthernetSwitchCreateDto{
+		EthernetSwitchBaseDto: dtos.EthernetSwitchBaseDto{
+			Name:        "UnmanagedSwitch",
+			Serial:      "unmanaged_serial",
+			SwitchModel: "unifi_switch_us-24-250w",
+			Address:     "127.0.0.2",
+			Username:    simTestLogin,
+		},
+		Password: simTestPassword,
+	})
+	if err != nil {
+		t.Fatalf("create switch failed: %v", err)
+	}
+	backups, err := env.service.GetConfigBackups(ctx, unmanaged.ID, "", "", 1, 10)
+	if err != nil || len(backups.Items) != 0 {
+		t.Errorf("expect no backups of the switch without manager, got %+v, error: %v", backups.Items, err)
+	}
+	if err = env.service.Delete(ctx, switchID); err != nil {
+		t.Fatalf("delete switch failed: %v", err)
+	}
+	var count int64
+	env.db.Model(new(domain.EthernetSwitchConfigBackup)).Where("ethernet_switch_id = ?", switchID).Count(&count)
+	if count != 0 {
+		t.Errorf("expect backups are deleted with switch, got %d", count)
+	}
+}
@@ -0,0 +1,117 @@
+package tests
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"reflect"
+	"rol/app/errors"
+	"rol/domain"
+	"rol/dtos"
+	"testing"
+)
+
+func Test_EthernetSwitchService_Discover(t *testing.T) {
+	env := newSimulatedSwitchServiceEnv(t, &domain.AppConfig{}, "ethernetSwitchDiscover_test.db")
+	defer env.close()
+	ctx := context.Background()
+	switchID, portsIDs := env.createSwitchWithPorts(t, "gi1/0/1")
+	manager, _ := env.managers.Get(ctx, switchID)
+	err := manager.ApplyOperations([]domain.EthernetSwitchOperation{
+		{Type: domain.EthernetSwitchOperationCreateVLAN, VLANID: 100},
+		{Type: domain.EthernetSwitchOperationAddTaggedVLAN, PortName: "gi1/0/1", VLANID: 100},
+		{Type: domain.EthernetSwitchOperationAddUntaggedVLAN, PortName: "gi1/0/2", VLANID: 100},
+		{Type: domain.EthernetSwitchOperationRemoveVLAN, PortName: "gi1/0/2", VLANID: 1},
+		{Type: domain.EthernetSwitchOperationSetPVID, PortName: "gi1/0/2", VLANID: 100},
+		{Type: domain.EthernetSwitchOperationDisablePOE, PortName: "gi1/0/1"},
+		{Type: domain.EthernetSwitchOperationEnablePOE, PortName: "gi1/0/2", POEType: "poe+"},
+	})
+	if err != nil {
+		t.Fatalf("change switch failed: %v", err)
+	}
+	discovery, err := env.service.Discover(ctx, switchID)
+	if err != nil {
+		t.Fatalf("discover failed: %v", err)
+	}
+	if len(discovery.Ports) != 24 || len(discovery.VLANs) != 1 {
+		t.Fatalf("unexpected discovered ports count %d and VLANs count %d", len(discovery.Ports), len(discovery.VLANs))
+	}
+	ports := map[string]dtos.EthernetSwitchPortDto{}
+	for _, port := range discovery.Ports {
+		ports[port.Name] = port
+	}
+	if ports["gi1/0/1"].ID != portsIDs[0] || ports["gi1/0/1"].POEEnabled || ports["gi1/0/1"].POEType != "poe" {
+		t.Errorf("unexpected existing port after discovery: %+v", ports["gi1/0/1"])
+	}
+	if ports["gi1/0/2"].PVID != 100 || !ports["gi1/0/2"].POEEnabled || ports["gi1/0/3"].PVID != 1 {
+		t.Errorf("unexpected discovered ports: %+v, %+v", ports["gi1/0/2"], ports["gi1/0/3"])
+	}
+	vlan := discovery.VLANs[0]
+	if vlan.VlanID != 100 || !reflect.DeepEqual(vlan.TaggedPorts, []uuid.UUID{portsIDs[0]}) ||
+		!reflect.DeepEqual(vlan.UntaggedPorts, []uuid.UUID{ports["gi1/0/2"].ID}) {
+		t.Errorf("unexpected discovered VLAN: %+v", vlan)
+	}
+	drift, err := env.service.GetDrift(ctx, switchID)
+	if err != nil || !drift.InSync {
+		t.Errorf("expect switch in sync after discovery, got %+v, error: %v", drift, err)
+	}
+
+	//repeated discovery updates records instead of creating new ones
+	err = manager.RemoveVLANFromPort("gi1/0/1", 100)
+	if err != nil {
+		t.Fatalf("remove VLAN from port failed: %v", err)
+	}
+	discovery, err = env.service.Discover(ctx, switchID)
+	if err != nil {
+		t.Fatalf("repeated discover failed: %v", err)
+	}
+	portsList, _ := env.service.GetPorts(ctx, switchID, "", "Name", "asc", 1, 100)
+	vlans, _ := env.service.GetVLANs(ctx, switchID, "", "VlanID", "asc", 1, 10)
+	if portsList.Pagination.TotalCount != 24 || len(vlans.Items) != 1 || vlans.Items[0].ID != vlan.ID {
+		t.Errorf("expect updated records, got %d ports and VLANs %+v", portsList.Pagination.TotalCount, vlans.Items)
+	}
+	if len(vlans.Items) == 1 && len(vlans.Items[0].TaggedPorts) != 0 {
+		t.Errorf("expect VLAN without tagged ports, got %+v", vlans.Items[0])
+	}
+}
+
+func Test_EthernetSwitchService_CreateWithDiscovery(t *testing.T) {
+	env := newSimulatedSwitchServiceEnv(t, &domain.AppConfig{}, "ethernetSwitchCreateDiscover_test.db")
+	defer env.close()
+	ctx := context.Background()
+	createDto := dtos.EthernetSwitchCreateDto{
+		EthernetSwitchBaseDto: dtos.EthernetSwitchBaseDto{
+			Name:        "SimulatedSwitch",
+			Serial:      "sim_serial",
+			SwitchModel: "sim-24p",
+			Address:     "127.0.0.1",
+			Username:    simTestLogin,
+		},
+		Password: simTestPassword,
+		Discover: true,
+	}
+	switchDto, err := env.service.Create(ctx, createDto)
+	if err != nil {
+		t.Fatalf("create switch with discovery failed: %v", err)
+	}
+	ports, _ := env.service.GetPorts(ctx, switchDto.ID, "", "Name", "asc", 1, 100)
+	if ports.Pagination.TotalCount != 24 {
+		t.Errorf("expect 24 discovered ports, got %d", ports.Pagination.TotalCount)
+	}
+
+	createDto.Name = "UnmanagedSwitch"
+	createDto.Serial = "unmanaged_serial"
+	createDto.SwitchModel = "unifi_switch_us-24-250w"
+	createDto.Address = "127.0.0.2"
+	_, err = env.service.Create(ctx, createDto)
+	if !errors.As(err, errors.Validation) {
+		t.Errorf("expect validation error for switch without manager, got %v", err)
+	}
+	switches, _ := env.service.GetList(ctx, "", "", "", 1, 10)
+	if switches.Pagination.TotalCount != 1 {
+		t.Errorf("expect switch without discovered ports to be deleted, got %d switches", switches.Pagination.TotalCount)
+	}
+	_, err = env.service.Discover(ctx, uuid.New())
+	if !errors.As(err, errors.NotFound) {
+		t.Errorf("expect not found error, got %v", err)
+	}
+}
@@ -0,0 +1,189 @@
+package tests
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"reflect"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/domain"
+	"rol/dtos"
+	"testing"
+)
+
+func Test_EthernetSwitchService_Drift(t *testing.T) {
+	env := newSimulatedSwitchServiceEnv(t, &domain.AppConfig{}, "ethernetSwitchDrift_test.db")
+	defer env.close()
+	ctx := context.Background()
+	switchID, portsIDs := env.createSwitchWithPorts(t, "gi1/0/1", "gi1/0/2", "gi1/0/3")
+	_, err := env.service.CreateVLAN(ctx, switchID, dtos.EthernetSwitchVLANCreateDto{
+		EthernetSwitchVLANBaseDto: dtos.EthernetSwitchVLANBaseDto{
+			TaggedPorts:   []uuid.UUID{portsIDs[0]},
+			UntaggedPorts: []uuid.UUID{portsIDs[1]},
+		},
+		VlanID: 100,
+	})
+	if err != nil {
+		t.Fatalf("create vlan failed: %v", err)
+	}
+	drift, err := env.service.GetDrift(ctx, switchID)
+	if err != nil || !drift.InSync {
+		t.Fatalf("expect switch in sync, got %+v, error: %v", drift, err)
+	}
+
+	manager, _ := env.managers.Get(ctx, switchID)
+	changeSwitchByHand(t, env, switchID, manager)
+	drift, err = env.service.GetDrift(ctx, switchID)
+	if err != nil || drift.InSync {
+		t.Fatalf("expect drift, got %+v, error: %v", drift, err)
+	}
+	expectedVLANs := []dtos.EthernetSwitchVLANDriftDto{{
+		VlanID:                100,
+		State:                 domain.EthernetSwitchDriftPortsMismatch,
+		DatabaseTaggedPorts:   []string{"gi1/0/1"},
+		DatabaseUntaggedPorts: []string{"gi1/0/2"},
+		SwitchTaggedPorts:     []string{},
+		SwitchUntaggedPorts:   []string{"gi1/0/2"},
+	}, {
+		VlanID:                200,
+		State:                 domain.EthernetSwitchDriftMissingInDatabase,
+		DatabaseTaggedPorts:   []string{},
+		DatabaseUntaggedPorts: []string{},
+		SwitchTaggedPorts:     []string{"gi1/0/3"},
+		SwitchUntaggedPorts:   []string{},
+	}, {
+		VlanID:                300,
+		State:                 domain.EthernetSwitchDriftMissingOnSwitch,
+		DatabaseTaggedPorts:   []string{"gi1/0/2"},
+		DatabaseUntaggedPorts: []string{},
+		SwitchTaggedPorts:     []string{},
+		SwitchUntaggedPorts:   []string{},
+	}}
+	if !reflect.DeepEqual(drift.VLANs, expectedVLANs) {
+		t.Errorf("unexpected VLANs drift:\n%+v\nexpect:\n%+v", drift.VLANs, expectedVLANs)
+	}
+	if len(drift.Ports) != 2 || drift.Ports[0].PortName != "gi1/0/2" || drift.Ports[0].State != domain.EthernetSwitchDriftPOEMismatch ||
+		drift.Ports[0].SwitchPOEEnabled || !drift.Ports[0].DatabasePOEEnabled {
+		t.Fatalf("unexpected ports drift: %+v", drift.Ports)
+	}
+	if drift.Ports[1].PortName != "gi1/0/2" || drift.Ports[1].State != domain.EthernetSwitchDriftPVIDMismatch ||
+		drift.Ports[1].SwitchPVID != 100 || drift.Ports[1].DatabasePVID != 1 {
+		t.Errorf("unexpected port PVID drift: %+v", drift.Ports[1])
+	}
+	reports := env.service.GetDriftReports()
+	if len(reports) != 1 || reports[0].SwitchID != switchID || reports[0].InSync {
+		t.Errorf("unexpected drift reports: %+v", reports)
+	}
+
+	drift, err = env.service.Reconcile(ctx, switchID, dtos.EthernetSwitchReconcileDto{Mode: domain.EthernetSwitchReconcilePush})
+	if err != nil || !drift.InSync {
+		t.Fatalf("expect switch in sync after push, got %+v, error: %v", drift, err)
+	}
+	if vlans, _ := manager.GetVLANs(); !reflect.DeepEqual(vlans, []int{1, 100, 300}) {
+		t.Errorf("unexpected switch vlans after push: %v", vlans)
+	}
+	if status, _ := manager.(interfaces.IEthernetSwitchPOEManager).GetPOEPortStatus("gi1/0/2"); status != "enable" {
+		t.Errorf("unexpected poe status after push: %s", status)
+	}
+	if pvid, _ := manager.GetPortPVID("gi1/0/2"); pvid != 1 {
+		t.Errorf("unexpected pvid %d after push, expect 1", pvid)
+	}
+}
+
+func Test_EthernetSwitchService_DriftImport(t *testing.T) {
+	env := newSimulatedSwitchServiceEnv(t, &domain.AppConfig{}, "ethernetSwitchDriftImport_test.db")
+	defer env.close()
+	ctx := context.Background()
+	switchID, portsIDs := env.createSwitchWithPorts(t, "gi1/0/1", "gi1/0/2", "gi1/0/3")
+	_, err := env.service.CreateVLAN(ctx, switchID, dtos.EthernetSwitchVLANCreateDto{
+		EthernetSwitchVLANBaseDto: dtos.EthernetSwitchVLANBaseDto{
+			TaggedPorts:   []uuid.UUID{portsIDs[0]},
+			UntaggedPorts: []uuid.UUID{portsIDs[1]},
+		},
+		VlanID: 100,
+	})
+	if err != nil {
+		t.Fatalf("create vlan failed: %v", err)
+	}
+	manager, _ := env.managers.Get(ctx, switchID)
+	changeSwitchByHand(t, env, switchID, manager)
+
+	drift, err := env.service.Reconcile(ctx, switchID, dtos.EthernetSwitchReconcileDto{Mode: domain.EthernetSwitchReconcileImport})
+	if err != nil || !drift.InSync {
+		t.Fatalf("expect switch in sync after import, got %+v, error: %v", drift, err)
+	}
+	vlans, err := env.service.GetVLANs(ctx, switchID, "", "VlanID", "asc", 1, 10)
+	if err != nil || len(vlans.Items) != 2 {
+		t.Fatalf("unexpected vlans after import: %+v, error: %v", vlans.Items, err)
+	}
+	if vlans.Items[0].VlanID != 100 || len(vlans.Items[0].TaggedPorts) != 0 ||
+		!reflect.DeepEqual(vlans.Items[0].UntaggedPorts, []uuid.UUID{portsIDs[1]}) {
+		t.Errorf("unexpected VLAN 100 after import: %+v", vlans.Items[0])
+	}
+	if vlans.Items[1].VlanID != 200 || !reflect.DeepEqual(vlans.Items[1].TaggedPorts, []uuid.UUID{portsIDs[2]}) {
+		t.Errorf("unexpected VLAN 200 after import: %+v", vlans.Items[1])
+	}
+	port, _ := env.service.GetPortByID(ctx, switchID, portsIDs[1])
+	if port.POEEnabled {
+		t.Error("expect imported disabled poe on port")
+	}
+	if port.PVID != 100 {
+		t.Errorf("unexpected imported pvid %d of the port, expect 100", port.PVID)
+	}
+}
+
+func Test_EthernetSwitchService_DriftErrors(t *testing.T) {
+	env := newSimulatedSwitchServiceEnv(t, &domain.AppConfig{}, "ethernetSwitchDriftErrors_test.db")
+	defer env.close()
+	ctx := context.Background()
+	switchID, _ := env.createSwitchWithPorts(t)
+	_, err := env.service.Reconcile(ctx, switchID, dtos.EthernetSwitchReconcileDto{Mode: "bad"})
+	if !errors.As(err, errors.Validation) {
+		t.Errorf("expect validation error for bad mode, got %v", err)
+	}
+	_, err = env.service.GetDrift(ctx, uuid.New())
+	if !errors.As(err, errors.NotFound) {
+		t.Errorf("expect not found error, got %v", err)
+	}
+	unmanaged, err := env.service.Create(ctx, dtos.EthernetSwitchCreateDto{
+		EthernetSwitchBaseDto: dtos.EthernetSwitchBaseDto{
+			Name:        "UnmanagedSwitch",
+			Serial:      "unmanaged_serial",
+			SwitchModel: "unifi_switch_us-24-250w",
+			Address:     "127.0.0.2",
+			Username:    simTestLogin,
+		},
+		Password: simTestPassword,
+	})
+	if err != nil {
+		t.Fatalf("create switch failed: %v", err)
+	}
+	_, err = env.service.GetDrift(ctx, unmanaged.ID)
+	if !errors.As(err, errors.Validation) {
+		t.Errorf("expect validation error for switch without manager, got %v", err)
+	}
+}
+
+//changeSwitchByHand changes switch bypassing the service: VLAN 100 loses tagged port gi1/0/1, VLAN 200 is added
+//with tagged port gi1/0/3, PoE is disabled and PVID is set to 100 on gi1/0/2 and VLAN 300 with tagged port gi1/0/2 is added only to the database
+func changeSwitchByHand(t *testing.T, env *simulatedSwitchServiceEnv, switchID uuid.UUID, manager interfaces.IEthernetSwitchManager) {
+	err := manager.ApplyOperations([]domain.EthernetSwitchOperation{
+		{Type: domain.EthernetSwitchOperationRemoveVLAN, PortName: "gi1/0/1", VLANID: 100},
+		{Type: domain.EthernetSwitchOperationCreateVLAN, VLANID: 200},
+		{Type: domain.EthernetSwitchOperationAddTaggedVLAN, PortName: "gi1/0/3", VLANID: 200},
+		{Type: domain.EthernetSwitchOperationDisablePOE, PortName: "gi1/0/2"},
+		{Type: domain.EthernetSwitchOperationSetPVID, PortName: "gi1/0/2", VLANID: 100},
+	})
+	if err != nil {
+		t.Fatalf("change switch failed: %v", err)
+	}
+	ports, _ := env.service.GetPorts(context.Background(), switchID, "", "Name", "asc", 1, 10)
+	_, err = env.vlanRepo.Insert(context.Background(), domain.EthernetSwitchVLAN{
+		VlanID:           300,
+		EthernetSwitchID: switchID,
+		TaggedPorts:      ports.Items[1].ID.String(),
+	})
+	if err != nil {
+		t.Fatalf("insert vlan failed: %v", err)
+	}
+}
@@ -0,0 +1,187 @@
+package tests
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"reflect"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/domain"
+	"rol/dtos"
+	"rol/infrastructure"
+	"testing"
+	"time"
+)
+
+func Test_EthernetSwitchService_PortNeighbors(t *testing.T) {
+	env := newSimulatedSwitchServiceEnv(t, &domain.AppConfig{}, "ethernetSwitchNeighbors_test.db")
+	defer env.close()
+	ctx := context.Background()
+	switchID, portsIDs := env.createSwitchWithPorts(t, "gi1/0/1", "gi1/0/2")
+	manager, _ := env.managers.Get(ctx, switchID)
+	simulator := manager.(*infrastructure.SimulatedEthernetSwitchManager).GetSimulator()
+	if err := simulator.CreateVLAN(10); err != nil {
+		t.Fatalf("create vlan failed: %v", err)
+	}
+	if err := simulator.AddVLANOnPort("gi1/0/1", 10, false); err != nil {
+		t.Fatalf("add vlan on port failed: %v", err)
+	}
+	learned := []domain.EthernetSwitchMACAddress{
+		{MAC: "00:11:22:33:44:55", VLANID: 1, PortName: "gi1/0/1"},
+		{MAC: "00:11:22:33:44:55", VLANID: 10, PortName: "gi1/0/1"},
+		{MAC: "00:11:22:33:44:56", VLANID: 1, PortName: "gi1/0/1"},
+		{MAC: "00:11:22:33:44:57", VLANID: 1, PortName: "gi1/0/1"},
+		{MAC: "00:11:22:33:44:58", VLANID: 1, PortName: "gi1/0/2"},
+	}
+	for _, entry := range learned {
+		if err := simulator.LearnMACAddress(entry.PortName, entry.MAC, entry.VLANID); err != nil {
+			t.Fatalf("learn mac address failed: %v", err)
+		}
+	}
+	neighbor := domain.EthernetSwitchLLDPNeighbor{PortName: "gi1/0/1", ChassisID: "00:11:22:33:44:55", SystemName: "node-1"}
+	if err := simulator.AddLLDPNeighbor(neighbor); err != nil {
+		t.Fatalf("add lldp neighbor failed: %v", err)
+	}
+
+	serverID := uuid.New()
+	leases := []domain.DHCP4Lease{
+		{IP: "10.0.0.5", MAC: "00:11:22:33:44:55", Expires: time.Now().Add(-time.Hour), DHCP4ConfigID: serverID},
+		{IP: "10.0.0.10", MAC: "00:11:22:33:44:55", Expires: time.Now().Add(time.Hour), DHCP4ConfigID: serverID},
+		{IP: "10.0.0.11", MAC: "00:11:22:33:44:56", Expires: time.Now().Add(time.Hour), DHCP4ConfigID: serverID},
+	}
+	for _, lease := range leases {
+		if _, err := env.leaseRepo.Insert(ctx, lease); err != nil {
+			t.Fatalf("insert lease failed: %v", err)
+		}
+	}
+	deviceID := uuid.New()
+	networkInterface, err := env.interfaceRepo.Insert(ctx, domain.DeviceNetworkInterface{
+		DeviceID:             deviceID,
+		Name:                 "eth0",
+		MAC:                  "00:11:22:33:44:55",
+		EthernetSwitchID:     switchID,
+		EthernetSwitchPortID: portsIDs[0],
+	})
+	if err != nil {
+		t.Fatalf("insert device network interface failed: %v", err)
+	}
+	otherInterface, err := env.interfaceRepo.Insert(ctx, domain.DeviceNetworkInterface{
+		DeviceID: deviceID,
+		Name:     "eth1",
+		MAC:      "00:11:22:33:44:56",
+	})
+	if err != nil {
+		t.Fatalf("insert device network interface failed: %v", err)
+	}
+
+	neighbors, err := env.service.GetPortNeighbors(ctx, switchID, portsIDs[0])
+	if err != nil {
+		t.Fatalf("get port neighbors failed: %v", err)
+	}
+	expectedMACs := []dtos.EthernetSwitchMACAddressDto{
+		{MAC: "00:11:22:33:44:55", VLANID: 1},
+		{MAC: "00:11:22:33:44:56", VLANID: 1},
+		{MAC: "00:11:22:33:44:57", VLANID: 1},
+		{MAC: "00:11:22:33:44:55", VLANID: 10},
+	}
+	if !reflect.DeepEqual(neighbors.MACAddresses, expectedMACs) {
+		t.Errorf("unexpected mac addresses: %+v, expect %+v", neighbors.MACAddresses, expectedMACs)
+	}
+	expectedLLDP := []dtos.EthernetSwitchLLDPNeighborDto{{ChassisID: "00:11:22:33:44:55", SystemName: "node-1"}}
+	if !reflect.DeepEqual(neighbors.LLDPNeighbors, expectedLLDP) {
+		t.Errorf("unexpected lldp neighbors: %+v, expect %+v", neighbors.LLDPNeighbors, expectedLLDP)
+	}
+	expectedSuggestions := []dtos.EthernetSwitchCablingSuggestionDto{{
+		MAC:                      "00:11:22:33:44:55",
+		IP:                       "10.0.0.10",
+		DHCP4ServerID:            serverID,
+		DeviceID:                 deviceID,
+		DeviceNetworkInterfaceID: networkInterface.ID,
+		Cabled:                   true,
+	}, {
+		MAC:                      "00:11:22:33:44:56",
+		IP:                       "10.0.0.11",
+		DHCP4ServerID:            serverID,
+		DeviceID:                 deviceID,
+		DeviceNetworkInterfaceID: otherInterface.ID,
+	}}
+	if !reflect.DeepEqual(neighbors.Suggestions, expectedSuggestions) {
+		t.Errorf("unexpected suggestions: %+v, expect %+v", neighbors.Suggestions, expectedSuggestions)
+	}
+
+	neighbors, err = env.service.GetPortNeighbors(ctx, switchID, portsIDs[1])
+	if err != nil || len(neighbors.MACAddresses) != 1 || len(neighbors.LLDPNeighbors) != 0 || len(neighbors.Suggestions) != 0 {
+		t.Errorf("unexpected port neighbors: %+v, error: %v", neighbors, err)
+	}
+}
+
+func Test_EthernetSwitchService_PortNeighborsErrors(t *testing.T) {
+	env := newSimulatedSwitchServiceEnv(t, &domain.AppConfig{}, "ethernetSwitchNeighborsErrors_test.db")
+	defer env.close()
+	ctx := context.Background()
+	switchID, _ := env.createSwitchWithPorts(t, "gi1/0/1")
+	_, err := env.service.GetPortNeighbors(ctx, switchID, uuid.New())
+	if !errors.As(err, errors.NotFound) {
+		t.Errorf("expect not found error for nonexistent port, got %v", err)
+	}
+	unmanaged, err := env.service.Create(ctx, dtos.EthernetSwitchCreateDto{
+		EthernetSwitchBaseDto: dtos.EthernetSwitchBaseDto{
+			Name:        "UnmanagedSwitch",
+			Serial:      "unmanaged_serial",
+			SwitchModel: "unifi_switch_us-24-250w",
+			Address:     "127.0.0.2",
+			Username:    simTestLogin,
+		},
+		Password: simTestPassword,
+	})
+	if err != nil {
+		t.Fatalf("create switch failed: %v", err)
+	}
+	port, err := env.service.CreatePort(ctx, unmanaged.ID, dtos.EthernetSwitchPortCreateDto{
+		EthernetSwitchPortBaseDto: dtos.EthernetSwitchPortBaseDto{Name: "gi1/0/1", POEType: "poe", PVID: 1},
+	})
+	if err != nil {
+		t.Fatalf("create port failed: %v", err)
+	}
+	_, err = env.service.GetPortNeighbors(ctx, unmanaged.ID, port.ID)
+	if !errors.As(err, errors.Validation) {
+		t.Errorf("expect validation error for the switch without manager, got %v", err)
+	}
+	err = env.drivers.Register(infrastructure.EthernetSwitchDriver{
+		Models: []domain.EthernetSwitchModel{{
+			Model:        "Simulated switch without LLDP",
+			Manufacturer: "ROL",
+			Code:         "sim-no-lldp",
+			Capabilities: []string{domain.EthernetSwitchCapabilityVLAN, domain.EthernetSwitchCapabilityPOE},
+		}},
+		NewManager: func(domain.EthernetSwitch) (interfaces.IEthernetSwitchManager, error) {
+			return infrastructure.NewSimulatedEthernetSwitchManager(infrastructure.NewSimulatedEthernetSwitch(8, 8)), nil
+		},
+	})
+	if err != nil {
+		t.Fatalf("register driver failed: %v", err)
+	}
+	withoutLLDP, err := env.service.Create(ctx, dtos.EthernetSwitchCreateDto{
+		EthernetSwitchBaseDto: dtos.EthernetSwitchBaseDto{
+			Name:        "SwitchWithoutLLDP",
+			Serial:      "without_lldp_serial",
+			SwitchModel: "sim-no-lldp",
+			Address:     "127.0.0.3",
+			Username:    simTestLogin,
+		},
+		Password: simTestPassword,
+	})
+	if err != nil {
+		t.Fatalf("create switch failed: %v", err)
+	}
+	port, err = env.service.CreatePort(ctx, withoutLLDP.ID, dtos.EthernetSwitchPortCreateDto{
+		EthernetSwitchPortBaseDto: dtos.EthernetSwitchPortBaseDto{Name: "gi1/0/1", POEType: "poe", PVID: 1},
+	})
+	if err != nil {
+		t.Fatalf("create port failed: %v", err)
+	}
+	_, err = env.service.GetPortNeighbors(ctx, withoutLLDP.ID, port.ID)
+	if !errors.As(err, errors.Validation) {
+		t.Errorf("expect validation error for the switch model without LLDP support, got %v", err)
+	}
+}
@@ -44,6 +44,9 @@ func Test_EthernetSwitchServiceVLAN_Prepare(t *testing.T) {
 		new(domain.EthernetSwitch),
 		new(domain.EthernetSwitchPort),
 		new(domain.EthernetSwitchVLAN),
+		new(domain.EthernetSwitchConfigBackup),
+		new(domain.DHCP4Lease),
+		new(domain.DeviceNetworkInterface),
 	)
 	if err != nil {
 		t.Errorf("migration failed: %v", err)
@@ -53,17 +56,20 @@ func Test_EthernetSwitchServiceVLAN_Prepare(t *testing.T) {
 	switchRepo := infrastructure.NewGormEthernetSwitchRepository(testGenDb, logger)
 	portRepo := infrastructure.NewGormEthernetSwitchPortRepository(testGenDb, logger)
 	vlanRepo := infrastructure.NewGormEthernetSwitchVLANRepository(testGenDb, logger)
+	backupRepo := infrastructure.NewGormEthernetSwitchConfigBackupRepository(testGenDb, logger)
+	leaseRepo := infrastructure.NewGormDHCP4LeaseRepository(testGenDb, logger)
+	interfaceRepo := infrastructure.NewGormDeviceNetworkInterfaceRepository(testGenDb, logger)
 	ethSwitchServiceTester.switchRepo = switchRepo
 	ethSwitchServiceTester.portRepo = portRepo
 	ethSwitchServiceTester.vlanRepo = vlanRepo
 
-	getter := infrastructure.NewEthernetSwitchManagerProvider(switchRepo)
-	service, _ := services.NewEthernetSwitchService(switchRepo, portRepo, vlanRepo, getter)
-	ethSwitchServiceTester.service = service
-	err = services.EthernetSwitchServiceInit(ethSwitchServiceTester.service)
+	drivers, err := infrastructure.NewEthernetSwitchDriverRegistry(&domain.AppConfig{})
 	if err != nil {
-		t.Errorf("init service failed:  %q", err)
+		t.Errorf("create switch drivers registry failed:  %q", err)
 	}
+	getter := infrastructure.NewEthernetSwitchManagerProvider(switchRepo, drivers)
+	service, _ := services.NewEthernetSwitchService(switchRepo, portRepo, vlanRepo, backupRepo, leaseRepo, interfaceRepo, getter, drivers)
+	ethSwitchServiceTester.service = service
 
 	_, filename, _, _ := runtime.Caller(1)
 	if _, err := os.Stat(path.Join(path.Dir(filename), ethSwitchServiceTester.dbPath)); errors.Is(err, os.ErrNotExist) {
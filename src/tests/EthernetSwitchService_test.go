@@ -9,6 +9,7 @@ import (
 	"gorm.io/gorm"
 	"os"
 	"path"
+	"reflect"
 	"rol/app/errors"
 	"rol/app/interfaces"
 	"rol/app/services"
@@ -47,6 +48,9 @@ func Test_EthernetSwitchService_Prepare(t *testing.T) {
 		new(domain.EthernetSwitch),
 		new(domain.EthernetSwitchPort),
 		new(domain.EthernetSwitchVLAN),
+		new(domain.EthernetSwitchConfigBackup),
+		new(domain.DHCP4Lease),
+		new(domain.DeviceNetworkInterface),
 	)
 	if err != nil {
 		t.Errorf("migration failed: %v", err)
@@ -56,14 +60,18 @@ func Test_EthernetSwitchService_Prepare(t *testing.T) {
 	ethSwitchRepo = infrastructure.NewGormGenericRepository[uuid.UUID, domain.EthernetSwitch](testGenDb, logger)
 	ethSwitchPortRepo = infrastructure.NewGormGenericRepository[uuid.UUID, domain.EthernetSwitchPort](testGenDb, logger)
 	ethSwitchVlanRepo = infrastructure.NewGormGenericRepository[uuid.UUID, domain.EthernetSwitchVLAN](testGenDb, logger)
-	getter := infrastructure.NewEthernetSwitchManagerProvider(ethSwitchRepo)
-	ethSwitchService, err = services.NewEthernetSwitchService(ethSwitchRepo, ethSwitchPortRepo, ethSwitchVlanRepo, getter)
+	ethSwitchBackupRepo := infrastructure.NewGormGenericRepository[uuid.UUID, domain.EthernetSwitchConfigBackup](testGenDb, logger)
+	leaseRepo := infrastructure.NewGormGenericRepository[uuid.UUID, domain.DHCP4Lease](testGenDb, logger)
+	interfaceRepo := infrastructure.NewGormGenericRepository[uuid.UUID, domain.DeviceNetworkInterface](testGenDb, logger)
+	drivers, err := infrastructure.NewEthernetSwitchDriverRegistry(&domain.AppConfig{})
 	if err != nil {
-		t.Errorf("create new service failed:  %q", err)
+		t.Errorf("create switch drivers registry failed:  %q", err)
 	}
-	err = services.EthernetSwitchServiceInit(ethSwitchService)
+	getter := infrastructure.NewEthernetSwitchManagerProvider(ethSwitchRepo, drivers)
+	ethSwitchService, err = services.NewEthernetSwitchService(ethSwitchRepo, ethSwitchPortRepo, ethSwitchVlanRepo, ethSwitchBackupRepo, leaseRepo,
+		interfaceRepo, getter, drivers)
 	if err != nil {
-		t.Errorf("init service failed:  %q", err)
+		t.Errorf("create new service failed:  %q", err)
 	}
 }
 
@@ -100,6 +108,83 @@ func Test_EthernetSwitchService_CreateFailByWrongModel(t *testing.T) {
 	}
 }
 
+func Test_EthernetSwitchService_CreateFailBySNMPCredentials(t *testing.T) {
+	createDto := dtos.EthernetSwitchCreateDto{
+		EthernetSwitchBaseDto: dtos.EthernetSwitchBaseDto{
+			Name:        "AutoTesting",
+			Serial:      "test_snmp_serial",
+			SwitchModel: "snmp_generic",
+			Address:     "123.123.123.124",
+			Username:    "AutoUser",
+		},
+		//  pragma: allowlist nextline secret
+		Password: "AutoPass",
+	}
+	ctx := context.TODO()
+	_, err := ethSwitchService.Create(ctx, createDto)
+	if err == nil || !errors.As(err, errors.Validation) {
+		t.Error("expect validation error")
+		return
+	}
+	if _, ok := errors.GetErrorContext(err)["SNMPVersion"]; !ok {
+		t.Error("expect snmp version validation error")
+	}
+	createDto.SNMPVersion = "2c"
+	_, err = ethSwitchService.Create(ctx, createDto)
+	if err == nil || !errors.As(err, errors.Validation) {
+		t.Error("expect validation error")
+		return
+	}
+	if _, ok := errors.GetErrorContext(err)["SNMPCommunity"]; !ok {
+		t.Error("expect snmp community validation error")
+	}
+	createDto.SNMPVersion = "3"
+	createDto.SNMPUsername = "rol"
+	createDto.SNMPPrivProtocol = "aes"
+	_, err = ethSwitchService.Create(ctx, createDto)
+	if err == nil || !errors.As(err, errors.Validation) {
+		t.Error("expect validation error")
+		return
+	}
+	if _, ok := errors.GetErrorContext(err)["SNMPAuthProtocol"]; !ok {
+		t.Error("expect snmp auth protocol validation error")
+	}
+}
+
+func Test_EthernetSwitchService_CreateFailBySSHHostKey(t *testing.T) {
+	createDto := dtos.EthernetSwitchCreateDto{
+		EthernetSwitchBaseDto: dtos.EthernetSwitchBaseDto{
+			Name:        "AutoTesting",
+			Serial:      "test_ssh_serial",
+			SwitchModel: "tl-sg2210mp",
+			Address:     "123.123.123.124",
+			Username:    "AutoUser",
+			Transport:   "ssh",
+		},
+		//  pragma: allowlist nextline secret
+		Password: "AutoPass",
+	}
+	ctx := context.TODO()
+	_, err := ethSwitchService.Create(ctx, createDto)
+	if err == nil || !errors.As(err, errors.Validation) {
+		t.Error("expect validation error")
+		return
+	}
+	if _, ok := errors.GetErrorContext(err)["SSHHostKeyFingerprint"]; !ok {
+		t.Error("expect ssh host key fingerprint validation error")
+	}
+	createDto.SSHHostKeyFingerprint = "MD5:16:27:ac:a5:76:28:2d:36:63:1b:56:4d:eb:df:a6:48"
+	_, err = ethSwitchService.Create(ctx, createDto)
+	if _, ok := errors.GetErrorContext(err)["SSHHostKeyFingerprint"]; !ok {
+		t.Error("expect ssh host key fingerprint format validation error")
+	}
+	createDto.Transport = "rsh"
+	_, err = ethSwitchService.Create(ctx, createDto)
+	if _, ok := errors.GetErrorContext(err)["Transport"]; !ok {
+		t.Error("expect transport validation error")
+	}
+}
+
 func Test_EthernetSwitchService_CreateOK(t *testing.T) {
 	createDto := dtos.EthernetSwitchCreateDto{
 		EthernetSwitchBaseDto: dtos.EthernetSwitchBaseDto{
@@ -421,6 +506,84 @@ func Test_EthernetSwitchService_Delete(t *testing.T) {
 	}
 }
 
+func Test_EthernetSwitchService_PortCapabilitiesCheck(t *testing.T) {
+	agent, err := newSNMPTestAgent()
+	if err != nil {
+		t.Errorf("start snmp agent failed: %v", err)
+		return
+	}
+	defer agent.close()
+	ctx := context.TODO()
+	switchDto, err := ethSwitchService.Create(ctx, dtos.EthernetSwitchCreateDto{
+		EthernetSwitchBaseDto: dtos.EthernetSwitchBaseDto{
+			Name:        "AutoTestingSNMP",
+			Serial:      "test_capabilities_serial",
+			SwitchModel: "snmp_generic",
+			Address:     "127.0.0.1",
+			Username:    "AutoUser",
+			SNMPVersion: "2c",
+			SNMPPort:    agent.port(),
+		},
+		//  pragma: allowlist nextline secret
+		Password:      "AutoPass",
+		SNMPCommunity: snmpTestCommunity,
+	})
+	if err != nil {
+		t.Errorf("create switch failed: %v", err)
+		return
+	}
+	defer func() {
+		if err := ethSwitchService.Delete(ctx, switchDto.ID); err != nil {
+			t.Errorf("delete switch failed: %v", err)
+		}
+	}()
+	//capabilities are not read from the switch by the switch getters
+	switchDto, err = ethSwitchService.GetByID(ctx, switchDto.ID)
+	if err != nil || switchDto.Capabilities != nil {
+		t.Errorf("unexpected switch capabilities before the switch request: %+v, error: %v", switchDto.Capabilities, err)
+	}
+	capabilities, err := ethSwitchService.GetCapabilities(ctx, switchDto.ID)
+	if err != nil || len(capabilities.Ports) != 5 {
+		t.Errorf("expect switch capabilities, got %+v, error: %v", capabilities, err)
+	}
+	switchDto, err = ethSwitchService.GetByID(ctx, switchDto.ID)
+	if err != nil || switchDto.Capabilities == nil || !reflect.DeepEqual(*switchDto.Capabilities, capabilities) {
+		t.Errorf("expect cached switch capabilities, got %+v, error: %v", switchDto.Capabilities, err)
+	}
+	switchesList, err := ethSwitchService.GetList(ctx, "test_capabilities_serial", "", "", 1, 10)
+	if err != nil || len(switchesList.Items) != 1 || !reflect.DeepEqual(switchesList.Items[0].Capabilities, switchDto.Capabilities) {
+		t.Errorf("expect cached switch capabilities in the list, got %+v, error: %v", switchesList.Items, err)
+	}
+	cases := []struct {
+		port     dtos.EthernetSwitchPortBaseDto
+		errorKey string
+	}{
+		{dtos.EthernetSwitchPortBaseDto{Name: "gi9", POEType: "none"}, "Name"},
+		{dtos.EthernetSwitchPortBaseDto{Name: "gi5", POEType: "poe", POEEnabled: true}, "POEEnabled"},
+		{dtos.EthernetSwitchPortBaseDto{Name: "gi2", POEType: "passive24", POEEnabled: true}, "POEType"},
+	}
+	for _, testCase := range cases {
+		_, err = ethSwitchService.CreatePort(ctx, switchDto.ID, dtos.EthernetSwitchPortCreateDto{EthernetSwitchPortBaseDto: testCase.port})
+		if !errors.As(err, errors.Validation) {
+			t.Errorf("port %s: expect validation error, got %v", testCase.port.Name, err)
+			continue
+		}
+		if _, ok := errors.GetErrorContext(err)[testCase.errorKey]; !ok {
+			t.Errorf("port %s: expect %s validation error, got %v", testCase.port.Name, testCase.errorKey, errors.GetErrorContext(err))
+		}
+	}
+	_, err = ethSwitchService.CreatePort(ctx, switchDto.ID, dtos.EthernetSwitchPortCreateDto{
+		EthernetSwitchPortBaseDto: dtos.EthernetSwitchPortBaseDto{Name: "gi2", POEType: "poe+", POEEnabled: true, PVID: 1},
+	})
+	if err != nil {
+		t.Errorf("create port failed: %v", err)
+		return
+	}
+	if value := agent.getValue(snmpTestPOEAdminEnableOID + ".2"); value != 1 {
+		t.Errorf("expect poe is enabled on the switch port, got %v", value)
+	}
+}
+
 func Test_EthernetSwitchService_CloseConnectionAndRemoveDb(t *testing.T) {
 	if err := ethSwitchRepo.Dispose(); err != nil {
 		t.Errorf("close db failed:  %s", err)
@@ -0,0 +1,157 @@
+package tests
+
+import (
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"rol/app/errors"
+	"rol/app/services"
+	"rol/domain"
+	"rol/dtos"
+	"rol/infrastructure"
+	"testing"
+)
+
+const httpBootTestAddress = "127.0.0.1:18180"
+
+func httpBootTestRequest(method, url, rangeHeader string) (int, string, error) {
+	request, err := http.NewRequest(method, url, nil)
+	if err != nil {
+		return 0, "", err
+	}
+	if rangeHeader != "" {
+		request.Header.Set("Range", rangeHeader)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		return 0, "", err
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	return response.StatusCode, string(body), err
+}
+
+func Test_HTTPBootServerService(t *testing.T) {
+	rootDir, err := filepath.Abs("httpBootFiles")
+	if err != nil {
+		t.Errorf("failed to get root dir path: %v", err)
+		return
+	}
+	_ = os.RemoveAll(rootDir)
+	defer os.RemoveAll(rootDir)
+	_ = os.MkdirAll(filepath.Join(rootDir, "eve"), os.ModePerm)
+	if err = os.WriteFile(filepath.Join(rootDir, "eve", "kernel"), []byte("0123456789"), 0600); err != nil {
+		t.Errorf("failed to create test file: %v", err)
+		return
+	}
+
+	dbPath := "httpBootServerService_test.db"
+	_ = os.Remove(dbPath)
+	testGenDb, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		t.Errorf("creating db failed: %v", err)
+		return
+	}
+	err = testGenDb.AutoMigrate(new(domain.HTTPBootConfig), new(domain.HTTPBootPathRatio))
+	if err != nil {
+		t.Errorf("migration failed: %v", err)
+		return
+	}
+	logger := logrus.New()
+	configsRepo := infrastructure.NewGormHTTPBootConfigRepository(testGenDb, logger)
+	pathsRepo := infrastructure.NewGormHTTPBootPathRatioRepository(testGenDb, logger)
+	defer func() {
+		_ = configsRepo.Dispose()
+		_ = os.Remove(dbPath)
+	}()
+	factory, _ := infrastructure.NewNetHTTPBootServerFactory()
+	service := services.NewHTTPBootServerService(configsRepo, pathsRepo, factory, logger)
+	ctx := context.Background()
+
+	_, err = service.CreateServer(ctx, dtos.HTTPBootServerCreateDto{HTTPBootServerBaseDto: dtos.HTTPBootServerBaseDto{
+		Address: "127.0.0.1",
+		Port:    "0",
+		Enabled: true,
+	}})
+	if !errors.As(err, errors.Validation) {
+		t.Errorf("expected validation error for the wrong port, got: %v", err)
+	}
+	server, err := service.CreateServer(ctx, dtos.HTTPBootServerCreateDto{HTTPBootServerBaseDto: dtos.HTTPBootServerBaseDto{
+		Address: "127.0.0.1",
+		Port:    "18180",
+		Enabled: true,
+	}})
+	if err != nil {
+		t.Errorf("create server failed: %v", err)
+		return
+	}
+	defer func() {
+		_ = service.DeleteServer(ctx, server.ID)
+	}()
+	if server.State != domain.HTTPBootStateLaunched.String() {
+		t.Errorf("unexpected server state: %s", server.State)
+	}
+	_, err = service.CreatePath(ctx, server.ID, dtos.HTTPBootPathCreateDto{HTTPBootPathBaseDto: dtos.HTTPBootPathBaseDto{
+		ActualPath:  filepath.Join(rootDir, "eve"),
+		VirtualPath: "/boot/eve",
+		IsDirectory: true,
+	}})
+	if err != nil {
+		t.Errorf("create path failed: %v", err)
+		return
+	}
+	_, err = service.CreatePath(ctx, uuid.New(), dtos.HTTPBootPathCreateDto{HTTPBootPathBaseDto: dtos.HTTPBootPathBaseDto{
+		ActualPath:  rootDir,
+		VirtualPath: "/",
+		IsDirectory: true,
+	}})
+	if !errors.As(err, errors.NotFound) {
+		t.Errorf("expected not found error for the absent server, got: %v", err)
+	}
+
+	url := fmt.Sprintf("http://%s/boot/eve/kernel", httpBootTestAddress)
+	status, body, err := httpBootTestRequest(http.MethodGet, url, "")
+	if err != nil || status != http.StatusOK || body != "0123456789" {
+		t.Errorf("unexpected full response: %d %q, err: %v", status, body, err)
+	}
+	status, body, err = httpBootTestRequest(http.MethodGet, url, "bytes=2-5")
+	if err != nil || status != http.StatusPartialContent || body != "2345" {
+		t.Errorf("unexpected range response: %d %q, err: %v", status, body, err)
+	}
+	status, _, _ = httpBootTestRequest(http.MethodGet, fmt.Sprintf("http://%s/boot/eve/../../httpBootServerService_test.db", httpBootTestAddress), "")
+	if status != http.StatusNotFound {
+		t.Errorf("unexpected status for the file outside of the paths: %d", status)
+	}
+	status, _, _ = httpBootTestRequest(http.MethodPost, url, "")
+	if status != http.StatusMethodNotAllowed {
+		t.Errorf("unexpected status for the post request: %d", status)
+	}
+
+	stats, err := service.GetFileStats(ctx, server.ID)
+	if err != nil || len(stats) != 1 {
+		t.Errorf("unexpected stats: %v, err: %v", stats, err)
+		return
+	}
+	if stats[0].VirtualPath != "boot/eve/kernel" || stats[0].Requests != 2 || stats[0].Downloads != 1 || stats[0].BytesSent != 14 {
+		t.Errorf("unexpected file stats: %+v", stats[0])
+	}
+
+	updated, err := service.UpdateServer(ctx, server.ID, dtos.HTTPBootServerUpdateDto{HTTPBootServerBaseDto: dtos.HTTPBootServerBaseDto{
+		Address: "127.0.0.1",
+		Port:    "18180",
+		Enabled: false,
+	}})
+	if err != nil || updated.State != domain.HTTPBootStateStopped.String() {
+		t.Errorf("disable server failed, state: %s, err: %v", updated.State, err)
+	}
+	if _, _, err = httpBootTestRequest(http.MethodGet, url, ""); err == nil {
+		t.Error("disabled server is still serving files")
+	}
+}
@@ -0,0 +1,291 @@
+package tests
+
+import (
+	"bytes"
+	"context"
+	"github.com/google/uuid"
+	"github.com/pin/tftp/v3"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"os"
+	"path/filepath"
+	"rol/domain"
+	"rol/infrastructure"
+	"testing"
+	"time"
+)
+
+func receiveTFTPFile(client *tftp.Client, filename string) (string, error) {
+	wt, err := client.Receive(filename, "octet")
+	if err != nil {
+		return "", err
+	}
+	buffer := &bytes.Buffer{}
+	_, err = wt.WriteTo(buffer)
+	return buffer.String(), err
+}
+
+func Test_PinTFTPServer_DirectoryPaths(t *testing.T) {
+	rootDir, err := filepath.Abs("tftpPaths")
+	if err != nil {
+		t.Errorf("failed to get root dir path: %v", err)
+		return
+	}
+	_ = os.RemoveAll(rootDir)
+	defer os.RemoveAll(rootDir)
+	files := map[string]string{
+		"bios/pxelinux.0":        "bios",
+		"bios/pxelinux.cfg/menu": "bios menu",
+		"efi/grubx64.efi":        "efi",
+		"single/file.txt":        "single",
+		"secret.txt":             "secret",
+	}
+	for name, content := range files {
+		filePath := filepath.Join(rootDir, filepath.FromSlash(name))
+		_ = os.MkdirAll(filepath.Dir(filePath), os.ModePerm)
+		if err = os.WriteFile(filePath, []byte(content), 0600); err != nil {
+			t.Errorf("failed to create test file: %v", err)
+			return
+		}
+	}
+
+	server, err := infrastructure.NewPinTFTPServer(domain.TFTPConfig{
+		Address: "127.0.0.1",
+		Port:    "16970",
+		Enabled: true,
+	}, nil, nil)
+	if err != nil {
+		t.Errorf("create tftp server failed: %v", err)
+		return
+	}
+	_ = server.ReloadPaths([]domain.TFTPPathRatio{{
+		VirtualPath: "/boot",
+		ActualPath:  filepath.Join(rootDir, "bios"),
+		IsDirectory: true,
+	}, {
+		VirtualPath: "boot/efi/",
+		ActualPath:  filepath.Join(rootDir, "efi"),
+		IsDirectory: true,
+	}, {
+		VirtualPath: "boot/efi/grub.cfg",
+		ActualPath:  filepath.Join(rootDir, "single", "file.txt"),
+	}})
+	_ = server.Start()
+	defer server.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client, err := tftp.NewClient("127.0.0.1:16970")
+	if err != nil {
+		t.Errorf("create tftp client failed: %v", err)
+		return
+	}
+
+	expected := map[string]string{
+		"boot/pxelinux.0":         "bios",
+		"/boot/pxelinux.cfg/menu": "bios menu",
+		"boot/efi/grubx64.efi":    "efi",
+		"boot/efi/grub.cfg":       "single",
+	}
+	for filename, content := range expected {
+		received, err := receiveTFTPFile(client, filename)
+		if err != nil || received != content {
+			t.Errorf("unexpected content for %s: %q, expect %q, err: %v", filename, received, content, err)
+		}
+	}
+	for _, filename := range []string{"boot/../secret.txt", "boot/../../secret.txt", "boot/efi", "other/file.txt"} {
+		if _, err = receiveTFTPFile(client, filename); err == nil {
+			t.Errorf("file %s was received, expect error", filename)
+		}
+	}
+}
+
+func Test_PinTFTPServer_ClientPaths(t *testing.T) {
+	rootDir, err := filepath.Abs("tftpClientPaths")
+	if err != nil {
+		t.Errorf("failed to get root dir path: %v", err)
+		return
+	}
+	_ = os.RemoveAll(rootDir)
+	defer os.RemoveAll(rootDir)
+	_ = os.MkdirAll(rootDir, os.ModePerm)
+	for _, name := range []string{"default", "by-mac", "by-ip", "other-ip"} {
+		if err = os.WriteFile(filepath.Join(rootDir, name), []byte(name), 0600); err != nil {
+			t.Errorf("failed to create test file: %v", err)
+			return
+		}
+	}
+
+	dbPath := "pinTFTPServerClientPaths_test.db"
+	_ = os.Remove(dbPath)
+	testGenDb, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		t.Errorf("creating db failed: %v", err)
+		return
+	}
+	err = testGenDb.AutoMigrate(new(domain.DHCP4Lease), new(domain.DHCP4Reservation))
+	if err != nil {
+		t.Errorf("migration failed: %v", err)
+		return
+	}
+	leasesRepo := infrastructure.NewGormDHCP4LeaseRepository(testGenDb, logrus.New())
+	reservationsRepo := infrastructure.NewGormDHCP4ReservationRepository(testGenDb, logrus.New())
+	defer func() {
+		_ = leasesRepo.Dispose()
+		_ = os.Remove(dbPath)
+	}()
+	_, err = leasesRepo.Insert(context.TODO(), domain.DHCP4Lease{
+		IP:            "127.0.0.1",
+		MAC:           "00:11:22:33:44:55",
+		Expires:       time.Now().Add(time.Hour),
+		DHCP4ConfigID: uuid.New(),
+	})
+	if err != nil {
+		t.Errorf("insert lease failed: %v", err)
+		return
+	}
+
+	server, err := infrastructure.NewPinTFTPServer(domain.TFTPConfig{
+		Address: "127.0.0.1",
+		Port:    "16971",
+		Enabled: true,
+	}, infrastructure.NewTFTPClientResolver(leasesRepo, reservationsRepo, nil, nil, nil), nil)
+	if err != nil {
+		t.Errorf("create tftp server failed: %v", err)
+		return
+	}
+	_ = server.ReloadPaths([]domain.TFTPPathRatio{
+		{VirtualPath: "kernel", ActualPath: filepath.Join(rootDir, "default")},
+		{VirtualPath: "kernel", ActualPath: filepath.Join(rootDir, "by-mac"), ClientMAC: "00:11:22:33:44:55"},
+		{VirtualPath: "kernel", ActualPath: filepath.Join(rootDir, "other-ip"), ClientIP: "127.0.0.2"},
+		{VirtualPath: "cmdline", ActualPath: filepath.Join(rootDir, "default")},
+		{VirtualPath: "cmdline", ActualPath: filepath.Join(rootDir, "by-ip"), ClientIP: "127.0.0.1"},
+		{VirtualPath: "cmdline", ActualPath: filepath.Join(rootDir, "by-mac"), ClientMAC: "00:11:22:33:44:55"},
+		{VirtualPath: "dtb", ActualPath: filepath.Join(rootDir, "other-ip"), ClientIP: "127.0.0.2"},
+	})
+	_ = server.Start()
+	defer server.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client, err := tftp.NewClient("127.0.0.1:16971")
+	if err != nil {
+		t.Errorf("create tftp client failed: %v", err)
+		return
+	}
+	expected := map[string]string{
+		"kernel":  "by-mac",
+		"cmdline": "by-ip",
+	}
+	for filename, content := range expected {
+		received, err := receiveTFTPFile(client, filename)
+		if err != nil || received != content {
+			t.Errorf("unexpected content for %s: %q, expect %q, err: %v", filename, received, content, err)
+		}
+	}
+	if _, err = receiveTFTPFile(client, "dtb"); err == nil {
+		t.Error("file scoped to another client was received")
+	}
+}
+
+func Test_PinTFTPServer_TemplatePaths(t *testing.T) {
+	rootDir, err := filepath.Abs("tftpTemplatePaths")
+	if err != nil {
+		t.Errorf("failed to get root dir path: %v", err)
+		return
+	}
+	_ = os.RemoveAll(rootDir)
+	defer os.RemoveAll(rootDir)
+	_ = os.MkdirAll(filepath.Join(rootDir, "pxelinux.cfg"), os.ModePerm)
+	templateContent := `{{ .Device.Name }} {{ .NetworkInterface.Name }} {{ replace .ClientMAC ":" "-" }} {{ .Lease.IP }}` +
+		`{{ if .DeviceTemplate }} template{{ end }}`
+	if err = os.WriteFile(filepath.Join(rootDir, "pxelinux.cfg", "default"), []byte(templateContent), 0600); err != nil {
+		t.Errorf("failed to create test file: %v", err)
+		return
+	}
+	if err = os.WriteFile(filepath.Join(rootDir, "broken"), []byte("{{ .Unknown }}"), 0600); err != nil {
+		t.Errorf("failed to create test file: %v", err)
+		return
+	}
+
+	dbPath := "pinTFTPServerTemplatePaths_test.db"
+	_ = os.Remove(dbPath)
+	testGenDb, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		t.Errorf("creating db failed: %v", err)
+		return
+	}
+	err = testGenDb.AutoMigrate(new(domain.DHCP4Lease), new(domain.DHCP4Reservation), new(domain.Device), new(domain.DeviceNetworkInterface))
+	if err != nil {
+		t.Errorf("migration failed: %v", err)
+		return
+	}
+	leasesRepo := infrastructure.NewGormDHCP4LeaseRepository(testGenDb, logrus.New())
+	reservationsRepo := infrastructure.NewGormDHCP4ReservationRepository(testGenDb, logrus.New())
+	devicesRepo := infrastructure.NewGormDeviceRepository(testGenDb, logrus.New())
+	interfacesRepo := infrastructure.NewGormDeviceNetworkInterfaceRepository(testGenDb, logrus.New())
+	defer func() {
+		_ = leasesRepo.Dispose()
+		_ = os.Remove(dbPath)
+	}()
+	_, err = leasesRepo.Insert(context.TODO(), domain.DHCP4Lease{
+		IP:            "127.0.0.1",
+		MAC:           "00:11:22:33:44:aa",
+		Expires:       time.Now().Add(time.Hour),
+		DHCP4ConfigID: uuid.New(),
+	})
+	if err != nil {
+		t.Errorf("insert lease failed: %v", err)
+		return
+	}
+	device, err := devicesRepo.Insert(context.TODO(), domain.Device{Name: "board-01"})
+	if err != nil {
+		t.Errorf("insert device failed: %v", err)
+		return
+	}
+	_, err = interfacesRepo.Insert(context.TODO(), domain.DeviceNetworkInterface{
+		DeviceID: device.ID,
+		Name:     "eth0",
+		MAC:      "00:11:22:33:44:aa",
+	})
+	if err != nil {
+		t.Errorf("insert device network interface failed: %v", err)
+		return
+	}
+
+	server, err := infrastructure.NewPinTFTPServer(domain.TFTPConfig{
+		Address: "127.0.0.1",
+		Port:    "16972",
+		Enabled: true,
+	}, infrastructure.NewTFTPClientResolver(leasesRepo, reservationsRepo, devicesRepo, interfacesRepo, nil), nil)
+	if err != nil {
+		t.Errorf("create tftp server failed: %v", err)
+		return
+	}
+	_ = server.ReloadPaths([]domain.TFTPPathRatio{
+		{VirtualPath: "pxelinux.cfg/01-00-11-22-33-44-aa", ActualPath: filepath.Join(rootDir, "pxelinux.cfg", "default"), IsTemplate: true},
+		{VirtualPath: "raw", ActualPath: filepath.Join(rootDir, "pxelinux.cfg", "default")},
+		{VirtualPath: "broken", ActualPath: filepath.Join(rootDir, "broken"), IsTemplate: true},
+	})
+	_ = server.Start()
+	defer server.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client, err := tftp.NewClient("127.0.0.1:16972")
+	if err != nil {
+		t.Errorf("create tftp client failed: %v", err)
+		return
+	}
+	client.RequestTSize(true)
+	received, err := receiveTFTPFile(client, "pxelinux.cfg/01-00-11-22-33-44-aa")
+	expected := "board-01 eth0 00-11-22-33-44-aa 127.0.0.1"
+	if err != nil || received != expected {
+		t.Errorf("unexpected rendered content: %q, expect %q, err: %v", received, expected, err)
+	}
+	received, err = receiveTFTPFile(client, "raw")
+	if err != nil || received != templateContent {
+		t.Errorf("file without template flag was changed: %q, err: %v", received, err)
+	}
+	if _, err = receiveTFTPFile(client, "broken"); err == nil {
+		t.Error("broken template was received")
+	}
+}
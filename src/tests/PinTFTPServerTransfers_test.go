@@ -0,0 +1,85 @@
+package tests
+
+import (
+	"github.com/pin/tftp/v3"
+	"os"
+	"path/filepath"
+	"rol/domain"
+	"rol/infrastructure"
+	"testing"
+	"time"
+)
+
+func Test_PinTFTPServer_Transfers(t *testing.T) {
+	rootDir, err := filepath.Abs("tftpTransfers")
+	if err != nil {
+		t.Errorf("failed to get root dir path: %v", err)
+		return
+	}
+	_ = os.RemoveAll(rootDir)
+	defer os.RemoveAll(rootDir)
+	_ = os.MkdirAll(rootDir, os.ModePerm)
+	if err = os.WriteFile(filepath.Join(rootDir, "kernel"), []byte("kernel content"), 0600); err != nil {
+		t.Errorf("failed to create test file: %v", err)
+		return
+	}
+
+	server, err := infrastructure.NewPinTFTPServer(domain.TFTPConfig{
+		Address: "127.0.0.1",
+		Port:    "16973",
+		Enabled: true,
+	}, nil, nil)
+	if err != nil {
+		t.Errorf("create tftp server failed: %v", err)
+		return
+	}
+	_ = server.ReloadPaths([]domain.TFTPPathRatio{
+		{VirtualPath: "kernel", ActualPath: filepath.Join(rootDir, "kernel")},
+		{VirtualPath: "missing", ActualPath: filepath.Join(rootDir, "missing")},
+	})
+	_ = server.Start()
+	defer server.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client, err := tftp.NewClient("127.0.0.1:16973")
+	if err != nil {
+		t.Errorf("create tftp client failed: %v", err)
+		return
+	}
+	for i := 0; i < 2; i++ {
+		if _, err = receiveTFTPFile(client, "kernel"); err != nil {
+			t.Errorf("receive file failed: %v", err)
+		}
+	}
+	if _, err = receiveTFTPFile(client, "missing"); err == nil {
+		t.Error("missing file was received")
+	}
+	//success hook is called after the last ack is received by the server
+	time.Sleep(100 * time.Millisecond)
+
+	transfers := server.GetTransfers()
+	if len(transfers) != 3 {
+		t.Errorf("unexpected transfers count: %d", len(transfers))
+		return
+	}
+	if transfers[0].VirtualPath != "missing" || transfers[0].State != domain.TFTPTransferFailed || transfers[0].Error == "" {
+		t.Errorf("unexpected failed transfer: %+v", transfers[0])
+	}
+	kernelTransfer := transfers[1]
+	if kernelTransfer.State != domain.TFTPTransferSucceeded || kernelTransfer.Bytes != int64(len("kernel content")) ||
+		kernelTransfer.ActualPath != filepath.Join(rootDir, "kernel") || kernelTransfer.ClientAddress == "" {
+		t.Errorf("unexpected succeeded transfer: %+v", kernelTransfer)
+	}
+
+	stats := server.GetPathStats()
+	if len(stats) != 2 {
+		t.Errorf("unexpected path stats count: %d", len(stats))
+		return
+	}
+	if stats[0].VirtualPath != "kernel" || stats[0].Succeeded != 2 || stats[0].Bytes != 2*int64(len("kernel content")) {
+		t.Errorf("unexpected kernel stats: %+v", stats[0])
+	}
+	if stats[1].VirtualPath != "missing" || stats[1].Failed != 1 {
+		t.Errorf("unexpected missing file stats: %+v", stats[1])
+	}
+}
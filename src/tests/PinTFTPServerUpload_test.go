@@ -0,0 +1,146 @@
+package tests
+
+import (
+	"bytes"
+	"github.com/pin/tftp/v3"
+	"os"
+	"path/filepath"
+	"rol/domain"
+	"rol/infrastructure"
+	"testing"
+	"time"
+)
+
+func Test_PinTFTPServer_Upload(t *testing.T) {
+	uploadDir, err := filepath.Abs("tftpUploads")
+	if err != nil {
+		t.Errorf("failed to get upload dir path: %v", err)
+		return
+	}
+	_ = os.RemoveAll(uploadDir)
+	defer os.RemoveAll(uploadDir)
+
+	config := domain.TFTPConfig{
+		Address:       "127.0.0.1",
+		Port:          "16969",
+		Enabled:       true,
+		WriteEnabled:  true,
+		UploadDir:     uploadDir,
+		MaxUploadSize: 16,
+	}
+	server, err := infrastructure.NewPinTFTPServer(config, nil, nil)
+	if err != nil {
+		t.Errorf("create tftp server failed: %v", err)
+		return
+	}
+	_ = server.Start()
+	defer server.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client, err := tftp.NewClient("127.0.0.1:16969")
+	if err != nil {
+		t.Errorf("create tftp client failed: %v", err)
+		return
+	}
+	send := func(filename string, content []byte) error {
+		rf, err := client.Send(filename, "octet")
+		if err != nil {
+			return err
+		}
+		_, err = rf.ReadFrom(bytes.NewReader(content))
+		return err
+	}
+
+	err = send("switch/running config.txt", []byte("hostname sw1"))
+	if err != nil {
+		t.Errorf("upload failed: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(uploadDir, "switch", "running_config.txt"))
+	if err != nil || string(content) != "hostname sw1" {
+		t.Errorf("uploaded file is not saved with sanitized name: %v", err)
+	}
+
+	err = send("../../escape.txt", []byte("escape"))
+	if err != nil {
+		t.Errorf("upload failed: %v", err)
+	}
+	if _, err = os.Stat(filepath.Join(uploadDir, "escape.txt")); err != nil {
+		t.Errorf("file with parent directory references is not saved inside upload dir: %v", err)
+	}
+
+	err = send("big.bin", bytes.Repeat([]byte{1}, 1024))
+	if err == nil {
+		t.Error("file bigger than the limit was uploaded")
+	}
+	if _, err = os.Stat(filepath.Join(uploadDir, "big.bin")); !os.IsNotExist(err) {
+		t.Error("file bigger than the limit was saved")
+	}
+
+	config.WriteEnabled = false
+	_ = server.ReloadConfig(config)
+	err = send("disabled.txt", []byte("data"))
+	if err == nil {
+		t.Error("file was uploaded with disabled write mode")
+	}
+}
+
+func Test_PinTFTPServer_RejectedUploadTransfers(t *testing.T) {
+	uploadDir, err := filepath.Abs("tftpRejectedUploads")
+	if err != nil {
+		t.Errorf("failed to get upload dir path: %v", err)
+		return
+	}
+	_ = os.RemoveAll(uploadDir)
+	defer os.RemoveAll(uploadDir)
+
+	config := domain.TFTPConfig{
+		Address:       "127.0.0.1",
+		Port:          "16976",
+		Enabled:       true,
+		UploadDir:     uploadDir,
+		MaxUploadSize: 16,
+	}
+	server, err := infrastructure.NewPinTFTPServer(config, nil, nil)
+	if err != nil {
+		t.Errorf("create tftp server failed: %v", err)
+		return
+	}
+	_ = server.Start()
+	defer server.Stop()
+	time.Sleep(100 * time.Millisecond)
+
+	client, err := tftp.NewClient("127.0.0.1:16976")
+	if err != nil {
+		t.Errorf("create tftp client failed: %v", err)
+		return
+	}
+	send := func(filename string, size int) error {
+		rf, err := client.Send(filename, "octet")
+		if err != nil {
+			return err
+		}
+		rf.(tftp.OutgoingTransfer).SetSize(int64(size))
+		_, err = rf.ReadFrom(bytes.NewReader(bytes.Repeat([]byte{1}, size)))
+		return err
+	}
+	if err = send("readonly.txt", 4); err == nil {
+		t.Error("file was uploaded to read-only server")
+	}
+	//size is rejected before the first data packet, when the transfer has no id yet
+	config.WriteEnabled = true
+	_ = server.ReloadConfig(config)
+	if err = send("big.bin", 1024); err == nil {
+		t.Error("file bigger than the limit was uploaded")
+	}
+	time.Sleep(100 * time.Millisecond)
+
+	transfers := server.GetTransfers()
+	if len(transfers) != 2 {
+		t.Errorf("unexpected transfers count: %d", len(transfers))
+	}
+	for _, transfer := range transfers {
+		if transfer.State != domain.TFTPTransferFailed {
+			t.Errorf("expect rejected upload is failed, got %+v", transfer)
+		}
+	}
+}
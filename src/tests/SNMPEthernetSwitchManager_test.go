@@ -0,0 +1,414 @@
+package tests
+
+import (
+	"fmt"
+	"github.com/gosnmp/gosnmp"
+	"net"
+	"reflect"
+	"rol/app/interfaces"
+	"rol/domain"
+	"rol/infrastructure"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const (
+	snmpTestCommunity         = "private"
+	snmpTestEgressPortsOID    = ".1.3.6.1.2.1.17.7.1.4.3.1.2"
+	snmpTestUntaggedPortsOID  = ".1.3.6.1.2.1.17.7.1.4.3.1.4"
+	snmpTestVLANRowStatusOID  = ".1.3.6.1.2.1.17.7.1.4.3.1.5"
+	snmpTestPvidOID           = ".1.3.6.1.2.1.17.7.1.4.5.1.1"
+	snmpTestPOEAdminEnableOID = ".1.3.6.1.2.1.105.1.1.1.3.1"
+)
+
+//tSNMPAgent in-process SNMP v2c agent with the in-memory MIB, it supports get, get-next and set requests
+type tSNMPAgent struct {
+	conn   net.PacketConn
+	values map[string]gosnmp.SnmpPDU
+	mutex  sync.Mutex
+}
+
+//newSNMPTestAgent starts agent of the switch with 5 ports gi1-gi5, ports gi1-gi4 are in the default vlan 1
+//and supply PoE, bridge ports 1-5 are mapped to ifIndexes 101-105
+func newSNMPTestAgent() (*tSNMPAgent, error) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	agent := &tSNMPAgent{conn: conn, values: map[string]gosnmp.SnmpPDU{}}
+	for port := 1; port <= 5; port++ {
+		ifIndex := 100 + port
+		agent.setValue(fmt.Sprintf(".1.3.6.1.2.1.31.1.1.1.1.%d", ifIndex), gosnmp.OctetString, []byte(fmt.Sprintf("gi%d", port)))
+		agent.setValue(fmt.Sprintf(".1.3.6.1.2.1.17.1.4.1.2.%d", port), gosnmp.Integer, ifIndex)
+		agent.setValue(fmt.Sprintf("%s.%d", snmpTestPvidOID, port), gosnmp.Gauge32, uint(1))
+		if port <= 4 {
+			agent.setValue(fmt.Sprintf("%s.%d", snmpTestPOEAdminEnableOID, port), gosnmp.Integer, 2)
+		}
+	}
+	agent.setValue(".1.3.6.1.2.1.17.7.1.1.3.0", gosnmp.Gauge32, uint(256))
+	agent.setValue(".1.3.6.1.2.1.105.1.3.1.1.2.1", gosnmp.Gauge32, uint(60))
+	agent.createVLAN(1, []byte{0xf0})
+	go agent.serve()
+	return agent, nil
+}
+
+func (a *tSNMPAgent) port() int {
+	return a.conn.LocalAddr().(*net.UDPAddr).Port
+}
+
+func (a *tSNMPAgent) setValue(oid string, valueType gosnmp.Asn1BER, value interface{}) {
+	a.values[oid] = gosnmp.SnmpPDU{Name: oid, Type: valueType, Value: value}
+}
+
+func (a *tSNMPAgent) getValue(oid string) interface{} {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	return a.values[oid].Value
+}
+
+func (a *tSNMPAgent) createVLAN(vlanID int, members []byte) {
+	a.setValue(fmt.Sprintf("%s.%d", snmpTestEgressPortsOID, vlanID), gosnmp.OctetString, members)
+	a.setValue(fmt.Sprintf("%s.%d", snmpTestUntaggedPortsOID, vlanID), gosnmp.OctetString, members)
+	a.setValue(fmt.Sprintf("%s.%d", snmpTestVLANRowStatusOID, vlanID), gosnmp.Integer, 1)
+}
+
+func (a *tSNMPAgent) deleteVLAN(vlanID int) {
+	for _, column := range []string{snmpTestEgressPortsOID, snmpTestUntaggedPortsOID, snmpTestVLANRowStatusOID} {
+		delete(a.values, fmt.Sprintf("%s.%d", column, vlanID))
+	}
+}
+
+//compareOIDs compares oids numerically by their sub identifiers
+func compareOIDs(first, second string) int {
+	firstIDs := strings.Split(strings.Trim(first, "."), ".")
+	secondIDs := strings.Split(strings.Trim(second, "."), ".")
+	for i := 0; i < len(firstIDs) && i < len(secondIDs); i++ {
+		firstID, _ := strconv.Atoi(firstIDs[i])
+		secondID, _ := strconv.Atoi(secondIDs[i])
+		if firstID != secondID {
+			return firstID - secondID
+		}
+	}
+	return len(firstIDs) - len(secondIDs)
+}
+
+func (a *tSNMPAgent) getNext(oid string) (gosnmp.SnmpPDU, bool) {
+	oids := []string{}
+	for name := range a.values {
+		if compareOIDs(name, oid) > 0 {
+			oids = append(oids, name)
+		}
+	}
+	if len(oids) == 0 {
+		return gosnmp.SnmpPDU{}, false
+	}
+	sort.Slice(oids, func(i, j int) bool { return compareOIDs(oids[i], oids[j]) < 0 })
+	return a.values[oids[0]], true
+}
+
+func (a *tSNMPAgent) set(pdu gosnmp.SnmpPDU) gosnmp.SNMPError {
+	if strings.HasPrefix(pdu.Name, snmpTestVLANRowStatusOID+".") {
+		vlanID, _ := strconv.Atoi(strings.TrimPrefix(pdu.Name, snmpTestVLANRowStatusOID+"."))
+		switch pdu.Value {
+		case 4:
+			a.createVLAN(vlanID, []byte{})
+		case 6:
+			a.deleteVLAN(vlanID)
+		default:
+			return gosnmp.WrongValue
+		}
+		return gosnmp.NoError
+	}
+	current, ok := a.values[pdu.Name]
+	if !ok {
+		return gosnmp.NoCreation
+	}
+	if current.Type != pdu.Type {
+		return gosnmp.WrongType
+	}
+	if value, ok := pdu.Value.([]byte); ok {
+		//decoded octet strings share the request buffer
+		pdu.Value = append([]byte{}, value...)
+	}
+	a.values[pdu.Name] = pdu
+	return gosnmp.NoError
+}
+
+func (a *tSNMPAgent) handle(request *gosnmp.SnmpPacket) *gosnmp.SnmpPacket {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+	response := &gosnmp.SnmpPacket{
+		Version:   request.Version,
+		Community: request.Community,
+		PDUType:   gosnmp.GetResponse,
+		RequestID: request.RequestID,
+	}
+	for index, variable := range request.Variables {
+		name := "." + strings.TrimPrefix(variable.Name, ".")
+		switch request.PDUType {
+		case gosnmp.GetRequest:
+			value, ok := a.values[name]
+			if !ok {
+				value = gosnmp.SnmpPDU{Name: name, Type: gosnmp.NoSuchObject}
+			}
+			response.Variables = append(response.Variables, value)
+		case gosnmp.GetNextRequest:
+			value, ok := a.getNext(name)
+			if !ok {
+				value = gosnmp.SnmpPDU{Name: name, Type: gosnmp.EndOfMibView}
+			}
+			response.Variables = append(response.Variables, value)
+		case gosnmp.SetRequest:
+			variable.Name = name
+			if status := a.set(variable); status != gosnmp.NoError && response.Error == gosnmp.NoError {
+				response.Error = status
+				response.ErrorIndex = uint8(index + 1)
+			}
+			response.Variables = append(response.Variables, variable)
+		default:
+			response.Error = gosnmp.GenErr
+		}
+	}
+	return response
+}
+
+func (a *tSNMPAgent) serve() {
+	decoder := &gosnmp.GoSNMP{Version: gosnmp.Version2c, Community: snmpTestCommunity}
+	buf := make([]byte, 65535)
+	for {
+		count, addr, err := a.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+		request, err := decoder.SnmpDecodePacket(buf[:count])
+		if err != nil || request.Community != snmpTestCommunity {
+			continue
+		}
+		out, err := a.handle(request).MarshalMsg()
+		if err != nil {
+			continue
+		}
+		_, _ = a.conn.WriteTo(out, addr)
+	}
+}
+
+func (a *tSNMPAgent) close() {
+	_ = a.conn.Close()
+}
+
+var (
+	snmpTestAgent   *tSNMPAgent
+	snmpTestManager interfaces.IEthernetSwitchManager
+)
+
+func Test_SNMPEthernetSwitchManager_Prepare(t *testing.T) {
+	var err error
+	snmpTestAgent, err = newSNMPTestAgent()
+	if err != nil {
+		t.Errorf("start snmp agent failed: %v", err)
+		return
+	}
+	snmpTestManager = infrastructure.NewSNMPEthernetSwitchManager(domain.EthernetSwitch{
+		Address:       "127.0.0.1",
+		SNMPVersion:   "2c",
+		SNMPPort:      snmpTestAgent.port(),
+		SNMPCommunity: snmpTestCommunity,
+	})
+}
+
+func Test_SNMPEthernetSwitchManager_CreateVLAN(t *testing.T) {
+	if err := snmpTestManager.CreateVLAN(10); err != nil {
+		t.Errorf("create vlan failed: %v", err)
+		return
+	}
+	vlans, err := snmpTestManager.GetVLANs()
+	if err != nil {
+		t.Errorf("get vlans failed: %v", err)
+		return
+	}
+	if !reflect.DeepEqual(vlans, []int{1, 10}) {
+		t.Errorf("unexpected vlans: %v, expect [1 10]", vlans)
+	}
+}
+
+func Test_SNMPEthernetSwitchManager_AddVLANsOnPort(t *testing.T) {
+	if err := snmpTestManager.AddTaggedVLANOnPort("gi2", 10); err != nil {
+		t.Errorf("add tagged vlan failed: %v", err)
+		return
+	}
+	untagged, tagged, err := snmpTestManager.GetVLANsOnPort("gi2")
+	if err != nil {
+		t.Errorf("get port vlans failed: %v", err)
+		return
+	}
+	if untagged != 1 || !reflect.DeepEqual(tagged, []int{10}) {
+		t.Errorf("unexpected port vlans: untagged %d, tagged %v", untagged, tagged)
+	}
+	if err = snmpTestManager.RemoveVLANFromPort("gi3", 1); err != nil {
+		t.Errorf("remove vlan from port failed: %v", err)
+		return
+	}
+	if err = snmpTestManager.AddUntaggedVLANOnPort("gi3", 10); err != nil {
+		t.Errorf("add untagged vlan failed: %v", err)
+		return
+	}
+	untagged, tagged, err = snmpTestManager.GetVLANsOnPort("gi3")
+	if err != nil {
+		t.Errorf("get port vlans failed: %v", err)
+		return
+	}
+	if untagged != 10 || len(tagged) != 0 {
+		t.Errorf("unexpected port vlans: untagged %d, tagged %v", untagged, tagged)
+	}
+	egress, _ := snmpTestAgent.getValue(snmpTestEgressPortsOID + ".10").([]byte)
+	if !reflect.DeepEqual(egress, []byte{0x60}) {
+		t.Errorf("unexpected vlan 10 egress ports: %x, expect 60", egress)
+	}
+}
+
+func Test_SNMPEthernetSwitchManager_SetPortPVID(t *testing.T) {
+	if err := snmpTestManager.SetPortPVID("gi3", 10); err != nil {
+		t.Errorf("set port pvid failed: %v", err)
+		return
+	}
+	if pvid := snmpTestAgent.getValue(snmpTestPvidOID + ".3"); pvid != uint(10) {
+		t.Errorf("unexpected pvid: %v, expect 10", pvid)
+	}
+}
+
+func Test_SNMPEthernetSwitchManager_DeleteVLAN(t *testing.T) {
+	if err := snmpTestManager.RemoveVLANFromPort("gi2", 10); err != nil {
+		t.Errorf("remove vlan from port failed: %v", err)
+		return
+	}
+	_, tagged, err := snmpTestManager.GetVLANsOnPort("gi2")
+	if err != nil {
+		t.Errorf("get port vlans failed: %v", err)
+		return
+	}
+	if len(tagged) != 0 {
+		t.Errorf("unexpected tagged vlans: %v", tagged)
+	}
+	if err = snmpTestManager.DeleteVLAN(10); err != nil {
+		t.Errorf("delete vlan failed: %v", err)
+		return
+	}
+	vlans, err := snmpTestManager.GetVLANs()
+	if err != nil {
+		t.Errorf("get vlans failed: %v", err)
+		return
+	}
+	if !reflect.DeepEqual(vlans, []int{1}) {
+		t.Errorf("unexpected vlans: %v, expect [1]", vlans)
+	}
+}
+
+func Test_SNMPEthernetSwitchManager_POE(t *testing.T) {
+	poeManager := snmpTestManager.(interfaces.IEthernetSwitchPOEManager)
+	if err := poeManager.EnablePOEPort("gi4", "poe"); err != nil {
+		t.Errorf("enable poe failed: %v", err)
+		return
+	}
+	status, err := poeManager.GetPOEPortStatus("gi4")
+	if err != nil || status != "enable" {
+		t.Errorf("unexpected poe status: %q, error: %v", status, err)
+		return
+	}
+	if err = poeManager.DisablePOEPort("gi4"); err != nil {
+		t.Errorf("disable poe failed: %v", err)
+		return
+	}
+	status, err = poeManager.GetPOEPortStatus("gi4")
+	if err != nil || status != "disable" {
+		t.Errorf("unexpected poe status: %q, error: %v", status, err)
+	}
+}
+
+func Test_SNMPEthernetSwitchManager_GetCapabilities(t *testing.T) {
+	capabilities, err := snmpTestManager.GetCapabilities()
+	if err != nil {
+		t.Errorf("get capabilities failed: %v", err)
+		return
+	}
+	expected := domain.EthernetSwitchCapabilities{
+		Ports:     []string{"gi1", "gi2", "gi3", "gi4", "gi5"},
+		MaxVLANs:  256,
+		POEPorts:  []string{"gi1", "gi2", "gi3", "gi4"},
+		POETypes:  []string{"poe", "poe+"},
+		POEBudget: 60,
+	}
+	if !reflect.DeepEqual(capabilities, expected) {
+		t.Errorf("unexpected capabilities: %+v, expect %+v", capabilities, expected)
+	}
+}
+
+func Test_SNMPEthernetSwitchManager_UnknownPort(t *testing.T) {
+	poeManager := snmpTestManager.(interfaces.IEthernetSwitchPOEManager)
+	if err := poeManager.EnablePOEPort("gi9", "poe"); err == nil {
+		t.Error("expect error for unknown port")
+	}
+}
+
+func Test_SNMPEthernetSwitchManager_GetMACAddressTable(t *testing.T) {
+	//vlan 1 uses fdb 5, fdb 7 has no vlan mapping and is treated as vlan 7
+	snmpTestAgent.setValue(".1.3.6.1.2.1.17.7.1.4.2.1.3.0.1", gosnmp.Gauge32, uint(5))
+	snmpTestAgent.setValue(".1.3.6.1.2.1.17.7.1.2.2.1.2.5.0.17.34.51.68.85", gosnmp.Integer, 2)
+	snmpTestAgent.setValue(".1.3.6.1.2.1.17.7.1.2.2.1.2.7.170.187.204.221.238.255", gosnmp.Integer, 2)
+	snmpTestAgent.setValue(".1.3.6.1.2.1.17.7.1.2.2.1.2.5.0.17.34.51.68.86", gosnmp.Integer, 4)
+	//address of the CPU port
+	snmpTestAgent.setValue(".1.3.6.1.2.1.17.7.1.2.2.1.2.5.0.17.34.51.68.87", gosnmp.Integer, 0)
+	table, err := snmpTestManager.GetMACAddressTable()
+	if err != nil {
+		t.Errorf("get mac address table failed: %v", err)
+		return
+	}
+	expected := []domain.EthernetSwitchMACAddress{
+		{MAC: "00:11:22:33:44:55", VLANID: 1, PortName: "gi2"},
+		{MAC: "aa:bb:cc:dd:ee:ff", VLANID: 7, PortName: "gi2"},
+		{MAC: "00:11:22:33:44:56", VLANID: 1, PortName: "gi4"},
+	}
+	if !reflect.DeepEqual(table, expected) {
+		t.Errorf("unexpected mac address table: %+v, expect %+v", table, expected)
+	}
+}
+
+func Test_SNMPEthernetSwitchManager_GetLLDPNeighbors(t *testing.T) {
+	neighbors := []struct {
+		index     string
+		chassisID []byte
+		portID    []byte
+		sysName   string
+	}{
+		{"0.3.1", []byte{0x00, 0x11, 0x22, 0x33, 0x44, 0x55}, []byte("eth0"), "node-1"},
+		{"0.1.2", []byte("switch-2"), []byte{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}, "switch-2"},
+	}
+	for _, neighbor := range neighbors {
+		snmpTestAgent.setValue(".1.0.8802.1.1.2.1.4.1.1.4."+neighbor.index, gosnmp.Integer, 4)
+		snmpTestAgent.setValue(".1.0.8802.1.1.2.1.4.1.1.5."+neighbor.index, gosnmp.OctetString, neighbor.chassisID)
+		snmpTestAgent.setValue(".1.0.8802.1.1.2.1.4.1.1.6."+neighbor.index, gosnmp.Integer, 3)
+		snmpTestAgent.setValue(".1.0.8802.1.1.2.1.4.1.1.7."+neighbor.index, gosnmp.OctetString, neighbor.portID)
+		snmpTestAgent.setValue(".1.0.8802.1.1.2.1.4.1.1.9."+neighbor.index, gosnmp.OctetString, []byte(neighbor.sysName))
+	}
+	snmpTestAgent.setValue(".1.0.8802.1.1.2.1.4.1.1.8.0.3.1", gosnmp.OctetString, []byte("uplink"))
+	snmpTestAgent.setValue(".1.0.8802.1.1.2.1.4.2.1.3.0.3.1.1.4.192.168.0.10", gosnmp.Integer, 2)
+	result, err := snmpTestManager.GetLLDPNeighbors()
+	if err != nil {
+		t.Errorf("get lldp neighbors failed: %v", err)
+		return
+	}
+	expected := []domain.EthernetSwitchLLDPNeighbor{
+		{PortName: "gi1", ChassisID: "switch-2", PortID: "aa:bb:cc:dd:ee:ff", SystemName: "switch-2"},
+		{PortName: "gi3", ChassisID: "00:11:22:33:44:55", PortID: "eth0", PortDescription: "uplink",
+			SystemName: "node-1", ManagementAddress: "192.168.0.10"},
+	}
+	if !reflect.DeepEqual(result, expected) {
+		t.Errorf("unexpected lldp neighbors: %+v, expect %+v", result, expected)
+	}
+}
+
+func Test_SNMPEthernetSwitchManager_Close(t *testing.T) {
+	snmpTestAgent.close()
+}
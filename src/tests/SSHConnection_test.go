@@ -0,0 +1,212 @@
+package tests
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	"fmt"
+	"golang.org/x/crypto/ssh"
+	"net"
+	"reflect"
+	"rol/infrastructure"
+	"strings"
+	"sync/atomic"
+	"testing"
+)
+
+const (
+	sshTestUsername = "admin"
+	//  pragma: allowlist nextline secret
+	sshTestPassword = "admin_password"
+)
+
+//tSSHSwitchServer in-process ssh server with the shell of tp link switch CLI, it answers on the show vlan command only
+type tSSHSwitchServer struct {
+	listener    net.Listener
+	config      *ssh.ServerConfig
+	fingerprint string
+	//connections number of the accepted connections
+	connections int32
+}
+
+func newSSHTestSwitchServer() (*tSSHSwitchServer, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := ssh.NewSignerFromKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	config := &ssh.ServerConfig{
+		PasswordCallback: func(conn ssh.ConnMetadata, password []byte) (*ssh.Permissions, error) {
+			if conn.User() == sshTestUsername && string(password) == sshTestPassword {
+				return nil, nil
+			}
+			return nil, fmt.Errorf("wrong password for %s", conn.User())
+		},
+	}
+	config.AddHostKey(signer)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+	server := &tSSHSwitchServer{
+		listener:    listener,
+		config:      config,
+		fingerprint: ssh.FingerprintSHA256(signer.PublicKey()),
+	}
+	go server.serve()
+	return server, nil
+}
+
+func (s *tSSHSwitchServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		atomic.AddInt32(&s.connections, 1)
+		go s.handleConn(conn)
+	}
+}
+
+func (s *tSSHSwitchServer) handleConn(conn net.Conn) {
+	_, channels, requests, err := ssh.NewServerConn(conn, s.config)
+	if err != nil {
+		_ = conn.Close()
+		return
+	}
+	go ssh.DiscardRequests(requests)
+	for newChannel := range channels {
+		if newChannel.ChannelType() != "session" {
+			_ = newChannel.Reject(ssh.UnknownChannelType, "unknown channel type")
+			continue
+		}
+		channel, channelRequests, err := newChannel.Accept()
+		if err != nil {
+			continue
+		}
+		go func() {
+			for request := range channelRequests {
+				_ = request.Reply(request.Type == "pty-req" || request.Type == "shell", nil)
+			}
+		}()
+		go s.handleShell(channel)
+	}
+}
+
+func (s *tSSHSwitchServer) handleShell(channel ssh.Channel) {
+	defer channel.Close()
+	_, _ = channel.Write([]byte("\r\nTL-SG2210MP>"))
+	scanner := bufio.NewScanner(channel)
+	for scanner.Scan() {
+		command := strings.TrimSpace(scanner.Text())
+		if command == "show vlan" {
+			_, _ = channel.Write([]byte("\r\nVLAN  Name                 Status    Ports\r\n" +
+				"----- -------------------- --------- ----------------------------------------\r\n" +
+				"1     System-VLAN          active    Gi1/0/1, Gi1/0/2, Gi1/0/3\r\n" +
+				"10    VLAN0010             active    Gi1/0/2\r\n" +
+				"\r\n"))
+		}
+		_, _ = channel.Write([]byte("TL-SG2210MP#"))
+	}
+}
+
+func (s *tSSHSwitchServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+var sshTestServer *tSSHSwitchServer
+
+func Test_SSHConnection_Prepare(t *testing.T) {
+	var err error
+	sshTestServer, err = newSSHTestSwitchServer()
+	if err != nil {
+		t.Errorf("start ssh server failed: %v", err)
+	}
+}
+
+func Test_SSHConnection_ConnectFailByHostKeyMismatch(t *testing.T) {
+	conn := infrastructure.NewSSHConnection(sshTestUsername, sshTestPassword, "SHA256:AAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA")
+	err := conn.Connect(fmt.Sprintf("127.0.0.1:%d", sshTestServer.port()))
+	if err == nil {
+		_ = conn.Close()
+		t.Error("expect host key mismatch error")
+		return
+	}
+	if !strings.Contains(err.Error(), "fingerprint mismatch") {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
+
+func Test_SSHConnection_ConnectFailByNotPinnedHostKey(t *testing.T) {
+	conn := infrastructure.NewSSHConnection(sshTestUsername, sshTestPassword, "")
+	if err := conn.Connect(fmt.Sprintf("127.0.0.1:%d", sshTestServer.port())); err == nil {
+		_ = conn.Close()
+		t.Error("expect error for not pinned host key")
+	}
+}
+
+func Test_SSHConnection_SendAndRead(t *testing.T) {
+	conn := infrastructure.NewSSHConnection(sshTestUsername, sshTestPassword, sshTestServer.fingerprint)
+	err := conn.Connect(fmt.Sprintf("127.0.0.1:%d", sshTestServer.port()))
+	if err != nil {
+		t.Errorf("connect failed: %v", err)
+		return
+	}
+	defer conn.Close()
+	//byte after the expect word is read too, so wait for the greeting line break followed by the prompt
+	if _, err = conn.Read("\r\n"); err != nil {
+		t.Errorf("read prompt failed: %v", err)
+		return
+	}
+	if err = conn.Send("show vlan"); err != nil {
+		t.Errorf("send failed: %v", err)
+		return
+	}
+	out, err := conn.Read("-----\r\n")
+	if err != nil {
+		t.Errorf("read failed: %v", err)
+		return
+	}
+	if !strings.Contains(out, "VLAN  Name") {
+		t.Errorf("unexpected output: %q", out)
+	}
+}
+
+func Test_SSHConnection_TPLinkManagerGetVLANs(t *testing.T) {
+	conn := infrastructure.NewSSHConnection(sshTestUsername, sshTestPassword, sshTestServer.fingerprint)
+	manager := infrastructure.NewTPLinkEthernetSwitchManagerWithConnection(conn,
+		fmt.Sprintf("127.0.0.1:%d", sshTestServer.port()), sshTestUsername, sshTestPassword)
+	vlans, err := manager.GetVLANs()
+	if err != nil {
+		t.Errorf("get vlans failed: %v", err)
+		return
+	}
+	if !reflect.DeepEqual(vlans, []int{1, 10}) {
+		t.Errorf("unexpected vlans: %v, expect [1 10]", vlans)
+	}
+}
+
+func Test_SSHConnection_TPLinkManagerReusesSession(t *testing.T) {
+	conn := infrastructure.NewSSHConnection(sshTestUsername, sshTestPassword, sshTestServer.fingerprint)
+	manager := infrastructure.NewTPLinkEthernetSwitchManagerWithConnection(conn,
+		fmt.Sprintf("127.0.0.1:%d", sshTestServer.port()), sshTestUsername, sshTestPassword)
+	connectionsBefore := atomic.LoadInt32(&sshTestServer.connections)
+	for i := 0; i < 3; i++ {
+		vlans, err := manager.GetVLANs()
+		if err != nil || !reflect.DeepEqual(vlans, []int{1, 10}) {
+			t.Errorf("unexpected vlans: %v, error: %v", vlans, err)
+			return
+		}
+	}
+	if connections := atomic.LoadInt32(&sshTestServer.connections) - connectionsBefore; connections != 1 {
+		t.Errorf("expect one ssh connection for all requests, got %d", connections)
+	}
+	_ = conn.Close()
+}
+
+func Test_SSHConnection_Close(t *testing.T) {
+	_ = sshTestServer.listener.Close()
+}
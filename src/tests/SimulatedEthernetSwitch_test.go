@@ -0,0 +1,432 @@
+package tests
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"os"
+	"reflect"
+	"rol/app/errors"
+	"rol/app/interfaces"
+	"rol/app/services"
+	"rol/domain"
+	"rol/dtos"
+	"rol/infrastructure"
+	"testing"
+)
+
+const (
+	simTestLogin = "admin"
+	//  pragma: allowlist nextline secret
+	simTestPassword = "admin_password"
+)
+
+func newSimulatedSwitchTelnetManager(t *testing.T) (*infrastructure.SimulatedEthernetSwitch,
+	*infrastructure.SimulatedEthernetSwitchTelnetServer, interfaces.IEthernetSwitchManager) {
+	simulator := infrastructure.NewSimulatedEthernetSwitch(10, 8)
+	server, err := infrastructure.NewSimulatedEthernetSwitchTelnetServer(simulator, "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("start simulated switch telnet server failed: %v", err)
+	}
+	server.SetCredentials(simTestLogin, simTestPassword)
+	manager := infrastructure.NewTPLinkEthernetSwitchManagerWithConnection(infrastructure.NewTelnetConnection(),
+		server.Address(), simTestLogin, simTestPassword)
+	return simulator, server, manager
+}
+
+func Test_SimulatedEthernetSwitch_TPLinkManagerVLANs(t *testing.T) {
+	simulator, server, manager := newSimulatedSwitchTelnetManager(t)
+	defer server.Close()
+	err := manager.ApplyOperations([]domain.EthernetSwitchOperation{
+		{Type: domain.EthernetSwitchOperationCreateVLAN, VLANID: 10},
+		{Type: domain.EthernetSwitchOperationCreateVLAN, VLANID: 20},
+		{Type: domain.EthernetSwitchOperationAddTaggedVLAN, PortName: "gi1/0/2", VLANID: 10},
+		{Type: domain.EthernetSwitchOperationAddUntaggedVLAN, PortName: "gi1/0/3", VLANID: 20},
+		{Type: domain.EthernetSwitchOperationRemoveVLAN, PortName: "gi1/0/3", VLANID: 1},
+		{Type: domain.EthernetSwitchOperationSetPVID, PortName: "gi1/0/3", VLANID: 20},
+	})
+	if err != nil {
+		t.Errorf("apply operations failed: %v", err)
+		return
+	}
+	vlans, err := manager.GetVLANs()
+	if err != nil || !reflect.DeepEqual(vlans, []int{1, 10, 20}) {
+		t.Errorf("unexpected vlans: %v, error: %v", vlans, err)
+	}
+	untagged, tagged, err := manager.GetVLANsOnPort("gi1/0/2")
+	if err != nil || untagged != 1 || !reflect.DeepEqual(tagged, []int{10}) {
+		t.Errorf("unexpected vlans on port: %d, %v, error: %v", untagged, tagged, err)
+	}
+	untagged, tagged, err = manager.GetVLANsOnPort("gi1/0/3")
+	if err != nil || untagged != 20 || len(tagged) != 0 {
+		t.Errorf("unexpected vlans on port: %d, %v, error: %v", untagged, tagged, err)
+	}
+	if pvid, _ := simulator.GetPortPVID("gi1/0/3"); pvid != 20 {
+		t.Errorf("unexpected pvid %d, expect 20", pvid)
+	}
+	if pvid, err := manager.GetPortPVID("gi1/0/3"); err != nil || pvid != 20 {
+		t.Errorf("unexpected pvid from switch CLI %d, expect 20, error: %v", pvid, err)
+	}
+	err = manager.AddTaggedVLANOnPort("gi1/0/4", 30)
+	if !errors.As(err, errors.NotFound) {
+		t.Errorf("expect not found error for nonexistent vlan, got %v", err)
+	}
+	if err = manager.DeleteVLAN(10); err != nil {
+		t.Errorf("delete vlan failed: %v", err)
+	}
+	if err = manager.SaveConfig(); err != nil {
+		t.Errorf("save config failed: %v", err)
+	}
+	if vlans, _ = manager.GetVLANs(); !reflect.DeepEqual(vlans, []int{1, 20}) {
+		t.Errorf("unexpected vlans after delete: %v", vlans)
+	}
+	if simulator.GetSavesCount() != 1 {
+		t.Errorf("expect saved config, saves count %d", simulator.GetSavesCount())
+	}
+	if failed := server.GetFailedCommands(); len(failed) != 0 {
+		t.Errorf("switch CLI rejected commands: %v", failed)
+	}
+}
+
+func Test_SimulatedEthernetSwitch_TPLinkManagerPOE(t *testing.T) {
+	simulator, server, manager := newSimulatedSwitchTelnetManager(t)
+	defer server.Close()
+	poeManager := manager.(interfaces.IEthernetSwitchPOEManager)
+	if err := poeManager.EnablePOEPort("gi1/0/1", "poe+"); err != nil {
+		t.Errorf("enable poe failed: %v", err)
+		return
+	}
+	status, err := poeManager.GetPOEPortStatus("gi1/0/1")
+	if err != nil || status != "enable" {
+		t.Errorf("unexpected poe status %s, error: %v", status, err)
+	}
+	if err = poeManager.DisablePOEPort("gi1/0/1"); err != nil {
+		t.Errorf("disable poe failed: %v", err)
+	}
+	//config commands are not answered, status request waits until they are executed
+	if status, _ = poeManager.GetPOEPortStatus("gi1/0/1"); status != "disable" {
+		t.Errorf("unexpected poe status %s, expect disable", status)
+	}
+	if enabled, _ := simulator.GetPOEPortStatus("gi1/0/1"); enabled {
+		t.Error("expect poe is disabled")
+	}
+	if err = poeManager.EnablePOEPort("gi1/0/2", "passive24"); err == nil {
+		t.Error("expect error for unsupported poe type")
+	}
+	if failed := server.GetFailedCommands(); len(failed) != 0 {
+		t.Errorf("switch CLI rejected commands: %v", failed)
+	}
+}
+
+func Test_SimulatedEthernetSwitch_TPLinkManagerWrongPassword(t *testing.T) {
+	_, server, _ := newSimulatedSwitchTelnetManager(t)
+	defer server.Close()
+	manager := infrastructure.NewTPLinkEthernetSwitchManagerWithConnection(infrastructure.NewTelnetConnection(),
+		server.Address(), simTestLogin, "wrong")
+	if _, err := manager.GetVLANs(); err == nil {
+		t.Error("expect error for wrong password")
+	}
+}
+
+func Test_SimulatedEthernetSwitch_TPLinkManagerRunningConfig(t *testing.T) {
+	simulator, server, manager := newSimulatedSwitchTelnetManager(t)
+	defer server.Close()
+	configManager := manager.(interfaces.IEthernetSwitchConfigManager)
+	initialConfig, err := configManager.GetRunningConfig()
+	if err != nil || initialConfig != simulator.GetRunningConfig() {
+		t.Fatalf("unexpected running config:\n%s\nexpect:\n%s\nerror: %v", initialConfig, simulator.GetRunningConfig(), err)
+	}
+	err = configManager.RestoreConfig("#\nvlan 10,20-21\n#\ninterface gigabitEthernet 1/0/2\n" +
+		" no switchport general allowed vlan 1\n switchport general allowed vlan 20 untagged\n" +
+		" switchport general allowed vlan 10,21 tagged\n switchport pvid 20\n power inline supply enable\n#\nend\n")
+	if err != nil {
+		t.Fatalf("restore config failed: %v", err)
+	}
+	//config commands are not answered, read request waits until they are executed
+	if vlans, _ := manager.GetVLANs(); !reflect.DeepEqual(vlans, []int{1, 10, 20, 21}) {
+		t.Errorf("unexpected vlans after restore: %v", vlans)
+	}
+	untagged, tagged, err := manager.GetVLANsOnPort("gi1/0/2")
+	if err != nil || untagged != 20 || !reflect.DeepEqual(tagged, []int{10, 21}) {
+		t.Errorf("unexpected vlans on port after restore: %d, %v, error: %v", untagged, tagged, err)
+	}
+	if pvid, _ := manager.GetPortPVID("gi1/0/2"); pvid != 20 {
+		t.Errorf("unexpected pvid %d after restore, expect 20", pvid)
+	}
+	if enabled, _ := simulator.GetPOEPortStatus("gi1/0/2"); !enabled {
+		t.Error("expect poe is enabled after restore")
+	}
+	if err = configManager.RestoreConfig(initialConfig); err != nil {
+		t.Fatalf("restore initial config failed: %v", err)
+	}
+	if config, _ := configManager.GetRunningConfig(); config != initialConfig {
+		t.Errorf("unexpected running config after restore:\n%s\nexpect:\n%s", config, initialConfig)
+	}
+	if failed := server.GetFailedCommands(); len(failed) != 0 {
+		t.Errorf("switch CLI rejected commands: %v", failed)
+	}
+}
+
+func Test_SimulatedEthernetSwitch_TPLinkManagerNeighbors(t *testing.T) {
+	simulator, server, manager := newSimulatedSwitchTelnetManager(t)
+	defer server.Close()
+	if err := simulator.CreateVLAN(10); err != nil {
+		t.Fatalf("create vlan failed: %v", err)
+	}
+	if err := simulator.AddVLANOnPort("gi1/0/2", 10, false); err != nil {
+		t.Fatalf("add vlan on port failed: %v", err)
+	}
+	learned := []domain.EthernetSwitchMACAddress{
+		{MAC: "00:11:22:33:44:55", VLANID: 1, PortName: "gi1/0/2"},
+		{MAC: "00:11:22:33:44:56", VLANID: 10, PortName: "gi1/0/2"},
+		{MAC: "AA:BB:CC:DD:EE:FF", VLANID: 1, PortName: "gi1/0/10"},
+	}
+	for _, entry := range learned {
+		if err := simulator.LearnMACAddress(entry.PortName, entry.MAC, entry.VLANID); err != nil {
+			t.Fatalf("learn mac address failed: %v", err)
+		}
+	}
+	if err := simulator.LearnMACAddress("gi1/0/2", "00:11:22:33:44:55", 30); !errors.As(err, errors.NotFound) {
+		t.Errorf("expect not found error for nonexistent vlan, got %v", err)
+	}
+	table, err := manager.GetMACAddressTable()
+	expectedTable := []domain.EthernetSwitchMACAddress{
+		{MAC: "aa:bb:cc:dd:ee:ff", VLANID: 1, PortName: "gi1/0/10"},
+		{MAC: "00:11:22:33:44:55", VLANID: 1, PortName: "gi1/0/2"},
+		{MAC: "00:11:22:33:44:56", VLANID: 10, PortName: "gi1/0/2"},
+	}
+	if err != nil || !reflect.DeepEqual(table, expectedTable) {
+		t.Errorf("unexpected mac address table: %+v, error: %v", table, err)
+	}
+	neighbor := domain.EthernetSwitchLLDPNeighbor{
+		PortName:          "gi1/0/3",
+		ChassisID:         "00:11:22:33:44:55",
+		PortID:            "eth0",
+		PortDescription:   "uplink",
+		SystemName:        "node-1",
+		ManagementAddress: "192.168.0.10",
+	}
+	if err = simulator.AddLLDPNeighbor(neighbor); err != nil {
+		t.Fatalf("add lldp neighbor failed: %v", err)
+	}
+	neighbors, err := manager.GetLLDPNeighbors()
+	if err != nil || !reflect.DeepEqual(neighbors, []domain.EthernetSwitchLLDPNeighbor{neighbor}) {
+		t.Errorf("unexpected lldp neighbors: %+v, error: %v", neighbors, err)
+	}
+	//learned addresses are removed with the port VLAN membership
+	if err = manager.RemoveVLANFromPort("gi1/0/2", 10); err != nil {
+		t.Errorf("remove vlan from port failed: %v", err)
+	}
+	if table, _ = manager.GetMACAddressTable(); len(table) != 2 {
+		t.Errorf("unexpected mac address table after vlan removal: %+v", table)
+	}
+	if failed := server.GetFailedCommands(); len(failed) != 0 {
+		t.Errorf("switch CLI rejected commands: %v", failed)
+	}
+}
+
+//Test_SimulatedEthernetSwitch_ServiceFlow runs VLAN and port service flow with in-memory and telnet simulated switches
+func Test_SimulatedEthernetSwitch_ServiceFlow(t *testing.T) {
+	for _, telnetEnabled := range []bool{false, true} {
+		config := &domain.AppConfig{}
+		config.EthernetSwitchSimulator.TelnetEnabled = telnetEnabled
+		testSimulatedSwitchServiceFlow(t, config)
+	}
+}
+
+//simulatedSwitchServiceEnv ethernet switch service with simulated switches driver and sqlite repositories
+type simulatedSwitchServiceEnv struct {
+	service       *services.EthernetSwitchService
+	managers      interfaces.IEthernetSwitchManagerProvider
+	drivers       *infrastructure.EthernetSwitchDriverRegistry
+	vlanRepo      interfaces.IGenericRepository[uuid.UUID, domain.EthernetSwitchVLAN]
+	leaseRepo     interfaces.IGenericRepository[uuid.UUID, domain.DHCP4Lease]
+	interfaceRepo interfaces.IGenericRepository[uuid.UUID, domain.DeviceNetworkInterface]
+	db            *gorm.DB
+	dbPath        string
+}
+
+func newSimulatedSwitchServiceEnv(t *testing.T, config *domain.AppConfig, dbPath string) *simulatedSwitchServiceEnv {
+	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		t.Fatalf("creating db failed: %v", err)
+	}
+	err = db.AutoMigrate(new(domain.EthernetSwitch), new(domain.EthernetSwitchPort), new(domain.EthernetSwitchVLAN),
+		new(domain.EthernetSwitchConfigBackup), new(domain.DHCP4Lease), new(domain.DeviceNetworkInterface))
+	if err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+	logger := logrus.New()
+	switchRepo := infrastructure.NewGormEthernetSwitchRepository(db, logger)
+	portRepo := infrastructure.NewGormEthernetSwitchPortRepository(db, logger)
+	vlanRepo := infrastructure.NewGormEthernetSwitchVLANRepository(db, logger)
+	backupRepo := infrastructure.NewGormEthernetSwitchConfigBackupRepository(db, logger)
+	leaseRepo := infrastructure.NewGormDHCP4LeaseRepository(db, logger)
+	interfaceRepo := infrastructure.NewGormDeviceNetworkInterfaceRepository(db, logger)
+	drivers, err := infrastructure.NewEthernetSwitchDriverRegistry(config)
+	if err != nil {
+		t.Fatalf("create switch drivers registry failed: %v", err)
+	}
+	managers := infrastructure.NewEthernetSwitchManagerProvider(switchRepo, drivers)
+	service, _ := services.NewEthernetSwitchService(switchRepo, portRepo, vlanRepo, backupRepo, leaseRepo, interfaceRepo, managers, drivers)
+	return &simulatedSwitchServiceEnv{
+		service:       service,
+		managers:      managers,
+		drivers:       drivers.(*infrastructure.EthernetSwitchDriverRegistry),
+		vlanRepo:      vlanRepo,
+		leaseRepo:     leaseRepo,
+		interfaceRepo: interfaceRepo,
+		db:            db,
+		dbPath:        dbPath,
+	}
+}
+
+func (e *simulatedSwitchServiceEnv) close() {
+	sqlDB, _ := e.db.DB()
+	_ = sqlDB.Close()
+	_ = os.Remove(e.dbPath)
+}
+
+//createSwitchWithPorts creates simulated switch with PoE enabled ports
+func (e *simulatedSwitchServiceEnv) createSwitchWithPorts(t *testing.T, portsNames ...string) (uuid.UUID, []uuid.UUID) {
+	ctx := context.Background()
+	switchDto, err := e.service.Create(ctx, dtos.EthernetSwitchCreateDto{
+		EthernetSwitchBaseDto: dtos.EthernetSwitchBaseDto{
+			Name:        "SimulatedSwitch",
+			Serial:      "sim_serial",
+			SwitchModel: "sim-24p",
+			Address:     "127.0.0.1",
+			Username:    simTestLogin,
+		},
+		Password: simTestPassword,
+	})
+	if err != nil {
+		t.Fatalf("create switch failed: %v", err)
+	}
+	portsIDs := []uuid.UUID{}
+	for _, portName := range portsNames {
+		port, err := e.service.CreatePort(ctx, switchDto.ID, dtos.EthernetSwitchPortCreateDto{
+			EthernetSwitchPortBaseDto: dtos.EthernetSwitchPortBaseDto{Name: portName, POEType: "poe", POEEnabled: true, PVID: 1},
+		})
+		if err != nil {
+			t.Fatalf("create port %s failed: %v", portName, err)
+		}
+		portsIDs = append(portsIDs, port.ID)
+	}
+	return switchDto.ID, portsIDs
+}
+
+func testSimulatedSwitchServiceFlow(t *testing.T, config *domain.AppConfig) {
+	env := newSimulatedSwitchServiceEnv(t, config, "simulatedEthernetSwitch_test.db")
+	defer env.close()
+	service := env.service
+	managers := env.managers
+	ctx := context.Background()
+	switchID, portsIDs := env.createSwitchWithPorts(t, "gi1/0/1", "gi1/0/2", "gi1/0/3")
+	vlan, err := service.CreateVLAN(ctx, switchID, dtos.EthernetSwitchVLANCreateDto{
+		EthernetSwitchVLANBaseDto: dtos.EthernetSwitchVLANBaseDto{
+			TaggedPorts:   []uuid.UUID{portsIDs[0], portsIDs[1]},
+			UntaggedPorts: []uuid.UUID{portsIDs[2]},
+		},
+		VlanID: 100,
+	})
+	if err != nil {
+		t.Fatalf("create vlan failed: %v", err)
+	}
+	_, err = service.UpdateVLAN(ctx, switchID, vlan.ID, dtos.EthernetSwitchVLANUpdateDto{
+		EthernetSwitchVLANBaseDto: dtos.EthernetSwitchVLANBaseDto{
+			TaggedPorts:   []uuid.UUID{portsIDs[1]},
+			UntaggedPorts: []uuid.UUID{portsIDs[0], portsIDs[2]},
+		},
+	})
+	if err != nil {
+		t.Fatalf("update vlan failed: %v", err)
+	}
+
+	manager, err := managers.Get(ctx, switchID)
+	if err != nil || manager == nil {
+		t.Fatalf("get switch manager failed: %v", err)
+	}
+	expected := map[string][]int{"gi1/0/1": {}, "gi1/0/2": {100}, "gi1/0/3": {}}
+	for portName, expectedTagged := range expected {
+		untagged, tagged, err := manager.GetVLANsOnPort(portName)
+		if err != nil || !reflect.DeepEqual(tagged, expectedTagged) {
+			t.Errorf("telnet %v, port %s: unexpected tagged vlans %v, expect %v, error: %v",
+				config.EthernetSwitchSimulator.TelnetEnabled, portName, tagged, expectedTagged, err)
+		}
+		if portName != "gi1/0/2" && untagged != 100 {
+			t.Errorf("telnet %v, port %s: unexpected untagged vlan %d, expect 100",
+				config.EthernetSwitchSimulator.TelnetEnabled, portName, untagged)
+		}
+	}
+	status, err := manager.(interfaces.IEthernetSwitchPOEManager).GetPOEPortStatus("gi1/0/1")
+	if err != nil || status != "enable" {
+		t.Errorf("unexpected poe status %s, error: %v", status, err)
+	}
+	if err = service.DeleteVLAN(ctx, switchID, vlan.ID); err != nil {
+		t.Errorf("delete vlan failed: %v", err)
+	}
+	if vlans, _ := manager.GetVLANs(); !reflect.DeepEqual(vlans, []int{1}) {
+		t.Errorf("unexpected vlans after delete: %v", vlans)
+	}
+}
+
+func Test_SimulatedEthernetSwitch_DeleteReleasesTelnetServer(t *testing.T) {
+	config := &domain.AppConfig{}
+	config.EthernetSwitchSimulator.TelnetEnabled = true
+	env := newSimulatedSwitchServiceEnv(t, config, "simulatedEthernetSwitchRelease_test.db")
+	defer env.close()
+	ctx := context.Background()
+	switchID, _ := env.createSwitchWithPorts(t, "gi1/0/1")
+	manager, err := env.managers.Get(ctx, switchID)
+	if err != nil || manager == nil {
+		t.Fatalf("get switch manager failed: %v", err)
+	}
+	if _, err = manager.GetVLANs(); err != nil {
+		t.Fatalf("get vlans failed: %v", err)
+	}
+	if err = env.service.Delete(ctx, switchID); err != nil {
+		t.Fatalf("delete switch failed: %v", err)
+	}
+	//telnet listener and opened CLI session of the deleted switch are closed
+	if _, err = manager.GetVLANs(); err == nil {
+		t.Error("simulated switch telnet server is still running after the switch delete")
+	}
+}
+
+func Test_SimulatedEthernetSwitch_DeleteFailByCabledInterface(t *testing.T) {
+	env := newSimulatedSwitchServiceEnv(t, &domain.AppConfig{}, "simulatedEthernetSwitchDeleteCabled_test.db")
+	defer env.close()
+	ctx := context.Background()
+	switchID, portsIDs := env.createSwitchWithPorts(t, "gi1/0/1")
+	networkInterface, err := env.interfaceRepo.Insert(ctx, domain.DeviceNetworkInterface{
+		DeviceID:             uuid.New(),
+		Name:                 "eth0",
+		MAC:                  "00:11:22:33:44:55",
+		EthernetSwitchID:     switchID,
+		EthernetSwitchPortID: portsIDs[0],
+	})
+	if err != nil {
+		t.Fatalf("insert network interface failed: %v", err)
+	}
+	err = env.service.Delete(ctx, switchID)
+	if !errors.As(err, errors.Validation) {
+		t.Fatalf("expect validation error on cabled switch delete, got %v", err)
+	}
+	if _, err = env.service.GetByID(ctx, switchID); err != nil {
+		t.Errorf("cabled switch was deleted: %v", err)
+	}
+	if _, err = env.service.GetPortByID(ctx, switchID, portsIDs[0]); err != nil {
+		t.Errorf("cabled switch port was deleted: %v", err)
+	}
+	networkInterface.EthernetSwitchID = uuid.Nil
+	networkInterface.EthernetSwitchPortID = uuid.Nil
+	if _, err = env.interfaceRepo.Update(ctx, networkInterface); err != nil {
+		t.Fatalf("uncable network interface failed: %v", err)
+	}
+	if err = env.service.Delete(ctx, switchID); err != nil {
+		t.Errorf("delete uncabled switch failed: %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package tests
+
+import (
+	"fmt"
+	"rol/app/interfaces"
+	"rol/infrastructure"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+//tSwitchCLIConnection fake switch CLI connection that records sent commands
+type tSwitchCLIConnection struct {
+	mutex    sync.Mutex
+	commands []string
+	closed   bool
+}
+
+func (c *tSwitchCLIConnection) Connect(address string) error {
+	return nil
+}
+
+func (c *tSwitchCLIConnection) Read(expect string) (string, error) {
+	return "", nil
+}
+
+func (c *tSwitchCLIConnection) Send(command string) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.commands = append(c.commands, command)
+	return nil
+}
+
+func (c *tSwitchCLIConnection) Close() error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.closed = true
+	return nil
+}
+
+func (c *tSwitchCLIConnection) isClosed() bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.closed
+}
+
+func (c *tSwitchCLIConnection) commandsCount(command string) int {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	count := 0
+	for _, sent := range c.commands {
+		if sent == command {
+			count++
+		}
+	}
+	return count
+}
+
+//tSwitchCLISessionOpener opens fake connections and counts them
+type tSwitchCLISessionOpener struct {
+	opened []*tSwitchCLIConnection
+}
+
+func (o *tSwitchCLISessionOpener) open() (interfaces.ISwitchCLIConnection, error) {
+	conn := &tSwitchCLIConnection{}
+	o.opened = append(o.opened, conn)
+	return conn, nil
+}
+
+func switchCLIKeepAlive(conn interfaces.ISwitchCLIConnection) error {
+	return conn.Send("")
+}
+
+func Test_SwitchCLISessionPool_SessionIsReused(t *testing.T) {
+	pool := infrastructure.NewSwitchCLISessionPool(time.Minute, time.Minute)
+	defer pool.Close()
+	opener := &tSwitchCLISessionOpener{}
+	for i := 0; i < 10; i++ {
+		err := pool.Do("switch", opener.open, switchCLIKeepAlive, func(conn interfaces.ISwitchCLIConnection) error {
+			return conn.Send(fmt.Sprintf("command %d", i))
+		})
+		if err != nil {
+			t.Errorf("action failed: %v", err)
+			return
+		}
+	}
+	if len(opener.opened) != 1 {
+		t.Errorf("expect one session, got %d", len(opener.opened))
+	}
+	err := pool.Do("other switch", opener.open, switchCLIKeepAlive, func(conn interfaces.ISwitchCLIConnection) error {
+		return nil
+	})
+	if err != nil || len(opener.opened) != 2 {
+		t.Errorf("expect separate session for other switch, sessions: %d, error: %v", len(opener.opened), err)
+	}
+}
+
+func Test_SwitchCLISessionPool_FailedActionClosesSession(t *testing.T) {
+	pool := infrastructure.NewSwitchCLISessionPool(time.Minute, time.Minute)
+	defer pool.Close()
+	opener := &tSwitchCLISessionOpener{}
+	err := pool.Do("switch", opener.open, switchCLIKeepAlive, func(conn interfaces.ISwitchCLIConnection) error {
+		return fmt.Errorf("action error")
+	})
+	if err == nil {
+		t.Error("expect action error")
+	}
+	if !opener.opened[0].isClosed() {
+		t.Error("expect session is closed after failed action")
+	}
+	_ = pool.Do("switch", opener.open, switchCLIKeepAlive, func(conn interfaces.ISwitchCLIConnection) error {
+		return nil
+	})
+	if len(opener.opened) != 2 {
+		t.Errorf("expect new session after failed action, got %d sessions", len(opener.opened))
+	}
+}
+
+func Test_SwitchCLISessionPool_KeepAliveAndIdleTimeout(t *testing.T) {
+	pool := infrastructure.NewSwitchCLISessionPool(300*time.Millisecond, 50*time.Millisecond)
+	defer pool.Close()
+	opener := &tSwitchCLISessionOpener{}
+	err := pool.Do("switch", opener.open, switchCLIKeepAlive, func(conn interfaces.ISwitchCLIConnection) error {
+		return nil
+	})
+	if err != nil {
+		t.Errorf("action failed: %v", err)
+		return
+	}
+	conn := opener.opened[0]
+	time.Sleep(200 * time.Millisecond)
+	if conn.isClosed() || conn.commandsCount("") == 0 {
+		t.Errorf("expect opened session with keep-alive commands, closed: %v", conn.isClosed())
+	}
+	time.Sleep(400 * time.Millisecond)
+	if !conn.isClosed() {
+		t.Error("expect idle session is closed")
+	}
+}
+
+func Test_SwitchCLISessionPool_SessionKeyChangesWithCredentials(t *testing.T) {
+	key := infrastructure.SwitchCLISessionKey("admin", "10.0.0.1:22", "ssh", "pass", "SHA256:abc")
+	if key != infrastructure.SwitchCLISessionKey("admin", "10.0.0.1:22", "ssh", "pass", "SHA256:abc") {
+		t.Error("expect the same key for the same credentials")
+	}
+	changed := map[string]string{
+		"password":             infrastructure.SwitchCLISessionKey("admin", "10.0.0.1:22", "ssh", "new pass", "SHA256:abc"),
+		"host key fingerprint": infrastructure.SwitchCLISessionKey("admin", "10.0.0.1:22", "ssh", "pass", "SHA256:def"),
+		"transport":            infrastructure.SwitchCLISessionKey("admin", "10.0.0.1:22", "telnet", "pass", "SHA256:abc"),
+		"login":                infrastructure.SwitchCLISessionKey("root", "10.0.0.1:22", "ssh", "pass", "SHA256:abc"),
+	}
+	for change, changedKey := range changed {
+		if changedKey == key {
+			t.Errorf("expect new session key after %s change", change)
+		}
+	}
+	if strings.Contains(key, "pass") {
+		t.Errorf("session key contains password: %s", key)
+	}
+}
@@ -60,7 +60,7 @@ func Test_TFTPServerService_Prepare(t *testing.T) {
 	logger := logrus.New()
 	tftpTester.configRepo = infrastructure.NewGormGenericRepository[uuid.UUID, domain.TFTPConfig](testGenDb, logger)
 	tftpTester.pathsRepo = infrastructure.NewGormGenericRepository[uuid.UUID, domain.TFTPPathRatio](testGenDb, logger)
-	factory, _ := infrastructure.NewPinTFTPServerFactory()
+	factory, _ := infrastructure.NewPinTFTPServerFactory(nil, nil)
 	tftpTester.service = services.NewTFTPServerService(tftpTester.configRepo, tftpTester.pathsRepo, factory, logger)
 	if err != nil {
 		t.Errorf("create new service failed: %q", err)
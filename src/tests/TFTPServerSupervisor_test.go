@@ -0,0 +1,120 @@
+package tests
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"net"
+	"os"
+	"rol/app/services"
+	"rol/domain"
+	"rol/dtos"
+	"rol/infrastructure"
+	"testing"
+	"time"
+)
+
+const tftpSupervisorTestAddress = "127.0.0.1:16974"
+
+func Test_PinTFTPServer_StartBindError(t *testing.T) {
+	blocker, err := net.ListenPacket("udp", tftpSupervisorTestAddress)
+	if err != nil {
+		t.Errorf("failed to occupy port: %v", err)
+		return
+	}
+	defer blocker.Close()
+	server, _ := infrastructure.NewPinTFTPServer(domain.TFTPConfig{Address: "127.0.0.1", Port: "16974", Enabled: true}, nil, nil)
+	err = server.Start()
+	if err == nil {
+		server.Stop()
+		t.Error("server is started on the busy port")
+		return
+	}
+	if server.GetState() != domain.TFTPStateError || server.GetLastError() == "" {
+		t.Errorf("unexpected state after bind error: %s, last error: %q", server.GetState().String(), server.GetLastError())
+	}
+	_ = blocker.Close()
+	if err = server.Start(); err != nil {
+		t.Errorf("failed to start server on the released port: %v", err)
+		return
+	}
+	defer server.Stop()
+	if server.GetState() != domain.TFTPStateLaunched || server.GetLastError() != "" {
+		t.Errorf("unexpected state after start: %s, last error: %q", server.GetState().String(), server.GetLastError())
+	}
+}
+
+func Test_PinTFTPServer_StopRightAfterStart(t *testing.T) {
+	server, _ := infrastructure.NewPinTFTPServer(domain.TFTPConfig{Address: "127.0.0.1", Port: "16974", Enabled: true}, nil, nil)
+	for i := 0; i < 100; i++ {
+		if err := server.Start(); err != nil {
+			t.Fatalf("start %d failed: %v", i, err)
+		}
+		server.Stop()
+		if server.GetState() != domain.TFTPStateStopped {
+			t.Fatalf("unexpected state after stop: %s", server.GetState().String())
+		}
+	}
+}
+
+func Test_TFTPServerService_SupervisorRestart(t *testing.T) {
+	dbPath := "tftpServerSupervisor_test.db"
+	_ = os.Remove(dbPath)
+	defer os.Remove(dbPath)
+	testGenDb, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{})
+	if err != nil {
+		t.Errorf("creating db failed: %v", err)
+		return
+	}
+	if err = testGenDb.AutoMigrate(new(domain.TFTPConfig), new(domain.TFTPPathRatio)); err != nil {
+		t.Errorf("migration failed: %v", err)
+		return
+	}
+	logger := logrus.New()
+	configsRepo := infrastructure.NewGormGenericRepository[uuid.UUID, domain.TFTPConfig](testGenDb, logger)
+	pathsRepo := infrastructure.NewGormGenericRepository[uuid.UUID, domain.TFTPPathRatio](testGenDb, logger)
+	factory, _ := infrastructure.NewPinTFTPServerFactory(nil, nil)
+	service := services.NewTFTPServerService(configsRepo, pathsRepo, factory, logger)
+	if err = services.TFTPServerServiceInit(service); err != nil {
+		t.Errorf("service init failed: %v", err)
+		return
+	}
+
+	blocker, err := net.ListenPacket("udp", tftpSupervisorTestAddress)
+	if err != nil {
+		t.Errorf("failed to occupy port: %v", err)
+		return
+	}
+	ctx := context.Background()
+	server, err := service.CreateServer(ctx, dtos.TFTPServerCreateDto{
+		TFTPServerBaseDto: dtos.TFTPServerBaseDto{Address: "127.0.0.1", Port: "16974", Enabled: true},
+	})
+	if err != nil {
+		_ = blocker.Close()
+		t.Errorf("create server failed: %v", err)
+		return
+	}
+	defer service.DeleteServer(ctx, server.ID)
+	if server.State != domain.TFTPStateError.String() || server.LastError == "" {
+		t.Errorf("bind error is not returned, state: %s, last error: %q", server.State, server.LastError)
+	}
+	_ = blocker.Close()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		server, err = service.GetServerByID(ctx, server.ID)
+		if err != nil {
+			t.Errorf("get server failed: %v", err)
+			return
+		}
+		if server.State == domain.TFTPStateLaunched.String() {
+			break
+		}
+		time.Sleep(200 * time.Millisecond)
+	}
+	if server.State != domain.TFTPStateLaunched.String() || server.LastError != "" {
+		t.Errorf("server is not restarted by the supervisor, state: %s, last error: %q", server.State, server.LastError)
+	}
+}
@@ -0,0 +1,84 @@
+package tests
+
+import (
+	"os"
+	"reflect"
+	"rol/domain"
+	"rol/infrastructure"
+	"strings"
+	"testing"
+)
+
+//tTPLinkLLDPConnection fake TP-Link CLI connection that answers LLDP neighbor information commands
+type tTPLinkLLDPConnection struct {
+	//outputs LLDP neighbor information output by the port number
+	outputs map[string]string
+	pending string
+}
+
+func (c *tTPLinkLLDPConnection) Connect(address string) error {
+	return nil
+}
+
+func (c *tTPLinkLLDPConnection) Read(expect string) (string, error) {
+	index := strings.Index(c.pending, expect)
+	if index < 0 {
+		out := c.pending
+		c.pending = ""
+		return out, nil
+	}
+	out := c.pending[:index+len(expect)]
+	c.pending = c.pending[index+len(expect):]
+	return out, nil
+}
+
+func (c *tTPLinkLLDPConnection) Send(command string) error {
+	portNumber := strings.TrimPrefix(command, "show lldp neighbor-information interface gigabitEthernet ")
+	if portNumber == command {
+		return nil
+	}
+	output, ok := c.outputs[portNumber]
+	if !ok {
+		output = "\r\nLLDP Neighbor Information\r\n\r\ngi" + portNumber + "\r\n"
+	}
+	c.pending += output + "\n\r"
+	return nil
+}
+
+func (c *tTPLinkLLDPConnection) Close() error {
+	return nil
+}
+
+func Test_TPLinkEthernetSwitchManager_GetLLDPNeighbors(t *testing.T) {
+	//neighbor information in the TP-Link CLI layout, keys are padded to the colon column
+	output, err := os.ReadFile("testdata/tplink_lldp_neighbor_information.txt")
+	if err != nil {
+		t.Errorf("read fixture failed: %v", err)
+		return
+	}
+	conn := &tTPLinkLLDPConnection{outputs: map[string]string{"1/0/5": string(output)}}
+	manager := infrastructure.NewTPLinkEthernetSwitchManagerWithConnection(conn, "127.0.0.1:23", "admin", "admin")
+	neighbors, err := manager.GetLLDPNeighbors()
+	if err != nil {
+		t.Errorf("get lldp neighbors failed: %v", err)
+		return
+	}
+	expected := []domain.EthernetSwitchLLDPNeighbor{{
+		PortName:          "gi1/0/5",
+		ChassisID:         "00:0a:eb:13:23:97",
+		PortID:            "gi1/0/24",
+		PortDescription:   "gigabitEthernet 1/0/24 : copper",
+		SystemName:        "T2600G-28TS",
+		ManagementAddress: "192.168.0.1",
+	}, {
+		PortName:          "gi1/0/5",
+		ChassisID:         "52:54:00:12:34:56",
+		PortID:            "52:54:00:12:34:56",
+		PortDescription:   "eth0",
+		SystemName:        "node-01",
+		ManagementAddress: "10.10.10.5",
+	}}
+	if !reflect.DeepEqual(neighbors, expected) {
+		t.Errorf("unexpected lldp neighbors: %+v", neighbors)
+	}
+}
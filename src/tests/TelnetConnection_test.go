@@ -0,0 +1,69 @@
+package tests
+
+import (
+	"net"
+	"rol/infrastructure"
+	"testing"
+	"time"
+)
+
+func Test_TelnetConnection_Read(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Errorf("listen failed: %v", err)
+		return
+	}
+	defer listener.Close()
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		//option negotiation and subnegotiation are sent before the login prompt
+		_, _ = conn.Write([]byte{255, 251, 1, 255, 250, 24, 1, 255, 240})
+		_, _ = conn.Write([]byte("Login:"))
+		buffer := make([]byte, 64)
+		n, _ := conn.Read(buffer)
+		received <- string(buffer[:n])
+		_, _ = conn.Write([]byte("first\r\nsecond\r\nsw1#"))
+		time.Sleep(time.Second)
+	}()
+
+	conn := infrastructure.NewTelnetConnection()
+	if err = conn.Connect(listener.Addr().String()); err != nil {
+		t.Errorf("connect failed: %v", err)
+		return
+	}
+	defer conn.Close()
+	read := func(expect string) string {
+		result := make(chan string, 1)
+		go func() {
+			out, _ := conn.Read(expect)
+			result <- out
+		}()
+		select {
+		case out := <-result:
+			return out
+		case <-time.After(500 * time.Millisecond):
+			t.Errorf("read of %q is blocked", expect)
+			return ""
+		}
+	}
+	//nothing is sent after the prompt until the answer
+	if out := read("Login:"); out != "Login:" {
+		t.Errorf("unexpected login prompt: %q", out)
+		return
+	}
+	_ = conn.Send("admin")
+	if command := <-received; command != "admin\r\n" {
+		t.Errorf("unexpected sent command: %q", command)
+	}
+	//output after the expect word is kept for the next reads
+	for _, expected := range []string{"first\r\n", "second\r\n", "sw1#"} {
+		if out := read(expected[len(expected)-2:]); out != expected {
+			t.Errorf("unexpected output: %q, expect %q", out, expected)
+		}
+	}
+}
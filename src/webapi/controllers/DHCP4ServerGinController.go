@@ -28,6 +28,18 @@ func RegisterDHCP4ServerGinController(controller *DHCP4ServerGinController, serv
 	groupRoute.POST("/dhcp/:id/lease", controller.CreateLease)
 	groupRoute.PUT("/dhcp/:id/lease/:leaseID", controller.UpdateLease)
 	groupRoute.DELETE("/dhcp/:id/lease/:leaseID", controller.DeleteServer)
+	//Reservations
+	groupRoute.GET("/dhcp/:id/reservation", controller.GetReservationList)
+	groupRoute.GET("/dhcp/:id/reservation/:reservationID", controller.GetReservationByID)
+	groupRoute.POST("/dhcp/:id/reservation", controller.CreateReservation)
+	groupRoute.PUT("/dhcp/:id/reservation/:reservationID", controller.UpdateReservation)
+	groupRoute.DELETE("/dhcp/:id/reservation/:reservationID", controller.DeleteReservation)
+	//Boot rules
+	groupRoute.GET("/dhcp/:id/boot-rule", controller.GetBootRuleList)
+	groupRoute.GET("/dhcp/:id/boot-rule/:ruleID", controller.GetBootRuleByID)
+	groupRoute.POST("/dhcp/:id/boot-rule", controller.CreateBootRule)
+	groupRoute.PUT("/dhcp/:id/boot-rule/:ruleID", controller.UpdateBootRule)
+	groupRoute.DELETE("/dhcp/:id/boot-rule/:ruleID", controller.DeleteBootRule)
 }
 
 //NewDHCP4ServerGinController dhcp v4 server controller constructor. Parameters pass through DI
@@ -333,3 +345,326 @@ func (e *DHCP4ServerGinController) DeleteLease(ctx *gin.Context) {
 	err = e.service.DeleteLease(ctx, serverID, leaseID)
 	handle(ctx, err)
 }
+
+//GetReservationList get list of dhcp v4 reservations with search and pagination
+//	Params
+//	ctx - gin context
+// @Summary Get paginated list of dhcp v4 server reservations
+// @version 1.0
+// @Tags	dhcp
+// @Accept  json
+// @Produce json
+// @param	id				path	string	true	"DHCP v4 server ID"
+// @param	orderBy			query	string	false	"Order by field"
+// @param	orderDirection	query	string	false	"'asc' or 'desc' for ascending or descending order"
+// @param	search			query	string	false	"Searchable value in entity"
+// @param	page			query	int		false	"Page number"
+// @param	pageSize		query	int		false	"Number of entities per page"
+// @Success	200		{object}	dtos.PaginatedItemsDto[dtos.DHCP4ReservationDto]
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /dhcp/{id}/reservation [get]
+func (e *DHCP4ServerGinController) GetReservationList(ctx *gin.Context) {
+	req := newPaginatedRequestStructForParsing(1, 10, "IP", "asc", "")
+	err := parseGinRequest(ctx, &req)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	paginatedList, err := e.service.GetReservationList(ctx, serverID, req.Search, req.OrderBy, req.OrderDirection,
+		req.Page, req.PageSize)
+	handleWithData(ctx, err, paginatedList)
+}
+
+//GetReservationByID get dhcp v4 reservation by id
+//	Params
+//	ctx - gin context
+// @Summary	Get dhcp v4 reservation by id
+// @version 1.0
+// @Tags	dhcp
+// @Accept	json
+// @Produce	json
+// @param	id				path		string		true	"DHCP v4 server ID"
+// @param	reservationID	path		string		true	"DHCP v4 reservation ID"
+// @Success	200			{object}	dtos.DHCP4ReservationDto
+// @Failure	404			"Not Found"
+// @Failure	500			"Internal Server Error"
+// @router /dhcp/{id}/reservation/{reservationID} [get]
+func (e *DHCP4ServerGinController) GetReservationByID(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	reservationID, err := parseUUIDParam(ctx, "reservationID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := e.service.GetReservationByID(ctx, serverID, reservationID)
+	handleWithData(ctx, err, dto)
+}
+
+//CreateReservation new DHCP v4 reservation
+//	Params
+//	ctx - gin context
+// @Summary	Create DHCP v4 reservation
+// @version	1.0
+// @Tags	dhcp
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"DHCP v4 server ID"
+// @Param	request	body		dtos.DHCP4ReservationCreateDto	true	"DHCP v4 reservation fields"
+// @Success	200		{object}	dtos.DHCP4ReservationDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /dhcp/{id}/reservation [post]
+func (e *DHCP4ServerGinController) CreateReservation(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.DHCP4ReservationCreateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+
+	dto, err := e.service.CreateReservation(ctx, serverID, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//UpdateReservation DHCP v4 reservation by id
+//	Params
+//	ctx - gin context
+// @Summary	Updates DHCP v4 reservation by id
+// @version	1.0
+// @Tags	dhcp
+// @Accept	json
+// @Produce	json
+// @param	id				path		string		true	"DHCP v4 server ID"
+// @param	reservationID	path		string		true	"DHCP v4 reservation ID"
+// @Param	request			body		dtos.DHCP4ReservationUpdateDto true "DHCP v4 reservation fields"
+// @Success	200		{object}	dtos.DHCP4ReservationDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /dhcp/{id}/reservation/{reservationID} [put]
+func (e *DHCP4ServerGinController) UpdateReservation(ctx *gin.Context) {
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.DHCP4ReservationUpdateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	reservationID, err := parseUUIDParam(ctx, "reservationID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+
+	dto, err := e.service.UpdateReservation(ctx, serverID, reservationID, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//DeleteReservation deleting dhcp v4 reservation
+//	Params
+//	ctx - gin context
+// @Summary	Delete dhcp v4 reservation by id
+// @version	1.0
+// @Tags	dhcp
+// @Accept	json
+// @Produce	json
+// @param	id				path	string		true	"DHCP v4 server ID"
+// @param	reservationID	path	string		true	"DHCP v4 reservation ID"
+// @Success	204		"OK, but No Content"
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /dhcp/{id}/reservation/{reservationID} [delete]
+func (e *DHCP4ServerGinController) DeleteReservation(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	reservationID, err := parseUUIDParam(ctx, "reservationID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+
+	err = e.service.DeleteReservation(ctx, serverID, reservationID)
+	handle(ctx, err)
+}
+//GetBootRuleList get list of dhcp v4 boot rules with search and pagination
+//	Params
+//	ctx - gin context
+// @Summary Get paginated list of dhcp v4 server boot rules
+// @version 1.0
+// @Tags	dhcp
+// @Accept  json
+// @Produce json
+// @param	id				path	string	true	"DHCP v4 server ID"
+// @param	orderBy			query	string	false	"Order by field"
+// @param	orderDirection	query	string	false	"'asc' or 'desc' for ascending or descending order"
+// @param	search			query	string	false	"Searchable value in entity"
+// @param	page			query	int		false	"Page number"
+// @param	pageSize		query	int		false	"Number of entities per page"
+// @Success	200		{object}	dtos.PaginatedItemsDto[dtos.DHCP4BootRuleDto]
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /dhcp/{id}/boot-rule [get]
+func (e *DHCP4ServerGinController) GetBootRuleList(ctx *gin.Context) {
+	req := newPaginatedRequestStructForParsing(1, 10, "Priority", "asc", "")
+	err := parseGinRequest(ctx, &req)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	paginatedList, err := e.service.GetBootRuleList(ctx, serverID, req.Search, req.OrderBy, req.OrderDirection,
+		req.Page, req.PageSize)
+	handleWithData(ctx, err, paginatedList)
+}
+
+//GetBootRuleByID get dhcp v4 boot rule by id
+//	Params
+//	ctx - gin context
+// @Summary	Get dhcp v4 boot rule by id
+// @version 1.0
+// @Tags	dhcp
+// @Accept	json
+// @Produce	json
+// @param	id				path		string		true	"DHCP v4 server ID"
+// @param	ruleID	path		string		true	"DHCP v4 boot rule ID"
+// @Success	200			{object}	dtos.DHCP4BootRuleDto
+// @Failure	404			"Not Found"
+// @Failure	500			"Internal Server Error"
+// @router /dhcp/{id}/boot-rule/{ruleID} [get]
+func (e *DHCP4ServerGinController) GetBootRuleByID(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	ruleID, err := parseUUIDParam(ctx, "ruleID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := e.service.GetBootRuleByID(ctx, serverID, ruleID)
+	handleWithData(ctx, err, dto)
+}
+
+//CreateBootRule new DHCP v4 boot rule
+//	Params
+//	ctx - gin context
+// @Summary	Create DHCP v4 boot rule
+// @version	1.0
+// @Tags	dhcp
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"DHCP v4 server ID"
+// @Param	request	body		dtos.DHCP4BootRuleCreateDto	true	"DHCP v4 boot rule fields"
+// @Success	200		{object}	dtos.DHCP4BootRuleDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /dhcp/{id}/boot-rule [post]
+func (e *DHCP4ServerGinController) CreateBootRule(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.DHCP4BootRuleCreateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+
+	dto, err := e.service.CreateBootRule(ctx, serverID, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//UpdateBootRule DHCP v4 boot rule by id
+//	Params
+//	ctx - gin context
+// @Summary	Updates DHCP v4 boot rule by id
+// @version	1.0
+// @Tags	dhcp
+// @Accept	json
+// @Produce	json
+// @param	id				path		string		true	"DHCP v4 server ID"
+// @param	ruleID	path		string		true	"DHCP v4 boot rule ID"
+// @Param	request			body		dtos.DHCP4BootRuleUpdateDto true "DHCP v4 boot rule fields"
+// @Success	200		{object}	dtos.DHCP4BootRuleDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /dhcp/{id}/boot-rule/{ruleID} [put]
+func (e *DHCP4ServerGinController) UpdateBootRule(ctx *gin.Context) {
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.DHCP4BootRuleUpdateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	ruleID, err := parseUUIDParam(ctx, "ruleID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+
+	dto, err := e.service.UpdateBootRule(ctx, serverID, ruleID, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//DeleteBootRule deleting dhcp v4 boot rule
+//	Params
+//	ctx - gin context
+// @Summary	Delete dhcp v4 boot rule by id
+// @version	1.0
+// @Tags	dhcp
+// @Accept	json
+// @Produce	json
+// @param	id				path	string		true	"DHCP v4 server ID"
+// @param	ruleID	path	string		true	"DHCP v4 boot rule ID"
+// @Success	204		"OK, but No Content"
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /dhcp/{id}/boot-rule/{ruleID} [delete]
+func (e *DHCP4ServerGinController) DeleteBootRule(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	ruleID, err := parseUUIDParam(ctx, "ruleID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+
+	err = e.service.DeleteBootRule(ctx, serverID, ruleID)
+	handle(ctx, err)
+}
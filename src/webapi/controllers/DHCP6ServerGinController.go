@@ -0,0 +1,335 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"rol/app/services"
+	"rol/dtos"
+	"rol/webapi"
+)
+
+//DHCP6ServerGinController DHCP v6 server GIN controller constructor
+type DHCP6ServerGinController struct {
+	service *services.DHCP6ServerService
+	logger  *logrus.Logger
+}
+
+//RegisterDHCP6ServerGinController registers controller for the DHCP v6 servers
+func RegisterDHCP6ServerGinController(controller *DHCP6ServerGinController, server *webapi.GinHTTPServer) {
+	groupRoute := server.Engine.Group("/api/v1")
+	groupRoute.GET("/dhcp6/", controller.GetServersList)
+	groupRoute.GET("/dhcp6/:id", controller.GetServerByID)
+	groupRoute.POST("/dhcp6", controller.CreateServer)
+	groupRoute.PUT("/dhcp6/:id", controller.UpdateServer)
+	groupRoute.DELETE("/dhcp6/:id", controller.DeleteServer)
+	//Leases
+	groupRoute.GET("/dhcp6/:id/lease", controller.GetLeaseList)
+	groupRoute.GET("/dhcp6/:id/lease/:leaseID", controller.GetLeaseByID)
+	groupRoute.POST("/dhcp6/:id/lease", controller.CreateLease)
+	groupRoute.PUT("/dhcp6/:id/lease/:leaseID", controller.UpdateLease)
+	groupRoute.DELETE("/dhcp6/:id/lease/:leaseID", controller.DeleteLease)
+}
+
+//NewDHCP6ServerGinController dhcp v6 server controller constructor. Parameters pass through DI
+//Params
+//	service - dhcp v6 server service
+//	log - logrus logger
+//Return
+//	*DHCP6ServerGinController - Gin controller for dhcp v6 servers
+func NewDHCP6ServerGinController(service *services.DHCP6ServerService, log *logrus.Logger) *DHCP6ServerGinController {
+	switchContr := &DHCP6ServerGinController{
+		service: service,
+		logger:  log,
+	}
+	return switchContr
+}
+
+//GetServersList get list of dhcp v6 servers with search and pagination
+//	Params
+//	ctx - gin context
+// @Summary Get paginated list of dhcp v6 servers
+// @version 1.0
+// @Tags	dhcp6
+// @Accept  json
+// @Produce json
+// @param	orderBy			query	string	false	"Order by field"
+// @param	orderDirection	query	string	false	"'asc' or 'desc' for ascending or descending order"
+// @param	search			query	string	false	"Searchable value in entity"
+// @param	page			query	int		false	"Page number"
+// @param	pageSize		query	int		false	"Number of entities per page"
+// @Success	200		{object}	dtos.PaginatedItemsDto[dtos.DHCP6ServerDto]
+// @Failure	500		"Internal Server Error"
+// @router /dhcp6/ [get]
+func (e *DHCP6ServerGinController) GetServersList(ctx *gin.Context) {
+	req := newPaginatedRequestStructForParsing(1, 10, "CreatedAt", "asc", "")
+	err := parseGinRequest(ctx, &req)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	paginatedList, err := e.service.GetServerList(ctx, req.Search, req.OrderBy, req.OrderDirection,
+		req.Page, req.PageSize)
+	handleWithData(ctx, err, paginatedList)
+}
+
+//GetServerByID get dhcp v6 server by id
+//	Params
+//	ctx - gin context
+// @Summary	Get dhcp v6 server by id
+// @version 1.0
+// @Tags	dhcp6
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"DHCP v6 server ID"
+// @Success	200		{object}	dtos.DHCP6ServerDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /dhcp6/{id} [get]
+func (e *DHCP6ServerGinController) GetServerByID(ctx *gin.Context) {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := e.service.GetServerByID(ctx, id)
+	handleWithData(ctx, err, dto)
+}
+
+//CreateServer new DHCP v6 server
+//	Params
+//	ctx - gin context
+// @Summary	Create DHCP v6 server
+// @version	1.0
+// @Tags	dhcp6
+// @Accept	json
+// @Produce	json
+// @Param	request	body		dtos.DHCP6ServerCreateDto	true	"DHCP v6 server fields"
+// @Success	200		{object}	dtos.DHCP6ServerDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	500		"Internal Server Error"
+// @router /dhcp6/ [post]
+func (e *DHCP6ServerGinController) CreateServer(ctx *gin.Context) {
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.DHCP6ServerCreateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+
+	dto, err := e.service.CreateServer(ctx, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//UpdateServer DHCP v6 server by id
+//	Params
+//	ctx - gin context
+// @Summary	Updates DHCP v6 server by id
+// @version	1.0
+// @Tags	dhcp6
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"DHCP v6 server ID"
+// @Param	request	body		dtos.DHCP6ServerUpdateDto true "DHCP v6 server fields"
+// @Success	200		{object}	dtos.DHCP6ServerDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /dhcp6/{id} [put]
+func (e *DHCP6ServerGinController) UpdateServer(ctx *gin.Context) {
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.DHCP6ServerUpdateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+
+	dto, err := e.service.UpdateServer(ctx, id, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//DeleteServer deleting dhcp v6 server
+//	Params
+//	ctx - gin context
+// @Summary	Delete dhcp v6 server by id
+// @version	1.0
+// @Tags	dhcp6
+// @Accept	json
+// @Produce	json
+// @param	id		path	string		true	"DHCP v6 server ID"
+// @Success	204		"OK, but No Content"
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /dhcp6/{id} [delete]
+func (e *DHCP6ServerGinController) DeleteServer(ctx *gin.Context) {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+
+	err = e.service.DeleteServer(ctx, id)
+	handle(ctx, err)
+}
+
+//GetLeaseList get list of dhcp v6 leases with search and pagination
+//	Params
+//	ctx - gin context
+// @Summary Get paginated list of dhcp v6 server leases
+// @version 1.0
+// @Tags	dhcp6
+// @Accept  json
+// @Produce json
+// @param	id				path	string	true	"DHCP v6 server ID"
+// @param	orderBy			query	string	false	"Order by field"
+// @param	orderDirection	query	string	false	"'asc' or 'desc' for ascending or descending order"
+// @param	search			query	string	false	"Searchable value in entity"
+// @param	page			query	int		false	"Page number"
+// @param	pageSize		query	int		false	"Number of entities per page"
+// @Success	200		{object}	dtos.PaginatedItemsDto[dtos.DHCP6LeaseDto]
+// @Failure	500		"Internal Server Error"
+// @router /dhcp6/{id}/lease [get]
+func (e *DHCP6ServerGinController) GetLeaseList(ctx *gin.Context) {
+	req := newPaginatedRequestStructForParsing(1, 10, "CreatedAt", "asc", "")
+	err := parseGinRequest(ctx, &req)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	paginatedList, err := e.service.GetLeaseList(ctx, serverID, req.Search, req.OrderBy, req.OrderDirection,
+		req.Page, req.PageSize)
+	handleWithData(ctx, err, paginatedList)
+}
+
+//GetLeaseByID get dhcp v6 lease by id
+//	Params
+//	ctx - gin context
+// @Summary	Get dhcp v6 lease by id
+// @version 1.0
+// @Tags	dhcp6
+// @Accept	json
+// @Produce	json
+// @param	id			path		string		true	"DHCP v6 server ID"
+// @param	leaseID		path		string		true	"DHCP v6 lease ID"
+// @Success	200			{object}	dtos.DHCP6LeaseDto
+// @Failure	404			"Not Found"
+// @Failure	500			"Internal Server Error"
+// @router /dhcp6/{id}/lease/{leaseID} [get]
+func (e *DHCP6ServerGinController) GetLeaseByID(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	leaseID, err := parseUUIDParam(ctx, "leaseID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := e.service.GetLeaseByID(ctx, serverID, leaseID)
+	handleWithData(ctx, err, dto)
+}
+
+//CreateLease new DHCP v6 lease
+//	Params
+//	ctx - gin context
+// @Summary	Create DHCP v6 lease
+// @version	1.0
+// @Tags	dhcp6
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"DHCP v6 server ID"
+// @Param	request	body		dtos.DHCP6LeaseCreateDto	true	"DHCP v6 lease fields"
+// @Success	200		{object}	dtos.DHCP6LeaseDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	500		"Internal Server Error"
+// @router /dhcp6/{id}/lease [post]
+func (e *DHCP6ServerGinController) CreateLease(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.DHCP6LeaseCreateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+
+	dto, err := e.service.CreateLease(ctx, serverID, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//UpdateLease DHCP v6 lease by id
+//	Params
+//	ctx - gin context
+// @Summary	Updates DHCP v6 lease by id
+// @version	1.0
+// @Tags	dhcp6
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"DHCP v6 server ID"
+// @param	leaseID	path		string		true	"DHCP v6 lease ID"
+// @Param	request	body		dtos.DHCP6LeaseUpdateDto true "DHCP v6 lease fields"
+// @Success	200		{object}	dtos.DHCP6LeaseDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /dhcp6/{id}/lease/{leaseID} [put]
+func (e *DHCP6ServerGinController) UpdateLease(ctx *gin.Context) {
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.DHCP6LeaseUpdateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	leaseID, err := parseUUIDParam(ctx, "leaseID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+
+	dto, err := e.service.UpdateLease(ctx, serverID, leaseID, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//DeleteLease deleting dhcp v6 lease
+//	Params
+//	ctx - gin context
+// @Summary	Delete dhcp v6 lease by id
+// @version	1.0
+// @Tags	dhcp6
+// @Accept	json
+// @Produce	json
+// @param	id		path	string		true	"DHCP v6 server ID"
+// @param	leaseID	path	string		true	"DHCP v6 lease ID"
+// @Success	204		"OK, but No Content"
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /dhcp6/{id}/lease/{leaseID} [delete]
+func (e *DHCP6ServerGinController) DeleteLease(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	leaseID, err := parseUUIDParam(ctx, "leaseID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+
+	err = e.service.DeleteLease(ctx, serverID, leaseID)
+	handle(ctx, err)
+}
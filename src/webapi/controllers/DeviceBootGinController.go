@@ -0,0 +1,147 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"rol/app/services"
+	"rol/dtos"
+	"rol/webapi"
+)
+
+//DeviceBootGinController device boot API controller for domain.DeviceBoot entity
+type DeviceBootGinController struct {
+	service *services.DeviceBootService
+	logger  *logrus.Logger
+}
+
+//NewDeviceBootGinController device boot controller constructor. Parameters pass through DI
+//Params
+//	service - device boot service
+//	log - logrus logger
+//Return
+//	*DeviceBootGinController - instance of device boot controller
+func NewDeviceBootGinController(service *services.DeviceBootService, log *logrus.Logger) *DeviceBootGinController {
+	return &DeviceBootGinController{
+		service: service,
+		logger:  log,
+	}
+}
+
+//RegisterDeviceBootGinController registers controller for the device boots
+func RegisterDeviceBootGinController(controller *DeviceBootGinController, server *webapi.GinHTTPServer) {
+	groupRoute := server.Engine.Group("/api/v1")
+	groupRoute.POST("/device/:id/boot", controller.Start)
+	groupRoute.GET("/device/:id/boot", controller.GetCurrent)
+	groupRoute.POST("/device/:id/boot/cancel", controller.Cancel)
+	groupRoute.GET("/device/:id/boot/history/", controller.GetList)
+}
+
+//Start starts device boot by the stages of its template
+//	Params
+//	ctx - gin context
+// @Summary	Start device boot
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @param	id		path		string					true	"Device ID"
+// @Param	request	body		dtos.DeviceBootStartDto	true	"Device boot start fields"
+// @Success	200		{object}	dtos.DeviceBootDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /device/{id}/boot [post]
+func (d *DeviceBootGinController) Start(ctx *gin.Context) {
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.DeviceBootStartDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := d.service.StartBoot(ctx, id, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//GetCurrent get the last device boot with its stages
+//	Params
+//	ctx - gin context
+// @Summary	Get current device boot with stages history
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"Device ID"
+// @Success	200		{object}	dtos.DeviceBootDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /device/{id}/boot [get]
+func (d *DeviceBootGinController) GetCurrent(ctx *gin.Context) {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := d.service.GetCurrentBoot(ctx, id)
+	handleWithData(ctx, err, dto)
+}
+
+//Cancel cancels running device boot
+//	Params
+//	ctx - gin context
+// @Summary	Cancel running device boot
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"Device ID"
+// @Success	200		{object}	dtos.DeviceBootDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /device/{id}/boot/cancel [post]
+func (d *DeviceBootGinController) Cancel(ctx *gin.Context) {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := d.service.CancelBoot(ctx, id)
+	handleWithData(ctx, err, dto)
+}
+
+//GetList get list of device boots with pagination
+//	Params
+//	ctx - gin context
+// @Summary	Get paginated list of device boots
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @param	id				path	string	true	"Device ID"
+// @param	orderBy			query	string	false	"Order by field, default value - CreatedAt"
+// @param	orderDirection	query	string	false	"'asc' or 'desc' for ascending or descending order, desc by default"
+// @param	page			query	int		false	"Page number"
+// @param	pageSize		query	int		false	"Number of entities per page"
+// @Success	200		{object}	dtos.PaginatedItemsDto[dtos.DeviceBootDto]
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /device/{id}/boot/history/ [get]
+func (d *DeviceBootGinController) GetList(ctx *gin.Context) {
+	req := newPaginatedRequestStructForParsing(1, 10, "CreatedAt", "desc", "")
+	err := parseGinRequest(ctx, &req)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	deviceID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	paginatedList, err := d.service.GetBootList(ctx, deviceID, req.OrderBy, req.OrderDirection,
+		req.Page, req.PageSize)
+	handleWithData(ctx, err, paginatedList)
+}
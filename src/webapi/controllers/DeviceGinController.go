@@ -0,0 +1,241 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"rol/app/services"
+	"rol/dtos"
+	"rol/webapi"
+)
+
+//DeviceGinController device API controller for domain.Device entity
+type DeviceGinController struct {
+	service *services.DeviceService
+	logger  *logrus.Logger
+}
+
+//NewDeviceGinController device controller constructor. Parameters pass through DI
+//Params
+//	service - device service
+//	log - logrus logger
+//Return
+//	*DeviceGinController - instance of device controller
+func NewDeviceGinController(service *services.DeviceService, log *logrus.Logger) *DeviceGinController {
+	return &DeviceGinController{
+		service: service,
+		logger:  log,
+	}
+}
+
+//RegisterDeviceGinController registers controller for the devices on path /api/v1/device/
+func RegisterDeviceGinController(controller *DeviceGinController, server *webapi.GinHTTPServer) {
+	groupRoute := server.Engine.Group("/api/v1")
+	groupRoute.GET("/device/", controller.GetList)
+	groupRoute.GET("/device/:id", controller.GetByID)
+	groupRoute.POST("/device/", controller.Create)
+	groupRoute.PUT("/device/:id", controller.Update)
+	groupRoute.DELETE("/device/:id", controller.Delete)
+	groupRoute.POST("/device/:id/power/on", controller.PowerOn)
+	groupRoute.POST("/device/:id/power/off", controller.PowerOff)
+	groupRoute.POST("/device/:id/power/cycle", controller.PowerCycle)
+}
+
+//GetList get list of devices with search and pagination
+//	Params
+//	ctx - gin context
+// @Summary	Get paginated list of devices
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @param	orderBy			query	string	false	"Order by field, default value - Name"
+// @param	orderDirection	query	string	false	"'asc' or 'desc' for ascending or descending order, asc by default"
+// @param	search			query	string	false	"Searchable value in entity"
+// @param	page			query	int		false	"Page number"
+// @param	pageSize		query	int		false	"Number of entities per page"
+// @Success	200		{object}	dtos.PaginatedItemsDto[dtos.DeviceDto]
+// @Failure	500		"Internal Server Error"
+// @router /device/ [get]
+func (d *DeviceGinController) GetList(ctx *gin.Context) {
+	req := newPaginatedRequestStructForParsing(1, 10, "Name", "asc", "")
+	err := parseGinRequest(ctx, &req)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	paginatedList, err := d.service.GetList(ctx, req.Search, req.OrderBy, req.OrderDirection,
+		req.Page, req.PageSize)
+	handleWithData(ctx, err, paginatedList)
+}
+
+//GetByID get device by id
+//	Params
+//	ctx - gin context
+// @Summary	Get device by id
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"Device ID"
+// @Success	200		{object}	dtos.DeviceDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /device/{id} [get]
+func (d *DeviceGinController) GetByID(ctx *gin.Context) {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := d.service.GetByID(ctx, id)
+	handleWithData(ctx, err, dto)
+}
+
+//Create new device from the device template
+//	Params
+//	ctx - gin context
+// @Summary	Create new device from the device template
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @Param	request	body		dtos.DeviceCreateDto	true	"Device fields"
+// @Success	200		{object}	dtos.DeviceDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	500		"Internal Server Error"
+// @router /device/ [post]
+func (d *DeviceGinController) Create(ctx *gin.Context) {
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.DeviceCreateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := d.service.Create(ctx, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//Update device by id
+//	Params
+//	ctx - gin context
+// @Summary	Updates device by id
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @param	id		path		string				true	"Device ID"
+// @Param	request	body		dtos.DeviceUpdateDto	true	"Device fields"
+// @Success	200		{object}	dtos.DeviceDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /device/{id} [put]
+func (d *DeviceGinController) Update(ctx *gin.Context) {
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.DeviceUpdateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := d.service.Update(ctx, reqDto, id)
+	handleWithData(ctx, err, dto)
+}
+
+//Delete soft deleting device in database
+//	Params
+//	ctx - gin context
+// @Summary	Delete device by id
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @param	id		path	string		true	"Device ID"
+// @Success	204		"OK, but No Content"
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /device/{id} [delete]
+func (d *DeviceGinController) Delete(ctx *gin.Context) {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	err = d.service.Delete(ctx, id)
+	handle(ctx, err)
+}
+
+//PowerOn power on device through POE on the cabled ethernet switch port
+//	Params
+//	ctx - gin context
+// @Summary	Power on device by id
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"Device ID"
+// @Success	200		{object}	dtos.DeviceDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /device/{id}/power/on [post]
+func (d *DeviceGinController) PowerOn(ctx *gin.Context) {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := d.service.PowerOn(ctx, id)
+	handleWithData(ctx, err, dto)
+}
+
+//PowerOff power off device through POE on the cabled ethernet switch port
+//	Params
+//	ctx - gin context
+// @Summary	Power off device by id
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"Device ID"
+// @Success	200		{object}	dtos.DeviceDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /device/{id}/power/off [post]
+func (d *DeviceGinController) PowerOff(ctx *gin.Context) {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := d.service.PowerOff(ctx, id)
+	handleWithData(ctx, err, dto)
+}
+
+//PowerCycle power cycle device through POE on the cabled ethernet switch port, response is sent after power off
+//and the device is powered on in background
+//	Params
+//	ctx - gin context
+// @Summary	Power cycle device by id, device is powered on in background
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"Device ID"
+// @Success	200		{object}	dtos.DeviceDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /device/{id}/power/cycle [post]
+func (d *DeviceGinController) PowerCycle(ctx *gin.Context) {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := d.service.PowerCycle(ctx, id)
+	handleWithData(ctx, err, dto)
+}
@@ -0,0 +1,135 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"rol/app/services"
+	"rol/dtos"
+	"rol/webapi"
+)
+
+//DeviceNetworkInterfaceGinController device network interface API controller for domain.DeviceNetworkInterface entity
+type DeviceNetworkInterfaceGinController struct {
+	service *services.DeviceService
+	logger  *logrus.Logger
+}
+
+//NewDeviceNetworkInterfaceGinController device network interface controller constructor. Parameters pass through DI
+//Params
+//	service - device service
+//	log - logrus logger
+//Return
+//	*DeviceNetworkInterfaceGinController - instance of device network interface controller
+func NewDeviceNetworkInterfaceGinController(service *services.DeviceService, log *logrus.Logger) *DeviceNetworkInterfaceGinController {
+	return &DeviceNetworkInterfaceGinController{
+		service: service,
+		logger:  log,
+	}
+}
+
+//RegisterDeviceNetworkInterfaceGinController registers controller for the device network interfaces
+func RegisterDeviceNetworkInterfaceGinController(controller *DeviceNetworkInterfaceGinController, server *webapi.GinHTTPServer) {
+	groupRoute := server.Engine.Group("/api/v1")
+	groupRoute.GET("/device/:id/interface/", controller.GetList)
+	groupRoute.GET("/device/:id/interface/:interfaceID", controller.GetByID)
+	groupRoute.PUT("/device/:id/interface/:interfaceID", controller.Update)
+}
+
+//GetList get list of device network interfaces with pagination
+//	Params
+//	ctx - gin context
+// @Summary	Get paginated list of device network interfaces
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @param	id				path	string	true	"Device ID"
+// @param	orderBy			query	string	false	"Order by field, default value - Name"
+// @param	orderDirection	query	string	false	"'asc' or 'desc' for ascending or descending order, asc by default"
+// @param	page			query	int		false	"Page number"
+// @param	pageSize		query	int		false	"Number of entities per page"
+// @Success	200		{object}	dtos.PaginatedItemsDto[dtos.DeviceNetworkInterfaceDto]
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /device/{id}/interface/ [get]
+func (d *DeviceNetworkInterfaceGinController) GetList(ctx *gin.Context) {
+	req := newPaginatedRequestStructForParsing(1, 10, "Name", "asc", "")
+	err := parseGinRequest(ctx, &req)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	deviceID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	paginatedList, err := d.service.GetNetworkInterfaces(ctx, deviceID, req.OrderBy, req.OrderDirection,
+		req.Page, req.PageSize)
+	handleWithData(ctx, err, paginatedList)
+}
+
+//GetByID get device network interface by id
+//	Params
+//	ctx - gin context
+// @Summary	Get device network interface by id
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @param	id			path		string		true	"Device ID"
+// @param	interfaceID	path		string		true	"Device network interface ID"
+// @Success	200		{object}	dtos.DeviceNetworkInterfaceDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /device/{id}/interface/{interfaceID} [get]
+func (d *DeviceNetworkInterfaceGinController) GetByID(ctx *gin.Context) {
+	deviceID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	interfaceID, err := parseUUIDParam(ctx, "interfaceID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := d.service.GetNetworkInterfaceByID(ctx, deviceID, interfaceID)
+	handleWithData(ctx, err, dto)
+}
+
+//Update device network interface mac address and cabling
+//	Params
+//	ctx - gin context
+// @Summary	Updates device network interface by id
+// @version	1.0
+// @Tags	device
+// @Accept	json
+// @Produce	json
+// @param	id			path		string								true	"Device ID"
+// @param	interfaceID	path		string								true	"Device network interface ID"
+// @Param	request		body		dtos.DeviceNetworkInterfaceUpdateDto	true	"Device network interface fields"
+// @Success	200		{object}	dtos.DeviceNetworkInterfaceDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /device/{id}/interface/{interfaceID} [put]
+func (d *DeviceNetworkInterfaceGinController) Update(ctx *gin.Context) {
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.DeviceNetworkInterfaceUpdateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	deviceID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	interfaceID, err := parseUUIDParam(ctx, "interfaceID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := d.service.UpdateNetworkInterface(ctx, deviceID, interfaceID, reqDto)
+	handleWithData(ctx, err, dto)
+}
@@ -0,0 +1,162 @@
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/sirupsen/logrus"
+	"rol/app/services"
+	"rol/webapi"
+)
+
+//EthernetSwitchConfigBackupGinController ethernet switch config backups GIN controller
+type EthernetSwitchConfigBackupGinController struct {
+	service *services.EthernetSwitchService
+	logger  *logrus.Logger
+}
+
+//configBackupsDiffRequest request params of the config backups diff
+type configBackupsDiffRequest struct {
+	SwitchID uuid.UUID `param:"id"`
+	From     int       `query:"from"`
+	To       int       `query:"to"`
+}
+
+//NewEthernetSwitchConfigBackupGinController ethernet switch config backups controller constructor. Parameters pass through DI
+//Params
+//	service - ethernet switch service
+//	log - logrus logger
+//Return
+//	*EthernetSwitchConfigBackupGinController - instance of controller for ethernet switch config backups
+func NewEthernetSwitchConfigBackupGinController(service *services.EthernetSwitchService, log *logrus.Logger) *EthernetSwitchConfigBackupGinController {
+	return &EthernetSwitchConfigBackupGinController{
+		service: service,
+		logger:  log,
+	}
+}
+
+//RegisterEthernetSwitchConfigBackupGinController registers controller for ethernet switch config backups
+func RegisterEthernetSwitchConfigBackupGinController(controller *EthernetSwitchConfigBackupGinController, server *webapi.GinHTTPServer) {
+	groupRoute := server.Engine.Group("/api/v1")
+	groupRoute.GET("/ethernet-switch/:id/config-backup/", controller.GetList)
+	groupRoute.GET("/ethernet-switch/:id/config-backup/diff", controller.GetDiff)
+	groupRoute.GET("/ethernet-switch/:id/config-backup/:backupID", controller.GetByID)
+	groupRoute.POST("/ethernet-switch/:id/config-backup/:backupID/restore", controller.Restore)
+}
+
+//GetList get list of switch config backups with pagination
+//	Params
+//	ctx - gin context
+// @Summary Get paginated list of switch running config backups
+// @version 1.0
+// @Tags ethernet-switch
+// @Accept  json
+// @Produce json
+// @param 	 id 			 path   string  true "Ethernet switch ID"
+// @param	 orderBy		 query	string	false	"Order by field, default value - Version"
+// @param	 orderDirection	 query	string	false	"'asc' or 'desc' for ascending or descending order, desc by default"
+// @param	 page			 query	int		false	"Page number"
+// @param	 pageSize		 query	int		false	"Number of entities per page"
+// @Success 200 {object} dtos.PaginatedItemsDto[dtos.EthernetSwitchConfigBackupDto]
+// @Failure	404		"Not Found"
+// @Failure		500		"Internal Server Error"
+// @router /ethernet-switch/{id}/config-backup [get]
+func (e *EthernetSwitchConfigBackupGinController) GetList(ctx *gin.Context) {
+	req := newPaginatedRequestStructForParsing(1, 10, "Version", "desc", "")
+	err := parseGinRequest(ctx, &req)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	switchID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	paginatedList, err := e.service.GetConfigBackups(ctx, switchID, req.OrderBy, req.OrderDirection, req.Page, req.PageSize)
+	handleWithData(ctx, err, paginatedList)
+}
+
+//GetByID get switch config backup by id
+//	Params
+//	ctx - gin context
+// @Summary Get ethernet switch running config backup by id
+// @version 1.0
+// @Tags 	ethernet-switch
+// @Accept  json
+// @Produce json
+// @param	id			path		string		true	"Ethernet switch ID"
+// @param	backupID	path		string		true	"Config backup ID"
+// @Success 200 	{object} 	dtos.EthernetSwitchConfigBackupDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /ethernet-switch/{id}/config-backup/{backupID} [get]
+func (e *EthernetSwitchConfigBackupGinController) GetByID(ctx *gin.Context) {
+	switchID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	id, err := parseUUIDParam(ctx, "backupID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := e.service.GetConfigBackupByID(ctx, switchID, id)
+	handleWithData(ctx, err, dto)
+}
+
+//GetDiff get unified diff between two switch config backups
+//	Params
+//	ctx - gin context
+// @Summary Get unified diff between two ethernet switch running config backup versions
+// @version 1.0
+// @Tags 	ethernet-switch
+// @Accept  json
+// @Produce json
+// @param	id		path		string		true	"Ethernet switch ID"
+// @param	from	query		int			true	"Original backup version"
+// @param	to		query		int			true	"Changed backup version"
+// @Success 200 	{object} 	dtos.EthernetSwitchConfigDiffDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /ethernet-switch/{id}/config-backup/diff [get]
+func (e *EthernetSwitchConfigBackupGinController) GetDiff(ctx *gin.Context) {
+	req := configBackupsDiffRequest{}
+	err := parseGinRequest(ctx, &req)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := e.service.GetConfigBackupsDiff(ctx, req.SwitchID, req.From, req.To)
+	handleWithData(ctx, err, dto)
+}
+
+//Restore apply switch config backup to the switch
+//	Params
+//	ctx - gin context
+// @Summary Restore ethernet switch running config from the backup, switch VLANs and ports PoE status are imported after restore
+// @version 1.0
+// @Tags 	ethernet-switch
+// @Accept  json
+// @Produce json
+// @param	id			path		string		true	"Ethernet switch ID"
+// @param	backupID	path		string		true	"Config backup ID"
+// @Success 200 	{object} 	dtos.EthernetSwitchConfigBackupDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /ethernet-switch/{id}/config-backup/{backupID}/restore [post]
+func (e *EthernetSwitchConfigBackupGinController) Restore(ctx *gin.Context) {
+	switchID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	id, err := parseUUIDParam(ctx, "backupID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := e.service.RestoreConfigBackup(ctx, switchID, id)
+	handleWithData(ctx, err, dto)
+}
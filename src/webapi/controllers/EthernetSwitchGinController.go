@@ -21,10 +21,15 @@ func RegisterEthernetSwitchController(controller *EthernetSwitchGinController, s
 	groupRoute := server.Engine.Group("/api/v1")
 	groupRoute.GET("/ethernet-switch/", controller.GetList)
 	groupRoute.GET("/ethernet-switch/models/", controller.GetSupportedModels)
+	groupRoute.GET("/ethernet-switch/drift/", controller.GetDriftReports)
 	groupRoute.GET("/ethernet-switch/:id", controller.GetByID)
 	groupRoute.POST("/ethernet-switch", controller.Create)
 	groupRoute.PUT("/ethernet-switch/:id", controller.Update)
 	groupRoute.DELETE("/ethernet-switch/:id", controller.Delete)
+	groupRoute.GET("/ethernet-switch/:id/drift", controller.GetDrift)
+	groupRoute.GET("/ethernet-switch/:id/capabilities", controller.GetCapabilities)
+	groupRoute.POST("/ethernet-switch/:id/reconcile", controller.Reconcile)
+	groupRoute.POST("/ethernet-switch/:id/discover", controller.Discover)
 }
 
 //NewEthernetSwitchGinController ethernet switch controller constructor. Parameters pass through DI
@@ -187,3 +192,119 @@ func (e *EthernetSwitchGinController) GetSupportedModels(ctx *gin.Context) {
 	modelsDtoSlice := e.service.GetSupportedModels()
 	ctx.JSON(http.StatusOK, modelsDtoSlice)
 }
+
+//GetDriftReports get last drift reports of the switches
+//	Params
+//	ctx - gin context
+// @Summary	Get last drift reports of the ethernet switches, reports are updated periodically
+// @version	1.0
+// @Tags	ethernet-switch
+// @Accept	json
+// @Produce	json
+// @Success	200		{object} []dtos.EthernetSwitchDriftDto
+// @router /ethernet-switch/drift/ [get]
+func (e *EthernetSwitchGinController) GetDriftReports(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, e.service.GetDriftReports())
+}
+
+//GetDrift get differences between the switch configuration in the database and the actual switch state
+//	Params
+//	ctx - gin context
+// @Summary	Detect ethernet switch configuration drift
+// @version	1.0
+// @Tags	ethernet-switch
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"Ethernet switch ID"
+// @Success	200		{object}	dtos.EthernetSwitchDriftDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /ethernet-switch/{id}/drift [get]
+func (e *EthernetSwitchGinController) GetDrift(ctx *gin.Context) {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := e.service.GetDrift(ctx, id)
+	handleWithData(ctx, err, dto)
+}
+
+//GetCapabilities get capabilities of the switch from its manager
+//	Params
+//	ctx - gin context
+// @Summary	Get ethernet switch capabilities from the switch
+// @version	1.0
+// @Tags	ethernet-switch
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"Ethernet switch ID"
+// @Success	200		{object}	dtos.EthernetSwitchCapabilitiesDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /ethernet-switch/{id}/capabilities [get]
+func (e *EthernetSwitchGinController) GetCapabilities(ctx *gin.Context) {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := e.service.GetCapabilities(ctx, id)
+	handleWithData(ctx, err, dto)
+}
+
+//Reconcile import switch state to the database or push database state to the switch
+//	Params
+//	ctx - gin context
+// @Summary	Reconcile ethernet switch configuration
+// @version	1.0
+// @Tags	ethernet-switch
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"Ethernet switch ID"
+// @Param	request	body		dtos.EthernetSwitchReconcileDto	true	"Reconciliation mode"
+// @Success	200		{object}	dtos.EthernetSwitchDriftDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /ethernet-switch/{id}/reconcile [post]
+func (e *EthernetSwitchGinController) Reconcile(ctx *gin.Context) {
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.EthernetSwitchReconcileDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := e.service.Reconcile(ctx, id, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//Discover import switch ports and VLANs from the switch
+//	Params
+//	ctx - gin context
+// @Summary	Import ethernet switch ports with their PVID, PoE state and VLANs membership from the switch
+// @version	1.0
+// @Tags	ethernet-switch
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"Ethernet switch ID"
+// @Success	200		{object}	dtos.EthernetSwitchDiscoveryDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /ethernet-switch/{id}/discover [post]
+func (e *EthernetSwitchGinController) Discover(ctx *gin.Context) {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := e.service.Discover(ctx, id)
+	handleWithData(ctx, err, dto)
+}
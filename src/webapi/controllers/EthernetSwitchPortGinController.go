@@ -39,6 +39,7 @@ func RegisterEthernetSwitchPortController(controller *EthernetSwitchPortGinContr
 	groupRoute.POST("/ethernet-switch/:id/port/", controller.CreatePort)
 	groupRoute.PUT("/ethernet-switch/:id/port/:portID", controller.UpdatePort)
 	groupRoute.DELETE("/ethernet-switch/:id/port/:portID", controller.DeletePort)
+	groupRoute.GET("/ethernet-switch/:id/port/:portID/neighbors", controller.GetPortNeighbors)
 }
 
 //GetPortByID Get ethernet switch port by id
@@ -202,3 +203,33 @@ func (e *EthernetSwitchPortGinController) DeletePort(ctx *gin.Context) {
 	err = e.service.DeletePort(ctx, switchID, portID)
 	handle(ctx, err)
 }
+
+//GetPortNeighbors get MAC addresses and LLDP neighbors that are visible on the switch port
+//	Params
+//	ctx - gin context
+// @Summary	Get ethernet switch port neighbors with suggested devices plugged into the port
+// @version	1.0
+// @Tags	ethernet-switch
+// @Accept	json
+// @Produce	json
+// @param	id		path		string		true	"Ethernet switch ID"
+// @param	portID	path		string		true	"Ethernet switch port ID"
+// @Success	200		{object}	dtos.EthernetSwitchPortNeighborsDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /ethernet-switch/{id}/port/{portID}/neighbors [get]
+func (e *EthernetSwitchPortGinController) GetPortNeighbors(ctx *gin.Context) {
+	switchID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	portID, err := parseUUIDParam(ctx, "portID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := e.service.GetPortNeighbors(ctx, switchID, portID)
+	handleWithData(ctx, err, dto)
+}
@@ -0,0 +1,381 @@
+// Package controllers describes controllers for webapi
+package controllers
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"rol/app/services"
+	"rol/dtos"
+	"rol/webapi"
+	"strconv"
+)
+
+//HTTPBootServerGinController http boot server GIN controller constructor
+type HTTPBootServerGinController struct {
+	service *services.HTTPBootServerService
+	logger  *logrus.Logger
+}
+
+//NewHTTPBootServerGinController http boot server controller constructor. Parameters pass through DI
+//
+//Params
+//	service - http boot server service
+//	log - logrus logger
+//Return
+//	*HTTPBootServerGinController - instance of http boot server controller
+func NewHTTPBootServerGinController(service *services.HTTPBootServerService, log *logrus.Logger) *HTTPBootServerGinController {
+	return &HTTPBootServerGinController{
+		service: service,
+		logger:  log,
+	}
+}
+
+//RegisterHTTPBootServerGinController registers controller for managing http boot servers via api
+func RegisterHTTPBootServerGinController(controller *HTTPBootServerGinController, server *webapi.GinHTTPServer) {
+
+	groupRoute := server.Engine.Group("/api/v1")
+
+	groupRoute.GET("/http-boot/", controller.GetList)
+	groupRoute.GET("/http-boot/:id", controller.GetByID)
+	groupRoute.POST("/http-boot/", controller.Create)
+	groupRoute.PUT("/http-boot/:id", controller.Update)
+	groupRoute.DELETE("/http-boot/:id", controller.Delete)
+
+	groupRoute.GET("/http-boot/:id/path/", controller.GetPaths)
+	groupRoute.GET("/http-boot/:id/path/:pathID", controller.GetPathByID)
+	groupRoute.POST("/http-boot/:id/path/", controller.CreatePath)
+	groupRoute.PUT("/http-boot/:id/path/:pathID", controller.UpdatePath)
+	groupRoute.DELETE("/http-boot/:id/path/:pathID", controller.DeletePath)
+
+	groupRoute.GET("/http-boot/:id/stats", controller.GetFileStats)
+}
+
+//GetList get list of http boot servers with search and pagination
+//
+//Params
+//	ctx - gin context
+// @Summary Get paginated list of http boot servers
+// @version 1.0
+// @Tags	http-boot
+// @Accept  json
+// @Produce json
+// @param	orderBy			query	string	false	"Order by field"
+// @param	orderDirection	query	string	false	"'asc' or 'desc' for ascending or descending order"
+// @param	search			query	string	false	"Searchable value in entity"
+// @param	page			query	int		false	"Page number"
+// @param	pageSize		query	int		false	"Number of entities per page"
+// @Success	200		{object}	dtos.PaginatedItemsDto[dtos.HTTPBootServerDto]
+// @Failure	500		"Internal Server Error"
+// @router /http-boot/ [get]
+func (h *HTTPBootServerGinController) GetList(ctx *gin.Context) {
+	orderBy := ctx.DefaultQuery("orderBy", "id")
+	orderDirection := ctx.DefaultQuery("orderDirection", "asc")
+	search := ctx.DefaultQuery("search", "")
+	page := ctx.DefaultQuery("page", "1")
+	pageInt64, err := strconv.ParseInt(page, 10, 64)
+	if err != nil {
+		pageInt64 = 1
+	}
+	pageSize := ctx.DefaultQuery("pageSize", "10")
+	pageSizeInt64, err := strconv.ParseInt(pageSize, 10, 64)
+	if err != nil {
+		pageSizeInt64 = 10
+	}
+	paginatedList, err := h.service.GetServerList(ctx, search, orderBy, orderDirection, int(pageInt64), int(pageSizeInt64))
+	handleWithData(ctx, err, paginatedList)
+}
+
+//GetByID get http boot server by id
+//
+//Params
+//	ctx - gin context
+// @Summary	Get http boot server by id
+// @version 1.0
+// @Tags	http-boot
+// @Accept	json
+// @Produce	json
+// @param	id		path		string				true	"HTTP boot server ID"
+// @Success	200		{object}	dtos.HTTPBootServerDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /http-boot/{id} [get]
+func (h *HTTPBootServerGinController) GetByID(ctx *gin.Context) {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := h.service.GetServerByID(ctx, id)
+	handleWithData(ctx, err, dto)
+}
+
+//Create new http boot server
+//
+//Params
+//	ctx - gin context
+// @Summary	Create new http boot server
+// @version	1.0
+// @Tags	http-boot
+// @Accept	json
+// @Produce	json
+// @Param	request	body		dtos.HTTPBootServerCreateDto	true	"HTTP boot server fields"
+// @Success	200		{object}	dtos.HTTPBootServerDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	500		"Internal Server Error"
+// @router /http-boot/ [post]
+func (h *HTTPBootServerGinController) Create(ctx *gin.Context) {
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.HTTPBootServerCreateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := h.service.CreateServer(ctx, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//Update http boot server by id
+//
+//Params
+//	ctx - gin context
+// @Summary	Updates http boot server by id
+// @version	1.0
+// @Tags	http-boot
+// @Accept	json
+// @Produce	json
+// @param	id		path		string						true	"HTTP boot server ID"
+// @Param	request	body		dtos.HTTPBootServerUpdateDto	true	"HTTP boot server fields"
+// @Success	200		{object}	dtos.HTTPBootServerDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /http-boot/{id} [put]
+func (h *HTTPBootServerGinController) Update(ctx *gin.Context) {
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.HTTPBootServerUpdateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := h.service.UpdateServer(ctx, id, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//Delete http boot server with its paths
+//
+//Params
+//	ctx - gin context
+// @Summary	Delete http boot server by id
+// @version	1.0
+// @Tags	http-boot
+// @Accept	json
+// @Produce	json
+// @param	id		path					string		true	"HTTP boot server ID"
+// @Success	204		"OK, but No Content"
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /http-boot/{id} [delete]
+func (h *HTTPBootServerGinController) Delete(ctx *gin.Context) {
+	id, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	err = h.service.DeleteServer(ctx, id)
+	handle(ctx, err)
+}
+
+//GetPaths Get list of http boot server paths with pagination
+//
+//Params
+//	ctx - gin context
+// @Summary	Gets paginated list of http boot server paths
+// @version	1.0
+// @Tags	http-boot
+// @Accept  json
+// @Produce	json
+// @param	id				path	string		true	"HTTP boot server ID"
+// @param	orderBy			query	string		false	"Order by field"
+// @param	orderDirection	query	string		false	"'asc' or 'desc' for ascending or descending order"
+// @param	page			query	int			false	"Page number"
+// @param	pageSize		query	int			false	"Number of entities per page"
+// @Success	200		{object}	dtos.PaginatedItemsDto[dtos.HTTPBootPathDto]
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /http-boot/{id}/path/ [get]
+func (h *HTTPBootServerGinController) GetPaths(ctx *gin.Context) {
+	orderBy := ctx.DefaultQuery("orderBy", "VirtualPath")
+	orderDirection := ctx.DefaultQuery("orderDirection", "asc")
+	page := ctx.DefaultQuery("page", "1")
+	pageInt64, err := strconv.ParseInt(page, 10, 64)
+	if err != nil {
+		pageInt64 = 1
+	}
+	pageSize := ctx.DefaultQuery("pageSize", "10")
+	pageSizeInt64, err := strconv.ParseInt(pageSize, 10, 64)
+	if err != nil {
+		pageSizeInt64 = 10
+	}
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	paginatedList, err := h.service.GetPathsList(ctx, serverID, orderBy, orderDirection, int(pageInt64), int(pageSizeInt64))
+	handleWithData(ctx, err, paginatedList)
+}
+
+//GetPathByID get http boot server path by id
+//
+//Params
+//	ctx - gin context
+// @Summary	Get http boot server path by id
+// @version	1.0
+// @Tags	http-boot
+// @Accept	json
+// @Produce	json
+// @param	id		path		string	true	"HTTP boot server ID"
+// @param	pathID	path		string	true	"HTTP boot server path ID"
+// @Success	200		{object}	dtos.HTTPBootPathDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /http-boot/{id}/path/{pathID} [get]
+func (h *HTTPBootServerGinController) GetPathByID(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	pathID, err := parseUUIDParam(ctx, "pathID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := h.service.GetPathByID(ctx, serverID, pathID)
+	handleWithData(ctx, err, dto)
+}
+
+//CreatePath new http boot server path
+//
+//Params
+//	ctx - gin context
+// @Summary Creates new http boot server path
+// @version 1.0
+// @Tags	http-boot
+// @Accept	json
+// @Produce	json
+// @param	id		path		string						true	"HTTP boot server ID"
+// @Param	request	body		dtos.HTTPBootPathCreateDto	true	"HTTP boot server path fields"
+// @Success	200		{object}	dtos.HTTPBootPathDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /http-boot/{id}/path/ [post]
+func (h *HTTPBootServerGinController) CreatePath(ctx *gin.Context) {
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.HTTPBootPathCreateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := h.service.CreatePath(ctx, serverID, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//UpdatePath update http boot server path
+//
+//Params
+//	ctx - gin context
+// @Summary Updates http boot server path by id
+// @version 1.0
+// @Tags	http-boot
+// @Accept	json
+// @Produce	json
+// @param	id		path		string						true	"HTTP boot server ID"
+// @param	pathID	path		string						true	"HTTP boot server path ID"
+// @Param	request	body		dtos.HTTPBootPathUpdateDto	true	"HTTP boot server path fields"
+// @Success	200		{object}	dtos.HTTPBootPathDto
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /http-boot/{id}/path/{pathID} [put]
+func (h *HTTPBootServerGinController) UpdatePath(ctx *gin.Context) {
+	reqDto, err := getRequestDtoAndRestoreBody[dtos.HTTPBootPathUpdateDto](ctx)
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	pathID, err := parseUUIDParam(ctx, "pathID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	dto, err := h.service.UpdatePath(ctx, serverID, pathID, reqDto)
+	handleWithData(ctx, err, dto)
+}
+
+//DeletePath deleting http boot server path
+//
+//Params
+//	ctx - gin context
+// @Summary Delete http boot server path by id
+// @version 1.0
+// @Tags	http-boot
+// @Accept	json
+// @Produce	json
+// @param	id		path					string	true	"HTTP boot server ID"
+// @param	pathID	path					string	true	"HTTP boot server path ID"
+// @Success	204		"OK, but No Content"
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /http-boot/{id}/path/{pathID} [delete]
+func (h *HTTPBootServerGinController) DeletePath(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	pathID, err := parseUUIDParam(ctx, "pathID")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	err = h.service.DeletePath(ctx, serverID, pathID)
+	handle(ctx, err)
+}
+
+//GetFileStats get download statistics of the files served by the http boot server
+//
+//Params
+//	ctx - gin context
+// @Summary	Gets download statistics of the files served by the http boot server
+// @version	1.0
+// @Tags	http-boot
+// @Accept  json
+// @Produce	json
+// @param	id		path	string		true	"HTTP boot server ID"
+// @Success	200		{object}	[]dtos.HTTPBootFileStatsDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /http-boot/{id}/stats [get]
+func (h *HTTPBootServerGinController) GetFileStats(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	stats, err := h.service.GetFileStats(ctx, serverID)
+	handleWithData(ctx, err, stats)
+}
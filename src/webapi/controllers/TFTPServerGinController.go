@@ -5,6 +5,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
+	"path/filepath"
 	"rol/app/services"
 	"rol/dtos"
 	"rol/webapi"
@@ -46,6 +47,13 @@ func RegisterTFTPServerGinController(controller *TFTPServerGinController, server
 	groupRoute.GET("/tftp/:id/path/", controller.GetPaths)
 	groupRoute.POST("/tftp/:id/path/", controller.CreatePath)
 	groupRoute.DELETE("/tftp/:id/path/:pathID", controller.DeletePath)
+
+	groupRoute.GET("/tftp/:id/upload", controller.GetUploads)
+	groupRoute.GET("/tftp/:id/upload/*name", controller.DownloadUpload)
+	groupRoute.DELETE("/tftp/:id/upload/*name", controller.DeleteUpload)
+
+	groupRoute.GET("/tftp/:id/transfers", controller.GetTransfers)
+	groupRoute.GET("/tftp/:id/transfers/stats", controller.GetPathStats)
 }
 
 //GetList get list of tftp servers with search and pagination
@@ -291,3 +299,131 @@ func (t *TFTPServerGinController) DeletePath(ctx *gin.Context) {
 	err = t.service.DeletePath(ctx, serverID, pathID)
 	handle(ctx, err)
 }
+
+//GetUploads Get list of files uploaded to the TFTP server
+//
+//Params
+//	ctx - gin context
+// @Summary	Gets list of files uploaded to the TFTP server
+// @version	1.0
+// @Tags	tftp
+// @Accept  json
+// @Produce	json
+// @param	id		path	string		true	"TFTP server ID"
+// @Success	200		{object}	[]dtos.TFTPUploadDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /tftp/{id}/upload [get]
+func (t *TFTPServerGinController) GetUploads(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	uploads, err := t.service.GetUploadList(ctx, serverID)
+	handleWithData(ctx, err, uploads)
+}
+
+//DownloadUpload download file uploaded to the TFTP server
+//
+//Params
+//	ctx - gin context
+// @Summary	Download file uploaded to the TFTP server
+// @version	1.0
+// @Tags	tftp
+// @Produce	octet-stream
+// @param	id		path	string		true	"TFTP server ID"
+// @param	name	path	string		true	"Uploaded file name relative to the upload directory"
+// @Success	200		{file}	file
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /tftp/{id}/upload/{name} [get]
+func (t *TFTPServerGinController) DownloadUpload(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	filePath, err := t.service.GetUploadFilePath(ctx, serverID, ctx.Param("name"))
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	ctx.FileAttachment(filePath, filepath.Base(filePath))
+}
+
+//DeleteUpload deleting file uploaded to the TFTP server
+//
+//Params
+//	ctx - gin context
+// @Summary Delete file uploaded to the TFTP server
+// @version 1.0
+// @Tags tftp
+// @Accept	json
+// @Produce	json
+// @param	id		path	string	true	"TFTP server ID"
+// @param	name	path	string	true	"Uploaded file name relative to the upload directory"
+// @Success	204		"OK, but No Content"
+// @Failure	400		{object}	dtos.ValidationErrorDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /tftp/{id}/upload/{name} [delete]
+func (t *TFTPServerGinController) DeleteUpload(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	err = t.service.DeleteUpload(ctx, serverID, ctx.Param("name"))
+	handle(ctx, err)
+}
+
+//GetTransfers Get active and recent finished transfers of the TFTP server
+//
+//Params
+//	ctx - gin context
+// @Summary	Gets active and recent finished transfers of the TFTP server, the newest first
+// @version	1.0
+// @Tags	tftp
+// @Accept  json
+// @Produce	json
+// @param	id		path	string		true	"TFTP server ID"
+// @param	state	query	string		false	"Transfer state filter: active, succeeded or failed"
+// @Success	200		{object}	[]dtos.TFTPTransferDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /tftp/{id}/transfers [get]
+func (t *TFTPServerGinController) GetTransfers(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	transfers, err := t.service.GetTransferList(ctx, serverID, ctx.DefaultQuery("state", ""))
+	handleWithData(ctx, err, transfers)
+}
+
+//GetPathStats Get transfer counters for each file requested from the TFTP server
+//
+//Params
+//	ctx - gin context
+// @Summary	Gets transfer counters for each file requested from the TFTP server
+// @version	1.0
+// @Tags	tftp
+// @Accept  json
+// @Produce	json
+// @param	id		path	string		true	"TFTP server ID"
+// @Success	200		{object}	[]dtos.TFTPPathStatsDto
+// @Failure	404		"Not Found"
+// @Failure	500		"Internal Server Error"
+// @router /tftp/{id}/transfers/stats [get]
+func (t *TFTPServerGinController) GetPathStats(ctx *gin.Context) {
+	serverID, err := parseUUIDParam(ctx, "id")
+	if err != nil {
+		abortWithStatusByErrorType(ctx, err)
+		return
+	}
+	stats, err := t.service.GetPathStatsList(ctx, serverID)
+	handleWithData(ctx, err, stats)
+}